@@ -17,6 +17,9 @@ type bfvContext struct {
 	// Polynomial degree
 	n uint64
 
+	// Number of available levels
+	levels uint64
+
 	gaussianSampler *ring.KYSampler
 
 	// Polynomial contexts
@@ -44,6 +47,7 @@ func newBFVContext(params *Parameters) (context *bfvContext) {
 	N := uint64(1 << LogN)
 
 	context.n = N
+	context.levels = uint64(len(params.Qi))
 
 	if context.contextT, err = ring.NewContextWithParams(N, []uint64{params.T}); err != nil {
 		panic(err)
@@ -74,3 +78,18 @@ func newBFVContext(params *Parameters) (context *bfvContext) {
 	context.galElRotRow = 2*context.n - 1
 	return
 }
+
+// contextQLvl returns the polynomial context of the moduli q_0 up to q_level. The NTT parameters are not
+// generated for the lower levels, hence the returned context must only be used for operations outside of
+// the NTT domain (e.g. basis extension and scaling).
+func (context *bfvContext) contextQLvl(level uint64) *ring.Context {
+
+	if level == context.levels-1 {
+		return context.contextQ
+	}
+
+	contextQLvl := ring.NewContext()
+	contextQLvl.SetParameters(context.n, context.contextQ.Modulus[:level+1])
+
+	return contextQLvl
+}
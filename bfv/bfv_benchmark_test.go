@@ -1,7 +1,11 @@
 package bfv
 
 import (
+	"fmt"
+	"runtime"
 	"testing"
+
+	"github.com/ldsec/lattigo/ring"
 )
 
 type benchParams struct {
@@ -14,6 +18,7 @@ func BenchmarkBFV(b *testing.B) {
 	b.Run("Encrypt", benchEncrypt)
 	b.Run("Decrypt", benchDecrypt)
 	b.Run("Evaluator", benchEvaluator)
+	b.Run("WorkerPool", benchWorkerPool)
 }
 
 func benchEncoder(b *testing.B) {
@@ -113,11 +118,22 @@ func benchEvaluator(b *testing.B) {
 		ciphertext2 := NewCiphertextRandom(parameters, 1)
 		receiver := NewCiphertextRandom(parameters, 2)
 
+		plaintext := NewPlaintext(parameters)
+		plaintextMul := NewPlaintextMul(parameters)
+		params.encoder.EncodeUint(params.bfvContext.contextT.NewUniformPoly().Coeffs[0], plaintext)
+		params.encoder.EncodeUintMul(params.bfvContext.contextT.NewUniformPoly().Coeffs[0], plaintextMul)
+
 		rlk := params.kgen.GenRelinKey(params.sk, 1)
 		rotkey := NewRotationKeys()
 		params.kgen.GenRot(RotationLeft, params.sk, 1, rotkey)
 		params.kgen.GenRot(RotationRow, params.sk, 0, rotkey)
 
+		// Rotations compared between the hoisted and the sequential rotations of the columns
+		rotations := []uint64{1, 2, 3, 4, 5, 6, 7, 8}
+		for _, k := range rotations {
+			params.kgen.GenRot(RotationLeft, params.sk, k, rotkey)
+		}
+
 		b.Run(testString("Add/", parameters), func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				evaluator.Add(ciphertext1, ciphertext2, ciphertext1)
@@ -136,6 +152,18 @@ func benchEvaluator(b *testing.B) {
 			}
 		})
 
+		b.Run(testString("MulPlain/", parameters), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				evaluator.Mul(ciphertext1, plaintext, ciphertext2)
+			}
+		})
+
+		b.Run(testString("MulPlainMul/", parameters), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				evaluator.Mul(ciphertext1, plaintextMul, ciphertext2)
+			}
+		})
+
 		b.Run(testString("Square/", parameters), func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
 				evaluator.Mul(ciphertext1, ciphertext1, receiver)
@@ -160,5 +188,65 @@ func benchEvaluator(b *testing.B) {
 			}
 		})
 
+		b.Run(testString("RotateColsSequential/", parameters), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, k := range rotations {
+					evaluator.RotateColumns(ciphertext1, k, rotkey, ciphertext2)
+				}
+			}
+		})
+
+		b.Run(testString("RotateHoisted/", parameters), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				evaluator.RotateHoisted(ciphertext1, rotations, rotkey)
+			}
+		})
+
+	}
+}
+
+func benchWorkerPool(b *testing.B) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		ciphertext1 := NewCiphertextRandom(parameters, 1)
+		ciphertext2 := NewCiphertextRandom(parameters, 1)
+		receiver := NewCiphertextRandom(parameters, 2)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		for _, workers := range benchWorkers() {
+
+			evaluator := NewEvaluator(parameters)
+
+			var pool *ring.WorkerPool
+			if workers != 0 {
+				pool = ring.NewWorkerPool(workers)
+				evaluator.SetWorkerPool(pool)
+			}
+
+			b.Run(testString(fmt.Sprintf("MulRelin/workers=%d/", workers), parameters), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					evaluator.Mul(ciphertext1, ciphertext2, receiver)
+					evaluator.Relinearize(receiver, rlk, ciphertext1)
+				}
+			})
+
+			if pool != nil {
+				pool.Close()
+			}
+		}
+	}
+}
+
+// benchWorkers returns the numbers of workers of the WorkerPools to benchmark: 0 (no WorkerPool), which is the
+// reference, then the powers of two up to the number of available cores.
+func benchWorkers() (workers []uint64) {
+	workers = []uint64{0}
+	for w := uint64(1); w <= uint64(runtime.NumCPU()); w <<= 1 {
+		workers = append(workers, w)
 	}
+	return
 }
@@ -1,9 +1,13 @@
 package bfv
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"math/rand"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,6 +22,15 @@ func check(t *testing.T, err error) {
 	}
 }
 
+// panics returns true if f panics.
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	f()
+	return
+}
+
 func testString(opname string, params *Parameters) string {
 	return fmt.Sprintf("%sLogN=%d/logQ=%d", opname, params.LogN, params.logQP)
 }
@@ -62,7 +75,16 @@ func TestBFV(t *testing.T) {
 	t.Run("Evaluator/KeySwitch", testKeySwitch)
 	t.Run("Evaluator/RotateRows", testRotateRows)
 	t.Run("Evaluator/RotateCols", testRotateCols)
+	t.Run("Evaluator/MulMatrix", testMulMatrix)
+	t.Run("Evaluator/DotProduct", testDotProduct)
+	t.Run("Evaluator/EvaluatePoly", testEvaluatePoly)
+	t.Run("Evaluator/ModSwitch", testModSwitch)
 	t.Run("Marshalling", testMarshaller)
+	t.Run("Streaming", testStreaming)
+	t.Run("SeededKeys", testSeededKeys)
+	t.Run("SeededCiphertexts", testSeededCiphertexts)
+	t.Run("ShallowCopy", testShallowCopy)
+	t.Run("NoiseBudget", testNoiseBudget)
 }
 
 func testMarshaller(t *testing.T) {
@@ -190,49 +212,25 @@ func testMarshaller(t *testing.T) {
 			err = resRotationKey.UnmarshalBinary(data)
 			check(t, err)
 
-			for i := uint64(1); i < params.bfvContext.n>>1; i++ {
-
-				if rotationKey.evakeyRotColLeft[i] != nil {
-
-					evakeyWant := rotationKey.evakeyRotColLeft[i].evakey
-					evakeyTest := resRotationKey.evakeyRotColLeft[i].evakey
-
-					for j := range evakeyWant {
-
-						for k := range evakeyWant[j] {
-							if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-								t.Errorf("marshal RotationKey RotateLeft %d element [%d][%d]", i, j, k)
-							}
-						}
-					}
-				}
-
-				if rotationKey.evakeyRotColRight[i] != nil {
-
-					evakeyWant := rotationKey.evakeyRotColRight[i].evakey
-					evakeyTest := resRotationKey.evakeyRotColRight[i].evakey
+			if len(resRotationKey.keys) != len(rotationKey.keys) {
+				t.Errorf("marshal RotationKey number of keys")
+			}
 
-					for j := range evakeyWant {
+			for galEl, switchkey := range rotationKey.keys {
 
-						for k := range evakeyWant[j] {
-							if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-								t.Errorf("marshal RotationKey RotateRight %d element [%d][%d]", i, j, k)
-							}
-						}
-					}
+				if resRotationKey.keys[galEl] == nil {
+					t.Errorf("marshal RotationKey Galois element %d missing", galEl)
+					continue
 				}
-			}
 
-			if rotationKey.evakeyRotRow != nil {
-
-				evakeyWant := rotationKey.evakeyRotRow.evakey
-				evakeyTest := resRotationKey.evakeyRotRow.evakey
+				evakeyWant := switchkey.evakey
+				evakeyTest := resRotationKey.keys[galEl].evakey
 
 				for j := range evakeyWant {
 
 					for k := range evakeyWant[j] {
 						if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-							t.Errorf("marshal RotationKey RotateRow element [%d][%d]", j, k)
+							t.Errorf("marshal RotationKey Galois element %d element [%d][%d]", galEl, j, k)
 						}
 					}
 				}
@@ -241,260 +239,946 @@ func testMarshaller(t *testing.T) {
 	}
 }
 
-func genBfvParams(contextParameters *Parameters) (params *bfvParams) {
+type streamable interface {
+	io.WriterTo
+	MarshalBinary() ([]byte, error)
+	GetDataLen(WithMetadata bool) uint64
+}
 
-	params = new(bfvParams)
+// writeAndCompare writes the object with WriteTo and checks that the result matches MarshalBinary.
+func writeAndCompare(t *testing.T, object streamable) []byte {
 
-	params.params = contextParameters.Copy()
+	buffer := new(bytes.Buffer)
 
-	params.bfvContext = newBFVContext(contextParameters)
+	n, err := object.WriteTo(buffer)
+	check(t, err)
 
-	params.kgen = NewKeyGenerator(contextParameters)
+	if uint64(n) != object.GetDataLen(true) || n != int64(buffer.Len()) {
+		t.Errorf("invalid number of bytes written")
+	}
 
-	params.sk, params.pk = params.kgen.GenKeyPair()
+	data, err := object.MarshalBinary()
+	check(t, err)
 
-	params.encoder = NewEncoder(contextParameters)
+	if !bytes.Equal(data, buffer.Bytes()) {
+		t.Errorf("WriteTo does not match MarshalBinary")
+	}
 
-	params.encryptorPk = NewEncryptorFromPk(contextParameters, params.pk)
-	params.encryptorSk = NewEncryptorFromSk(contextParameters, params.sk)
-	params.decryptor = NewDecryptor(contextParameters, params.sk)
+	return buffer.Bytes()
+}
 
-	params.evaluator = NewEvaluator(contextParameters)
+func testStreaming(t *testing.T) {
 
-	return
+	for _, parameters := range testParams.bfvParameters {
 
-}
+		params := genBfvParams(parameters)
 
-func newTestVectors(params *bfvParams, encryptor Encryptor, t *testing.T) (coeffs *ring.Poly, plaintext *Plaintext, ciphertext *Ciphertext) {
+		contextQP := params.bfvContext.contextQP
 
-	coeffs = params.bfvContext.contextT.NewUniformPoly()
+		equalSwitchingKey := func(swkWant, swkTest *SwitchingKey) bool {
+			for j := range swkWant.evakey {
+				for k := range swkWant.evakey[j] {
+					if !contextQP.Equal(swkWant.evakey[j][k], swkTest.evakey[j][k]) {
+						return false
+					}
+				}
+			}
+			return true
+		}
 
-	plaintext = NewPlaintext(params.params)
+		t.Run(testString("Ciphertext/", parameters), func(t *testing.T) {
 
-	params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+			ciphertextWant := NewCiphertextRandom(parameters, 2)
 
-	if encryptor != nil {
-		ciphertext = params.encryptorPk.EncryptNew(plaintext)
-	}
+			data := writeAndCompare(t, ciphertextWant)
 
-	return coeffs, plaintext, ciphertext
-}
+			ciphertextTest := new(Ciphertext)
+			_, err := ciphertextTest.ReadFrom(bytes.NewReader(data))
+			check(t, err)
 
-func verifyTestVectors(params *bfvParams, decryptor Decryptor, coeffs *ring.Poly, element Operand, t *testing.T) {
+			if ciphertextTest.Degree() != ciphertextWant.Degree() || ciphertextTest.IsNTT() != ciphertextWant.IsNTT() {
+				t.Errorf("stream Ciphertext metadata")
+			}
 
-	var coeffsTest []uint64
+			for i := range ciphertextWant.value {
+				if !params.bfvContext.contextQ.Equal(ciphertextWant.value[i], ciphertextTest.value[i]) {
+					t.Errorf("stream Ciphertext")
+				}
+			}
+		})
 
-	el := element.Element()
+		t.Run(testString("Ciphertext/InvalidHeader/", parameters), func(t *testing.T) {
 
-	if el.Degree() == 0 {
+			ciphertext := NewCiphertextRandom(parameters, 2)
 
-		coeffsTest = params.encoder.DecodeUint(el.Plaintext())
+			data := writeAndCompare(t, ciphertext)
 
-	} else {
+			// Offset of the header of the first polynomial, which follows the header of the ciphertext
+			offset := len(data) - len(ciphertext.value)*int(ciphertext.value[0].GetDataLen(true))
 
-		coeffsTest = params.encoder.DecodeUint(decryptor.DecryptNew(el.Ciphertext()))
-	}
+			corrupt := func(i int, b byte) []byte {
+				corrupted := append([]byte{}, data...)
+				corrupted[i] = b
+				return corrupted
+			}
 
-	if utils.EqualSliceUint64(coeffs.Coeffs[0], coeffsTest) != true {
-		t.Errorf("decryption error")
-	}
-}
+			for name, invalid := range map[string][]byte{
+				"logN=0":       corrupt(offset, 0),
+				"logN>MaxLogN": corrupt(offset, MaxLogN+1),
+				"logN=255":     corrupt(offset, 255),
+				"moduli=0":     corrupt(offset+1, 0),
+				"moduli=255":   corrupt(offset+1, 255),
+				"truncated":    data[:len(data)/2],
+				"headerOnly":   data[:offset+2],
+			} {
+				if _, err := new(Ciphertext).ReadFrom(bytes.NewReader(invalid)); err == nil {
+					t.Errorf("invalid stream (%s) has been accepted", name)
+				}
+			}
+		})
 
-func testEncoder(t *testing.T) {
+		t.Run(testString("Sk/", parameters), func(t *testing.T) {
 
-	for _, parameters := range testParams.bfvParameters {
+			data := writeAndCompare(t, params.sk)
 
-		params := genBfvParams(parameters)
+			sk := new(SecretKey)
+			_, err := sk.ReadFrom(bytes.NewReader(data))
+			check(t, err)
 
-		t.Run(testString("Encode&Decode/", parameters), func(t *testing.T) {
+			if !contextQP.Equal(sk.sk, params.sk.sk) {
+				t.Errorf("stream SecretKey")
+			}
+		})
 
-			values, plaintext, _ := newTestVectors(params, nil, t)
+		t.Run(testString("Pk/", parameters), func(t *testing.T) {
 
-			verifyTestVectors(params, params.decryptor, values, plaintext, t)
-		})
-	}
-}
+			data := writeAndCompare(t, params.pk)
 
-func testEncryptor(t *testing.T) {
+			pk := new(PublicKey)
+			_, err := pk.ReadFrom(bytes.NewReader(data))
+			check(t, err)
 
-	for _, parameters := range testParams.bfvParameters {
+			for k := range params.pk.pk {
+				if !contextQP.Equal(pk.pk[k], params.pk.pk[k]) {
+					t.Errorf("stream PublicKey element [%d]", k)
+				}
+			}
+		})
 
-		params := genBfvParams(parameters)
+		t.Run(testString("EvaluationKey/", parameters), func(t *testing.T) {
 
-		t.Run(testString("EncryptFromPk/", parameters), func(t *testing.T) {
+			evalkey := params.kgen.GenRelinKey(params.sk, 2)
 
-			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+			data := writeAndCompare(t, evalkey)
 
-			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+			resEvalKey := new(EvaluationKey)
+			_, err := resEvalKey.ReadFrom(bytes.NewReader(data))
+			check(t, err)
+
+			for deg := range evalkey.evakey {
+				if !equalSwitchingKey(evalkey.evakey[deg], resEvalKey.evakey[deg]) {
+					t.Errorf("stream EvaluationKey deg %d", deg)
+				}
+			}
 		})
 
-		t.Run(testString("EncryptFromPkFast/", parameters), func(t *testing.T) {
+		t.Run(testString("SwitchingKey/", parameters), func(t *testing.T) {
 
-			coeffs := params.bfvContext.contextT.NewUniformPoly()
+			switchingKey := params.kgen.GenSwitchingKey(params.sk, params.kgen.GenSecretKey())
 
-			plaintext := NewPlaintext(params.params)
+			data := writeAndCompare(t, switchingKey)
 
-			params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+			resSwitchingKey := new(SwitchingKey)
+			_, err := resSwitchingKey.ReadFrom(bytes.NewReader(data))
+			check(t, err)
 
-			verifyTestVectors(params, params.decryptor, coeffs, params.encryptorPk.EncryptFastNew(plaintext), t)
+			if !equalSwitchingKey(switchingKey, resSwitchingKey) {
+				t.Errorf("stream SwitchingKey")
+			}
 		})
 
-		t.Run(testString("EncryptFromSk/", parameters), func(t *testing.T) {
+		t.Run(testString("RotationKey/", parameters), func(t *testing.T) {
 
-			values, _, ciphertext := newTestVectors(params, params.encryptorSk, t)
+			rotationKey := NewRotationKeys()
 
-			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
-		})
+			params.kgen.GenRot(RotationRow, params.sk, 0, rotationKey)
+			params.kgen.GenRot(RotationLeft, params.sk, 1, rotationKey)
+			params.kgen.GenRot(RotationRight, params.sk, 3, rotationKey)
 
-		t.Run(testString("EncryptFromSkFast/", parameters), func(t *testing.T) {
+			buffer := new(bytes.Buffer)
 
-			coeffs := params.bfvContext.contextT.NewUniformPoly()
+			n, err := rotationKey.WriteTo(buffer)
+			check(t, err)
 
-			plaintext := NewPlaintext(params.params)
+			if uint64(n) != rotationKey.GetDataLen(true) {
+				t.Errorf("invalid number of bytes written")
+			}
 
-			params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+			resRotationKey := new(RotationKeys)
+			_, err = resRotationKey.ReadFrom(buffer)
+			check(t, err)
 
-			verifyTestVectors(params, params.decryptor, coeffs, params.encryptorSk.EncryptFastNew(plaintext), t)
+			if !equalSwitchingKey(rotationKey.keys[params.bfvContext.galElRotColLeft[1]], resRotationKey.keys[params.bfvContext.galElRotColLeft[1]]) {
+				t.Errorf("stream RotationKey RotateLeft")
+			}
+
+			if !equalSwitchingKey(rotationKey.keys[params.bfvContext.galElRotColRight[3]], resRotationKey.keys[params.bfvContext.galElRotColRight[3]]) {
+				t.Errorf("stream RotationKey RotateRight")
+			}
+
+			if !equalSwitchingKey(rotationKey.keys[params.bfvContext.galElRotRow], resRotationKey.keys[params.bfvContext.galElRotRow]) {
+				t.Errorf("stream RotationKey RotateRow")
+			}
+
+			// A truncated stream must not be accepted
+			data, err := rotationKey.MarshalBinary()
+			check(t, err)
+
+			if _, err = new(RotationKeys).ReadFrom(bytes.NewReader(data[:len(data)-1])); err == nil {
+				t.Errorf("stream RotationKey truncated")
+			}
+
+			// Only the keys announced in the header must be read from the stream
+			buffer = bytes.NewBuffer(append(data, 0xFF))
+
+			n, err = new(RotationKeys).ReadFrom(buffer)
+			check(t, err)
+
+			if n != int64(len(data)) || buffer.Len() != 1 {
+				t.Errorf("stream RotationKey trailing data")
+			}
 		})
 	}
 }
 
-func testEvaluatorAdd(t *testing.T) {
+func testSeededKeys(t *testing.T) {
 
 	for _, parameters := range testParams.bfvParameters {
 
 		params := genBfvParams(parameters)
 
-		t.Run(testString("CtCtInPlace/", parameters), func(t *testing.T) {
-
-			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+		contextQP := params.bfvContext.contextQP
 
-			params.evaluator.Add(ciphertext1, ciphertext2, ciphertext1)
-			params.bfvContext.contextT.Add(values1, values2, values1)
+		kgen := NewKeyGeneratorSeeded(parameters)
 
-			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
-		})
+		equalSwitchingKey := func(swkWant, swkTest *SwitchingKey) bool {
+			for j := range swkWant.evakey {
+				for k := range swkWant.evakey[j] {
+					if !contextQP.Equal(swkWant.evakey[j][k], swkTest.evakey[j][k]) {
+						return false
+					}
+				}
+			}
+			return true
+		}
 
-		t.Run(testString("CtCtNew/", parameters), func(t *testing.T) {
+		t.Run(testString("Pk/", parameters), func(t *testing.T) {
 
-			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+			pk := kgen.GenPublicKey(params.sk)
 
-			ciphertext1 = params.evaluator.AddNew(ciphertext1, ciphertext2)
-			params.bfvContext.contextT.Add(values1, values2, values1)
+			if !pk.IsSeeded() || params.pk.IsSeeded() {
+				t.Errorf("invalid seeded PublicKey")
+			}
 
-			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
-		})
+			data, err := pk.MarshalBinary()
+			check(t, err)
 
-		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+			dataUnseeded, err := params.pk.MarshalBinary()
+			check(t, err)
 
-			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, plaintext2, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+			if uint64(len(data)) != uint64(len(dataUnseeded))-pk.pk[1].GetDataLen(true)+pk.seed.GetDataLen(true) {
+				t.Errorf("seeded PublicKey is not compressed")
+			}
 
-			params.evaluator.Add(ciphertext1, plaintext2, ciphertext2)
-			params.bfvContext.contextT.Add(values1, values2, values2)
+			pkTest := new(PublicKey)
+			check(t, pkTest.UnmarshalBinary(data))
 
-			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+			pkStream := new(PublicKey)
+			_, err = pkStream.ReadFrom(bytes.NewReader(writeAndCompare(t, pk)))
+			check(t, err)
 
-			params.evaluator.Add(plaintext2, ciphertext1, ciphertext2)
+			for k := range pk.pk {
+				if !contextQP.Equal(pk.pk[k], pkTest.pk[k]) || !contextQP.Equal(pk.pk[k], pkStream.pk[k]) {
+					t.Errorf("seeded PublicKey element [%d]", k)
+				}
+			}
 
-			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+			// The seeded PublicKey must be a valid encryption key
+			coeffs, _, ciphertext := newTestVectors(params, NewEncryptorFromPk(parameters, pkTest), t)
+			verifyTestVectors(params, params.decryptor, coeffs, ciphertext, t)
 		})
-	}
-}
 
-func testEvaluatorSub(t *testing.T) {
+		t.Run(testString("EvaluationKey/", parameters), func(t *testing.T) {
 
-	for _, parameters := range testParams.bfvParameters {
+			evalkey := kgen.GenRelinKey(params.sk, 2)
 
-		params := genBfvParams(parameters)
+			data, err := evalkey.MarshalBinary()
+			check(t, err)
 
-		t.Run(testString("CtCtInPlace/", parameters), func(t *testing.T) {
+			evalkeyTest := new(EvaluationKey)
+			check(t, evalkeyTest.UnmarshalBinary(data))
 
-			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+			evalkeyStream := new(EvaluationKey)
+			_, err = evalkeyStream.ReadFrom(bytes.NewReader(writeAndCompare(t, evalkey)))
+			check(t, err)
 
-			params.evaluator.Sub(ciphertext1, ciphertext2, ciphertext1)
-			params.bfvContext.contextT.Sub(values1, values2, values1)
+			for deg := range evalkey.evakey {
+				if !evalkey.evakey[deg].IsSeeded() {
+					t.Errorf("EvaluationKey deg %d is not seeded", deg)
+				}
 
-			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+				if !equalSwitchingKey(evalkey.evakey[deg], evalkeyTest.evakey[deg]) || !equalSwitchingKey(evalkey.evakey[deg], evalkeyStream.evakey[deg]) {
+					t.Errorf("seeded EvaluationKey deg %d", deg)
+				}
+			}
 		})
 
-		t.Run(testString("CtCtNew/", parameters), func(t *testing.T) {
+		t.Run(testString("SwitchingKey/", parameters), func(t *testing.T) {
 
-			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+			switchingKey := kgen.GenSwitchingKey(params.sk, kgen.GenSecretKey())
 
-			ciphertext1 = params.evaluator.SubNew(ciphertext1, ciphertext2)
-			params.bfvContext.contextT.Sub(values1, values2, values1)
+			data, err := switchingKey.MarshalBinary()
+			check(t, err)
 
-			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+			switchingKeyTest := new(SwitchingKey)
+			check(t, switchingKeyTest.UnmarshalBinary(data))
+
+			switchingKeyStream := new(SwitchingKey)
+			_, err = switchingKeyStream.ReadFrom(bytes.NewReader(writeAndCompare(t, switchingKey)))
+			check(t, err)
+
+			if !switchingKeyTest.IsSeeded() || !equalSwitchingKey(switchingKey, switchingKeyTest) || !equalSwitchingKey(switchingKey, switchingKeyStream) {
+				t.Errorf("seeded SwitchingKey")
+			}
 		})
 
-		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+		t.Run(testString("RotationKey/", parameters), func(t *testing.T) {
 
-			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, plaintext2, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+			rotationKey := NewRotationKeys()
 
-			valuesWant := params.bfvContext.contextT.NewPoly()
+			kgen.GenRot(RotationRow, params.sk, 0, rotationKey)
+			kgen.GenRot(RotationLeft, params.sk, 1, rotationKey)
 
-			params.evaluator.Sub(ciphertext1, plaintext2, ciphertext2)
-			params.bfvContext.contextT.Sub(values1, values2, valuesWant)
-			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext2, t)
+			data, err := rotationKey.MarshalBinary()
+			check(t, err)
 
-			params.evaluator.Sub(plaintext2, ciphertext1, ciphertext2)
-			params.bfvContext.contextT.Sub(values2, values1, valuesWant)
-			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext2, t)
+			rotationKeyTest := new(RotationKeys)
+			check(t, rotationKeyTest.UnmarshalBinary(data))
+
+			if !equalSwitchingKey(rotationKey.keys[params.bfvContext.galElRotColLeft[1]], rotationKeyTest.keys[params.bfvContext.galElRotColLeft[1]]) {
+				t.Errorf("seeded RotationKey RotateLeft")
+			}
+
+			if !equalSwitchingKey(rotationKey.keys[params.bfvContext.galElRotRow], rotationKeyTest.keys[params.bfvContext.galElRotRow]) {
+				t.Errorf("seeded RotationKey RotateRow")
+			}
+
+			// The unmarshaled seeded RotationKeys must be valid rotation keys
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+			params.evaluator.RotateRows(ciphertext, rotationKeyTest, ciphertext)
+			values.Coeffs[0] = append(values.Coeffs[0][params.bfvContext.n>>1:], values.Coeffs[0][:params.bfvContext.n>>1]...)
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
 	}
 }
 
-func testEvaluatorMul(t *testing.T) {
+func testSeededCiphertexts(t *testing.T) {
 
 	for _, parameters := range testParams.bfvParameters {
 
 		params := genBfvParams(parameters)
 
-		rlk := params.kgen.GenRelinKey(params.sk, 1)
+		contextQ := params.bfvContext.contextQ
 
-		t.Run(testString("CtCt/", parameters), func(t *testing.T) {
+		encryptor := NewEncryptorFromSkSeeded(parameters, params.sk)
 
-			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+		t.Run(testString("Marshalling/", parameters), func(t *testing.T) {
 
-			receiver := NewCiphertext(parameters, ciphertext1.Degree()+ciphertext2.Degree())
-			params.evaluator.Mul(ciphertext1, ciphertext2, receiver)
-			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+			coeffs, plaintext, _ := newTestVectors(params, nil, t)
 
-			verifyTestVectors(params, params.decryptor, values1, receiver, t)
-		})
+			ciphertext := encryptor.EncryptNew(plaintext)
 
-		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+			data, err := ciphertext.MarshalBinary()
+			check(t, err)
+
+			dataUnseeded, err := (&Ciphertext{bfvElement: ciphertext.bfvElement}).MarshalBinary()
+			check(t, err)
+
+			if uint64(len(data)) != uint64(len(dataUnseeded))-ciphertext.value[1].GetDataLen(true)+ciphertext.seed.GetDataLen(true) {
+				t.Errorf("seeded Ciphertext is not compressed")
+			}
+
+			ciphertextTest := new(Ciphertext)
+			check(t, ciphertextTest.UnmarshalBinary(data))
+
+			ciphertextStream := new(Ciphertext)
+			_, err = ciphertextStream.ReadFrom(bytes.NewReader(writeAndCompare(t, ciphertext)))
+			check(t, err)
+
+			for i := range ciphertext.value {
+				if !contextQ.Equal(ciphertext.value[i], ciphertextTest.value[i]) || !contextQ.Equal(ciphertext.value[i], ciphertextStream.value[i]) {
+					t.Errorf("seeded Ciphertext element [%d]", i)
+				}
+			}
+
+			verifyTestVectors(params, params.decryptor, coeffs, ciphertextTest, t)
+		})
+
+		t.Run(testString("Modified/", parameters), func(t *testing.T) {
+
+			coeffs, plaintext, _ := newTestVectors(params, nil, t)
+
+			ciphertext := encryptor.EncryptNew(plaintext)
+
+			params.evaluator.Add(ciphertext, ciphertext, ciphertext)
+			params.bfvContext.contextT.Add(coeffs, coeffs, coeffs)
+
+			// The modified Ciphertext can no longer be regenerated from its seed and must be marshaled in full
+			data, err := ciphertext.MarshalBinary()
+			check(t, err)
+
+			if uint64(len(data)) != 2+ciphertext.value[0].GetDataLen(true)+ciphertext.value[1].GetDataLen(true) {
+				t.Errorf("modified seeded Ciphertext is compressed")
+			}
+
+			ciphertextTest := new(Ciphertext)
+			check(t, ciphertextTest.UnmarshalBinary(data))
+
+			verifyTestVectors(params, params.decryptor, coeffs, ciphertextTest, t)
+		})
+
+		t.Run(testString("ModifiedValue/", parameters), func(t *testing.T) {
+
+			coeffs, plaintext, _ := newTestVectors(params, nil, t)
+
+			ciphertext := encryptor.EncryptNew(plaintext)
+
+			// Reading the Value of the Ciphertext does not discard its seed
+			if ciphertext.Value()[1] != ciphertext.value[1] || ciphertext.validSeed() == nil {
+				t.Errorf("seeded Ciphertext discarded its seed on read")
+			}
+
+			// The Ciphertext is modified through its Value, as done by the multiparty protocols
+			for _, pol := range ciphertext.Value() {
+				contextQ.Add(pol, pol, pol)
+			}
+
+			params.bfvContext.contextT.Add(coeffs, coeffs, coeffs)
+
+			data, err := ciphertext.MarshalBinary()
+			check(t, err)
+
+			if uint64(len(data)) != 2+ciphertext.value[0].GetDataLen(true)+ciphertext.value[1].GetDataLen(true) {
+				t.Errorf("modified seeded Ciphertext is compressed")
+			}
+
+			ciphertextTest := new(Ciphertext)
+			check(t, ciphertextTest.UnmarshalBinary(data))
+
+			verifyTestVectors(params, params.decryptor, coeffs, ciphertextTest, t)
+		})
+	}
+}
+
+// testShallowCopy uses shallow copies of the encoder, encryptors, decryptor and evaluator concurrently,
+// and is meant to be run with the race detector (go test -race).
+func testShallowCopy(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		t.Run(testString("Concurrent/", parameters), func(t *testing.T) {
+
+			var wg sync.WaitGroup
+
+			for i := 0; i < 4; i++ {
+
+				encoder := params.encoder.ShallowCopy()
+				encryptor := params.encryptorPk.ShallowCopy()
+				if i&1 == 1 {
+					encryptor = params.encryptorSk.ShallowCopy()
+				}
+				decryptor := params.decryptor.ShallowCopy()
+				evaluator := params.evaluator.ShallowCopy()
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					coeffs := params.bfvContext.contextT.NewUniformPoly()
+
+					plaintext := NewPlaintext(parameters)
+					encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+
+					ciphertext := encryptor.EncryptNew(plaintext)
+
+					receiver := NewCiphertext(parameters, 2)
+					evaluator.Mul(ciphertext, ciphertext, receiver)
+					evaluator.Relinearize(receiver, rlk, ciphertext)
+					params.bfvContext.contextT.MulCoeffs(coeffs, coeffs, coeffs)
+
+					if !utils.EqualSliceUint64(coeffs.Coeffs[0], encoder.DecodeUint(decryptor.DecryptNew(ciphertext))) {
+						t.Errorf("concurrent evaluation error")
+					}
+				}()
+			}
+
+			wg.Wait()
+		})
+
+		t.Run(testString("WorkerPool/", parameters), func(t *testing.T) {
+
+			pool := ring.NewWorkerPool(4)
+			defer pool.Close()
+
+			// The shallow copies share the WorkerPool of the evaluator they are copied from
+			evaluator := NewEvaluator(parameters)
+			evaluator.SetWorkerPool(pool)
+
+			var wg sync.WaitGroup
+
+			for i := 0; i < 2; i++ {
+
+				encoder := params.encoder.ShallowCopy()
+				encryptor := params.encryptorPk.ShallowCopy()
+				decryptor := params.decryptor.ShallowCopy()
+				evaluator := evaluator.ShallowCopy()
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					coeffs := params.bfvContext.contextT.NewUniformPoly()
+
+					plaintext := NewPlaintext(parameters)
+					encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+
+					ciphertext := encryptor.EncryptNew(plaintext)
+
+					receiver := NewCiphertext(parameters, 2)
+					evaluator.Mul(ciphertext, ciphertext, receiver)
+					evaluator.Relinearize(receiver, rlk, ciphertext)
+					params.bfvContext.contextT.MulCoeffs(coeffs, coeffs, coeffs)
+
+					if !utils.EqualSliceUint64(coeffs.Coeffs[0], encoder.DecodeUint(decryptor.DecryptNew(ciphertext))) {
+						t.Errorf("worker pool evaluation error")
+					}
+				}()
+			}
+
+			wg.Wait()
+		})
+	}
+}
+
+func genBfvParams(contextParameters *Parameters) (params *bfvParams) {
+
+	params = new(bfvParams)
+
+	params.params = contextParameters.Copy()
+
+	params.bfvContext = newBFVContext(contextParameters)
+
+	params.kgen = NewKeyGenerator(contextParameters)
+
+	params.sk, params.pk = params.kgen.GenKeyPair()
+
+	params.encoder = NewEncoder(contextParameters)
+
+	params.encryptorPk = NewEncryptorFromPk(contextParameters, params.pk)
+	params.encryptorSk = NewEncryptorFromSk(contextParameters, params.sk)
+	params.decryptor = NewDecryptor(contextParameters, params.sk)
+
+	params.evaluator = NewEvaluator(contextParameters)
+
+	return
+
+}
+
+func newTestVectors(params *bfvParams, encryptor Encryptor, t *testing.T) (coeffs *ring.Poly, plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	coeffs = params.bfvContext.contextT.NewUniformPoly()
+
+	plaintext = NewPlaintext(params.params)
+
+	params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+
+	if encryptor != nil {
+		ciphertext = params.encryptorPk.EncryptNew(plaintext)
+	}
+
+	return coeffs, plaintext, ciphertext
+}
+
+func newTestVectorsLvl(params *bfvParams, level uint64, encryptor Encryptor, t *testing.T) (coeffs *ring.Poly, plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	coeffs = params.bfvContext.contextT.NewUniformPoly()
+
+	plaintext = NewPlaintextLvl(params.params, level)
+
+	params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+
+	if encryptor != nil {
+		plaintextMax := NewPlaintext(params.params)
+		params.encoder.EncodeUint(coeffs.Coeffs[0], plaintextMax)
+		ciphertext = encryptor.EncryptNew(plaintextMax)
+
+		if err := params.evaluator.DropLevel(ciphertext, params.params.MaxLevel()-level); err != nil {
+			t.Error(err)
+		}
+	}
+
+	return coeffs, plaintext, ciphertext
+}
+
+func verifyTestVectors(params *bfvParams, decryptor Decryptor, coeffs *ring.Poly, element Operand, t *testing.T) {
+
+	var coeffsTest []uint64
+
+	el := element.Element()
+
+	if el.Degree() == 0 {
+
+		coeffsTest = params.encoder.DecodeUint(el.Plaintext())
+
+	} else {
+
+		coeffsTest = params.encoder.DecodeUint(decryptor.DecryptNew(el.Ciphertext()))
+	}
+
+	if utils.EqualSliceUint64(coeffs.Coeffs[0], coeffsTest) != true {
+		t.Errorf("decryption error")
+	}
+}
+
+func testEncoder(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		t.Run(testString("Encode&Decode/", parameters), func(t *testing.T) {
+
+			values, plaintext, _ := newTestVectors(params, nil, t)
+
+			verifyTestVectors(params, params.decryptor, values, plaintext, t)
+		})
+	}
+}
+
+func testEncryptor(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		t.Run(testString("EncryptFromPk/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+
+		t.Run(testString("EncryptFromPkFast/", parameters), func(t *testing.T) {
+
+			coeffs := params.bfvContext.contextT.NewUniformPoly()
+
+			plaintext := NewPlaintext(params.params)
+
+			params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+
+			verifyTestVectors(params, params.decryptor, coeffs, params.encryptorPk.EncryptFastNew(plaintext), t)
+		})
+
+		t.Run(testString("EncryptFromSk/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorSk, t)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+
+		t.Run(testString("EncryptFromSkFast/", parameters), func(t *testing.T) {
+
+			coeffs := params.bfvContext.contextT.NewUniformPoly()
+
+			plaintext := NewPlaintext(params.params)
+
+			params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+
+			verifyTestVectors(params, params.decryptor, coeffs, params.encryptorSk.EncryptFastNew(plaintext), t)
+		})
+	}
+}
+
+func testEvaluatorAdd(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		t.Run(testString("CtCtInPlace/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Add(ciphertext1, ciphertext2, ciphertext1)
+			params.bfvContext.contextT.Add(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtCtNew/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			ciphertext1 = params.evaluator.AddNew(ciphertext1, ciphertext2)
+			params.bfvContext.contextT.Add(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, plaintext2, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Add(ciphertext1, plaintext2, ciphertext2)
+			params.bfvContext.contextT.Add(values1, values2, values2)
+
+			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+
+			params.evaluator.Add(plaintext2, ciphertext1, ciphertext2)
+
+			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+		})
+	}
+}
+
+func testEvaluatorSub(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		t.Run(testString("CtCtInPlace/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Sub(ciphertext1, ciphertext2, ciphertext1)
+			params.bfvContext.contextT.Sub(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtCtNew/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			ciphertext1 = params.evaluator.SubNew(ciphertext1, ciphertext2)
+			params.bfvContext.contextT.Sub(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, plaintext2, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			valuesWant := params.bfvContext.contextT.NewPoly()
+
+			params.evaluator.Sub(ciphertext1, plaintext2, ciphertext2)
+			params.bfvContext.contextT.Sub(values1, values2, valuesWant)
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext2, t)
+
+			params.evaluator.Sub(plaintext2, ciphertext1, ciphertext2)
+			params.bfvContext.contextT.Sub(values2, values1, valuesWant)
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext2, t)
+		})
+	}
+}
+
+func testEvaluatorMul(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		t.Run(testString("CtCt/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, ciphertext1.Degree()+ciphertext2.Degree())
+			params.evaluator.Mul(ciphertext1, ciphertext2, receiver)
+			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+		})
+
+		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, plaintext2, _ := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Mul(ciphertext1, plaintext2, ciphertext1)
+			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtPlainMul/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, _ := newTestVectors(params, nil, t)
+
+			plaintextMul := NewPlaintextMul(parameters)
+			params.encoder.EncodeUintMul(values2.Coeffs[0], plaintextMul)
+
+			receiver := params.evaluator.MulNew(ciphertext1, plaintextMul)
+			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+
+			// Signed values
+			valuesInt := make([]int64, params.bfvContext.n)
+			for i := range valuesInt {
+				valuesInt[i] = int64(i%7) - 3
+			}
+
+			params.encoder.EncodeIntMul(valuesInt, plaintextMul)
+
+			params.evaluator.Mul(receiver, plaintextMul, receiver)
+
+			for i := range valuesInt {
+				values1.Coeffs[0][i] = ring.BRed(values1.Coeffs[0][i], uint64(int64(parameters.T)+valuesInt[i]), parameters.T, ring.BRedParams(parameters.T))
+			}
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+
+			// A PlaintextMul can only be multiplied
+			if panics(func() { params.evaluator.Mul(receiver, plaintextMul, receiver) }) {
+				t.Errorf("error : PlaintextMul rejected by Mul")
+			}
+
+			for _, op := range []func(){
+				func() { params.evaluator.Add(receiver, plaintextMul, receiver) },
+				func() { params.evaluator.Sub(plaintextMul, receiver, receiver) },
+				func() { params.evaluator.AddNoMod(receiver, plaintextMul, receiver) },
+				func() { params.evaluator.SubNoMod(receiver, plaintextMul, receiver) },
+			} {
+				if !panics(op) {
+					t.Errorf("error : PlaintextMul accepted by an operation other than the multiplication")
+				}
+			}
+		})
+
+		t.Run(testString("Relinearize/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, ciphertext1.Degree()+ciphertext2.Degree())
+			params.evaluator.Mul(ciphertext1, ciphertext2, receiver)
+			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			receiver2 := params.evaluator.RelinearizeNew(receiver, rlk)
+			verifyTestVectors(params, params.decryptor, values1, receiver2, t)
+
+			params.evaluator.Relinearize(receiver, rlk, receiver)
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+		})
+
+		t.Run(testString("MulAndAdd/", parameters), func(t *testing.T) {
+
+			valuesWant := params.bfvContext.contextT.NewPoly()
+
+			// The receiver starts at degree 1 and is resized by the first product of two ciphertexts
+			receiver := NewCiphertext(parameters, 1)
+
+			for k := 0; k < 4; k++ {
+
+				values1, plaintext1, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+				values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+				params.bfvContext.contextT.MulCoeffsAndAdd(values1, values2, valuesWant)
+
+				// Products of a ciphertext with a plaintext and of two ciphertexts
+				if k == 0 {
+					params.evaluator.MulAndAdd(ciphertext2, plaintext1, receiver)
+				} else {
+					params.evaluator.MulAndAdd(ciphertext1, ciphertext2, receiver)
+				}
+			}
+
+			if receiver.Degree() != 2 {
+				t.Errorf("invalid degree: %d", receiver.Degree())
+			}
+
+			params.evaluator.Relinearize(receiver, rlk, receiver)
+			verifyTestVectors(params, params.decryptor, valuesWant, receiver, t)
+		})
+	}
+}
+
+func testNoiseBudget(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		t.Run(testString("Mul/", parameters), func(t *testing.T) {
 
 			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
-			values2, plaintext2, _ := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
 
-			params.evaluator.Mul(ciphertext1, plaintext2, ciphertext1)
+			budgetFresh := params.decryptor.NoiseBudget(ciphertext1)
+
+			if budgetFresh <= 0 || budgetFresh >= float64(params.bfvContext.contextQ.ModulusBigint.BitLen()) {
+				t.Errorf("invalid noise budget of a fresh ciphertext : %.2f bits", budgetFresh)
+			}
+
+			receiver := params.evaluator.RelinearizeNew(params.evaluator.MulNew(ciphertext1, ciphertext2), rlk)
 			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
 
-			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+			budgetMul := params.decryptor.NoiseBudget(receiver)
+
+			if budgetMul <= 0 || budgetMul >= budgetFresh {
+				t.Errorf("invalid noise budget after a multiplication : %.2f bits (fresh : %.2f bits)", budgetMul, budgetFresh)
+			}
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
 		})
 
-		t.Run(testString("Relinearize/", parameters), func(t *testing.T) {
+		t.Run(testString("DebugEvaluator/", parameters), func(t *testing.T) {
 
 			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
 			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
 
-			receiver := NewCiphertext(parameters, ciphertext1.Degree()+ciphertext2.Degree())
-			params.evaluator.Mul(ciphertext1, ciphertext2, receiver)
-			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+			buffer := new(bytes.Buffer)
+			evaluator := NewDebugEvaluator(params.evaluator, params.decryptor, log.New(buffer, "", 0))
 
-			receiver2 := params.evaluator.RelinearizeNew(receiver, rlk)
-			verifyTestVectors(params, params.decryptor, values1, receiver2, t)
+			receiver := evaluator.MulNew(ciphertext1, ciphertext2)
+			evaluator.Relinearize(receiver, rlk, receiver)
+			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
 
-			params.evaluator.Relinearize(receiver, rlk, receiver)
 			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+
+			lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+
+			if len(lines) != 2 || !strings.HasPrefix(lines[0], "MulNew : ") || !strings.HasPrefix(lines[1], "Relinearize : ") {
+				t.Errorf("invalid debug evaluator log : %q", buffer.String())
+			}
 		})
 	}
 }
@@ -627,5 +1311,406 @@ func testRotateCols(t *testing.T) {
 				verifyTestVectors(params, params.decryptor, valuesWant, receiver, t)
 			}
 		})
+
+		t.Run(testString("ForRotations/", parameters), func(t *testing.T) {
+
+			rotations := []int{3, -5, 7, 100, -(int(slots) - 3)}
+
+			rotkeyDirect := params.kgen.GenRotationKeysForRotations(rotations, params.sk)
+
+			// The rotation by slots-3 positions to the right is the rotation by 3 positions to the left
+			if len(rotkeyDirect.keys) != len(rotations)-1 {
+				t.Errorf("invalid number of rotation keys : %d", len(rotkeyDirect.keys))
+			}
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, 1)
+			for _, k := range rotations {
+
+				n := uint64(k) & mask
+
+				// Only the keys of the requested rotations are available, so that each rotation is done with a single key-switching
+				params.evaluator.RotateColumns(ciphertext, n, rotkeyDirect, receiver)
+
+				for i := uint64(0); i < slots; i++ {
+					valuesWant.Coeffs[0][i] = values.Coeffs[0][(i+n)&mask]
+					valuesWant.Coeffs[0][i+slots] = values.Coeffs[0][((i+n)&mask)+slots]
+				}
+
+				verifyTestVectors(params, params.decryptor, valuesWant, receiver, t)
+			}
+		})
+
+		t.Run(testString("Hoisted/", parameters), func(t *testing.T) {
+
+			rotations := []uint64{0, 1, 2, 3, 4, 5, slots - 1}
+
+			rotkeyHoisted := params.kgen.GenRotationKeysForRotations([]int{1, 2, 3, 4, 5, -1}, params.sk)
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receivers := params.evaluator.RotateHoisted(ciphertext, rotations, rotkeyHoisted)
+
+			if len(receivers) != len(rotations) {
+				t.Errorf("invalid number of rotated ciphertexts : %d", len(receivers))
+			}
+
+			for _, n := range rotations {
+
+				for i := uint64(0); i < slots; i++ {
+					valuesWant.Coeffs[0][i] = values.Coeffs[0][(i+n)&mask]
+					valuesWant.Coeffs[0][i+slots] = values.Coeffs[0][((i+n)&mask)+slots]
+				}
+
+				verifyTestVectors(params, params.decryptor, valuesWant, receivers[n], t)
+			}
+		})
+	}
+}
+
+func testMulMatrix(t *testing.T) {
+
+	for i, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		n := params.bfvContext.n
+		rowSize := n >> 1
+		T := parameters.T
+
+		bredParams := ring.BRedParams(T)
+
+		// Applies the matrix given by its diagonals to the values
+		applyDiagonals := func(diags map[uint64][]uint64, values *ring.Poly) (valuesWant *ring.Poly) {
+			valuesWant = params.bfvContext.contextT.NewPoly()
+			for k, diag := range diags {
+				for s := uint64(0); s < n; s++ {
+					valuesWant.Coeffs[0][s] = (valuesWant.Coeffs[0][s] + ring.BRed(diag[s], values.Coeffs[0][rotateSlot(s, k, n)], T, bredParams)) % T
+				}
+			}
+			return
+		}
+
+		t.Run(testString("Diagonals/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			diags := make(map[uint64][]uint64)
+			for _, k := range []uint64{0, 1, 5, 17, rowSize - 1, rowSize, rowSize + 3} {
+				diags[k] = params.bfvContext.contextT.NewUniformPoly().Coeffs[0]
+			}
+
+			// Drops one level of the input, if the parameters have enough levels
+			level := parameters.MaxLevel()
+			if level > 1 {
+				level--
+			}
+
+			matrix := NewPlaintextMatrixFromDiagonals(parameters, params.encoder, diags, level)
+
+			if !matrix.RotatesRows() {
+				t.Errorf("MulMatrix: the matrix should require the rotation of the rows")
+			}
+
+			rotKey := params.kgen.GenRotationKeysForRotations(matrix.Rotations(), params.sk)
+			params.kgen.GenRot(RotationRow, params.sk, 0, rotKey)
+
+			receiver := params.evaluator.MulMatrixNew(ciphertext, matrix, rotKey)
+
+			if receiver.Level() != level || ciphertext.Level() != parameters.MaxLevel() {
+				t.Errorf("MulMatrix: wrong levels")
+			}
+
+			verifyTestVectors(params, params.decryptor, applyDiagonals(diags, values), receiver, t)
+		})
+
+		// The dense matrix is only tested with the smallest parameters
+		if i != 0 {
+			continue
+		}
+
+		t.Run(testString("Matrix/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			diags := make(map[uint64][]uint64)
+			for _, k := range []uint64{2, 3, rowSize + 1} {
+				diags[k] = params.bfvContext.contextT.NewUniformPoly().Coeffs[0]
+			}
+
+			matrixValues := make([][]uint64, n)
+			for s := range matrixValues {
+				matrixValues[s] = make([]uint64, n)
+			}
+			for k, diag := range diags {
+				for s := uint64(0); s < n; s++ {
+					matrixValues[s][rotateSlot(s, k, n)] = diag[s]
+				}
+			}
+
+			matrix := NewPlaintextMatrix(parameters, params.encoder, matrixValues, parameters.MaxLevel())
+
+			rotKey := params.kgen.GenRotationKeysForRotations(matrix.Rotations(), params.sk)
+			params.kgen.GenRot(RotationRow, params.sk, 0, rotKey)
+
+			receiver := NewCiphertext(parameters, 1)
+
+			params.evaluator.MulMatrix(ciphertext, matrix, rotKey, receiver)
+
+			verifyTestVectors(params, params.decryptor, applyDiagonals(diags, values), receiver, t)
+		})
+	}
+}
+
+func testDotProduct(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+		rotKey := params.kgen.GenRotationKeysPow2(params.sk)
+
+		T := parameters.T
+		bredParams := ring.BRedParams(T)
+
+		// Returns the dot product of the values in every slot
+		dotProduct := func(values0, values1 *ring.Poly) (valuesWant *ring.Poly) {
+			var sum uint64
+			for s := range values0.Coeffs[0] {
+				sum = (sum + ring.BRed(values0.Coeffs[0][s], values1.Coeffs[0][s], T, bredParams)) % T
+			}
+			valuesWant = params.bfvContext.contextT.NewPoly()
+			for s := range valuesWant.Coeffs[0] {
+				valuesWant.Coeffs[0][s] = sum
+			}
+			return
+		}
+
+		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+
+			values0, _, ciphertext0 := newTestVectors(params, params.encryptorPk, t)
+			values1, plaintext1, _ := newTestVectors(params, nil, t)
+
+			receiver := params.evaluator.DotProductNew(ciphertext0, plaintext1, nil, rotKey)
+
+			verifyTestVectors(params, params.decryptor, dotProduct(values0, values1), receiver, t)
+		})
+
+		t.Run(testString("CtCt/", parameters), func(t *testing.T) {
+
+			values0, _, ciphertext0 := newTestVectors(params, params.encryptorPk, t)
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, 1)
+
+			params.evaluator.DotProduct(ciphertext0, ciphertext1, rlk, rotKey, receiver)
+
+			verifyTestVectors(params, params.decryptor, dotProduct(values0, values1), receiver, t)
+		})
+	}
+}
+
+func testEvaluatePoly(t *testing.T) {
+
+	// Returns the evaluation of the polynomial on each value with the Horner scheme
+	evaluatePoly := func(coeffs []uint64, values []uint64, T uint64) (res []uint64) {
+		bredParams := ring.BRedParams(T)
+		res = make([]uint64, len(values))
+		for i, x := range values {
+			for j := len(coeffs) - 1; j >= 0; j-- {
+				res[i] = ring.CRed(ring.BRed(res[i], x, T, bredParams)+coeffs[j]%T, T)
+			}
+		}
+		return
+	}
+
+	t.Run("Interpolate/", func(t *testing.T) {
+
+		for _, T := range []uint64{17, 257} {
+
+			function := func(x uint64) uint64 { return (x*x*x + 7*(x%5)) % T }
+
+			coeffs := Interpolate(function, T)
+
+			if uint64(len(coeffs)) > T {
+				t.Errorf("invalid degree: %d", len(coeffs)-1)
+			}
+
+			values := make([]uint64, T)
+			for i := range values {
+				values[i] = uint64(i)
+			}
+
+			for x, y := range evaluatePoly(coeffs, values, T) {
+				if y != function(uint64(x)) {
+					t.Errorf("invalid interpolation at %d: %d != %d", x, y, function(uint64(x)))
+				}
+			}
+		}
+
+		// A polynomial function is interpolated by the polynomial of smallest degree
+		if coeffs := Interpolate(func(x uint64) uint64 { return (3*x*x + 2) % 257 }, 257); len(coeffs) != 3 || coeffs[0] != 2 || coeffs[1] != 0 || coeffs[2] != 3 {
+			t.Errorf("invalid interpolation of a polynomial: %v", coeffs)
+		}
+	})
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		t.Run(testString("Poly/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			// The polynomial of degree 7 has a multiplicative depth of 3
+			if params.decryptor.NoiseBudget(ciphertext) < 128 {
+				t.Skip("not enough noise budget")
+			}
+
+			coeffs := make([]uint64, 8)
+			for i := range coeffs {
+				coeffs[i] = rand.Uint64() % parameters.T
+			}
+
+			// The coefficients of the even powers are zero, except the constant one
+			coeffs[2], coeffs[4], coeffs[6] = 0, 0, 0
+
+			receiver := params.evaluator.EvaluatePoly(ciphertext, coeffs, rlk)
+
+			if receiver.Degree() != 1 {
+				t.Errorf("invalid output degree: %d", receiver.Degree())
+			}
+
+			copy(values.Coeffs[0], evaluatePoly(coeffs, values.Coeffs[0], parameters.T))
+
+			verifyTestVectors(params, params.decryptor, values, receiver, t)
+		})
+	}
+}
+
+func testModSwitch(t *testing.T) {
+
+	for _, parameters := range testParams.bfvParameters {
+
+		params := genBfvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		rotkey := NewRotationKeys()
+		params.kgen.GenRot(RotationRow, params.sk, 0, rotkey)
+		params.kgen.GenRot(RotationLeft, params.sk, 1, rotkey)
+
+		level := parameters.MaxLevel() - 1
+
+		t.Run(testString("ModSwitch/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receiver, err := params.evaluator.ModSwitchNew(ciphertext)
+			check(t, err)
+
+			if receiver.Level() != ciphertext.Level()-1 {
+				t.Errorf("invalid level after ModSwitch")
+			}
+
+			verifyTestVectors(params, params.decryptor, values, receiver, t)
+
+			check(t, params.evaluator.ModSwitch(ciphertext, ciphertext))
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+
+		t.Run(testString("DropLevel/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receiver, err := params.evaluator.DropLevelNew(ciphertext, parameters.MaxLevel())
+			check(t, err)
+
+			if receiver.Level() != 0 {
+				t.Errorf("invalid level after DropLevel")
+			}
+
+			verifyTestVectors(params, params.decryptor, values, receiver, t)
+
+			if err = params.evaluator.DropLevel(receiver, 1); err == nil {
+				t.Errorf("DropLevel below level 0 should return an error")
+			}
+		})
+
+		t.Run(testString("AddSub/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectorsLvl(params, level, params.encryptorPk, t)
+			values2, plaintext2, ciphertext2 := newTestVectorsLvl(params, level, params.encryptorPk, t)
+
+			receiver := params.evaluator.AddNew(ciphertext1, ciphertext2)
+			params.bfvContext.contextT.Add(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+
+			params.evaluator.Sub(receiver, plaintext2, receiver)
+			params.bfvContext.contextT.Sub(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+		})
+
+		t.Run(testString("Mul/", parameters), func(t *testing.T) {
+
+			if parameters.MaxLevel() < 2 {
+				t.Skip("not enough levels")
+			}
+
+			values1, _, ciphertext1 := newTestVectorsLvl(params, level, params.encryptorPk, t)
+			values2, plaintext2, ciphertext2 := newTestVectorsLvl(params, level, params.encryptorPk, t)
+
+			receiver := params.evaluator.MulNew(ciphertext1, ciphertext2)
+			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			if receiver.Level() != level {
+				t.Errorf("invalid level after Mul")
+			}
+
+			params.evaluator.Relinearize(receiver, rlk, receiver)
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+
+			params.evaluator.Mul(receiver, plaintext2, receiver)
+			params.bfvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+		})
+
+		t.Run(testString("RotateRows/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectorsLvl(params, level, params.encryptorPk, t)
+
+			receiver := params.evaluator.RotateRowsNew(ciphertext, rotkey)
+
+			values.Coeffs[0] = append(values.Coeffs[0][params.bfvContext.n>>1:], values.Coeffs[0][:params.bfvContext.n>>1]...)
+
+			verifyTestVectors(params, params.decryptor, values, receiver, t)
+		})
+
+		t.Run(testString("RotateCols/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectorsLvl(params, level, params.encryptorPk, t)
+
+			valuesWant := params.bfvContext.contextT.NewPoly()
+			mask := (params.bfvContext.n >> 1) - 1
+			slots := params.bfvContext.n >> 1
+
+			params.evaluator.RotateColumns(ciphertext, 1, rotkey, ciphertext)
+
+			for i := uint64(0); i < slots; i++ {
+				valuesWant.Coeffs[0][i] = values.Coeffs[0][(i+1)&mask]
+				valuesWant.Coeffs[0][i+slots] = values.Coeffs[0][((i+1)&mask)+slots]
+			}
+
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext, t)
+		})
 	}
 }
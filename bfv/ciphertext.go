@@ -1,26 +1,68 @@
 package bfv
 
+import (
+	"github.com/ldsec/lattigo/ring"
+)
+
 // Ciphertext is a *ring.Poly array representing a polynomial of degree > 0 with coefficients in R_Q.
 type Ciphertext struct {
 	*bfvElement
+
+	// seed is set for the ciphertexts encrypted by a seeded Encryptor, and is used
+	// in place of value[1] when marshaling the ciphertext. It is discarded by the
+	// evaluator when the ciphertext is used as a receiver, and ignored when marshaling
+	// if value[1] was modified through Value.
+	seed *ring.PolySeed
 }
 
-// NewCiphertext creates a new ciphertext parameterized by degree, level and scale.
+// NewCiphertext creates a new ciphertext parameterized by degree, at the maximum level.
 func NewCiphertext(params *Parameters, degree uint64) (ciphertext *Ciphertext) {
 
 	if !params.isValid {
 		panic("cannot NewCiphertext: params not valid (check if they were generated properly)")
 	}
 
-	return &Ciphertext{newBfvElement(params, degree)}
+	return &Ciphertext{bfvElement: newBfvElement(params, degree, params.MaxLevel())}
+}
+
+// NewCiphertextLvl creates a new ciphertext parameterized by degree and level.
+func NewCiphertextLvl(params *Parameters, degree, level uint64) (ciphertext *Ciphertext) {
+
+	if !params.isValid {
+		panic("cannot NewCiphertextLvl: params not valid (check if they were generated properly)")
+	}
+
+	if level > params.MaxLevel() {
+		panic("cannot NewCiphertextLvl: level is larger than the maximum level of the parameters")
+	}
+
+	return &Ciphertext{bfvElement: newBfvElement(params, degree, level)}
 }
 
-// NewCiphertextRandom generates a new uniformly distributed ciphertext of degree, level and scale.
+// NewCiphertextRandom generates a new uniformly distributed ciphertext of degree, at the maximum level.
 func NewCiphertextRandom(params *Parameters, degree uint64) (ciphertext *Ciphertext) {
 
 	if !params.isValid {
 		panic("cannot NewCiphertextRandom: params not valid (check if they were generated properly)")
 	}
 
-	return &Ciphertext{newBfvElementRandom(params, degree)}
+	return &Ciphertext{bfvElement: newBfvElementRandom(params, degree, params.MaxLevel())}
+}
+
+// SetValue assigns the input slice of polynomials to the target Ciphertext value and discards its seed.
+func (ciphertext *Ciphertext) SetValue(value []*ring.Poly) {
+	ciphertext.seed = nil
+	ciphertext.bfvElement.SetValue(value)
+}
+
+// Resize resizes the target Ciphertext degree to the degree given as input (see bfvElement.Resize) and discards its seed.
+func (ciphertext *Ciphertext) Resize(params *Parameters, degree uint64) {
+	ciphertext.seed = nil
+	ciphertext.bfvElement.Resize(params, degree)
+}
+
+// Copy copies the value and parameters of the input on the target Ciphertext and discards its seed.
+func (ciphertext *Ciphertext) Copy(ctxCopy *bfvElement) {
+	ciphertext.seed = nil
+	ciphertext.bfvElement.Copy(ctxCopy)
 }
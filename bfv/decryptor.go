@@ -2,6 +2,8 @@ package bfv
 
 import (
 	"github.com/ldsec/lattigo/ring"
+	"math"
+	"math/big"
 )
 
 // Decryptor is an interface for decryptors
@@ -13,6 +15,14 @@ type Decryptor interface {
 	// Decrypt decrypts the input ciphertext and returns the result on the
 	// provided receiver plaintext.
 	Decrypt(ciphertext *Ciphertext, plaintext *Plaintext)
+
+	// NoiseBudget returns the invariant noise budget of the input ciphertext in bits. The
+	// ciphertext decrypts correctly as long as its noise budget is positive.
+	NoiseBudget(ciphertext *Ciphertext) float64
+
+	// ShallowCopy creates a shallow copy of the Decryptor, which shares the read-only
+	// precomputations and the secret-key of the original but has its own memory pool.
+	ShallowCopy() Decryptor
 }
 
 // decryptor is a structure used to decrypt ciphertexts. It stores the secret-key.
@@ -44,32 +54,103 @@ func NewDecryptor(params *Parameters, sk *SecretKey) Decryptor {
 	}
 }
 
+func (decryptor *decryptor) ShallowCopy() Decryptor {
+	decryptorCopy := *decryptor
+	decryptorCopy.polypool = decryptor.bfvContext.contextQ.NewPoly()
+	return &decryptorCopy
+}
+
 func (decryptor *decryptor) DecryptNew(ciphertext *Ciphertext) *Plaintext {
-	plaintext := NewPlaintext(decryptor.params)
+	plaintext := NewPlaintextLvl(decryptor.params, ciphertext.Level())
 
 	decryptor.Decrypt(ciphertext, plaintext)
 
 	return plaintext
 }
 
+// Decrypt decrypts the ciphertext and returns the result on the provided receiver plaintext,
+// at the level of the ciphertext.
 func (decryptor *decryptor) Decrypt(ciphertext *Ciphertext, plaintext *Plaintext) {
 	ringContext := decryptor.bfvContext.contextQ
 
-	ringContext.NTT(ciphertext.value[ciphertext.Degree()], plaintext.value)
+	level := ciphertext.Level()
+
+	if plaintext.Level() < level {
+		panic("cannot Decrypt: receiver plaintext level is smaller than the ciphertext level")
+	}
+
+	plaintext.setLevel(level)
+
+	ringContext.NTTLvl(level, ciphertext.value[ciphertext.Degree()], plaintext.value)
 
 	for i := uint64(ciphertext.Degree()); i > 0; i-- {
-		ringContext.MulCoeffsMontgomery(plaintext.value, decryptor.sk.sk, plaintext.value)
-		ringContext.NTT(ciphertext.value[i-1], decryptor.polypool)
-		ringContext.Add(plaintext.value, decryptor.polypool, plaintext.value)
+		ringContext.MulCoeffsMontgomeryLvl(level, plaintext.value, decryptor.sk.sk, plaintext.value)
+		ringContext.NTTLvl(level, ciphertext.value[i-1], decryptor.polypool)
+		ringContext.AddLvl(level, plaintext.value, decryptor.polypool, plaintext.value)
 
 		if i&7 == 7 {
-			ringContext.Reduce(plaintext.value, plaintext.value)
+			ringContext.ReduceLvl(level, plaintext.value, plaintext.value)
 		}
 	}
 
 	if (ciphertext.Degree())&7 != 7 {
-		ringContext.Reduce(plaintext.value, plaintext.value)
+		ringContext.ReduceLvl(level, plaintext.value, plaintext.value)
+	}
+
+	ringContext.InvNTTLvl(level, plaintext.value, plaintext.value)
+}
+
+// NoiseBudget returns the invariant noise budget of the ciphertext in bits, at the level of the ciphertext.
+// Given the decryption c(s) = c0 + c1*s + ... mod Q = Q/t * m + e, the invariant noise is v = t * c(s) mod Q,
+// which is reconstructed with the CRT, and the noise budget is log2(Q/2) - log2(||v||). Each bit lost
+// doubles the noise, and the decryption fails once the budget reaches zero.
+func (decryptor *decryptor) NoiseBudget(ciphertext *Ciphertext) float64 {
+
+	ringContext := decryptor.bfvContext.contextQ
+
+	level := ciphertext.Level()
+
+	plaintext := NewPlaintextLvl(decryptor.params, level)
+
+	decryptor.Decrypt(ciphertext, plaintext)
+
+	ringContext.MulScalarLvl(level, plaintext.value, decryptor.params.T, plaintext.value)
+
+	coeffsBigint := make([]*big.Int, ringContext.N)
+	ringContext.PolyToBigint(plaintext.value, coeffsBigint)
+
+	Q := ring.NewUint(1)
+	for _, qi := range ringContext.Modulus[:level+1] {
+		Q.Mul(Q, ring.NewUint(qi))
+	}
+
+	QHalf := new(big.Int).Rsh(Q, 1)
+
+	// Infinity norm of the invariant noise, centered modulo Q
+	norm := new(big.Int)
+	for _, coeff := range coeffsBigint {
+
+		coeff.Mod(coeff, Q)
+		if coeff.Cmp(QHalf) > 0 {
+			coeff.Sub(Q, coeff)
+		}
+
+		if coeff.Cmp(norm) > 0 {
+			norm.Set(coeff)
+		}
 	}
 
-	ringContext.InvNTT(plaintext.value, plaintext.value)
+	if norm.Sign() == 0 {
+		return log2Bigint(QHalf)
+	}
+
+	return math.Max(log2Bigint(QHalf)-log2Bigint(norm), 0)
+}
+
+// log2Bigint returns log2(x) for a positive x.
+func log2Bigint(x *big.Int) float64 {
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(x).MantExp(mant)
+	mantFloat, _ := mant.Float64()
+	return math.Log2(mantFloat) + float64(exp)
 }
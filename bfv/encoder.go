@@ -10,8 +10,11 @@ import (
 type Encoder interface {
 	EncodeUint(coeffs []uint64, plaintext *Plaintext)
 	EncodeInt(coeffs []int64, plaintext *Plaintext)
+	EncodeUintMul(coeffs []uint64, plaintext *PlaintextMul)
+	EncodeIntMul(coeffs []int64, plaintext *PlaintextMul)
 	DecodeUint(plaintext *Plaintext) (coeffs []uint64)
 	DecodeInt(plaintext *Plaintext) (coeffs []int64)
+	ShallowCopy() Encoder
 }
 
 // Encoder is a structure that stores the parameters to encode values on a plaintext in a SIMD (Single-Instruction Multiple-Data) fashion.
@@ -19,9 +22,9 @@ type encoder struct {
 	params       *Parameters
 	bfvContext   *bfvContext
 	indexMatrix  []uint64
-	simplescaler *ring.SimpleScaler
+	simplescaler []*ring.SimpleScaler
 	polypool     *ring.Poly
-	deltaMont    []uint64
+	deltaMont    [][]uint64
 }
 
 // NewEncoder creates a new encoder from the provided parameters.
@@ -57,17 +60,34 @@ func NewEncoder(params *Parameters) Encoder {
 		pos &= (m - 1)
 	}
 
+	// Scaling factors and scalers for each level of the moduli chain
+	deltaMont := make([][]uint64, bfvContext.levels)
+	simplescaler := make([]*ring.SimpleScaler, bfvContext.levels)
+	for level := uint64(0); level < bfvContext.levels; level++ {
+		contextQ := bfvContext.contextQLvl(level)
+		deltaMont[level] = GenLiftParams(contextQ, params.T)
+		simplescaler[level] = ring.NewSimpleScaler(params.T, contextQ)
+	}
+
 	return &encoder{
 		params:       params.Copy(),
 		bfvContext:   bfvContext,
 		indexMatrix:  indexMatrix,
-		deltaMont:    GenLiftParams(bfvContext.contextQ, params.T),
-		simplescaler: ring.NewSimpleScaler(params.T, bfvContext.contextQ),
+		deltaMont:    deltaMont,
+		simplescaler: simplescaler,
 		polypool:     bfvContext.contextT.NewPoly(),
 	}
 }
 
-// EncodeUint encodes an uint64 slice of size at most N on a plaintext.
+// ShallowCopy creates a shallow copy of the target encoder, which shares the read-only precomputations of the
+// original but has its own memory pool. The copy and the original can then be used concurrently.
+func (encoder *encoder) ShallowCopy() Encoder {
+	encoderCopy := *encoder
+	encoderCopy.polypool = encoder.bfvContext.contextT.NewPoly()
+	return &encoderCopy
+}
+
+// EncodeUint encodes an uint64 slice of size at most N on a plaintext, at the level of the plaintext.
 func (encoder *encoder) EncodeUint(coeffs []uint64, plaintext *Plaintext) {
 
 	if len(coeffs) > len(encoder.indexMatrix) {
@@ -90,7 +110,7 @@ func (encoder *encoder) EncodeUint(coeffs []uint64, plaintext *Plaintext) {
 
 }
 
-// EncodeInt encodes an int64 slice of size at most N on a plaintext. It also encodes the sign of the given integer (as its inverse modulo the plaintext modulus).
+// EncodeInt encodes an int64 slice of size at most N on a plaintext, at the level of the plaintext. It also encodes the sign of the given integer (as its inverse modulo the plaintext modulus).
 // The sign will correctly decode as long as the absolute value of the coefficient does not exceed half of the plaintext modulus.
 func (encoder *encoder) EncodeInt(coeffs []int64, plaintext *Plaintext) {
 
@@ -124,10 +144,12 @@ func (encoder *encoder) encodePlaintext(p *Plaintext) {
 
 	ringContext := encoder.bfvContext.contextQ
 
-	for i := len(ringContext.Modulus) - 1; i >= 0; i-- {
+	level := p.Level()
+
+	for i := int(level); i >= 0; i-- {
 		tmp1 := p.value.Coeffs[i]
 		tmp2 := p.value.Coeffs[0]
-		deltaMont := encoder.deltaMont[i]
+		deltaMont := encoder.deltaMont[level][i]
 		qi := ringContext.Modulus[i]
 		bredParams := ringContext.GetMredParams()[i]
 		for j := uint64(0); j < ringContext.N; j++ {
@@ -136,10 +158,96 @@ func (encoder *encoder) encodePlaintext(p *Plaintext) {
 	}
 }
 
+// EncodeUintMul encodes an uint64 slice of size at most N on a PlaintextMul, at the level of the plaintext, such that
+// it can be multiplied with ciphertexts without further conversion.
+func (encoder *encoder) EncodeUintMul(coeffs []uint64, plaintext *PlaintextMul) {
+
+	if len(coeffs) > len(encoder.indexMatrix) {
+		panic("cannot EncodeUintMul: invalid input to encode (number of coefficients must be smaller or equal to the context)")
+	}
+
+	if len(plaintext.value.Coeffs[0]) != len(encoder.indexMatrix) {
+		panic("cannot EncodeUintMul: invalid plaintext to receive encoding (number of coefficients does not match the context of the encoder)")
+	}
+
+	for i := 0; i < len(coeffs); i++ {
+		plaintext.value.Coeffs[0][encoder.indexMatrix[i]] = coeffs[i] % encoder.params.T
+	}
+
+	for i := len(coeffs); i < len(encoder.indexMatrix); i++ {
+		plaintext.value.Coeffs[0][encoder.indexMatrix[i]] = 0
+	}
+
+	encoder.encodePlaintextMul(plaintext)
+}
+
+// EncodeIntMul encodes an int64 slice of size at most N on a PlaintextMul, at the level of the plaintext, such that
+// it can be multiplied with ciphertexts without further conversion. It also encodes the sign of the given integer
+// (as its inverse modulo the plaintext modulus).
+func (encoder *encoder) EncodeIntMul(coeffs []int64, plaintext *PlaintextMul) {
+
+	if len(coeffs) > len(encoder.indexMatrix) {
+		panic("cannot EncodeIntMul: invalid input to encode (number of coefficients must be smaller or equal to the context)")
+	}
+
+	if len(plaintext.value.Coeffs[0]) != len(encoder.indexMatrix) {
+		panic("cannot EncodeIntMul: invalid plaintext to receive encoding (number of coefficients does not match the context of the encoder)")
+	}
+
+	modulus := int64(encoder.params.T)
+
+	for i := 0; i < len(coeffs); i++ {
+
+		value := coeffs[i] % modulus
+
+		if value < 0 {
+			plaintext.value.Coeffs[0][encoder.indexMatrix[i]] = uint64(modulus + value)
+		} else {
+			plaintext.value.Coeffs[0][encoder.indexMatrix[i]] = uint64(value)
+		}
+	}
+
+	for i := len(coeffs); i < len(encoder.indexMatrix); i++ {
+		plaintext.value.Coeffs[0][encoder.indexMatrix[i]] = 0
+	}
+
+	encoder.encodePlaintextMul(plaintext)
+}
+
+// encodePlaintextMul lifts the message, centered modulo T, to each modulus of the level of the plaintext, and switches
+// it to the NTT domain and to the Montgomery form.
+func (encoder *encoder) encodePlaintextMul(p *PlaintextMul) {
+
+	encoder.bfvContext.contextT.InvNTT(p.value, p.value)
+
+	ringContext := encoder.bfvContext.contextQ
+
+	level := p.Level()
+
+	T := encoder.params.T
+	tHalf := T >> 1
+
+	for i := int(level); i >= 0; i-- {
+		tmp1 := p.value.Coeffs[i]
+		tmp2 := p.value.Coeffs[0]
+		qi := ringContext.Modulus[i]
+		for j := uint64(0); j < ringContext.N; j++ {
+			if tmp2[j] > tHalf {
+				tmp1[j] = qi - (T - tmp2[j])
+			} else {
+				tmp1[j] = tmp2[j]
+			}
+		}
+	}
+
+	ringContext.NTTLvl(level, p.value, p.value)
+	ringContext.MFormLvl(level, p.value, p.value)
+}
+
 // DecodeUint decodes a batched plaintext and returns the coefficients in a uint64 slice.
 func (encoder *encoder) DecodeUint(plaintext *Plaintext) (coeffs []uint64) {
 
-	encoder.simplescaler.Scale(plaintext.value, encoder.polypool)
+	encoder.simplescaler[plaintext.Level()].Scale(plaintext.value, encoder.polypool)
 
 	encoder.bfvContext.contextT.NTT(encoder.polypool, encoder.polypool)
 
@@ -159,7 +267,7 @@ func (encoder *encoder) DecodeInt(plaintext *Plaintext) (coeffs []int64) {
 
 	var value int64
 
-	encoder.simplescaler.Scale(plaintext.value, encoder.polypool)
+	encoder.simplescaler[plaintext.Level()].Scale(plaintext.value, encoder.polypool)
 
 	encoder.bfvContext.contextT.NTT(encoder.polypool, encoder.polypool)
 
@@ -52,6 +52,10 @@ type Encryptor interface {
 	// zero in Q, using the provided polynomial as the uniform polynomial, and
 	// then adding the plaintext.
 	EncryptFromCRPFast(plaintext *Plaintext, ciphertetx *Ciphertext, crp *ring.Poly)
+
+	// ShallowCopy creates a shallow copy of the Encryptor, which shares the read-only
+	// precomputations and the key of the original but has its own memory pool.
+	ShallowCopy() Encryptor
 }
 
 // encryptor is a structure that holds the parameters needed to encrypt plaintexts.
@@ -70,7 +74,8 @@ type pkEncryptor struct {
 
 type skEncryptor struct {
 	encryptor
-	sk *SecretKey
+	sk     *SecretKey
+	seeded bool
 }
 
 // NewEncryptorFromPk creates a new Encryptor with the provided public-key.
@@ -94,7 +99,22 @@ func NewEncryptorFromSk(params *Parameters, sk *SecretKey) Encryptor {
 		panic("error: sk ring degree doesn't match bfvcontext ring degree")
 	}
 
-	return &skEncryptor{enc, sk}
+	return &skEncryptor{encryptor: enc, sk: sk}
+}
+
+// NewEncryptorFromSkSeeded creates a new Encryptor with the provided secret-key, generating seeded ciphertexts :
+// the uniform polynomial a of each ciphertext [-a*s + m + e, a] is generated from a fresh random seed, which is
+// stored in the ciphertext. When marshaled, these ciphertexts only store the seed in place of a, which is regenerated
+// from it on unmarshal, halving their size. The seeded ciphertexts are always encrypted modulo Q (as with EncryptFast).
+// The encryptions from a CRP are not seeded.
+func NewEncryptorFromSkSeeded(params *Parameters, sk *SecretKey) Encryptor {
+	enc := newEncryptor(params)
+
+	if uint64(sk.sk.GetDegree()) != uint64(1<<params.LogN) {
+		panic("error: sk ring degree doesn't match bfvcontext ring degree")
+	}
+
+	return &skEncryptor{encryptor: enc, sk: sk, seeded: true}
 }
 
 func newEncryptor(params *Parameters) encryptor {
@@ -118,6 +138,29 @@ func newEncryptor(params *Parameters) encryptor {
 	}
 }
 
+// shallowCopy returns a copy of the target encryptor sharing its read-only precomputations, with a new memory pool.
+func (encryptor *encryptor) shallowCopy() encryptor {
+
+	qp := encryptor.bfvContext.contextQP
+
+	encryptorCopy := *encryptor
+	encryptorCopy.polypool = [3]*ring.Poly{qp.NewPoly(), qp.NewPoly(), qp.NewPoly()}
+
+	if encryptor.baseconverter != nil {
+		encryptorCopy.baseconverter = encryptor.baseconverter.ShallowCopy()
+	}
+
+	return encryptorCopy
+}
+
+func (encryptor *pkEncryptor) ShallowCopy() Encryptor {
+	return &pkEncryptor{encryptor.encryptor.shallowCopy(), encryptor.pk}
+}
+
+func (encryptor *skEncryptor) ShallowCopy() Encryptor {
+	return &skEncryptor{encryptor: encryptor.encryptor.shallowCopy(), sk: encryptor.sk, seeded: encryptor.seeded}
+}
+
 func (encryptor *pkEncryptor) EncryptNew(plaintext *Plaintext) *Ciphertext {
 
 	if encryptor.baseconverter == nil {
@@ -168,6 +211,13 @@ func (encryptor *pkEncryptor) EncryptFromCRPFastNew(plaintext *Plaintext, crp *r
 
 func (encryptor *pkEncryptor) encrypt(plaintext *Plaintext, ciphertext *Ciphertext, fast bool) {
 
+	if plaintext.Level() != encryptor.params.MaxLevel() {
+		panic("cannot Encrypt: plaintext must be at the maximum level (use DropLevel on the ciphertext instead)")
+	}
+
+	ciphertext.setLevel(plaintext.Level())
+	ciphertext.seed = nil
+
 	var ringContext *ring.Context
 
 	if fast {
@@ -284,6 +334,11 @@ func (encryptor *skEncryptor) EncryptFromCRPFast(plaintext *Plaintext, ciphertex
 }
 
 func (encryptor *skEncryptor) encryptSample(plaintext *Plaintext, ciphertext *Ciphertext, fast bool) {
+	if encryptor.seeded {
+		encryptor.encryptSeeded(plaintext, ciphertext)
+		return
+	}
+
 	if fast {
 		encryptor.bfvContext.contextQ.UniformPoly(encryptor.polypool[1])
 	} else {
@@ -305,6 +360,13 @@ func (encryptor *skEncryptor) encryptFromCRP(plaintext *Plaintext, ciphertext *C
 
 func (encryptor *skEncryptor) encrypt(plaintext *Plaintext, ciphertext *Ciphertext, crp *ring.Poly, fast bool) {
 
+	if plaintext.Level() != encryptor.params.MaxLevel() {
+		panic("cannot Encrypt: plaintext must be at the maximum level (use DropLevel on the ciphertext instead)")
+	}
+
+	ciphertext.setLevel(plaintext.Level())
+	ciphertext.seed = nil
+
 	var ringContext *ring.Context
 
 	if fast {
@@ -343,3 +405,33 @@ func (encryptor *skEncryptor) encrypt(plaintext *Plaintext, ciphertext *Cipherte
 	// ct = [-a*s + m + e , a]
 	ringContext.Add(ciphertext.value[0], plaintext.value, ciphertext.value[0])
 }
+
+// encryptSeeded encrypts the plaintext modulo Q, generating the uniform polynomial a of the ciphertext from a
+// fresh random seed, which is stored in the ciphertext.
+func (encryptor *skEncryptor) encryptSeeded(plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	if plaintext.Level() != encryptor.params.MaxLevel() {
+		panic("cannot Encrypt: plaintext must be at the maximum level (use DropLevel on the ciphertext instead)")
+	}
+
+	ciphertext.setLevel(plaintext.Level())
+
+	ringContext := encryptor.bfvContext.contextQ
+
+	seed := ring.NewPolySeed(ringContext.Modulus)
+
+	// ct = [-a*s + e, a], with a uniform in R_Q and generated from the seed
+	seed.CRPGenerator(ringContext.N).Clock(ciphertext.value[1])
+
+	ringContext.NTT(ciphertext.value[1], encryptor.polypool[0])
+	ringContext.MulCoeffsMontgomery(encryptor.polypool[0], encryptor.sk.sk, ciphertext.value[0])
+	ringContext.Neg(ciphertext.value[0], ciphertext.value[0])
+	ringContext.InvNTT(ciphertext.value[0], ciphertext.value[0])
+
+	ringContext.SampleGaussianAndAdd(ciphertext.value[0], encryptor.params.Sigma, uint64(6*encryptor.params.Sigma))
+
+	// ct = [-a*s + m + e , a]
+	ringContext.Add(ciphertext.value[0], plaintext.value, ciphertext.value[0])
+
+	ciphertext.seed = seed
+}
@@ -1,8 +1,10 @@
 package bfv
 
 import (
+	"errors"
 	"github.com/ldsec/lattigo/ring"
 	"github.com/ldsec/lattigo/utils"
+	"math"
 	"math/big"
 )
 
@@ -24,6 +26,7 @@ type Evaluator interface {
 	MulScalarNew(op Operand, scalar uint64) (ctOut *Ciphertext)
 	Mul(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext)
 	MulNew(op0 *Ciphertext, op1 Operand) (ctOut *Ciphertext)
+	MulAndAdd(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext)
 	Relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext)
 	RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (ctOut *Ciphertext)
 	SwitchKeys(ct0 *Ciphertext, switchKey *SwitchingKey, ctOut *Ciphertext)
@@ -32,7 +35,19 @@ type Evaluator interface {
 	RotateColumns(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext)
 	RotateRows(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext)
 	RotateRowsNew(ct0 *Ciphertext, evakey *RotationKeys) (ctOut *Ciphertext)
+	RotateHoisted(ct0 *Ciphertext, rotations []uint64, rotkeys *RotationKeys) (cOut map[uint64]*Ciphertext)
 	InnerSum(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext)
+	MulMatrix(ct0 *Ciphertext, matrix *PlaintextMatrix, rotKeys *RotationKeys, ctOut *Ciphertext)
+	MulMatrixNew(ct0 *Ciphertext, matrix *PlaintextMatrix, rotKeys *RotationKeys) (ctOut *Ciphertext)
+	DotProduct(ct0 *Ciphertext, op1 Operand, evakey *EvaluationKey, rotKeys *RotationKeys, ctOut *Ciphertext)
+	DotProductNew(ct0 *Ciphertext, op1 Operand, evakey *EvaluationKey, rotKeys *RotationKeys) (ctOut *Ciphertext)
+	EvaluatePoly(ct0 *Ciphertext, coeffs []uint64, evakey *EvaluationKey) (ctOut *Ciphertext)
+	ModSwitch(ct0 *Ciphertext, ctOut *Ciphertext) (err error)
+	ModSwitchNew(ct0 *Ciphertext) (ctOut *Ciphertext, err error)
+	DropLevel(ct0 *Ciphertext, levels uint64) (err error)
+	DropLevelNew(ct0 *Ciphertext, levels uint64) (ctOut *Ciphertext, err error)
+	SetWorkerPool(pool *ring.WorkerPool)
+	ShallowCopy() Evaluator
 }
 
 // evaluator is a struct that holds the necessary elements to perform the homomorphic operations between ciphertexts and/or plaintexts.
@@ -42,7 +57,9 @@ type evaluator struct {
 
 	bfvContext *bfvContext
 
-	baseconverterQ1Q2 *ring.FastBasisExtender
+	// One context and one basis extender from Q to QMul for each level
+	contextQLvl       []*ring.Context
+	baseconverterQ1Q2 []*ring.FastBasisExtender
 
 	baseconverterQ1P *ring.FastBasisExtender
 	decomposer       *ring.Decomposer
@@ -52,8 +69,11 @@ type evaluator struct {
 	poolQ [][]*ring.Poly
 	poolP [][]*ring.Poly
 
-	polypool      [2]*ring.Poly
-	keyswitchpool [5]*ring.Poly
+	polypool       [2]*ring.Poly
+	keyswitchpoolQ [4]*ring.Poly
+	keyswitchpoolP [3]*ring.Poly
+
+	ctxpool *Ciphertext
 }
 
 // NewEvaluator creates a new Evaluator, that can be used to do homomorphic
@@ -69,43 +89,126 @@ func NewEvaluator(params *Parameters) Evaluator {
 	q := bfvContext.contextQ
 	qm := bfvContext.contextQMul
 	p := bfvContext.contextP
-	qp := bfvContext.contextQP
 
-	poolQ := make([][]*ring.Poly, 4)
-	poolP := make([][]*ring.Poly, 4)
-	for i := 0; i < 4; i++ {
-		poolQ[i] = make([]*ring.Poly, 6)
-		poolP[i] = make([]*ring.Poly, 6)
-		for j := 0; j < 6; j++ {
-			poolQ[i][j] = q.NewPoly()
-			poolP[i][j] = qm.NewPoly()
-		}
+	contextQLvl := make([]*ring.Context, bfvContext.levels)
+	baseconverterQ1Q2 := make([]*ring.FastBasisExtender, bfvContext.levels)
+	for level := uint64(0); level < bfvContext.levels; level++ {
+		contextQLvl[level] = bfvContext.contextQLvl(level)
+		baseconverterQ1Q2[level] = ring.NewFastBasisExtender(contextQLvl[level], qm)
 	}
 
 	var baseconverter *ring.FastBasisExtender
 	var decomposer *ring.Decomposer
-	var keyswitchpool [5]*ring.Poly
 	if len(params.Pi) != 0 {
 		baseconverter = ring.NewFastBasisExtender(q, p)
 		decomposer = ring.NewDecomposer(q.Modulus, p.Modulus)
-		keyswitchpool = [5]*ring.Poly{qp.NewPoly(), qp.NewPoly(), qp.NewPoly(), qp.NewPoly(), qp.NewPoly()}
 	}
 
-	return &evaluator{
+	evaluator := &evaluator{
 		params:            params.Copy(),
 		bfvContext:        bfvContext,
-		baseconverterQ1Q2: ring.NewFastBasisExtender(q, qm),
+		contextQLvl:       contextQLvl,
+		baseconverterQ1Q2: baseconverterQ1Q2,
 		baseconverterQ1P:  baseconverter,
 		decomposer:        decomposer,
 		pHalf:             new(big.Int).Rsh(qm.ModulusBigint, 1),
-		polypool:          [2]*ring.Poly{q.NewPoly(), q.NewPoly()},
-		keyswitchpool:     keyswitchpool,
-		poolQ:             poolQ,
-		poolP:             poolP,
 	}
+
+	evaluator.allocatePools()
+
+	return evaluator
+}
+
+// ShallowCopy creates a shallow copy of the target evaluator, which shares the read-only precomputations of the
+// original (contexts, basis extension and decomposition parameters) but has its own memory pool. The copy and the
+// original can then be used concurrently, e.g. one per goroutine.
+func (evaluator *evaluator) ShallowCopy() Evaluator {
+
+	evaluatorCopy := *evaluator
+
+	evaluatorCopy.baseconverterQ1Q2 = make([]*ring.FastBasisExtender, len(evaluator.baseconverterQ1Q2))
+	for level, baseconverter := range evaluator.baseconverterQ1Q2 {
+		evaluatorCopy.baseconverterQ1Q2[level] = baseconverter.ShallowCopy()
+	}
+
+	if evaluator.baseconverterQ1P != nil {
+		evaluatorCopy.baseconverterQ1P = evaluator.baseconverterQ1P.ShallowCopy()
+	}
+
+	evaluatorCopy.allocatePools()
+
+	return &evaluatorCopy
 }
 
+// SetWorkerPool attaches the WorkerPool to the polynomial contexts of the target evaluator, so that the per-modulus
+// work of its operations (NTT, basis extensions, key-switching, ...) is split across the workers of the pool. A nil
+// WorkerPool restores the sequential execution. The contexts are shared with the shallow copies of the evaluator,
+// which therefore use the same WorkerPool, hence this method must not be called while one of them is being used.
+func (evaluator *evaluator) SetWorkerPool(pool *ring.WorkerPool) {
+
+	for _, context := range evaluator.contextQLvl {
+		context.SetWorkerPool(pool)
+	}
+
+	evaluator.bfvContext.contextT.SetWorkerPool(pool)
+	evaluator.bfvContext.contextQ.SetWorkerPool(pool)
+	evaluator.bfvContext.contextQMul.SetWorkerPool(pool)
+	evaluator.bfvContext.contextQP.SetWorkerPool(pool)
+
+	if evaluator.bfvContext.contextP != nil {
+		evaluator.bfvContext.contextP.SetWorkerPool(pool)
+	}
+}
+
+// allocatePools allocates the memory pool of the target evaluator.
+func (evaluator *evaluator) allocatePools() {
+
+	q := evaluator.bfvContext.contextQ
+	qm := evaluator.bfvContext.contextQMul
+	p := evaluator.bfvContext.contextP
+
+	evaluator.poolQ = make([][]*ring.Poly, 4)
+	evaluator.poolP = make([][]*ring.Poly, 4)
+	for i := 0; i < 4; i++ {
+		evaluator.poolQ[i] = make([]*ring.Poly, 6)
+		evaluator.poolP[i] = make([]*ring.Poly, 6)
+		for j := 0; j < 6; j++ {
+			evaluator.poolQ[i][j] = q.NewPoly()
+			evaluator.poolP[i][j] = qm.NewPoly()
+		}
+	}
+
+	evaluator.polypool = [2]*ring.Poly{q.NewPoly(), q.NewPoly()}
+	evaluator.ctxpool = NewCiphertext(evaluator.params, 2)
+
+	if len(evaluator.params.Pi) != 0 {
+		evaluator.keyswitchpoolQ = [4]*ring.Poly{q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly()}
+		evaluator.keyswitchpoolP = [3]*ring.Poly{p.NewPoly(), p.NewPoly(), p.NewPoly()}
+	}
+}
+
+// discardSeed discards the seed of the receiver if it is a seeded Ciphertext, since its second polynomial can no
+// longer be regenerated from the seed once it is modified by the evaluator.
+func discardSeed(opOut Operand) {
+	if ct, isCiphertext := opOut.(*Ciphertext); isCiphertext {
+		ct.seed = nil
+	}
+}
+
+// getElemAndCheckBinary checks the operands of the binary operations other than the multiplications, which do not
+// accept a PlaintextMul, and returns their elements.
 func (evaluator *evaluator) getElemAndCheckBinary(op0, op1, opOut Operand, opOutMinDegree uint64) (el0, el1, elOut *bfvElement) {
+
+	if isPlaintextMul(op0) || isPlaintextMul(op1) || isPlaintextMul(opOut) {
+		panic("cannot getElemAndCheckBinary: a PlaintextMul can only be multiplied with a Ciphertext (see Mul)")
+	}
+
+	return evaluator.getElemAndCheckBinaryMul(op0, op1, opOut, opOutMinDegree)
+}
+
+// getElemAndCheckBinaryMul checks the operands of the multiplications, which accept a PlaintextMul, and returns their
+// elements.
+func (evaluator *evaluator) getElemAndCheckBinaryMul(op0, op1, opOut Operand, opOutMinDegree uint64) (el0, el1, elOut *bfvElement) {
 	if op0 == nil || op1 == nil || opOut == nil {
 		panic("cannot getElemAndCheckBinary: operands cannot be nil")
 	}
@@ -118,10 +221,25 @@ func (evaluator *evaluator) getElemAndCheckBinary(op0, op1, opOut Operand, opOut
 		panic("cannot getElemAndCheckBinary: receiver operand degree is too small")
 	}
 
+	if op0.Level() != op1.Level() {
+		panic("cannot getElemAndCheckBinary: operands levels do not match (use DropLevel to align them)")
+	}
+
+	discardSeed(opOut)
+
 	el0, el1, elOut = op0.Element(), op1.Element(), opOut.Element()
+
+	elOut.setLevel(el0.Level())
+
 	return // TODO: more checks on elements
 }
 
+// isPlaintextMul returns true if the operand is a PlaintextMul, which can only be multiplied with a Ciphertext.
+func isPlaintextMul(op Operand) bool {
+	_, isPtMul := op.(*PlaintextMul)
+	return isPtMul
+}
+
 func (evaluator *evaluator) getElemAndCheckUnary(op0, opOut Operand, opOutMinDegree uint64) (el0, elOut *bfvElement) {
 	if op0 == nil || opOut == nil {
 		panic("cannot getElemAndCheckUnary: operand cannot be nil")
@@ -134,7 +252,13 @@ func (evaluator *evaluator) getElemAndCheckUnary(op0, opOut Operand, opOutMinDeg
 	if opOut.Degree() < opOutMinDegree {
 		panic("cannot getElemAndCheckUnary: receiver operand degree is too small")
 	}
+
+	discardSeed(opOut)
+
 	el0, elOut = op0.Element(), opOut.Element()
+
+	elOut.setLevel(el0.Level())
+
 	return // TODO: more checks on elements
 }
 
@@ -172,12 +296,14 @@ func evaluateInPlaceUnary(el0, elOut *bfvElement, evaluate func(*ring.Poly, *rin
 // Add adds op0 to op1 and returns the result in ctOut.
 func (evaluator *evaluator) Add(op0, op1 Operand, ctOut *Ciphertext) {
 	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
-	evaluateInPlaceBinary(el0, el1, elOut, evaluator.bfvContext.contextQ.Add)
+	level := el0.Level()
+	fun := func(p0, p1, p2 *ring.Poly) { evaluator.bfvContext.contextQ.AddLvl(level, p0, p1, p2) }
+	evaluateInPlaceBinary(el0, el1, elOut, fun)
 }
 
 // AddNew adds op0 to op1 and creates a new element ctOut to store the result.
 func (evaluator *evaluator) AddNew(op0, op1 Operand) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	ctOut = NewCiphertextLvl(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()), op0.Level())
 	evaluator.Add(op0, op1, ctOut)
 	return
 }
@@ -185,12 +311,14 @@ func (evaluator *evaluator) AddNew(op0, op1 Operand) (ctOut *Ciphertext) {
 // AddNoMod adds op0 to op1 without modular reduction, and returns the result in cOut.
 func (evaluator *evaluator) AddNoMod(op0, op1 Operand, ctOut *Ciphertext) {
 	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
-	evaluateInPlaceBinary(el0, el1, elOut, evaluator.bfvContext.contextQ.AddNoMod)
+	level := el0.Level()
+	fun := func(p0, p1, p2 *ring.Poly) { evaluator.bfvContext.contextQ.AddNoModLvl(level, p0, p1, p2) }
+	evaluateInPlaceBinary(el0, el1, elOut, fun)
 }
 
 // AddNoModNew adds op0 to op1 without modular reduction and creates a new element ctOut to store the result.
 func (evaluator *evaluator) AddNoModNew(op0, op1 Operand) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	ctOut = NewCiphertextLvl(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()), op0.Level())
 	evaluator.AddNoMod(op0, op1, ctOut)
 	return
 }
@@ -198,18 +326,20 @@ func (evaluator *evaluator) AddNoModNew(op0, op1 Operand) (ctOut *Ciphertext) {
 // Sub subtracts op1 from op0 and returns the result in cOut.
 func (evaluator *evaluator) Sub(op0, op1 Operand, ctOut *Ciphertext) {
 	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
-	evaluateInPlaceBinary(el0, el1, elOut, evaluator.bfvContext.contextQ.Sub)
+	level := el0.Level()
+	fun := func(p0, p1, p2 *ring.Poly) { evaluator.bfvContext.contextQ.SubLvl(level, p0, p1, p2) }
+	evaluateInPlaceBinary(el0, el1, elOut, fun)
 
 	if el0.Degree() < el1.Degree() {
 		for i := el0.Degree() + 1; i < el1.Degree()+1; i++ {
-			evaluator.bfvContext.contextQ.Neg(ctOut.Value()[i], ctOut.Value()[i])
+			evaluator.bfvContext.contextQ.NegLvl(level, ctOut.Value()[i], ctOut.Value()[i])
 		}
 	}
 }
 
 // SubNew subtracts op1 from op0 and creates a new element ctOut to store the result.
 func (evaluator *evaluator) SubNew(op0, op1 Operand) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	ctOut = NewCiphertextLvl(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()), op0.Level())
 	evaluator.Sub(op0, op1, ctOut)
 	return
 }
@@ -217,19 +347,20 @@ func (evaluator *evaluator) SubNew(op0, op1 Operand) (ctOut *Ciphertext) {
 // SubNoMod subtracts op1 from op0 without modular reduction and returns the result on ctOut.
 func (evaluator *evaluator) SubNoMod(op0, op1 Operand, ctOut *Ciphertext) {
 	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
-
-	evaluateInPlaceBinary(el0, el1, elOut, evaluator.bfvContext.contextQ.SubNoMod)
+	level := el0.Level()
+	fun := func(p0, p1, p2 *ring.Poly) { evaluator.bfvContext.contextQ.SubNoModLvl(level, p0, p1, p2) }
+	evaluateInPlaceBinary(el0, el1, elOut, fun)
 
 	if el0.Degree() < el1.Degree() {
 		for i := el0.Degree() + 1; i < el1.Degree()+1; i++ {
-			evaluator.bfvContext.contextQ.Neg(ctOut.Value()[i], ctOut.Value()[i])
+			evaluator.bfvContext.contextQ.NegLvl(level, ctOut.Value()[i], ctOut.Value()[i])
 		}
 	}
 }
 
 // SubNoModNew subtracts op1 from op0 without modular reduction and creates a new element ctOut to store the result.
 func (evaluator *evaluator) SubNoModNew(op0, op1 Operand) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	ctOut = NewCiphertextLvl(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()), op0.Level())
 	evaluator.SubNoMod(op0, op1, ctOut)
 	return
 }
@@ -237,12 +368,14 @@ func (evaluator *evaluator) SubNoModNew(op0, op1 Operand) (ctOut *Ciphertext) {
 // Neg negates op and returns the result in ctOut.
 func (evaluator *evaluator) Neg(op Operand, ctOut *Ciphertext) {
 	el0, elOut := evaluator.getElemAndCheckUnary(op, ctOut, op.Degree())
-	evaluateInPlaceUnary(el0, elOut, evaluator.bfvContext.contextQ.Neg)
+	level := el0.Level()
+	fun := func(p0, p1 *ring.Poly) { evaluator.bfvContext.contextQ.NegLvl(level, p0, p1) }
+	evaluateInPlaceUnary(el0, elOut, fun)
 }
 
 // NegNew negates op and creates a new element to store the result.
 func (evaluator *evaluator) NegNew(op Operand) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, op.Degree())
+	ctOut = NewCiphertextLvl(evaluator.params, op.Degree(), op.Level())
 	evaluator.Neg(op, ctOut)
 	return ctOut
 }
@@ -250,12 +383,14 @@ func (evaluator *evaluator) NegNew(op Operand) (ctOut *Ciphertext) {
 // Reduce applies a modular reduction to op and returns the result in ctOut.
 func (evaluator *evaluator) Reduce(op Operand, ctOut *Ciphertext) {
 	el0, elOut := evaluator.getElemAndCheckUnary(op, ctOut, op.Degree())
-	evaluateInPlaceUnary(el0, elOut, evaluator.bfvContext.contextQ.Reduce)
+	level := el0.Level()
+	fun := func(p0, p1 *ring.Poly) { evaluator.bfvContext.contextQ.ReduceLvl(level, p0, p1) }
+	evaluateInPlaceUnary(el0, elOut, fun)
 }
 
 // ReduceNew applies a modular reduction to op and creates a new element ctOut to store the result.
 func (evaluator *evaluator) ReduceNew(op Operand) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, op.Degree())
+	ctOut = NewCiphertextLvl(evaluator.params, op.Degree(), op.Level())
 	evaluator.Reduce(op, ctOut)
 	return ctOut
 }
@@ -263,26 +398,30 @@ func (evaluator *evaluator) ReduceNew(op Operand) (ctOut *Ciphertext) {
 // MulScalar multiplies op by a uint64 scalar and returns the result in ctOut.
 func (evaluator *evaluator) MulScalar(op Operand, scalar uint64, ctOut *Ciphertext) {
 	el0, elOut := evaluator.getElemAndCheckUnary(op, ctOut, op.Degree())
-	fun := func(el, elOut *ring.Poly) { evaluator.bfvContext.contextQ.MulScalar(el, scalar, elOut) }
+	level := el0.Level()
+	fun := func(el, elOut *ring.Poly) { evaluator.bfvContext.contextQ.MulScalarLvl(level, el, scalar, elOut) }
 	evaluateInPlaceUnary(el0, elOut, fun)
 }
 
 // MulScalarNew multiplies op by a uint64 scalar and creates a new element ctOut to store the result.
 func (evaluator *evaluator) MulScalarNew(op Operand, scalar uint64) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, op.Degree())
+	ctOut = NewCiphertextLvl(evaluator.params, op.Degree(), op.Level())
 	evaluator.MulScalar(op, scalar, ctOut)
 	return
 }
 
-// tensorAndRescale computes (ct0 x ct1) * (t/Q) and stores the result in ctOut.
+// tensorAndRescale computes (ct0 x ct1) * (t/Q) and stores the result in ctOut, where Q is the product
+// of the moduli up to the level of the inputs.
 func (evaluator *evaluator) tensorAndRescale(ct0, ct1, ctOut *bfvElement) {
 
 	contextQ := evaluator.bfvContext.contextQ
 	contextQMul := evaluator.bfvContext.contextQMul
 
-	levelQ := uint64(len(contextQ.Modulus) - 1)
+	level := ct0.Level()
 	levelQMul := uint64(len(contextQMul.Modulus) - 1)
 
+	baseconverter := evaluator.baseconverterQ1Q2[level]
+
 	// Prepares the ciphertexts for the Tensoring by extending their
 	// basis from Q to QP and transforming them to NTT form
 
@@ -296,18 +435,18 @@ func (evaluator *evaluator) tensorAndRescale(ct0, ct1, ctOut *bfvElement) {
 	c2Q2 := evaluator.poolP[2]
 
 	for i := range ct0.value {
-		evaluator.baseconverterQ1Q2.ModUpSplitQP(levelQ, ct0.value[i], c0Q2[i])
+		baseconverter.ModUpSplitQP(level, ct0.value[i], c0Q2[i])
 
-		contextQ.NTT(ct0.value[i], c0Q1[i])
+		contextQ.NTTLvl(level, ct0.value[i], c0Q1[i])
 		contextQMul.NTT(c0Q2[i], c0Q2[i])
 	}
 
 	if ct0 != ct1 {
 
 		for i := range ct1.value {
-			evaluator.baseconverterQ1Q2.ModUpSplitQP(levelQ, ct1.value[i], c1Q2[i])
+			baseconverter.ModUpSplitQP(level, ct1.value[i], c1Q2[i])
 
-			contextQ.NTT(ct1.value[i], c1Q1[i])
+			contextQ.NTTLvl(level, ct1.value[i], c1Q1[i])
 			contextQMul.NTT(c1Q2[i], c1Q2[i])
 		}
 	}
@@ -324,46 +463,46 @@ func (evaluator *evaluator) tensorAndRescale(ct0, ct1, ctOut *bfvElement) {
 		c01Q := evaluator.poolQ[3][1]
 		c01P := evaluator.poolP[3][1]
 
-		contextQ.MForm(c0Q1[0], c00Q)
+		contextQ.MFormLvl(level, c0Q1[0], c00Q)
 		contextQMul.MForm(c0Q2[0], c00Q2)
 
-		contextQ.MForm(c0Q1[1], c01Q)
+		contextQ.MFormLvl(level, c0Q1[1], c01Q)
 		contextQMul.MForm(c0Q2[1], c01P)
 
 		// Squaring case
 		if ct0 == ct1 {
 
 			// c0 = c0[0]*c0[0]
-			contextQ.MulCoeffsMontgomery(c00Q, c0Q1[0], c2Q1[0])
+			contextQ.MulCoeffsMontgomeryLvl(level, c00Q, c0Q1[0], c2Q1[0])
 			contextQMul.MulCoeffsMontgomery(c00Q2, c0Q2[0], c2Q2[0])
 
 			// c1 = 2*c0[0]*c0[1]
-			contextQ.MulCoeffsMontgomery(c00Q, c0Q1[1], c2Q1[1])
+			contextQ.MulCoeffsMontgomeryLvl(level, c00Q, c0Q1[1], c2Q1[1])
 			contextQMul.MulCoeffsMontgomery(c00Q2, c0Q2[1], c2Q2[1])
 
-			contextQ.AddNoMod(c2Q1[1], c2Q1[1], c2Q1[1])
+			contextQ.AddNoModLvl(level, c2Q1[1], c2Q1[1], c2Q1[1])
 			contextQMul.AddNoMod(c2Q2[1], c2Q2[1], c2Q2[1])
 
 			// c2 = c0[1]*c0[1]
-			contextQ.MulCoeffsMontgomery(c01Q, c0Q1[1], c2Q1[2])
+			contextQ.MulCoeffsMontgomeryLvl(level, c01Q, c0Q1[1], c2Q1[2])
 			contextQMul.MulCoeffsMontgomery(c01P, c0Q2[1], c2Q2[2])
 
 			// Normal case
 		} else {
 
 			// c0 = c0[0]*c1[0]
-			contextQ.MulCoeffsMontgomery(c00Q, c1Q1[0], c2Q1[0])
+			contextQ.MulCoeffsMontgomeryLvl(level, c00Q, c1Q1[0], c2Q1[0])
 			contextQMul.MulCoeffsMontgomery(c00Q2, c1Q2[0], c2Q2[0])
 
 			// c1 = c0[0]*c1[1] + c0[1]*c1[0]
-			contextQ.MulCoeffsMontgomery(c00Q, c1Q1[1], c2Q1[1])
+			contextQ.MulCoeffsMontgomeryLvl(level, c00Q, c1Q1[1], c2Q1[1])
 			contextQMul.MulCoeffsMontgomery(c00Q2, c1Q2[1], c2Q2[1])
 
-			contextQ.MulCoeffsMontgomeryAndAddNoMod(c01Q, c1Q1[0], c2Q1[1])
+			contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, c01Q, c1Q1[0], c2Q1[1])
 			contextQMul.MulCoeffsMontgomeryAndAddNoMod(c01P, c1Q2[0], c2Q2[1])
 
 			// c2 = c0[1]*c1[1]
-			contextQ.MulCoeffsMontgomery(c01Q, c1Q1[1], c2Q1[2])
+			contextQ.MulCoeffsMontgomeryLvl(level, c01Q, c1Q1[1], c2Q1[2])
 			contextQMul.MulCoeffsMontgomery(c01P, c1Q2[1], c2Q2[2])
 		}
 
@@ -382,32 +521,32 @@ func (evaluator *evaluator) tensorAndRescale(ct0, ct1, ctOut *bfvElement) {
 			c00Q2 := evaluator.poolP[3]
 
 			for i := range ct0.value {
-				contextQ.MForm(c0Q1[i], c00Q1[i])
+				contextQ.MFormLvl(level, c0Q1[i], c00Q1[i])
 				contextQMul.MForm(c0Q2[i], c00Q2[i])
 			}
 
 			for i := uint64(0); i < ct0.Degree()+1; i++ {
 				for j := i + 1; j < ct0.Degree()+1; j++ {
-					contextQ.MulCoeffsMontgomery(c00Q1[i], c0Q1[j], c2Q1[i+j])
+					contextQ.MulCoeffsMontgomeryLvl(level, c00Q1[i], c0Q1[j], c2Q1[i+j])
 					contextQMul.MulCoeffsMontgomery(c00Q2[i], c0Q2[j], c2Q2[i+j])
 
-					contextQ.Add(c2Q1[i+j], c2Q1[i+j], c2Q1[i+j])
+					contextQ.AddLvl(level, c2Q1[i+j], c2Q1[i+j], c2Q1[i+j])
 					contextQMul.Add(c2Q2[i+j], c2Q2[i+j], c2Q2[i+j])
 				}
 			}
 
 			for i := uint64(0); i < ct0.Degree()+1; i++ {
-				contextQ.MulCoeffsMontgomeryAndAdd(c00Q1[i], c0Q1[i], c2Q1[i<<1])
+				contextQ.MulCoeffsMontgomeryAndAddLvl(level, c00Q1[i], c0Q1[i], c2Q1[i<<1])
 				contextQMul.MulCoeffsMontgomeryAndAdd(c00Q2[i], c0Q2[i], c2Q2[i<<1])
 			}
 
 			// Normal case
 		} else {
 			for i := range ct0.value {
-				contextQ.MForm(c0Q1[i], c0Q1[i])
+				contextQ.MFormLvl(level, c0Q1[i], c0Q1[i])
 				contextQMul.MForm(c0Q2[i], c0Q2[i])
 				for j := range ct1.value {
-					contextQ.MulCoeffsMontgomeryAndAdd(c0Q1[i], c1Q1[j], c2Q1[i+j])
+					contextQ.MulCoeffsMontgomeryAndAddLvl(level, c0Q1[i], c1Q1[j], c2Q1[i+j])
 					contextQMul.MulCoeffsMontgomeryAndAdd(c0Q2[i], c1Q2[j], c2Q2[i+j])
 				}
 			}
@@ -421,7 +560,7 @@ func (evaluator *evaluator) tensorAndRescale(ct0, ct1, ctOut *bfvElement) {
 	// Applies the inverse NTT to the ciphertext, scales down the ciphertext
 	// by t/q and reduces its basis from QP to Q
 	for i := range ctOut.value {
-		contextQ.InvNTT(c2Q1[i], c2Q1[i])
+		contextQ.InvNTTLvl(level, c2Q1[i], c2Q1[i])
 		contextQMul.InvNTT(c2Q2[i], c2Q2[i])
 
 		/*
@@ -447,7 +586,7 @@ func (evaluator *evaluator) tensorAndRescale(ct0, ct1, ctOut *bfvElement) {
 		//fmt.Println(coeffs_bigint[0])
 
 		// Extends the basis Q of ct(x) to the basis P and Divides (ct(x)Q -> P) by Q
-		evaluator.baseconverterQ1Q2.ModDownSplitedQP(levelQ, levelQMul, c2Q1[i], c2Q2[i], c2Q2[i])
+		baseconverter.ModDownSplitedQP(level, levelQMul, c2Q1[i], c2Q2[i], c2Q2[i])
 
 		//contextQMul.PolyToBigint(c2Q2[i], coeffs_bigint)
 		//fmt.Println(coeffs_bigint[0])
@@ -455,44 +594,98 @@ func (evaluator *evaluator) tensorAndRescale(ct0, ct1, ctOut *bfvElement) {
 
 		// Centers (ct(x)Q -> P)/Q by (P-1)/2 and extends ((ct(x)Q -> P)/Q) to the basis Q
 		contextQMul.AddScalarBigint(c2Q2[i], evaluator.pHalf, c2Q2[i])
-		evaluator.baseconverterQ1Q2.ModUpSplitPQ(levelQMul, c2Q2[i], ctOut.value[i])
-		contextQ.SubScalarBigint(ctOut.value[i], evaluator.pHalf, ctOut.value[i])
+		baseconverter.ModUpSplitPQ(levelQMul, c2Q2[i], ctOut.value[i])
+		contextQ.SubScalarBigintLvl(level, ctOut.value[i], evaluator.pHalf, ctOut.value[i])
 
 		// Option (2) (ct(x)/Q)*T, doing so only requires that Q*P > Q*Q, faster but adds error ~|T|
-		contextQ.MulScalar(ctOut.value[i], evaluator.bfvContext.contextT.Modulus[0], ctOut.value[i])
+		contextQ.MulScalarLvl(level, ctOut.value[i], evaluator.bfvContext.contextT.Modulus[0], ctOut.value[i])
 	}
 }
 
-// Mul multiplies op0 by op1 and returns the result in ctOut.
+// Mul multiplies op0 by op1 and returns the result in ctOut. If op1 is a PlaintextMul, the multiplication
+// is done without any conversion of the plaintext and without tensoring.
 func (evaluator *evaluator) Mul(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext) {
-	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, op0.Degree()+op1.Degree())
-	evaluator.tensorAndRescale(el0, el1, elOut)
+	el0, el1, elOut := evaluator.getElemAndCheckBinaryMul(op0, op1, ctOut, op0.Degree()+op1.Degree())
+
+	if ptMul, isPtMul := op1.(*PlaintextMul); isPtMul {
+		evaluator.mulPlaintextMul(el0, ptMul, elOut)
+	} else {
+		evaluator.tensorAndRescale(el0, el1, elOut)
+	}
+}
+
+// mulPlaintextMul multiplies each polynomial of ct0 by the pre-computed plaintext and returns the result on ctOut.
+// Since the message of the plaintext is not scaled by Q/T, no rescaling is required.
+func (evaluator *evaluator) mulPlaintextMul(ct0 *bfvElement, ptMul *PlaintextMul, ctOut *bfvElement) {
+
+	context := evaluator.bfvContext.contextQ
+
+	level := ct0.Level()
+
+	tmp := evaluator.polypool[0]
+
+	for i := range ct0.value {
+		context.NTTLvl(level, ct0.value[i], tmp)
+		context.MulCoeffsMontgomeryLvl(level, tmp, ptMul.value, tmp)
+		context.InvNTTLvl(level, tmp, ctOut.value[i])
+	}
+
+	for i := len(ct0.value); i < len(ctOut.value); i++ {
+		ctOut.value[i].Zero()
+	}
 }
 
 // MulNew multiplies op0 by op1 and creates a new element ctOut to store the result.
 func (evaluator *evaluator) MulNew(op0 *Ciphertext, op1 Operand) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, op0.Degree()+op1.Degree())
+	ctOut = NewCiphertextLvl(evaluator.params, op0.Degree()+op1.Degree(), op0.Level())
 	evaluator.Mul(op0, op1, ctOut)
 	return
 }
 
+// MulAndAdd multiplies op0 by op1 and adds the result to ctOut, without relinearization, so that the sum of several
+// products (e.g. an inner product) can be accumulated on ctOut and relinearized only once. It only skips the
+// relinearization: each product is computed as by Mul, including the tensoring and the rescaling by T/Q. The product
+// of two ciphertexts is of degree two, hence ctOut is resized to degree two if needed. ctOut must be at the level of
+// the inputs.
+func (evaluator *evaluator) MulAndAdd(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext) {
+
+	if op0.Degree() > 1 || op1.Degree() > 1 {
+		panic("cannot MulAndAdd: input elements must be of degree 0 or 1")
+	}
+
+	degree := op0.Degree() + op1.Degree()
+
+	tmp := &Ciphertext{bfvElement: &bfvElement{value: evaluator.ctxpool.value[:degree+1]}}
+
+	evaluator.Mul(op0, op1, tmp)
+
+	if ctOut.Degree() < degree {
+		ctOut.Resize(evaluator.params, degree)
+	}
+
+	evaluator.Add(ctOut, tmp, ctOut)
+}
+
 // relinearize is a method common to Relinearize and RelinearizeNew. It switches ct0 to the NTT domain, applies the keyswitch, and returns the result out of the NTT domain.
 func (evaluator *evaluator) relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext) {
 
 	context := evaluator.bfvContext.contextQ
 
+	level := ct0.Level()
+
 	if ctOut != ct0 {
-		evaluator.bfvContext.contextQ.Copy(ct0.value[0], ctOut.value[0])
-		evaluator.bfvContext.contextQ.Copy(ct0.value[1], ctOut.value[1])
+		ctOut.setLevel(level)
+		context.CopyLvl(level, ct0.value[0], ctOut.value[0])
+		context.CopyLvl(level, ct0.value[1], ctOut.value[1])
 	}
 
-	p0 := evaluator.keyswitchpool[2]
-	p1 := evaluator.keyswitchpool[3]
+	p0 := evaluator.keyswitchpoolQ[2]
+	p1 := evaluator.keyswitchpoolQ[3]
 
 	for deg := uint64(ct0.Degree()); deg > 1; deg-- {
-		evaluator.switchKeys(ct0.value[deg], evakey.evakey[deg-2], p0, p1)
-		context.Add(ctOut.value[0], p0, ctOut.value[0])
-		context.Add(ctOut.value[1], p1, ctOut.value[1])
+		evaluator.switchKeys(level, ct0.value[deg], evakey.evakey[deg-2], p0, p1)
+		context.AddLvl(level, ctOut.value[0], p0, ctOut.value[0])
+		context.AddLvl(level, ctOut.value[1], p1, ctOut.value[1])
 	}
 
 	ctOut.SetValue(ctOut.value[:2])
@@ -508,6 +701,8 @@ func (evaluator *evaluator) relinearize(ct0 *Ciphertext, evakey *EvaluationKey,
 // of degree 3 will require that the evaluation key stores the keys for both degree 3 and degree 2 ciphertexts).
 func (evaluator *evaluator) Relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	if int(ct0.Degree()-1) > len(evakey.evakey) {
 		panic("cannot Relinearize: input ciphertext degree too large to allow relinearization")
 	}
@@ -530,7 +725,7 @@ func (evaluator *evaluator) Relinearize(ct0 *Ciphertext, evakey *EvaluationKey,
 // - it must be of degree high enough to relinearize the input ciphertext to degree 1 (e.g., a ciphertext
 // of degree 3 will require that the evaluation key stores the keys for both degree 3 and degree 2 ciphertexts).
 func (evaluator *evaluator) RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, 1)
+	ctOut = NewCiphertextLvl(evaluator.params, 1, ct0.Level())
 	evaluator.Relinearize(ct0, evakey, ctOut)
 	return
 }
@@ -539,32 +734,38 @@ func (evaluator *evaluator) RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKe
 // it must encrypt the target key under the public key under which ct0 is currently encrypted.
 func (evaluator *evaluator) SwitchKeys(ct0 *Ciphertext, switchKey *SwitchingKey, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	context := evaluator.bfvContext.contextQ
 
 	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
 		panic("cannot SwitchKeys: input and output must be of degree 1 to allow key switching")
 	}
 
-	p0 := evaluator.keyswitchpool[2]
-	p1 := evaluator.keyswitchpool[3]
+	level := ct0.Level()
+
+	ctOut.setLevel(level)
 
-	evaluator.switchKeys(ct0.value[1], switchKey, p0, p1)
+	p0 := evaluator.keyswitchpoolQ[2]
+	p1 := evaluator.keyswitchpoolQ[3]
 
-	context.Add(ct0.value[0], p0, ctOut.value[0])
-	context.Copy(p1, ctOut.value[1])
+	evaluator.switchKeys(level, ct0.value[1], switchKey, p0, p1)
+
+	context.AddLvl(level, ct0.value[0], p0, ctOut.value[0])
+	context.CopyLvl(level, p1, ctOut.value[1])
 }
 
 // SwitchKeysNew applies the key-switching procedure to the ciphertext ct0 and creates a new ciphertext to store the result. It requires as an additional input a valid switching-key:
 // it must encrypt the target key under the public key under which ct0 is currently encrypted.
 func (evaluator *evaluator) SwitchKeysNew(ct0 *Ciphertext, switchkey *SwitchingKey) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, 1)
+	ctOut = NewCiphertextLvl(evaluator.params, 1, ct0.Level())
 	evaluator.SwitchKeys(ct0, switchkey, ctOut)
 	return
 }
 
 // RotateColumnsNew applies RotateColumns and returns the result in a new Ciphertext.
 func (evaluator *evaluator) RotateColumnsNew(ct0 *Ciphertext, k uint64, evakey *RotationKeys) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, 1)
+	ctOut = NewCiphertextLvl(evaluator.params, 1, ct0.Level())
 	evaluator.RotateColumns(ct0, k, evakey, ctOut)
 	return
 }
@@ -577,6 +778,8 @@ func (evaluator *evaluator) RotateColumnsNew(ct0 *Ciphertext, k uint64, evakey *
 // hamming weight will be chosen; then the specific rotation will be computed as a sum of powers of two rotations.
 func (evaluator *evaluator) RotateColumns(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
 		panic("cannot RotateColumns: input and or output must be of degree 1")
 	}
@@ -589,17 +792,17 @@ func (evaluator *evaluator) RotateColumns(ct0 *Ciphertext, k uint64, evakey *Rot
 
 	} else {
 
-		// Looks in the rotation key if the corresponding rotation has been generated or if the input is a plaintext
-		if evakey.evakeyRotColLeft[k] != nil {
+		// Looks in the rotation key if the corresponding rotation has been generated, either to the left or to the right
+		if switchKey := evakey.keys[evaluator.bfvContext.galElRotColLeft[k]]; switchKey != nil {
 
-			evaluator.permute(ct0, evaluator.bfvContext.galElRotColLeft[k], evakey.evakeyRotColLeft[k], ctOut)
+			evaluator.permute(ct0, evaluator.bfvContext.galElRotColLeft[k], switchKey, ctOut)
 
 		} else {
 
 			// If the needed rotation key has not been generated, it looks if the left and right pow2 rotations have been generated
 			hasPow2Rotations := true
 			for i := uint64(1); i < evaluator.bfvContext.n>>1; i <<= 1 {
-				if evakey.evakeyRotColLeft[i] == nil || evakey.evakeyRotColRight[i] == nil {
+				if evakey.keys[evaluator.bfvContext.galElRotColLeft[i]] == nil || evakey.keys[evaluator.bfvContext.galElRotColRight[i]] == nil {
 					hasPow2Rotations = false
 					break
 				}
@@ -624,29 +827,30 @@ func (evaluator *evaluator) RotateColumns(ct0 *Ciphertext, k uint64, evakey *Rot
 
 // rotateColumnsLPow2 applies the Galois Automorphism on an element, rotating the element by k positions to the left, and returns the result in ctOut.
 func (evaluator *evaluator) rotateColumnsLPow2(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
-	evaluator.rotateColumnsPow2(ct0, GaloisGen, k, evakey.evakeyRotColLeft, ctOut)
+	evaluator.rotateColumnsPow2(ct0, GaloisGen, k, evakey, ctOut)
 }
 
 // rotateColumnsRPow2 applies the Galois Endomorphism on an element, rotating the element by k positions to the right, returns the result in ctOut.
 func (evaluator *evaluator) rotateColumnsRPow2(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
 	genInv := ring.ModExp(GaloisGen, 2*evaluator.bfvContext.n-1, 2*evaluator.bfvContext.n)
-	evaluator.rotateColumnsPow2(ct0, genInv, k, evakey.evakeyRotColRight, ctOut)
+	evaluator.rotateColumnsPow2(ct0, genInv, k, evakey, ctOut)
 }
 
 // rotateColumnsPow2 rotates ct0 by k positions (left or right depending on the input), decomposing k as a sum of power-of-2 rotations, and returns the result in ctOut.
-func (evaluator *evaluator) rotateColumnsPow2(ct0 *Ciphertext, generator, k uint64, evakeyRotCol map[uint64]*SwitchingKey, ctOut *Ciphertext) {
+func (evaluator *evaluator) rotateColumnsPow2(ct0 *Ciphertext, generator, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
 
-	var mask, evakeyIndex uint64
+	var mask uint64
 
 	context := evaluator.bfvContext.contextQ
 
 	mask = (evaluator.bfvContext.n << 1) - 1
 
-	evakeyIndex = 1
+	level := ct0.Level()
 
 	if ct0 != ctOut {
-		context.Copy(ct0.value[0], ctOut.value[0])
-		context.Copy(ct0.value[1], ctOut.value[1])
+		ctOut.setLevel(level)
+		context.CopyLvl(level, ct0.value[0], ctOut.value[0])
+		context.CopyLvl(level, ct0.value[1], ctOut.value[1])
 	}
 
 	// Applies the Galois automorphism and the key-switching process
@@ -654,13 +858,12 @@ func (evaluator *evaluator) rotateColumnsPow2(ct0 *Ciphertext, generator, k uint
 
 		if k&1 == 1 {
 
-			evaluator.permute(ctOut, generator, evakeyRotCol[evakeyIndex], ctOut)
+			evaluator.permute(ctOut, generator, evakey.keys[generator], ctOut)
 		}
 
 		generator *= generator
 		generator &= mask
 
-		evakeyIndex <<= 1
 		k >>= 1
 	}
 }
@@ -668,24 +871,98 @@ func (evaluator *evaluator) rotateColumnsPow2(ct0 *Ciphertext, generator, k uint
 // RotateRows rotates the rows of ct0 and returns the result in ctOut.
 func (evaluator *evaluator) RotateRows(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
 		panic("cannot RotateRows: input and/or output must be of degree 1")
 	}
 
-	if evakey.evakeyRotRow == nil {
+	switchKey := evakey.keys[evaluator.bfvContext.galElRotRow]
+
+	if switchKey == nil {
 		panic("cannot RotateRows: rotation key not generated")
 	}
 
-	evaluator.permute(ct0, evaluator.bfvContext.galElRotRow, evakey.evakeyRotRow, ctOut)
+	evaluator.permute(ct0, evaluator.bfvContext.galElRotRow, switchKey, ctOut)
 }
 
 // RotateRowsNew rotates the rows of ct0 and returns the result a new Ciphertext.
 func (evaluator *evaluator) RotateRowsNew(ct0 *Ciphertext, evakey *RotationKeys) (ctOut *Ciphertext) {
-	ctOut = NewCiphertext(evaluator.params, 1)
+	ctOut = NewCiphertextLvl(evaluator.params, 1, ct0.Level())
 	evaluator.RotateRows(ct0, evakey, ctOut)
 	return
 }
 
+// RotateHoisted takes an input Ciphertext and a list of rotations of the columns (to the left) and returns a map of Ciphertext,
+// where each element of the map is the input Ciphertext rotated by one element of the list. The decomposition of the input
+// Ciphertext required by the key-switching is computed only once and shared by all the rotations, which is much faster than
+// sequential calls to RotateColumns. The RotationKeys must store the specific rotations that are requested.
+func (evaluator *evaluator) RotateHoisted(ct0 *Ciphertext, rotations []uint64, rotkeys *RotationKeys) (cOut map[uint64]*Ciphertext) {
+
+	if ct0.Degree() != 1 {
+		panic("cannot RotateHoisted: input must be of degree 1")
+	}
+
+	// Pre-computation for rotations using hoisting
+	contextQ := evaluator.bfvContext.contextQ
+	contextP := evaluator.bfvContext.contextP
+
+	level := ct0.Level()
+
+	c2InvNTT := ct0.value[1]
+	c2NTT := contextQ.NewPoly()
+	contextQ.NTTLvl(level, c2InvNTT, c2NTT)
+
+	alpha := evaluator.params.alpha
+	beta := uint64(math.Ceil(float64(level+1) / float64(alpha)))
+
+	c2QiQDecomp := make([]*ring.Poly, beta)
+	c2QiPDecomp := make([]*ring.Poly, beta)
+
+	for i := uint64(0); i < beta; i++ {
+		c2QiQDecomp[i] = contextQ.NewPoly()
+		c2QiPDecomp[i] = contextP.NewPoly()
+		evaluator.decomposeAndSplitNTT(level, i, c2NTT, c2InvNTT, c2QiQDecomp[i], c2QiPDecomp[i])
+	}
+
+	cOut = make(map[uint64]*Ciphertext)
+
+	for _, k := range rotations {
+
+		k &= ((evaluator.bfvContext.n >> 1) - 1)
+
+		if _, ok := cOut[k]; ok {
+			continue
+		}
+
+		if k == 0 {
+			cOut[k] = ct0.CopyNew().Ciphertext()
+		} else {
+
+			galEl := evaluator.bfvContext.galElRotColLeft[k]
+
+			switchKey := rotkeys.keys[galEl]
+
+			if switchKey == nil {
+				panic("cannot RotateHoisted: specific rotation has not been generated")
+			}
+
+			cOut[k] = NewCiphertextLvl(evaluator.params, 1, level)
+
+			p0 := evaluator.keyswitchpoolQ[2]
+			p1 := evaluator.keyswitchpoolQ[3]
+
+			evaluator.switchKeysHoisted(level, c2QiQDecomp, c2QiPDecomp, ring.PermuteNTTIndex(galEl, 1, evaluator.bfvContext.n), switchKey, p0, p1)
+
+			contextQ.PermuteLvl(level, ct0.value[0], galEl, cOut[k].value[0])
+			contextQ.AddLvl(level, cOut[k].value[0], p0, cOut[k].value[0])
+			contextQ.CopyLvl(level, p1, cOut[k].value[1])
+		}
+	}
+
+	return
+}
+
 // InnerSum computes the inner sum of ct0 and returns the result in ctOut. It requires a rotation key storing all the left powers of two rotations.
 // The resulting vector will be of the form [sum, sum, .., sum, sum].
 func (evaluator *evaluator) InnerSum(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext) {
@@ -694,7 +971,7 @@ func (evaluator *evaluator) InnerSum(ct0 *Ciphertext, evakey *RotationKeys, ctOu
 		panic("cannot InnerSum: input and output must be of degree 1")
 	}
 
-	cTmp := NewCiphertext(evaluator.params, 1)
+	cTmp := NewCiphertextLvl(evaluator.params, 1, ct0.Level())
 
 	ctOut.Copy(ct0.Element())
 
@@ -712,6 +989,10 @@ func (evaluator *evaluator) permute(ct0 *Ciphertext, generator uint64, switchKey
 
 	context := evaluator.bfvContext.contextQ
 
+	level := ct0.Level()
+
+	ctOut.setLevel(level)
+
 	var el0, el1 *ring.Poly
 
 	if ct0 != ctOut {
@@ -720,94 +1001,275 @@ func (evaluator *evaluator) permute(ct0 *Ciphertext, generator uint64, switchKey
 		el0, el1 = evaluator.polypool[0], evaluator.polypool[1]
 	}
 
-	context.Permute(ct0.value[0], generator, el0)
-	context.Permute(ct0.value[1], generator, el1)
+	context.PermuteLvl(level, ct0.value[0], generator, el0)
+	context.PermuteLvl(level, ct0.value[1], generator, el1)
 
-	p0 := evaluator.keyswitchpool[2]
-	p1 := evaluator.keyswitchpool[3]
+	p0 := evaluator.keyswitchpoolQ[2]
+	p1 := evaluator.keyswitchpoolQ[3]
 
-	evaluator.switchKeys(el1, switchKey, p0, p1)
+	evaluator.switchKeys(level, el1, switchKey, p0, p1)
 
-	context.Add(el0, p0, ctOut.value[0])
-	context.Copy(p1, ctOut.value[1])
+	context.AddLvl(level, el0, p0, ctOut.value[0])
+	context.CopyLvl(level, p1, ctOut.value[1])
 }
 
 // switchKeys applies the general key-switching procedure of the form [c0 + cx*evakey[0], c1 + cx*evakey[1]]
-func (evaluator *evaluator) switchKeys(cx *ring.Poly, evakey *SwitchingKey, p0, p1 *ring.Poly) {
+// on the moduli up to the given level. The input cx and the outputs p0 and p1 are outside of the NTT domain.
+func (evaluator *evaluator) switchKeys(level uint64, cx *ring.Poly, evakey *SwitchingKey, p0, p1 *ring.Poly) {
 
-	var level, reduce uint64
+	var reduce uint64
 
-	context := evaluator.bfvContext.contextQ
-	contextKeys := evaluator.bfvContext.contextQP
+	contextQ := evaluator.bfvContext.contextQ
+	contextP := evaluator.bfvContext.contextP
 
-	level = uint64(len(context.Modulus)) - 1
+	for i := range evaluator.keyswitchpoolQ {
+		evaluator.keyswitchpoolQ[i].Zero()
+	}
 
-	for i := range evaluator.keyswitchpool {
-		evaluator.keyswitchpool[i].Zero()
+	for i := range evaluator.keyswitchpoolP {
+		evaluator.keyswitchpoolP[i].Zero()
 	}
 
-	c2Qi := evaluator.keyswitchpool[0]
-	c2 := evaluator.keyswitchpool[1]
+	c2QiQ := evaluator.keyswitchpoolQ[0]
+	c2QiP := evaluator.keyswitchpoolP[0]
+	c2 := evaluator.keyswitchpoolQ[1]
+
+	pool2Q := p0
+	pool2P := evaluator.keyswitchpoolP[1]
 
-	// We switch the element on which the key-switching operation will be conducted out of the NTT domain
-	context.NTT(cx, c2)
+	pool3Q := p1
+	pool3P := evaluator.keyswitchpoolP[2]
+
+	// We switch the element on which the key-switching operation will be conducted in the NTT domain
+	contextQ.NTTLvl(level, cx, c2)
 
 	reduce = 0
 
-	N := contextKeys.N
-	c2QiNtt := make([]uint64, N)
+	alpha := evaluator.params.alpha
+	beta := uint64(math.Ceil(float64(level+1) / float64(alpha)))
 
 	// Key switching with CRT decomposition for the Qi
-	for i := uint64(0); i < evaluator.params.beta; i++ {
+	for i := uint64(0); i < beta; i++ {
 
-		p0idxst := i * evaluator.params.alpha
-		p0idxed := p0idxst + evaluator.decomposer.Xalpha()[i]
+		evaluator.decomposeAndSplitNTT(level, i, c2, cx, c2QiQ, c2QiP)
 
-		// c2Qi = cx mod qi
-		evaluator.decomposer.Decompose(level, i, cx, c2Qi)
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakey[i][0], c2QiQ, pool2Q)
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakey[i][1], c2QiQ, pool3Q)
 
-		for x, qi := range contextKeys.Modulus {
+		// We continue with the key-switch primes.
+		for j, keysindex := uint64(0), evaluator.bfvContext.levels; j < uint64(len(contextP.Modulus)); j, keysindex = j+1, keysindex+1 {
 
-			nttPsi := contextKeys.GetNttPsi()[x]
-			bredParams := contextKeys.GetBredParams()[x]
-			mredParams := contextKeys.GetMredParams()[x]
+			pj := contextP.Modulus[j]
+			mredParams := contextP.GetMredParams()[j]
 
-			if p0idxst <= uint64(x) && uint64(x) < p0idxed {
-				p2tmp := c2.Coeffs[x]
-				for j := uint64(0); j < N; j++ {
-					c2QiNtt[j] = p2tmp[j]
-				}
-			} else {
-				ring.NTT(c2Qi.Coeffs[x], c2QiNtt, N, nttPsi, qi, mredParams, bredParams)
+			key0 := evakey.evakey[i][0].Coeffs[keysindex]
+			key1 := evakey.evakey[i][1].Coeffs[keysindex]
+			c2tmp := c2QiP.Coeffs[j]
+			p2tmp := pool2P.Coeffs[j]
+			p3tmp := pool3P.Coeffs[j]
+
+			for y := uint64(0); y < contextP.N; y++ {
+				p2tmp[y] += ring.MRed(key0[y], c2tmp[y], pj, mredParams)
+				p3tmp[y] += ring.MRed(key1[y], c2tmp[y], pj, mredParams)
 			}
+		}
+
+		if reduce&7 == 7 {
+			contextQ.ReduceLvl(level, pool2Q, pool2Q)
+			contextQ.ReduceLvl(level, pool3Q, pool3Q)
+			contextP.Reduce(pool2P, pool2P)
+			contextP.Reduce(pool3P, pool3P)
+		}
+
+		reduce++
+	}
+
+	if (reduce-1)&7 != 7 {
+		contextQ.ReduceLvl(level, pool2Q, pool2Q)
+		contextQ.ReduceLvl(level, pool3Q, pool3Q)
+		contextP.Reduce(pool2P, pool2P)
+		contextP.Reduce(pool3P, pool3P)
+	}
+
+	contextQ.InvNTTLvl(level, pool2Q, pool2Q)
+	contextQ.InvNTTLvl(level, pool3Q, pool3Q)
+	contextP.InvNTT(pool2P, pool2P)
+	contextP.InvNTT(pool3P, pool3P)
+
+	// Computes pool2Q = pool2Q/pool2P and pool3Q = pool3Q/pool3P
+	evaluator.baseconverterQ1P.ModDownSplitedPQ(level, pool2Q, pool2P, pool2Q)
+	evaluator.baseconverterQ1P.ModDownSplitedPQ(level, pool3Q, pool3P, pool3Q)
+}
+
+// switchKeysHoisted applies the key-switching procedure of the form [c0 + cx*evakey[0], c1 + cx*evakey[1]] to the automorphism of
+// cx given by the permutation index of the NTT domain, from the CRT decomposition of cx pre-computed by decomposeAndSplitNTT.
+func (evaluator *evaluator) switchKeysHoisted(level uint64, c2QiQDecomp, c2QiPDecomp []*ring.Poly, index []uint64, evakey *SwitchingKey, p0, p1 *ring.Poly) {
+
+	var reduce uint64
+
+	contextQ := evaluator.bfvContext.contextQ
+	contextP := evaluator.bfvContext.contextP
+
+	for i := range evaluator.keyswitchpoolQ {
+		evaluator.keyswitchpoolQ[i].Zero()
+	}
+
+	for i := range evaluator.keyswitchpoolP {
+		evaluator.keyswitchpoolP[i].Zero()
+	}
+
+	c2QiQPermute := evaluator.keyswitchpoolQ[0]
+	c2QiPPermute := evaluator.keyswitchpoolP[0]
+
+	pool2Q := p0
+	pool2P := evaluator.keyswitchpoolP[1]
+
+	pool3Q := p1
+	pool3P := evaluator.keyswitchpoolP[2]
 
-			key0 := evakey.evakey[i][0].Coeffs[x]
-			key1 := evakey.evakey[i][1].Coeffs[x]
-			p2tmp := p0.Coeffs[x]
-			p3tmp := p1.Coeffs[x]
+	reduce = 0
+
+	// Key switching with CRT decomposition for the Qi
+	for i := range c2QiQDecomp {
+
+		ring.PermuteNTTWithIndex(c2QiQDecomp[i], index, c2QiQPermute)
+		ring.PermuteNTTWithIndex(c2QiPDecomp[i], index, c2QiPPermute)
+
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakey[i][0], c2QiQPermute, pool2Q)
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakey[i][1], c2QiQPermute, pool3Q)
+
+		// We continue with the key-switch primes.
+		for j, keysindex := uint64(0), evaluator.bfvContext.levels; j < uint64(len(contextP.Modulus)); j, keysindex = j+1, keysindex+1 {
+
+			pj := contextP.Modulus[j]
+			mredParams := contextP.GetMredParams()[j]
 
-			for y := uint64(0); y < context.N; y++ {
-				p2tmp[y] += ring.MRed(key0[y], c2QiNtt[y], qi, mredParams)
-				p3tmp[y] += ring.MRed(key1[y], c2QiNtt[y], qi, mredParams)
+			key0 := evakey.evakey[i][0].Coeffs[keysindex]
+			key1 := evakey.evakey[i][1].Coeffs[keysindex]
+			c2tmp := c2QiPPermute.Coeffs[j]
+			p2tmp := pool2P.Coeffs[j]
+			p3tmp := pool3P.Coeffs[j]
+
+			for y := uint64(0); y < contextP.N; y++ {
+				p2tmp[y] += ring.MRed(key0[y], c2tmp[y], pj, mredParams)
+				p3tmp[y] += ring.MRed(key1[y], c2tmp[y], pj, mredParams)
 			}
 		}
 
 		if reduce&7 == 7 {
-			contextKeys.Reduce(p0, p0)
-			contextKeys.Reduce(p1, p1)
+			contextQ.ReduceLvl(level, pool2Q, pool2Q)
+			contextQ.ReduceLvl(level, pool3Q, pool3Q)
+			contextP.Reduce(pool2P, pool2P)
+			contextP.Reduce(pool3P, pool3P)
 		}
 
 		reduce++
 	}
 
 	if (reduce-1)&7 != 7 {
-		contextKeys.Reduce(p0, p0)
-		contextKeys.Reduce(p1, p1)
+		contextQ.ReduceLvl(level, pool2Q, pool2Q)
+		contextQ.ReduceLvl(level, pool3Q, pool3Q)
+		contextP.Reduce(pool2P, pool2P)
+		contextP.Reduce(pool3P, pool3P)
+	}
+
+	contextQ.InvNTTLvl(level, pool2Q, pool2Q)
+	contextQ.InvNTTLvl(level, pool3Q, pool3Q)
+	contextP.InvNTT(pool2P, pool2P)
+	contextP.InvNTT(pool3P, pool3P)
+
+	// Computes pool2Q = pool2Q/pool2P and pool3Q = pool3Q/pool3P
+	evaluator.baseconverterQ1P.ModDownSplitedPQ(level, pool2Q, pool2P, pool2Q)
+	evaluator.baseconverterQ1P.ModDownSplitedPQ(level, pool3Q, pool3P, pool3Q)
+}
+
+// decomposeAndSplitNTT decomposes the input polynomial into the target CRT basis.
+func (evaluator *evaluator) decomposeAndSplitNTT(level, beta uint64, c2NTT, c2InvNTT, c2QiQ, c2QiP *ring.Poly) {
+
+	contextQ := evaluator.bfvContext.contextQ
+	contextP := evaluator.bfvContext.contextP
+
+	evaluator.decomposer.DecomposeAndSplit(level, beta, c2InvNTT, c2QiQ, c2QiP)
+
+	p0idxst := beta * evaluator.params.alpha
+	p0idxed := p0idxst + evaluator.decomposer.Xalpha()[beta]
+
+	// c2_qi = cx mod qi mod qi
+	for x := uint64(0); x < level+1; x++ {
+
+		qi := contextQ.Modulus[x]
+		nttPsi := contextQ.GetNttPsi()[x]
+		bredParams := contextQ.GetBredParams()[x]
+		mredParams := contextQ.GetMredParams()[x]
+
+		if p0idxst <= x && x < p0idxed {
+			p0tmp := c2NTT.Coeffs[x]
+			p1tmp := c2QiQ.Coeffs[x]
+			for j := uint64(0); j < contextQ.N; j++ {
+				p1tmp[j] = p0tmp[j]
+			}
+		} else {
+			ring.NTT(c2QiQ.Coeffs[x], c2QiQ.Coeffs[x], contextQ.N, nttPsi, qi, mredParams, bredParams)
+		}
 	}
+	// c2QiP = c2 mod qi mod pj
+	contextP.NTT(c2QiP, c2QiP)
+}
+
+// ModSwitchNew switches ct0 to the next modulus of the moduli chain (see ModSwitch) and returns the result in a newly created element.
+func (evaluator *evaluator) ModSwitchNew(ct0 *Ciphertext) (ctOut *Ciphertext, err error) {
+
+	ctOut = NewCiphertextLvl(evaluator.params, ct0.Degree(), ct0.Level())
 
-	contextKeys.InvNTT(p0, p0)
-	contextKeys.InvNTT(p1, p1)
+	return ctOut, evaluator.ModSwitch(ct0, ctOut)
+}
+
+// ModSwitch switches ct0 to the next modulus of the moduli chain by dividing it, with rounding, by its last modulus,
+// and returns the result in ctOut. The plaintext is preserved, its scaling factor going from floor(Q/t) to floor((Q/q_level)/t),
+// and the level of ctOut is one less than the level of ct0. Subsequent operations on ctOut are done on one modulus less.
+func (evaluator *evaluator) ModSwitch(ct0 *Ciphertext, ctOut *Ciphertext) (err error) {
+
+	discardSeed(ctOut)
+
+	if ct0.Level() == 0 {
+		return errors.New("cannot ModSwitch: input Ciphertext already at level 0")
+	}
+
+	if ct0.Degree() != ctOut.Degree() {
+		panic("cannot ModSwitch: input and receiver Ciphertexts must be of the same degree")
+	}
+
+	ctOut.Copy(ct0.Element())
+
+	for i := range ctOut.value {
+		evaluator.bfvContext.contextQ.DivRoundByLastModulus(ctOut.value[i])
+	}
+
+	return nil
+}
+
+// DropLevelNew reduces the level of ct0 by levels and returns the result in a newly created element.
+// Each level is dropped by a modulus switch (see ModSwitch).
+func (evaluator *evaluator) DropLevelNew(ct0 *Ciphertext, levels uint64) (ctOut *Ciphertext, err error) {
+
+	ctOut = ct0.CopyNew().Ciphertext()
+
+	return ctOut, evaluator.DropLevel(ctOut, levels)
+}
+
+// DropLevel reduces the level of ct0 by levels and returns the result in ct0.
+// Each level is dropped by a modulus switch (see ModSwitch).
+func (evaluator *evaluator) DropLevel(ct0 *Ciphertext, levels uint64) (err error) {
+
+	discardSeed(ct0)
+
+	if ct0.Level() < levels {
+		return errors.New("cannot DropLevel: Ciphertext level is too small")
+	}
+
+	for i := range ct0.value {
+		evaluator.bfvContext.contextQ.DivRoundByLastModulusMany(ct0.value[i], levels)
+	}
 
-	evaluator.baseconverterQ1P.ModDownPQ(level, p0, p0)
-	evaluator.baseconverterQ1P.ModDownPQ(level, p1, p1)
+	return nil
 }
@@ -0,0 +1,118 @@
+package bfv
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// debugEvaluator is an Evaluator wrapping another Evaluator, which logs the noise budget of the output ciphertext
+// after each multiplication, relinearization and rotation.
+type debugEvaluator struct {
+	Evaluator
+	decryptor Decryptor
+	logger    *log.Logger
+}
+
+// NewDebugEvaluator creates a new Evaluator that performs the homomorphic operations with the given evaluator and
+// logs, with the given logger, the noise budget of the output ciphertext (see Decryptor.NoiseBudget) after each
+// Mul, MulAndAdd, Relinearize, RotateColumns, RotateHoisted and RotateRows. If the logger is nil, the noise budget is logged on the standard error.
+// Since it requires the secret-key, this evaluator is intended for debugging and for the sizing of circuits only.
+func NewDebugEvaluator(evaluator Evaluator, decryptor Decryptor, logger *log.Logger) Evaluator {
+
+	if evaluator == nil || decryptor == nil {
+		panic("cannot NewDebugEvaluator : evaluator and decryptor cannot be nil")
+	}
+
+	if logger == nil {
+		logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+
+	return &debugEvaluator{
+		Evaluator: evaluator,
+		decryptor: decryptor,
+		logger:    logger,
+	}
+}
+
+// ShallowCopy creates a shallow copy of the target debug evaluator, wrapping shallow copies of its evaluator and
+// decryptor and sharing its logger.
+func (evaluator *debugEvaluator) ShallowCopy() Evaluator {
+	return &debugEvaluator{
+		Evaluator: evaluator.Evaluator.ShallowCopy(),
+		decryptor: evaluator.decryptor.ShallowCopy(),
+		logger:    evaluator.logger,
+	}
+}
+
+func (evaluator *debugEvaluator) logNoiseBudget(operation string, ctOut *Ciphertext) {
+	evaluator.logger.Printf("%s : level %d, degree %d, noise budget %.2f bits\n", operation, ctOut.Level(), ctOut.Degree(), evaluator.decryptor.NoiseBudget(ctOut))
+}
+
+// Mul multiplies op0 by op1 and returns the result on ctOut, logging its noise budget.
+func (evaluator *debugEvaluator) Mul(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext) {
+	evaluator.Evaluator.Mul(op0, op1, ctOut)
+	evaluator.logNoiseBudget("Mul", ctOut)
+}
+
+// MulNew multiplies op0 by op1 and returns the result on a new ciphertext, logging its noise budget.
+func (evaluator *debugEvaluator) MulNew(op0 *Ciphertext, op1 Operand) (ctOut *Ciphertext) {
+	ctOut = evaluator.Evaluator.MulNew(op0, op1)
+	evaluator.logNoiseBudget("MulNew", ctOut)
+	return
+}
+
+// MulAndAdd multiplies op0 by op1 and adds the result to ctOut, logging its noise budget.
+func (evaluator *debugEvaluator) MulAndAdd(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext) {
+	evaluator.Evaluator.MulAndAdd(op0, op1, ctOut)
+	evaluator.logNoiseBudget("MulAndAdd", ctOut)
+}
+
+// Relinearize relinearizes ct0 and returns the result on ctOut, logging its noise budget.
+func (evaluator *debugEvaluator) Relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext) {
+	evaluator.Evaluator.Relinearize(ct0, evakey, ctOut)
+	evaluator.logNoiseBudget("Relinearize", ctOut)
+}
+
+// RelinearizeNew relinearizes ct0 and returns the result on a new ciphertext, logging its noise budget.
+func (evaluator *debugEvaluator) RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (ctOut *Ciphertext) {
+	ctOut = evaluator.Evaluator.RelinearizeNew(ct0, evakey)
+	evaluator.logNoiseBudget("RelinearizeNew", ctOut)
+	return
+}
+
+// RotateColumns rotates the columns of ct0 by k positions to the left and returns the result on ctOut, logging its noise budget.
+func (evaluator *debugEvaluator) RotateColumns(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
+	evaluator.Evaluator.RotateColumns(ct0, k, evakey, ctOut)
+	evaluator.logNoiseBudget("RotateColumns", ctOut)
+}
+
+// RotateColumnsNew rotates the columns of ct0 by k positions to the left and returns the result on a new ciphertext, logging its noise budget.
+func (evaluator *debugEvaluator) RotateColumnsNew(ct0 *Ciphertext, k uint64, evakey *RotationKeys) (ctOut *Ciphertext) {
+	ctOut = evaluator.Evaluator.RotateColumnsNew(ct0, k, evakey)
+	evaluator.logNoiseBudget("RotateColumnsNew", ctOut)
+	return
+}
+
+// RotateHoisted rotates the columns of ct0 by each of the given rotations and returns the results on new ciphertexts,
+// logging their noise budget.
+func (evaluator *debugEvaluator) RotateHoisted(ct0 *Ciphertext, rotations []uint64, rotkeys *RotationKeys) (cOut map[uint64]*Ciphertext) {
+	cOut = evaluator.Evaluator.RotateHoisted(ct0, rotations, rotkeys)
+	for k, ctOut := range cOut {
+		evaluator.logNoiseBudget(fmt.Sprintf("RotateHoisted(%d)", k), ctOut)
+	}
+	return
+}
+
+// RotateRows swaps the rows of ct0 and returns the result on ctOut, logging its noise budget.
+func (evaluator *debugEvaluator) RotateRows(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext) {
+	evaluator.Evaluator.RotateRows(ct0, evakey, ctOut)
+	evaluator.logNoiseBudget("RotateRows", ctOut)
+}
+
+// RotateRowsNew swaps the rows of ct0 and returns the result on a new ciphertext, logging its noise budget.
+func (evaluator *debugEvaluator) RotateRowsNew(ct0 *Ciphertext, evakey *RotationKeys) (ctOut *Ciphertext) {
+	ctOut = evaluator.Evaluator.RotateRowsNew(ct0, evakey)
+	evaluator.logNoiseBudget("RotateRowsNew", ctOut)
+	return
+}
@@ -14,14 +14,16 @@ type KeyGenerator interface {
 	GenSwitchingKey(skIn, skOut *SecretKey) (evk *SwitchingKey)
 	GenRot(rotType Rotation, sk *SecretKey, k uint64, rotKey *RotationKeys)
 	GenRotationKeysPow2(sk *SecretKey) (rotKey *RotationKeys)
+	GenRotationKeysForRotations(ks []int, sk *SecretKey) (rotKey *RotationKeys)
 }
 
 // keyGenerator is a structure that stores the elements required to create new keys,
 // as well as a small memory pool for intermediate values.
 type keyGenerator struct {
-	params     *Parameters
-	bfvContext *bfvContext
-	polypool   *ring.Poly
+	params       *Parameters
+	bfvContext   *bfvContext
+	polypool     *ring.Poly
+	crpGenerator *ring.CRPGenerator
 }
 
 // SecretKey is a structure that stores the SecretKey.
@@ -31,7 +33,8 @@ type SecretKey struct {
 
 // PublicKey is a structure that stores the PublicKey.
 type PublicKey struct {
-	pk [2]*ring.Poly
+	pk   [2]*ring.Poly
+	seed *ring.PolySeed
 }
 
 // Rotation is a type used to represent the rotations types.
@@ -44,11 +47,11 @@ const (
 	RotationRow
 )
 
-// RotationKeys is a structure that stores the switching-keys required during the homomorphic rotations.
+// RotationKeys is a structure that stores the switching-keys required during the homomorphic rotations, indexed
+// by the Galois element of their automorphism. Since a rotation of the columns by k positions to the right is a
+// rotation by N/2-k positions to the left, both rotations share the same switching-key.
 type RotationKeys struct {
-	evakeyRotColLeft  map[uint64]*SwitchingKey
-	evakeyRotColRight map[uint64]*SwitchingKey
-	evakeyRotRow      *SwitchingKey
+	keys map[uint64]*SwitchingKey
 }
 
 // EvaluationKey is a structure that stores the switching-keys required during the relinearization.
@@ -59,6 +62,7 @@ type EvaluationKey struct {
 // SwitchingKey is a structure that stores the switching-keys required during the key-switching.
 type SwitchingKey struct {
 	evakey [][2]*ring.Poly
+	seed   *ring.PolySeed
 }
 
 // Get returns the switching key backing slice.
@@ -66,6 +70,11 @@ func (swk *SwitchingKey) Get() [][2]*ring.Poly {
 	return swk.evakey
 }
 
+// IsSeeded returns true if the uniform polynomials of the SwitchingKey are generated from a seed.
+func (swk *SwitchingKey) IsSeeded() bool {
+	return swk.seed != nil
+}
+
 // NewKeyGenerator creates a new KeyGenerator, from which the secret and public keys, as well as the evaluation,
 // rotation and switching keys can be generated.
 func NewKeyGenerator(params *Parameters) KeyGenerator {
@@ -83,6 +92,50 @@ func NewKeyGenerator(params *Parameters) KeyGenerator {
 	}
 }
 
+// NewKeyGeneratorSeeded creates a new KeyGenerator generating seeded keys : the uniform polynomials of the public,
+// evaluation, rotation and switching keys are generated from a random seed, which is stored in the keys. When marshaled,
+// these keys only store the seed in place of the uniform polynomials, which are regenerated from it on unmarshal.
+func NewKeyGeneratorSeeded(params *Parameters) KeyGenerator {
+
+	if !params.isValid {
+		panic("cannot NewKeyGeneratorSeeded: params not valid (check if they were generated properly)")
+	}
+
+	bfvContext := newBFVContext(params)
+
+	return &keyGenerator{
+		params:       params.Copy(),
+		bfvContext:   bfvContext,
+		polypool:     bfvContext.contextQP.NewPoly(),
+		crpGenerator: ring.NewCRPGenerator(nil, bfvContext.contextQP),
+	}
+}
+
+// genUniformPolys returns nbPolys new uniform polynomials in R_QP. If the keyGenerator is seeded, the polynomials are
+// generated from a new random seed, which is also returned, else the returned seed is nil.
+func (keygen *keyGenerator) genUniformPolys(nbPolys uint64) (polys []*ring.Poly, seed *ring.PolySeed) {
+
+	ringContext := keygen.bfvContext.contextQP
+
+	polys = make([]*ring.Poly, nbPolys)
+
+	if keygen.crpGenerator == nil {
+		for i := range polys {
+			polys[i] = ringContext.NewUniformPoly()
+		}
+		return polys, nil
+	}
+
+	seed = ring.NewPolySeed(ringContext.Modulus)
+
+	keygen.crpGenerator.Seed(seed.Seed())
+	for i := range polys {
+		polys[i] = keygen.crpGenerator.ClockNew()
+	}
+
+	return polys, seed
+}
+
 // GenSecretKey creates a new SecretKey with the distribution [1/3, 1/3, 1/3].
 func (keygen *keyGenerator) GenSecretKey() (sk *SecretKey) {
 	return keygen.GenSecretkeyWithDistrib(1.0 / 3)
@@ -127,7 +180,10 @@ func (keygen *keyGenerator) GenPublicKey(sk *SecretKey) (pk *PublicKey) {
 	//pk[0] = [-(a*s + e)]
 	//pk[1] = [a]
 	pk.pk[0] = keygen.bfvContext.gaussianSampler.SampleNTTNew()
-	pk.pk[1] = ringContext.NewUniformPoly()
+
+	a, seed := keygen.genUniformPolys(1)
+	pk.pk[1] = a[0]
+	pk.seed = seed
 
 	ringContext.MulCoeffsMontgomeryAndAdd(sk.sk, pk.pk[1], pk.pk[0])
 	ringContext.Neg(pk.pk[0], pk.pk[0])
@@ -159,6 +215,12 @@ func (pk *PublicKey) Get() [2]*ring.Poly {
 func (pk *PublicKey) Set(p [2]*ring.Poly) {
 	pk.pk[0] = p[0].CopyNew()
 	pk.pk[1] = p[1].CopyNew()
+	pk.seed = nil
+}
+
+// IsSeeded returns true if the uniform polynomial of the PublicKey is generated from a seed.
+func (pk *PublicKey) IsSeeded() bool {
+	return pk.seed != nil
 }
 
 // NewKeyPair generates a new SecretKey with distribution [1/3, 1/3, 1/3] and a corresponding PublicKey.
@@ -295,13 +357,16 @@ func (keygen *keyGenerator) newswitchingkey(skIn, skOut *ring.Poly) (switchkey *
 
 	switchkey.evakey = make([][2]*ring.Poly, keygen.params.beta)
 
+	a, seed := keygen.genUniformPolys(keygen.params.beta)
+	switchkey.seed = seed
+
 	for i := uint64(0); i < keygen.params.beta; i++ {
 
 		// e
 		switchkey.evakey[i][0] = bfvContext.gaussianSampler.SampleNTTNew()
 		ringContext.MForm(switchkey.evakey[i][0], switchkey.evakey[i][0])
 		// a
-		switchkey.evakey[i][1] = ringContext.NewUniformPoly()
+		switchkey.evakey[i][1] = a[i]
 
 		// e + skIn * (qiBarre*qiStar) * 2^w
 		// (qiBarre*qiStar)%qi = 1, else 0
@@ -335,6 +400,7 @@ func (keygen *keyGenerator) newswitchingkey(skIn, skOut *ring.Poly) (switchkey *
 // NewRotationKeys returns a new empty RotationKeys struct.
 func NewRotationKeys() (rotKey *RotationKeys) {
 	rotKey = new(RotationKeys)
+	rotKey.keys = make(map[uint64]*SwitchingKey)
 	return
 }
 
@@ -347,83 +413,109 @@ func (keygen *keyGenerator) GenRot(rotType Rotation, sk *SecretKey, k uint64, ro
 
 	k &= ((keygen.bfvContext.n >> 1) - 1)
 
-	switch rotType {
-	case RotationLeft:
-		if rotKey.evakeyRotColLeft == nil {
-			rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
-		}
-		if rotKey.evakeyRotColLeft[k] == nil && k != 0 {
-			rotKey.evakeyRotColLeft[k] = genrotkey(keygen, sk.Get(), keygen.bfvContext.galElRotColLeft[k])
-		}
-	case RotationRight:
-		if rotKey.evakeyRotColRight == nil {
-			rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
-		}
-		if rotKey.evakeyRotColRight[k] == nil && k != 0 {
-			rotKey.evakeyRotColRight[k] = genrotkey(keygen, sk.Get(), keygen.bfvContext.galElRotColRight[k])
-		}
-	case RotationRow:
-		rotKey.evakeyRotRow = genrotkey(keygen, sk.Get(), keygen.bfvContext.galElRotRow)
+	if k == 0 && rotType != RotationRow {
+		return
 	}
+
+	keygen.genRotKey(sk, galoisElementForRotation(rotType, k, keygen.bfvContext.n), rotKey)
 }
 
 // GenRotationKeysPow2 generates a new struct of RotationKeys that stores the keys of all the left and right powers of two rotations. The provided SecretKey must be the SecretKey used to generate the PublicKey under
 // which the ciphertexts to rotate are encrypted under. rows is a boolean value that indicates if the keys for the row rotation have to be generated.
 func (keygen *keyGenerator) GenRotationKeysPow2(sk *SecretKey) (rotKey *RotationKeys) {
 
-	rotKey = new(RotationKeys)
+	if keygen.bfvContext.contextP == nil {
+		panic("Cannot GenRotationKeysPow2: modulus P is empty")
+	}
 
-	rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
-	rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
+	rotKey = NewRotationKeys()
 
 	for n := uint64(1); n < keygen.bfvContext.n>>1; n <<= 1 {
+		keygen.genRotKey(sk, keygen.bfvContext.galElRotColLeft[n], rotKey)
+		keygen.genRotKey(sk, keygen.bfvContext.galElRotColRight[n], rotKey)
+	}
+
+	keygen.genRotKey(sk, keygen.bfvContext.galElRotRow, rotKey)
+
+	return
+}
 
-		rotKey.evakeyRotColLeft[n] = genrotkey(keygen, sk.Get(), keygen.bfvContext.galElRotColLeft[n])
-		rotKey.evakeyRotColRight[n] = genrotkey(keygen, sk.Get(), keygen.bfvContext.galElRotColRight[n])
+// GenRotationKeysForRotations generates a new struct of RotationKeys that stores one key for each of the given
+// rotations of the columns, so that each of them is done with a single key-switching by RotateColumns. A positive
+// k is a rotation by k positions to the left, and a negative k a rotation by -k positions to the right. The key of
+// the row rotation can be added with GenRot.
+func (keygen *keyGenerator) GenRotationKeysForRotations(ks []int, sk *SecretKey) (rotKey *RotationKeys) {
+
+	if keygen.bfvContext.contextP == nil {
+		panic("Cannot GenRotationKeysForRotations: modulus P is empty")
 	}
 
-	rotKey.evakeyRotRow = genrotkey(keygen, sk.Get(), keygen.bfvContext.galElRotRow)
+	rotKey = NewRotationKeys()
+
+	for _, k := range ks {
+
+		// The two's complement of a negative k is, modulo N/2, the equivalent rotation to the left
+		if kLeft := uint64(k) & ((keygen.bfvContext.n >> 1) - 1); kLeft != 0 {
+			keygen.genRotKey(sk, keygen.bfvContext.galElRotColLeft[kLeft], rotKey)
+		}
+	}
 
 	return
 }
 
+// genRotKey generates the SwitchingKey of the automorphism of Galois element galEl in the target RotationKeys,
+// unless it already stores it.
+func (keygen *keyGenerator) genRotKey(sk *SecretKey, galEl uint64, rotKey *RotationKeys) {
+
+	if rotKey.keys == nil {
+		rotKey.keys = make(map[uint64]*SwitchingKey)
+	}
+
+	if rotKey.keys[galEl] == nil {
+		rotKey.keys[galEl] = genrotkey(keygen, sk.Get(), galEl)
+	}
+}
+
 // SetRotKey populates the target RotationKeys with a new SwitchingKey using the input polynomials.
 func (rotKey *RotationKeys) SetRotKey(rotType Rotation, k uint64, evakey [][2]*ring.Poly) {
+
+	N := uint64(evakey[0][0].GetDegree())
+
+	k &= (N >> 1) - 1
+
+	if k == 0 && rotType != RotationRow {
+		return
+	}
+
+	galEl := galoisElementForRotation(rotType, k, N)
+
+	if rotKey.keys == nil {
+		rotKey.keys = make(map[uint64]*SwitchingKey)
+	}
+
+	if rotKey.keys[galEl] == nil {
+		rotKey.keys[galEl] = new(SwitchingKey)
+		rotKey.keys[galEl].evakey = make([][2]*ring.Poly, len(evakey))
+		for j := range evakey {
+			rotKey.keys[galEl].evakey[j][0] = evakey[j][0].CopyNew()
+			rotKey.keys[galEl].evakey[j][1] = evakey[j][1].CopyNew()
+		}
+	}
+}
+
+// galoisElementForRotation returns the Galois element of the given rotation type and amount, for a ring of degree N.
+func galoisElementForRotation(rotType Rotation, k, N uint64) uint64 {
+
 	switch rotType {
 	case RotationLeft:
-		if rotKey.evakeyRotColLeft == nil {
-			rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
-		}
-		if rotKey.evakeyRotColLeft[k] == nil && k != 0 {
-			rotKey.evakeyRotColLeft[k] = new(SwitchingKey)
-			rotKey.evakeyRotColLeft[k].evakey = make([][2]*ring.Poly, len(evakey))
-			for j := range evakey {
-				rotKey.evakeyRotColLeft[k].evakey[j][0] = evakey[j][0].CopyNew()
-				rotKey.evakeyRotColLeft[k].evakey[j][1] = evakey[j][1].CopyNew()
-			}
-		}
+		return ring.ModExp(GaloisGen, k&((N>>1)-1), N<<1)
 	case RotationRight:
-		if rotKey.evakeyRotColRight == nil {
-			rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
-		}
-		if rotKey.evakeyRotColRight[k] == nil && k != 0 {
-			rotKey.evakeyRotColRight[k] = new(SwitchingKey)
-			rotKey.evakeyRotColRight[k].evakey = make([][2]*ring.Poly, len(evakey))
-			for j := range evakey {
-				rotKey.evakeyRotColRight[k].evakey[j][0] = evakey[j][0].CopyNew()
-				rotKey.evakeyRotColRight[k].evakey[j][1] = evakey[j][1].CopyNew()
-			}
-		}
+		return ring.ModExp(GaloisGen, (N>>1)-(k&((N>>1)-1)), N<<1)
 	case RotationRow:
-		if rotKey.evakeyRotRow == nil {
-			rotKey.evakeyRotRow = new(SwitchingKey)
-			rotKey.evakeyRotRow.evakey = make([][2]*ring.Poly, len(evakey))
-			for j := range evakey {
-				rotKey.evakeyRotRow.evakey[j][0] = evakey[j][0].CopyNew()
-				rotKey.evakeyRotRow.evakey[j][1] = evakey[j][1].CopyNew()
-			}
-		}
+		return (N << 1) - 1
 	}
+
+	panic("cannot galoisElementForRotation: invalid rotation type")
 }
 
 func genrotkey(keygen *keyGenerator, sk *ring.Poly, gen uint64) (switchkey *SwitchingKey) {
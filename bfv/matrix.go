@@ -0,0 +1,315 @@
+package bfv
+
+import (
+	"math"
+	"sort"
+)
+
+// PlaintextMatrix is a struct storing a plaintext N x N matrix M over Z_T, acting on the N slots of a batched plaintext.
+//
+// The slots are arranged as a 2 x (N/2) matrix: the slot of index i*N/2 + j (in the order of EncodeUint) is the
+// column j of the row i. The automorphisms of the ring act on this layout by rotating the columns of both rows
+// (RotateColumns) and by swapping the two rows (RotateRows), so that M is encoded by its N generalized diagonals
+// diag_k[s] = M[s][rot_k(s)], where, for k = r*N/2 + c, rot_k(s) is the slot reached from s by a rotation of the
+// columns by c positions to the left followed, if r = 1, by a swap of the rows. The diagonals are pre-rotated to be
+// evaluated with the baby-step giant-step algorithm, which requires the rotations given by Rotations and RotatesRows.
+type PlaintextMatrix struct {
+	level   uint64
+	rowSize uint64
+	n1      uint64
+	vec     map[uint64]*PlaintextMul
+}
+
+// NewPlaintextMatrix encodes the N x N matrix M over Z_T, given by its rows, on plaintexts at the given level.
+// The diagonals of M that are zero are not encoded.
+func NewPlaintextMatrix(params *Parameters, encoder Encoder, matrix [][]uint64, level uint64) (plaintextMatrix *PlaintextMatrix) {
+
+	n := uint64(1 << params.LogN)
+
+	if uint64(len(matrix)) != n {
+		panic("cannot NewPlaintextMatrix : the matrix must have N rows")
+	}
+
+	for i := range matrix {
+		if uint64(len(matrix[i])) != n {
+			panic("cannot NewPlaintextMatrix : the matrix must have N columns")
+		}
+	}
+
+	diags := make(map[uint64][]uint64)
+
+	for k := uint64(0); k < n; k++ {
+
+		diag := make([]uint64, n)
+
+		isZero := true
+		for s := uint64(0); s < n; s++ {
+			diag[s] = matrix[s][rotateSlot(s, k, n)] % params.T
+			isZero = isZero && diag[s] == 0
+		}
+
+		if !isZero {
+			diags[k] = diag
+		}
+	}
+
+	return NewPlaintextMatrixFromDiagonals(params, encoder, diags, level)
+}
+
+// NewPlaintextMatrixFromDiagonals encodes the given non-zero generalized diagonals of an N x N matrix over Z_T, indexed
+// by k in [0, N) (see PlaintextMatrix), on plaintexts pre-computed for the multiplication (see PlaintextMul) at the
+// given level. The size of the baby-steps is chosen to
+// minimize the number of rotations.
+func NewPlaintextMatrixFromDiagonals(params *Parameters, encoder Encoder, diags map[uint64][]uint64, level uint64) (plaintextMatrix *PlaintextMatrix) {
+
+	n := uint64(1 << params.LogN)
+	rowSize := n >> 1
+
+	if level > params.MaxLevel() {
+		panic("cannot NewPlaintextMatrix : level is larger than the maximum level of the parameters")
+	}
+
+	plaintextMatrix = new(PlaintextMatrix)
+	plaintextMatrix.level = level
+	plaintextMatrix.rowSize = rowSize
+	plaintextMatrix.n1 = findBestBSGSSplit(diags, rowSize)
+	plaintextMatrix.vec = make(map[uint64]*PlaintextMul)
+
+	n1 := plaintextMatrix.n1
+
+	values := make([]uint64, n)
+
+	for k, diag := range diags {
+
+		if k >= n || uint64(len(diag)) != n {
+			panic("cannot NewPlaintextMatrix : the diagonals must be indexed by [0, N) and be of length N")
+		}
+
+		// Pre-rotates the columns of the diagonal by the giant step (to the right)
+		c := k & (rowSize - 1)
+		giant := c - (c % n1)
+		for i := uint64(0); i < n; i += rowSize {
+			for j := uint64(0); j < rowSize; j++ {
+				values[i+((j+giant)&(rowSize-1))] = diag[i+j] % params.T
+			}
+		}
+
+		plaintextMatrix.vec[k] = NewPlaintextMulLvl(params, level)
+		encoder.EncodeUintMul(values, plaintextMatrix.vec[k])
+	}
+
+	return
+}
+
+// Level returns the level of the plaintexts of the PlaintextMatrix.
+func (plaintextMatrix *PlaintextMatrix) Level() uint64 {
+	return plaintextMatrix.level
+}
+
+// Rotations returns the list, in ascending order, of the rotations of the columns (to the left) required to multiply
+// a ciphertext by the PlaintextMatrix. The corresponding rotation keys can be generated with GenRotationKeysForRotations.
+func (plaintextMatrix *PlaintextMatrix) Rotations() (rotations []int) {
+
+	rotMap := make(map[uint64]bool)
+
+	n1 := plaintextMatrix.n1
+
+	for k := range plaintextMatrix.vec {
+		c := k & (plaintextMatrix.rowSize - 1)
+		rotMap[c%n1] = true
+		rotMap[c-(c%n1)] = true
+	}
+
+	delete(rotMap, 0)
+
+	rotations = make([]int, 0, len(rotMap))
+	for k := range rotMap {
+		rotations = append(rotations, int(k))
+	}
+
+	sort.Ints(rotations)
+
+	return
+}
+
+// RotatesRows returns true if the multiplication of a ciphertext by the PlaintextMatrix requires the rotation of the
+// rows, whose key can be generated with GenRot.
+func (plaintextMatrix *PlaintextMatrix) RotatesRows() bool {
+	for k := range plaintextMatrix.vec {
+		if k >= plaintextMatrix.rowSize {
+			return true
+		}
+	}
+	return false
+}
+
+// MulMatrixNew multiplies the plaintext matrix by the vector of the slots of ct0 and returns the result on a new
+// ciphertext (see MulMatrix).
+func (evaluator *evaluator) MulMatrixNew(ct0 *Ciphertext, matrix *PlaintextMatrix, rotKeys *RotationKeys) (ctOut *Ciphertext) {
+	ctOut = NewCiphertextLvl(evaluator.params, 1, ct0.Level())
+	evaluator.MulMatrix(ct0, matrix, rotKeys, ctOut)
+	return
+}
+
+// MulMatrix multiplies the plaintext matrix by the vector of the slots of ct0 with the baby-step giant-step algorithm,
+// the baby-step rotations being hoisted (see RotateHoisted), and returns the result on ctOut. If ct0 is at a higher level than the matrix, it is first brought to the level of the
+// matrix (see DropLevel). The RotationKeys must store the rotations given by matrix.Rotations() and, if
+// matrix.RotatesRows(), the rotation of the rows.
+func (evaluator *evaluator) MulMatrix(ct0 *Ciphertext, matrix *PlaintextMatrix, rotKeys *RotationKeys, ctOut *Ciphertext) {
+
+	discardSeed(ctOut)
+
+	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
+		panic("cannot MulMatrix: input and output must be of degree 1")
+	}
+
+	if ct0.Level() < matrix.level {
+		panic("cannot MulMatrix: input level is smaller than the level of the matrix")
+	}
+
+	ctIn := ct0
+	if ct0.Level() > matrix.level {
+		ctIn, _ = evaluator.DropLevelNew(ct0, ct0.Level()-matrix.level)
+	}
+
+	level := ctIn.Level()
+
+	rowSize := evaluator.bfvContext.n >> 1
+	n1 := matrix.n1
+
+	// Maps each giant step to the indexes of its diagonals
+	index := make(map[uint64][]uint64)
+	babies := [2]map[uint64]bool{make(map[uint64]bool), make(map[uint64]bool)}
+	for k := range matrix.vec {
+		c := k & (rowSize - 1)
+		giant := c - (c % n1)
+		index[giant] = append(index[giant], k)
+		babies[k/rowSize][c%n1] = true
+	}
+
+	giants := make([]uint64, 0, len(index))
+	for giant := range index {
+		sort.Slice(index[giant], func(i, j int) bool { return index[giant][i] < index[giant][j] })
+		giants = append(giants, giant)
+	}
+	sort.Slice(giants, func(i, j int) bool { return giants[i] < giants[j] })
+
+	// Pre-computes the baby-step rotations of the input and of the input with its rows swapped using hoisting
+	var ctRot [2]map[uint64]*Ciphertext
+	for r := range babies {
+
+		if len(babies[r]) == 0 {
+			continue
+		}
+
+		ctRow := ctIn
+		if r == 1 {
+			ctRow = evaluator.RotateRowsNew(ctIn, rotKeys)
+		}
+
+		rotations := make([]uint64, 0, len(babies[r]))
+		for baby := range babies[r] {
+			rotations = append(rotations, baby)
+		}
+
+		ctRot[r] = evaluator.RotateHoisted(ctRow, rotations, rotKeys)
+	}
+
+	acc := NewCiphertextLvl(evaluator.params, 1, level)
+	tmp := NewCiphertextLvl(evaluator.params, 1, level)
+	res := NewCiphertextLvl(evaluator.params, 1, level)
+
+	for j, giant := range giants {
+
+		for i, k := range index[giant] {
+
+			c := k & (rowSize - 1)
+
+			if i == 0 {
+				evaluator.Mul(ctRot[k/rowSize][c%n1], matrix.vec[k], acc)
+			} else {
+				evaluator.Mul(ctRot[k/rowSize][c%n1], matrix.vec[k], tmp)
+				evaluator.Add(acc, tmp, acc)
+			}
+		}
+
+		if giant != 0 {
+			evaluator.RotateColumns(acc, giant, rotKeys, acc)
+		}
+
+		if j == 0 {
+			res.Copy(acc.Element())
+		} else {
+			evaluator.Add(res, acc, res)
+		}
+	}
+
+	ctOut.Copy(res.Element())
+}
+
+// DotProductNew computes the dot product of the vectors of the slots of ct0 and op1 and returns it in every slot of
+// a new ciphertext (see DotProduct).
+func (evaluator *evaluator) DotProductNew(ct0 *Ciphertext, op1 Operand, evakey *EvaluationKey, rotKeys *RotationKeys) (ctOut *Ciphertext) {
+	ctOut = NewCiphertextLvl(evaluator.params, 1, ct0.Level())
+	evaluator.DotProduct(ct0, op1, evakey, rotKeys, ctOut)
+	return
+}
+
+// DotProduct computes the dot product over Z_T of the vectors of the N slots of ct0 and op1, which is batched in the
+// sense that the products of all the pairs of slots are computed at once, and returns it in every slot of ctOut.
+// If op1 is a ciphertext, the product is relinearized with the EvaluationKey, which can be nil otherwise. The
+// RotationKeys must store the left power of two rotations of the columns and the rotation of the rows (see InnerSum).
+func (evaluator *evaluator) DotProduct(ct0 *Ciphertext, op1 Operand, evakey *EvaluationKey, rotKeys *RotationKeys, ctOut *Ciphertext) {
+
+	if ct0.Degree() != 1 || op1.Degree() > 1 || ctOut.Degree() != 1 {
+		panic("cannot DotProduct: inputs must be of degree at most 1 and output must be of degree 1")
+	}
+
+	prod := NewCiphertextLvl(evaluator.params, ct0.Degree()+op1.Degree(), ct0.Level())
+
+	evaluator.Mul(ct0, op1, prod)
+
+	if prod.Degree() > 1 {
+		if evakey == nil {
+			panic("cannot DotProduct: the product of two ciphertexts requires an evaluation key")
+		}
+		evaluator.Relinearize(prod, evakey, prod)
+	}
+
+	evaluator.InnerSum(prod, rotKeys, ctOut)
+}
+
+// rotateSlot returns the slot reached from the slot s of the 2 x (N/2) layout by a rotation of the columns by
+// k mod N/2 positions to the left followed, if k >= N/2, by a swap of the rows.
+func rotateSlot(s, k, n uint64) uint64 {
+	rowSize := n >> 1
+	row := (s / rowSize) ^ (k / rowSize)
+	col := (s + k) & (rowSize - 1)
+	return row*rowSize + col
+}
+
+// findBestBSGSSplit returns the power of two n1 that minimizes the number of rotations of the columns (baby-steps +
+// giant-steps) required to multiply a ciphertext by the matrix represented by the given diagonals.
+func findBestBSGSSplit(diags map[uint64][]uint64, rowSize uint64) (n1 uint64) {
+
+	minRot := uint64(math.MaxUint64)
+
+	for N1 := uint64(1); N1 <= rowSize; N1 <<= 1 {
+
+		babies := make(map[uint64]bool)
+		giants := make(map[uint64]bool)
+
+		for k := range diags {
+			c := k & (rowSize - 1)
+			babies[(k/rowSize)*rowSize+c%N1] = true
+			giants[c-(c%N1)] = true
+		}
+
+		if nbRot := uint64(len(babies) + len(giants)); nbRot < minRot {
+			minRot = nbRot
+			n1 = N1
+		}
+	}
+
+	return
+}
@@ -8,6 +8,7 @@ import (
 type Operand interface {
 	Element() *bfvElement
 	Degree() uint64
+	Level() uint64
 }
 
 // bfvElement is a common struct for Plaintexts and Ciphertexts. It stores a value
@@ -17,8 +18,8 @@ type bfvElement struct {
 	isNTT bool
 }
 
-// newBfvElement creates a new bfvElement of the target degree with zero values.
-func newBfvElement(params *Parameters, degree uint64) *bfvElement {
+// newBfvElement creates a new bfvElement of the target degree and level with zero values.
+func newBfvElement(params *Parameters, degree, level uint64) *bfvElement {
 
 	if !params.isValid {
 		panic("cannot newBfvElement: params not valid (check if they were generated properly)")
@@ -27,13 +28,13 @@ func newBfvElement(params *Parameters, degree uint64) *bfvElement {
 	el := new(bfvElement)
 	el.value = make([]*ring.Poly, degree+1)
 	for i := uint64(0); i < degree+1; i++ {
-		el.value[i] = ring.NewPoly(1<<params.LogN, uint64(len(params.LogQi)))
+		el.value[i] = ring.NewPoly(1<<params.LogN, level+1)
 	}
 	el.isNTT = true
 	return el
 }
 
-func newBfvElementRandom(params *Parameters, degree uint64) *bfvElement {
+func newBfvElementRandom(params *Parameters, degree, level uint64) *bfvElement {
 
 	if !params.isValid {
 		panic("cannot newBfvElementRandom: params not valid (check if they were generated properly)")
@@ -42,7 +43,7 @@ func newBfvElementRandom(params *Parameters, degree uint64) *bfvElement {
 	el := new(bfvElement)
 	el.value = make([]*ring.Poly, degree+1)
 	for i := uint64(0); i < degree+1; i++ {
-		el.value[i] = ring.NewPolyUniform(1<<params.LogN, uint64(len(params.LogQi)))
+		el.value[i] = ring.NewPolyUniform(1<<params.LogN, level+1)
 	}
 	el.isNTT = true
 	return el
@@ -63,17 +64,35 @@ func (el *bfvElement) Degree() uint64 {
 	return uint64(len(el.value) - 1)
 }
 
+// Level returns the level of the target bfvElement, i.e. the index of the last modulus of its moduli chain.
+func (el *bfvElement) Level() uint64 {
+	return uint64(len(el.value[0].Coeffs) - 1)
+}
+
+// setLevel sets the level of the target bfvElement by reslicing the moduli of its polynomials. It does not
+// modify the coefficients and requires the underlying polynomials to have been allocated for at least the
+// input level.
+func (el *bfvElement) setLevel(level uint64) {
+	for i := range el.value {
+		if uint64(cap(el.value[i].Coeffs)) < level+1 {
+			panic("cannot setLevel: element was not allocated for the target level")
+		}
+		el.value[i].Coeffs = el.value[i].Coeffs[:level+1]
+	}
+}
+
 // Resize resizes the target bfvElement degree to the degree given as input. If the input degree is bigger, then
-// it will append new empty polynomials; if the degree is smaller, it will delete polynomials until the degree matches
-// the input degree.
+// it will append new empty polynomials at the level of the target bfvElement; if the degree is smaller, it will delete
+// polynomials until the degree matches the input degree.
 func (el *bfvElement) Resize(params *Parameters, degree uint64) {
 	if el.Degree() > degree {
 		el.value = el.value[:degree+1]
 	} else if el.Degree() < degree {
+		level := el.Level()
 		for el.Degree() < degree {
 			el.value = append(el.value, []*ring.Poly{new(ring.Poly)}...)
-			el.value[el.Degree()].Coeffs = make([][]uint64, len(params.LogQi))
-			for i := 0; i < len(params.LogQi); i++ {
+			el.value[el.Degree()].Coeffs = make([][]uint64, level+1)
+			for i := uint64(0); i < level+1; i++ {
 				el.value[el.Degree()].Coeffs[i] = make([]uint64, uint64(1<<params.LogN))
 			}
 		}
@@ -105,9 +124,11 @@ func (el *bfvElement) CopyNew() *bfvElement {
 	return ctxCopy
 }
 
-// Copy copies the value and parameters of the input on the target bfvElement.
+// Copy copies the value and parameters of the input on the target bfvElement. The level of the
+// target bfvElement is set to the level of the input.
 func (el *bfvElement) Copy(ctxCopy *bfvElement) {
 	if el != ctxCopy {
+		el.setLevel(ctxCopy.Level())
 		for i := range ctxCopy.Value() {
 			el.Value()[i].Copy(ctxCopy.Value()[i])
 		}
@@ -146,7 +167,7 @@ func (el *bfvElement) Element() *bfvElement {
 }
 
 func (el *bfvElement) Ciphertext() *Ciphertext {
-	return &Ciphertext{el}
+	return &Ciphertext{bfvElement: el}
 }
 
 func (el *bfvElement) Plaintext() *Plaintext {
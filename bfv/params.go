@@ -43,7 +43,8 @@ const (
 	PN15QP880
 )
 
-// DefaultParams is a set of default BFV parameters ensuring 128 bit security.
+// DefaultParams is a set of default BFV parameters ensuring 128 bit security (see SecurityLevel). Parameters sized
+// for a given circuit can instead be generated with GenParameters.
 var DefaultParams = []*Parameters{
 
 	//logQ1+P = 109
@@ -178,6 +179,11 @@ func NewParametersFromLogModuli(LogN, T uint64, logModuli LogModuli, sigma float
 	return
 }
 
+// MaxLevel returns #Qi -1
+func (p *Parameters) MaxLevel() uint64 {
+	return uint64(len(p.Qi) - 1)
+}
+
 // Alpha returns #Pi.
 func (p *Parameters) Alpha() uint64 {
 	return p.alpha
@@ -25,3 +25,45 @@ func TestParams_BinaryMarshaller(t *testing.T) {
 		}
 	})
 }
+
+func TestSecurityLevel(t *testing.T) {
+	for _, params := range DefaultParams {
+		securityLevel, err := SecurityLevel(params)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(128), securityLevel)
+	}
+}
+
+func TestGenParameters(t *testing.T) {
+
+	for _, depth := range []uint64{0, 1, 3} {
+
+		parameters, err := GenParameters(65537, depth, 128)
+		assert.Nil(t, err)
+		securityLevel, err := SecurityLevel(parameters)
+		assert.Nil(t, err)
+		assert.True(t, securityLevel >= 128)
+
+		params := genBfvParams(parameters)
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+		for i := uint64(0); i < depth; i++ {
+			ciphertext = params.evaluator.RelinearizeNew(params.evaluator.MulNew(ciphertext, ciphertext), rlk)
+			params.bfvContext.contextT.MulCoeffs(values, values, values)
+		}
+
+		assert.True(t, params.decryptor.NoiseBudget(ciphertext) > 0)
+		verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+	}
+
+	_, err := GenParameters(65537, 1, 100)
+	assert.NotNil(t, err)
+
+	_, err = GenParameters(65536, 1, 128)
+	assert.NotNil(t, err)
+
+	_, err = GenParameters(65537, 100, 128)
+	assert.NotNil(t, err)
+}
@@ -10,15 +10,73 @@ type Plaintext struct {
 	value *ring.Poly
 }
 
-// NewPlaintext creates a new plaintext from the target context.
+// NewPlaintext creates a new plaintext from the target context, at the maximum level.
 func NewPlaintext(params *Parameters) *Plaintext {
 
 	if !params.isValid {
 		panic("cannot NewPlaintext: params not valid (check if they were generated properly)")
 	}
 
-	plaintext := &Plaintext{newBfvElement(params, 0), nil}
+	return newPlaintext(params, params.MaxLevel())
+}
+
+// NewPlaintextLvl creates a new plaintext from the target context at the given level. A plaintext can only
+// be combined with ciphertexts of the same level.
+func NewPlaintextLvl(params *Parameters, level uint64) *Plaintext {
+
+	if !params.isValid {
+		panic("cannot NewPlaintextLvl: params not valid (check if they were generated properly)")
+	}
+
+	if level > params.MaxLevel() {
+		panic("cannot NewPlaintextLvl: level is larger than the maximum level of the parameters")
+	}
+
+	return newPlaintext(params, level)
+}
+
+func newPlaintext(params *Parameters, level uint64) *Plaintext {
+	plaintext := &Plaintext{newBfvElement(params, 0, level), nil}
 	plaintext.value = plaintext.bfvElement.value[0]
 	plaintext.isNTT = false
 	return plaintext
 }
+
+// PlaintextMul is a plaintext pre-computed for the multiplication with ciphertexts: its message is stored without
+// the scaling by Q/T, in the NTT domain and in the Montgomery form, so that Mul multiplies it directly with the
+// ciphertexts. It can only be used as the second operand of Mul and MulAndAdd: the other methods of the Evaluator
+// panic on a PlaintextMul.
+type PlaintextMul struct {
+	*Plaintext
+}
+
+// NewPlaintextMul creates a new PlaintextMul from the target context, at the maximum level.
+func NewPlaintextMul(params *Parameters) *PlaintextMul {
+
+	if !params.isValid {
+		panic("cannot NewPlaintextMul: params not valid (check if they were generated properly)")
+	}
+
+	return newPlaintextMul(params, params.MaxLevel())
+}
+
+// NewPlaintextMulLvl creates a new PlaintextMul from the target context at the given level. A PlaintextMul can
+// only be multiplied with ciphertexts of the same level.
+func NewPlaintextMulLvl(params *Parameters, level uint64) *PlaintextMul {
+
+	if !params.isValid {
+		panic("cannot NewPlaintextMulLvl: params not valid (check if they were generated properly)")
+	}
+
+	if level > params.MaxLevel() {
+		panic("cannot NewPlaintextMulLvl: level is larger than the maximum level of the parameters")
+	}
+
+	return newPlaintextMul(params, level)
+}
+
+func newPlaintextMul(params *Parameters, level uint64) *PlaintextMul {
+	plaintext := &PlaintextMul{newPlaintext(params, level)}
+	plaintext.isNTT = true
+	return plaintext
+}
@@ -0,0 +1,229 @@
+package bfv
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/ldsec/lattigo/ring"
+	"github.com/ldsec/lattigo/utils"
+)
+
+// EvaluatePoly evaluates the polynomial coeffs[0] + coeffs[1]*x + coeffs[2]*x^2 + ..., whose coefficients are taken
+// modulo T, on each slot of ct0 and returns the result on a new ciphertext of degree 1.
+//
+// The evaluation uses the Paterson-Stockmeyer algorithm: the polynomial is recursively split along the powers
+// x^(2^i) of the input (the giant steps) into polynomials of degree at most 2^L, which are evaluated as linear
+// combinations of x, x^2, ..., x^(2^L) (the baby steps). This requires about 2^L + deg/2^L ciphertext
+// multiplications and a multiplicative depth of ceil(log2(deg)). The relinearization is lazy: the sums of products
+// are relinearized once, before being multiplied again, instead of after each multiplication. The powers of the input
+// that are only multiplied by zero coefficients are neither computed nor used.
+func (evaluator *evaluator) EvaluatePoly(ct0 *Ciphertext, coeffs []uint64, evakey *EvaluationKey) (ctOut *Ciphertext) {
+
+	if ct0.Degree() != 1 {
+		panic("cannot EvaluatePoly : input ciphertext must be of degree 1")
+	}
+
+	T := evaluator.params.T
+
+	coeffsMap := make(map[uint64]uint64)
+
+	var degree uint64
+	for i := range coeffs {
+		if c := coeffs[i] % T; c != 0 {
+			coeffsMap[uint64(i)] = c
+			degree = uint64(i)
+		}
+	}
+
+	L := utils.MaxUint64(uint64(bits.Len64(degree))>>1, 1)
+
+	polyEval := &polynomialEvaluator{
+		evaluator: evaluator,
+		evakey:    evakey,
+		C:         map[uint64]*Ciphertext{1: ct0.CopyNew().Ciphertext()},
+		L:         L,
+		level:     ct0.Level(),
+	}
+
+	var M uint64
+	if degree > 0 {
+		M = uint64(bits.Len64(degree - 1))
+	}
+
+	ctOut = polyEval.recurse(degree, M, coeffsMap)
+
+	evaluator.Relinearize(ctOut, evakey, ctOut)
+
+	return
+}
+
+// polynomialEvaluator is a struct storing the power basis of the evaluation of a polynomial with the
+// Paterson-Stockmeyer algorithm, along with the log2 of the number of baby steps.
+type polynomialEvaluator struct {
+	*evaluator
+	evakey *EvaluationKey
+	C      map[uint64]*Ciphertext
+	L      uint64
+	level  uint64
+}
+
+// computePowerBasis computes the n-th power of the input, C[n] = C[a] * C[b] with a = ceil(n/2) and b = floor(n/2),
+// and relinearizes it.
+func (polyEval *polynomialEvaluator) computePowerBasis(n uint64) {
+
+	if polyEval.C[n] == nil {
+
+		a := (n + 1) >> 1
+		b := n >> 1
+
+		polyEval.computePowerBasis(a)
+		polyEval.computePowerBasis(b)
+
+		polyEval.C[n] = polyEval.MulNew(polyEval.C[a], polyEval.C[b])
+		polyEval.Relinearize(polyEval.C[n], polyEval.evakey, polyEval.C[n])
+	}
+}
+
+// splitCoeffs splits a polynomial p such that p = q*x^degree + r.
+func splitCoeffs(coeffs map[uint64]uint64, degree, maxDegree uint64) (coeffsq, coeffsr map[uint64]uint64) {
+
+	coeffsr = make(map[uint64]uint64)
+	coeffsq = make(map[uint64]uint64)
+
+	for i := range coeffs {
+		if i < degree {
+			coeffsr[i] = coeffs[i]
+		} else if i <= maxDegree {
+			coeffsq[i-degree] = coeffs[i]
+		}
+	}
+
+	return
+}
+
+// recurse recursively evaluates the polynomial p = q*x^(2^(M-1)) + r and returns the result, which is not
+// relinearized.
+func (polyEval *polynomialEvaluator) recurse(maxDegree, M uint64, coeffs map[uint64]uint64) (res *Ciphertext) {
+
+	if maxDegree <= (1 << polyEval.L) {
+		return polyEval.evaluatePolyFromPowerBasis(coeffs)
+	}
+
+	for 1<<(M-1) > maxDegree {
+		M--
+	}
+
+	giant := uint64(1) << (M - 1)
+
+	coeffsq, coeffsr := splitCoeffs(coeffs, giant, maxDegree)
+
+	polyEval.computePowerBasis(giant)
+
+	// The quotient is relinearized only once, before its multiplication by the giant step
+	q := polyEval.recurse(maxDegree-giant, M-1, coeffsq)
+	polyEval.Relinearize(q, polyEval.evakey, q)
+
+	res = polyEval.recurse(giant-1, M-1, coeffsr)
+
+	polyEval.MulAndAdd(q, polyEval.C[giant], res)
+
+	return
+}
+
+// evaluatePolyFromPowerBasis evaluates the polynomial, of degree at most the number of baby steps, as a linear
+// combination of the power basis. The coefficients larger than T/2 are multiplied as their negative centered
+// representative, which reduces the growth of the noise.
+func (polyEval *polynomialEvaluator) evaluatePolyFromPowerBasis(coeffs map[uint64]uint64) (res *Ciphertext) {
+
+	T := polyEval.params.T
+
+	res = NewCiphertextLvl(polyEval.params, 1, polyEval.level)
+
+	tmp := NewCiphertextLvl(polyEval.params, 1, polyEval.level)
+
+	for key, c := range coeffs {
+
+		if key == 0 {
+			polyEval.addScalar(res, c)
+			continue
+		}
+
+		polyEval.computePowerBasis(key)
+
+		if c > T>>1 {
+			polyEval.MulScalar(polyEval.C[key], T-c, tmp)
+			polyEval.Sub(res, tmp, res)
+		} else {
+			polyEval.MulScalar(polyEval.C[key], c, tmp)
+			polyEval.Add(res, tmp, res)
+		}
+	}
+
+	return
+}
+
+// addScalar adds the scalar, given modulo T, to each slot of ct0, by adding the scalar scaled by floor(Q/T) to the
+// constant coefficient of its first polynomial, Q being the product of the moduli up to the level of ct0.
+func (evaluator *evaluator) addScalar(ct0 *Ciphertext, scalar uint64) {
+
+	level := ct0.Level()
+
+	Q := big.NewInt(1)
+	for _, qi := range evaluator.bfvContext.contextQ.Modulus[:level+1] {
+		Q.Mul(Q, ring.NewUint(qi))
+	}
+
+	delta := new(big.Int).Quo(Q, ring.NewUint(evaluator.params.T))
+	delta.Mul(delta, ring.NewUint(scalar))
+
+	tmp := new(big.Int)
+	for i, qi := range evaluator.bfvContext.contextQ.Modulus[:level+1] {
+		coeffs := ct0.value[0].Coeffs[i]
+		coeffs[0] = ring.CRed(coeffs[0]+tmp.Mod(delta, ring.NewUint(qi)).Uint64(), qi)
+	}
+}
+
+// Interpolate returns the coefficients, modulo T, of the unique polynomial of degree at most T-1 that is equal to
+// the function on each element of Z_T, T being a prime, such that the function can be evaluated on the slots of a
+// ciphertext with EvaluatePoly. The polynomial is returned with its smallest degree, and can be of degree up to T-1.
+// The interpolation takes O(T^2) operations.
+func Interpolate(function func(uint64) uint64, T uint64) (coeffs []uint64) {
+
+	if !ring.IsPrime(T) {
+		panic("cannot Interpolate : T must be prime")
+	}
+
+	bredParams := ring.BRedParams(T)
+
+	// The Lagrange polynomial of a in Z_T is 1 - (x-a)^(T-1) = 1 - sum_{k=0}^{T-1} a^(T-1-k) * x^k, hence the constant
+	// coefficient of the interpolation is function(0) and its k-th coefficient, for k > 0, is -sum_{a} function(a) * a^(T-1-k)
+	coeffs = make([]uint64, T)
+
+	for a := uint64(0); a < T; a++ {
+
+		y := function(a) % T
+
+		if y == 0 {
+			continue
+		}
+
+		if a == 0 {
+			coeffs[0] = y
+		}
+
+		// Subtracts function(a) * a^j from the coefficient T-1-j
+		for j := uint64(0); j < T-1; j++ {
+			coeffs[T-1-j] = ring.CRed(coeffs[T-1-j]+T-y, T)
+			if y = ring.BRed(y, a, T, bredParams); y == 0 {
+				break
+			}
+		}
+	}
+
+	degree := T - 1
+	for degree > 0 && coeffs[degree] == 0 {
+		degree--
+	}
+
+	return coeffs[:degree+1]
+}
@@ -0,0 +1,98 @@
+package bfv
+
+import (
+	"fmt"
+	"github.com/ldsec/lattigo/ring"
+	"github.com/ldsec/lattigo/utils"
+	"math/bits"
+)
+
+// noiseBitsPerLevel is a bound on the number of bits, in addition to log2(T) + max(log2(T), LogN), by which the
+// encryption increases the invariant noise, and, in addition to log2(T) + LogN, by which each multiplication followed
+// by a relinearization increases the invariant noise.
+const noiseBitsPerLevel = 7
+
+// SecurityLevel returns the highest security level (128, 192 or 256 bits) ensured by the parameters for the uniform
+// ternary secrets generated by the KeyGenerator (see ring.SecurityLevel). It returns 0 if the parameters ensure none of
+// these security levels, and ring.ErrSecurityNotEstimated if their ring degree is not covered by the Homomorphic
+// Encryption Standard (LogN larger than 15).
+func SecurityLevel(params *Parameters) (uint64, error) {
+
+	if !params.isValid {
+		panic("cannot SecurityLevel: params not valid (check if they were generated properly)")
+	}
+
+	return ring.SecurityLevel(params.LogN, ring.Log2OfProduct(params.Qi, params.Pi))
+}
+
+// GenParameters returns the parameters with the smallest ring degree that ensure the given security level (128, 192
+// or 256 bits, see SecurityLevel) and enough noise budget to evaluate a circuit of the given multiplicative depth
+// with the plaintext modulus T, each multiplication being followed by a relinearization. Since the plaintexts are
+// batched, T must be a prime congruent to 1 modulo 2N, which restricts the possible ring degrees. As the security is
+// only estimated up to LogN = 15, the ring degree of the returned parameters is at most 2^15.
+func GenParameters(T, depth, securityLevel uint64) (params *Parameters, err error) {
+
+	if !ring.IsPrime(T) {
+		return nil, fmt.Errorf("cannot GenParameters: T must be a prime")
+	}
+
+	if _, err = ring.MaxLogQP(ring.MaxSecurityLogN, securityLevel); err != nil {
+		return nil, fmt.Errorf("cannot GenParameters: unsupported security level of %d bits", securityLevel)
+	}
+
+	logT := uint64(bits.Len64(T))
+
+	for logN := uint64(1); logN <= MaxLogN; logN++ {
+
+		maxLogQP, errLogN := ring.MaxLogQP(logN, securityLevel)
+		if errLogN != nil || T&((2<<logN)-1) != 1 {
+			continue
+		}
+
+		logQ := logT + utils.MaxUint64(logT, logN) + noiseBitsPerLevel + depth*(logT+logN+noiseBitsPerLevel) + 1
+
+		logModuli := genLogModuli(logQ, logN)
+
+		if len(logModuli.LogQi) > MaxModuliCount || len(logModuli.LogQiMul) > MaxModuliCount || utils.SumUint64(logModuli.LogQi)+utils.SumUint64(logModuli.LogPi) > maxLogQP {
+			continue
+		}
+
+		params = NewParametersFromLogModuli(logN, T, logModuli, 3.2)
+
+		if level, _ := SecurityLevel(params); level >= securityLevel {
+			return params, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot GenParameters: no parameters ensure %d bits of security for a depth of %d with T = %d", securityLevel, depth, T)
+}
+
+// genLogModuli splits a modulus Q of logQ bits into moduli of at most MaxModuliSize bits, and returns them along with
+// the moduli Pi (one for every six moduli Qi, each at least as large as the moduli Qi) and the moduli QiMul (large
+// enough to store the tensoring of two ciphertexts).
+func genLogModuli(logQ, logN uint64) (logModuli LogModuli) {
+
+	nbQi := (logQ + MaxModuliSize - 1) / MaxModuliSize
+	logQi := (logQ + nbQi - 1) / nbQi
+
+	nbPi := (nbQi + 5) / 6
+
+	nbQiMul := (logQ + logN + 2 + MaxModuliSize - 1) / MaxModuliSize
+
+	logModuli.LogQi = make([]uint64, nbQi)
+	for i := range logModuli.LogQi {
+		logModuli.LogQi[i] = logQi
+	}
+
+	logModuli.LogPi = make([]uint64, nbPi)
+	for i := range logModuli.LogPi {
+		logModuli.LogPi[i] = logQi
+	}
+
+	logModuli.LogQiMul = make([]uint64, nbQiMul)
+	for i := range logModuli.LogQiMul {
+		logModuli.LogQiMul[i] = MaxModuliSize
+	}
+
+	return
+}
@@ -0,0 +1,107 @@
+// Package bgv implements a RNS-accelerated version of the Brakerski-Gentry-Vaikuntanathan leveled homomorphic encryption scheme. It provides modular arithmetic over the integers.
+// Contrary to the scale invariant BFV scheme, the noise is managed with modulus switching, which makes the homomorphic multiplications cheaper at lower levels.
+package bgv
+
+import (
+	"github.com/ldsec/lattigo/ring"
+	"math/big"
+)
+
+// GaloisGen is an integer of order N/2 modulo M and that spans Z_M with the integer -1. The j-th ring automorphism takes the root zeta to zeta^(5j).
+// Any other integer or order N/2 modulo M and congruent with 1 modulo 4 could be used instead.
+const GaloisGen uint64 = 5
+
+// bgvContext is a struct which contains all the elements required to instantiate the BGV Scheme. This includes the parameters (polynomial degree, plaintext modulus, ciphertext modulus,
+// Gaussian sampler, polynomial contexts and other parameters required for the homomorphic operations).
+type bgvContext struct {
+	params *Parameters
+
+	// Polynomial degree
+	n uint64
+
+	// Number of available levels
+	levels uint64
+
+	gaussianSampler *ring.KYSampler
+
+	// Polynomial contexts
+	contextT  *ring.Context
+	contextQ  *ring.Context
+	contextP  *ring.Context
+	contextQP *ring.Context
+
+	// T^-1 mod QP
+	tInv *big.Int
+
+	galElRotRow      uint64
+	galElRotColLeft  []uint64
+	galElRotColRight []uint64
+}
+
+func newBGVContext(params *Parameters) (context *bgvContext) {
+
+	if !params.isValid {
+		panic("cannot newBGVContext: params not valid (check if they were generated properly)")
+	}
+
+	context = new(bgvContext)
+	context.params = params.Copy()
+	var err error
+
+	LogN := params.LogN
+	N := uint64(1 << LogN)
+
+	context.n = N
+	context.levels = uint64(len(params.Qi))
+
+	if context.contextT, err = ring.NewContextWithParams(N, []uint64{params.T}); err != nil {
+		panic(err)
+	}
+
+	if context.contextQ, err = ring.NewContextWithParams(N, params.Qi); err != nil {
+		panic(err)
+	}
+
+	if len(params.Pi) != 0 {
+		if context.contextP, err = ring.NewContextWithParams(N, params.Pi); err != nil {
+			panic(err)
+		}
+	}
+
+	if context.contextQP, err = ring.NewContextWithParams(N, append(params.Qi, params.Pi...)); err != nil {
+		panic(err)
+	}
+
+	context.tInv = new(big.Int).ModInverse(ring.NewUint(params.T), context.contextQP.ModulusBigint)
+
+	context.gaussianSampler = context.contextQP.NewKYSampler(params.Sigma, int(6*params.Sigma))
+
+	context.galElRotColLeft = ring.GenGaloisParams(context.n, GaloisGen)
+	context.galElRotColRight = ring.GenGaloisParams(context.n, ring.ModExp(GaloisGen, 2*context.n-1, 2*context.n))
+	context.galElRotRow = 2*context.n - 1
+	return
+}
+
+// divRoundByLastModulusNTT divides p0 by the last modulus of its level and rounds the result
+// such that its residue modulo T is preserved, i.e. it returns (p0 - r)/qi where r = p0 mod qi
+// and r = 0 mod T. Since all the Qi are congruent to 1 modulo T, the encoded plaintext is not
+// modified. The input must be in the NTT domain.
+func (context *bgvContext) divRoundByLastModulusNTT(p0 *ring.Poly) {
+
+	level := uint64(len(p0.Coeffs) - 1)
+
+	context.contextQ.MulScalarBigintLvl(level, p0, context.tInv, p0)
+	context.contextQ.DivRoundByLastModulusNTT(p0)
+	context.contextQ.MulScalarLvl(level-1, p0, context.params.T, p0)
+}
+
+// modDownPQ reduces the basis of p1 from QP to Q and divides it by P, preserving its residue modulo T.
+// The input must be outside of the NTT domain and is modified in the process.
+func (context *bgvContext) modDownPQ(baseconverter *ring.FastBasisExtender, p1, p2 *ring.Poly) {
+
+	level := context.levels - 1
+
+	context.contextQP.MulScalarBigint(p1, context.tInv, p1)
+	baseconverter.ModDownPQ(level, p1, p2)
+	context.contextQ.MulScalarLvl(level, p2, context.params.T, p2)
+}
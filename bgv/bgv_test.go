@@ -0,0 +1,837 @@
+package bgv
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ldsec/lattigo/ring"
+	"github.com/ldsec/lattigo/utils"
+)
+
+func check(t *testing.T, err error) {
+	if err != nil {
+		t.Error(err)
+		log.Fatal(err)
+	}
+}
+
+func testString(opname string, params *Parameters) string {
+	return fmt.Sprintf("%sLogN=%d/logQ=%d/levels=%d", opname, params.LogN, params.logQP, params.MaxLevel()+1)
+}
+
+type bgvParams struct {
+	params      *Parameters
+	bgvContext  *bgvContext
+	encoder     Encoder
+	kgen        KeyGenerator
+	sk          *SecretKey
+	pk          *PublicKey
+	encryptorPk Encryptor
+	encryptorSk Encryptor
+	decryptor   Decryptor
+	evaluator   Evaluator
+}
+
+type bgvTestParameters struct {
+	bgvParameters []*Parameters
+}
+
+var testParams = new(bgvTestParameters)
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+
+	testParams.bgvParameters = []*Parameters{
+		DefaultParams[PN12QP109],
+		DefaultParams[PN13QP218],
+		DefaultParams[PN14QP438],
+		DefaultParams[PN15QP880],
+	}
+}
+
+func TestBGV(t *testing.T) {
+	t.Run("Encoder", testEncoder)
+	t.Run("Encryptor", testEncryptor)
+	t.Run("Evaluator/Add", testEvaluatorAdd)
+	t.Run("Evaluator/Sub", testEvaluatorSub)
+	t.Run("Evaluator/MulScalar", testEvaluatorMulScalar)
+	t.Run("Evaluator/Mul", testEvaluatorMul)
+	t.Run("Evaluator/Rescale", testEvaluatorRescale)
+	t.Run("Evaluator/KeySwitch", testKeySwitch)
+	t.Run("Evaluator/RotateRows", testRotateRows)
+	t.Run("Evaluator/RotateCols", testRotateCols)
+	t.Run("Marshalling", testMarshaller)
+}
+
+func genBgvParams(contextParameters *Parameters) (params *bgvParams) {
+
+	params = new(bgvParams)
+
+	params.params = contextParameters.Copy()
+
+	params.bgvContext = newBGVContext(contextParameters)
+
+	params.kgen = NewKeyGenerator(contextParameters)
+
+	params.sk, params.pk = params.kgen.GenKeyPair()
+
+	params.encoder = NewEncoder(contextParameters)
+
+	params.encryptorPk = NewEncryptorFromPk(contextParameters, params.pk)
+	params.encryptorSk = NewEncryptorFromSk(contextParameters, params.sk)
+	params.decryptor = NewDecryptor(contextParameters, params.sk)
+
+	params.evaluator = NewEvaluator(contextParameters)
+
+	return
+
+}
+
+func newTestVectorsLvl(params *bgvParams, level uint64, encryptor Encryptor, t *testing.T) (coeffs *ring.Poly, plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	coeffs = params.bgvContext.contextT.NewUniformPoly()
+
+	plaintext = NewPlaintext(params.params, level)
+
+	params.encoder.EncodeUint(coeffs.Coeffs[0], plaintext)
+
+	if encryptor != nil {
+		ciphertext = encryptor.EncryptNew(plaintext)
+	}
+
+	return coeffs, plaintext, ciphertext
+}
+
+func newTestVectors(params *bgvParams, encryptor Encryptor, t *testing.T) (coeffs *ring.Poly, plaintext *Plaintext, ciphertext *Ciphertext) {
+	return newTestVectorsLvl(params, params.params.MaxLevel(), encryptor, t)
+}
+
+func verifyTestVectors(params *bgvParams, decryptor Decryptor, coeffs *ring.Poly, element Operand, t *testing.T) {
+
+	var coeffsTest []uint64
+
+	el := element.Element()
+
+	if el.Degree() == 0 {
+
+		coeffsTest = params.encoder.DecodeUint(el.Plaintext())
+
+	} else {
+
+		coeffsTest = params.encoder.DecodeUint(decryptor.DecryptNew(el.Ciphertext()))
+	}
+
+	if utils.EqualSliceUint64(coeffs.Coeffs[0], coeffsTest) != true {
+		t.Errorf("decryption error")
+	}
+}
+
+func testEncoder(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		t.Run(testString("Encode&Decode/", parameters), func(t *testing.T) {
+
+			values, plaintext, _ := newTestVectors(params, nil, t)
+
+			verifyTestVectors(params, params.decryptor, values, plaintext, t)
+		})
+
+		t.Run(testString("Encode&DecodeLvl0/", parameters), func(t *testing.T) {
+
+			values, plaintext, _ := newTestVectorsLvl(params, 0, nil, t)
+
+			verifyTestVectors(params, params.decryptor, values, plaintext, t)
+		})
+
+		t.Run(testString("EncodeInt&DecodeInt/", parameters), func(t *testing.T) {
+
+			slots := params.bgvContext.n
+			T := int64(params.params.T)
+
+			coeffs := make([]int64, slots)
+			for i := range coeffs {
+				coeffs[i] = int64(ring.RandUniform(uint64(T), uint64(2*T))) - T>>1
+			}
+
+			plaintext := NewPlaintext(params.params, params.params.MaxLevel())
+			params.encoder.EncodeInt(coeffs, plaintext)
+
+			coeffsTest := params.encoder.DecodeInt(plaintext)
+
+			for i := range coeffs {
+				if coeffs[i] != coeffsTest[i] {
+					t.Errorf("EncodeInt&DecodeInt error")
+					break
+				}
+			}
+		})
+	}
+}
+
+func testEncryptor(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		t.Run(testString("EncryptFromPk/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+
+		t.Run(testString("EncryptFromPkFast/", parameters), func(t *testing.T) {
+
+			values, plaintext, _ := newTestVectors(params, nil, t)
+
+			verifyTestVectors(params, params.decryptor, values, params.encryptorPk.EncryptFastNew(plaintext), t)
+		})
+
+		t.Run(testString("EncryptFromSk/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorSk, t)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+
+		t.Run(testString("EncryptFromSkFast/", parameters), func(t *testing.T) {
+
+			values, plaintext, _ := newTestVectors(params, nil, t)
+
+			verifyTestVectors(params, params.decryptor, values, params.encryptorSk.EncryptFastNew(plaintext), t)
+		})
+
+		t.Run(testString("EncryptFromPkLvl0/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectorsLvl(params, 0, params.encryptorPk, t)
+
+			if ciphertext.Level() != 0 {
+				t.Errorf("ciphertext level should be 0")
+			}
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+	}
+}
+
+func testEvaluatorAdd(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		t.Run(testString("CtCtInPlace/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Add(ciphertext1, ciphertext2, ciphertext1)
+			params.bgvContext.contextT.Add(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtCtNew/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			ciphertext1 = params.evaluator.AddNew(ciphertext1, ciphertext2)
+			params.bgvContext.contextT.Add(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, plaintext2, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Add(ciphertext1, plaintext2, ciphertext2)
+			params.bgvContext.contextT.Add(values1, values2, values2)
+
+			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+
+			params.evaluator.Add(plaintext2, ciphertext1, ciphertext2)
+
+			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+		})
+
+		t.Run(testString("CtCtDifferentLevels/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectorsLvl(params, 0, params.encryptorPk, t)
+
+			ciphertext1 = params.evaluator.AddNew(ciphertext1, ciphertext2)
+			params.bgvContext.contextT.Add(values1, values2, values1)
+
+			if ciphertext1.Level() != 0 {
+				t.Errorf("ciphertext level should be 0")
+			}
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+	}
+}
+
+func testEvaluatorSub(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		t.Run(testString("CtCtInPlace/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Sub(ciphertext1, ciphertext2, ciphertext1)
+			params.bgvContext.contextT.Sub(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtCtNew/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			ciphertext1 = params.evaluator.SubNew(ciphertext1, ciphertext2)
+			params.bgvContext.contextT.Sub(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, plaintext2, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			valuesWant := params.bgvContext.contextT.NewPoly()
+
+			params.evaluator.Sub(ciphertext1, plaintext2, ciphertext2)
+			params.bgvContext.contextT.Sub(values1, values2, valuesWant)
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext2, t)
+
+			params.evaluator.Sub(plaintext2, ciphertext1, ciphertext2)
+			params.bgvContext.contextT.Sub(values2, values1, valuesWant)
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext2, t)
+		})
+	}
+}
+
+func testEvaluatorMulScalar(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		t.Run(testString("", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			scalar := ring.RandUniform(params.params.T, params.params.T<<1)
+
+			params.evaluator.MulScalar(ciphertext, scalar, ciphertext)
+			params.bgvContext.contextT.MulScalar(values, scalar, values)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+	}
+}
+
+func testEvaluatorMul(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 2)
+
+		t.Run(testString("CtCt/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, ciphertext1.Degree()+ciphertext2.Degree(), ciphertext1.Level())
+			params.evaluator.Mul(ciphertext1, ciphertext2, receiver)
+			params.bgvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+		})
+
+		t.Run(testString("CtPlain/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, plaintext2, _ := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.Mul(ciphertext1, plaintext2, ciphertext1)
+			params.bgvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("Square/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+
+			ciphertext1 = params.evaluator.MulNew(ciphertext1, ciphertext1)
+			params.bgvContext.contextT.MulCoeffs(values1, values1, values1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+		})
+
+		t.Run(testString("Relinearize/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, ciphertext1.Degree()+ciphertext2.Degree(), ciphertext1.Level())
+			params.evaluator.Mul(ciphertext1, ciphertext2, receiver)
+			params.bgvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			receiver2 := params.evaluator.RelinearizeNew(receiver, rlk)
+			verifyTestVectors(params, params.decryptor, values1, receiver2, t)
+
+			params.evaluator.Relinearize(receiver, rlk, receiver)
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+		})
+
+		t.Run(testString("RelinearizeDeg3/", parameters), func(t *testing.T) {
+
+			// The noise of a degree 3 ciphertext requires at least three moduli
+			if parameters.MaxLevel() < 2 {
+				t.Skip()
+			}
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := params.evaluator.MulNew(ciphertext1, ciphertext2)
+			receiver = params.evaluator.MulNew(receiver, ciphertext1)
+
+			params.bgvContext.contextT.MulCoeffs(values1, values2, values2)
+			params.bgvContext.contextT.MulCoeffs(values1, values2, values1)
+
+			params.evaluator.Relinearize(receiver, rlk, receiver)
+
+			if receiver.Degree() != 1 {
+				t.Errorf("ciphertext degree should be 1")
+			}
+
+			verifyTestVectors(params, params.decryptor, values1, receiver, t)
+		})
+	}
+}
+
+func testEvaluatorRescale(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		if parameters.MaxLevel() == 0 {
+			continue
+		}
+
+		params := genBgvParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk, 1)
+
+		t.Run(testString("Rescale/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receiver, err := params.evaluator.RescaleNew(ciphertext)
+			check(t, err)
+
+			if receiver.Level() != ciphertext.Level()-1 {
+				t.Errorf("ciphertext level should be %d", ciphertext.Level()-1)
+			}
+
+			verifyTestVectors(params, params.decryptor, values, receiver, t)
+		})
+
+		t.Run(testString("MulRelinRescale/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorPk, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorPk, t)
+
+			for ciphertext1.Level() != 0 {
+
+				receiver := params.evaluator.MulNew(ciphertext1, ciphertext2)
+				params.evaluator.Relinearize(receiver, rlk, ciphertext1)
+				check(t, params.evaluator.Rescale(ciphertext1, ciphertext1))
+
+				params.bgvContext.contextT.MulCoeffs(values1, values2, values1)
+
+				verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+			}
+
+			if err := params.evaluator.Rescale(ciphertext1, ciphertext1); err == nil {
+				t.Errorf("Rescale at level 0 should return an error")
+			}
+		})
+
+		t.Run(testString("DropLevel/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := params.evaluator.DropLevelNew(ciphertext, ciphertext.Level())
+
+			if receiver.Level() != 0 {
+				t.Errorf("ciphertext level should be 0")
+			}
+
+			verifyTestVectors(params, params.decryptor, values, receiver, t)
+
+			check(t, params.evaluator.DropLevel(ciphertext, 1))
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+	}
+}
+
+func testKeySwitch(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		sk2 := params.kgen.GenSecretKey()
+		decryptorSk2 := NewDecryptor(parameters, sk2)
+		switchKey := params.kgen.GenSwitchingKey(params.sk, sk2)
+
+		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.SwitchKeys(ciphertext, switchKey, ciphertext)
+
+			verifyTestVectors(params, decryptorSk2, values, ciphertext, t)
+		})
+
+		t.Run(testString("New/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			ciphertext = params.evaluator.SwitchKeysNew(ciphertext, switchKey)
+			verifyTestVectors(params, decryptorSk2, values, ciphertext, t)
+		})
+	}
+}
+
+func testRotateRows(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		rotkey := NewRotationKeys()
+		params.kgen.GenRot(RotationRow, params.sk, 0, rotkey)
+
+		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			params.evaluator.RotateRows(ciphertext, rotkey, ciphertext)
+
+			values.Coeffs[0] = append(values.Coeffs[0][params.bgvContext.n>>1:], values.Coeffs[0][:params.bgvContext.n>>1]...)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+
+		t.Run(testString("New/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			ciphertext = params.evaluator.RotateRowsNew(ciphertext, rotkey)
+
+			values.Coeffs[0] = append(values.Coeffs[0][params.bgvContext.n>>1:], values.Coeffs[0][:params.bgvContext.n>>1]...)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+	}
+}
+
+func testRotateCols(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		rotkey := params.kgen.GenRotationKeysPow2(params.sk)
+
+		valuesWant := params.bgvContext.contextT.NewPoly()
+		mask := (params.bgvContext.n >> 1) - 1
+		slots := params.bgvContext.n >> 1
+
+		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, 1, ciphertext.Level())
+			for n := uint64(1); n < slots; n <<= 1 {
+
+				params.evaluator.RotateColumns(ciphertext, n, rotkey, receiver)
+
+				for i := uint64(0); i < slots; i++ {
+					valuesWant.Coeffs[0][i] = values.Coeffs[0][(i+n)&mask]
+					valuesWant.Coeffs[0][i+slots] = values.Coeffs[0][((i+n)&mask)+slots]
+				}
+
+				verifyTestVectors(params, params.decryptor, valuesWant, receiver, t)
+			}
+		})
+
+		t.Run(testString("New/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			for n := uint64(1); n < slots; n <<= 1 {
+
+				receiver := params.evaluator.RotateColumnsNew(ciphertext, n, rotkey)
+
+				for i := uint64(0); i < slots; i++ {
+					valuesWant.Coeffs[0][i] = values.Coeffs[0][(i+n)&mask]
+					valuesWant.Coeffs[0][i+slots] = values.Coeffs[0][((i+n)&mask)+slots]
+				}
+
+				verifyTestVectors(params, params.decryptor, valuesWant, receiver, t)
+			}
+		})
+
+		t.Run(testString("Random/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			receiver := NewCiphertext(parameters, 1, ciphertext.Level())
+			for n := 0; n < 4; n++ {
+
+				rand := ring.RandUniform(slots, mask)
+
+				params.evaluator.RotateColumns(ciphertext, rand, rotkey, receiver)
+
+				for i := uint64(0); i < slots; i++ {
+					valuesWant.Coeffs[0][i] = values.Coeffs[0][(i+rand)&mask]
+					valuesWant.Coeffs[0][i+slots] = values.Coeffs[0][((i+rand)&mask)+slots]
+				}
+
+				verifyTestVectors(params, params.decryptor, valuesWant, receiver, t)
+			}
+		})
+
+		t.Run(testString("InnerSum/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, t)
+
+			T := params.params.T
+
+			var sum uint64
+			for i := range values.Coeffs[0] {
+				sum = (sum + values.Coeffs[0][i]) % T
+			}
+
+			for i := range valuesWant.Coeffs[0] {
+				valuesWant.Coeffs[0][i] = sum
+			}
+
+			params.evaluator.InnerSum(ciphertext, rotkey, ciphertext)
+
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext, t)
+		})
+	}
+}
+
+func testMarshaller(t *testing.T) {
+
+	for _, parameters := range testParams.bgvParameters {
+
+		params := genBgvParams(parameters)
+
+		contextQP := params.bgvContext.contextQP
+
+		t.Run(testString("Ciphertext/", parameters), func(t *testing.T) {
+
+			ciphertextWant := NewCiphertextRandom(parameters, 2, parameters.MaxLevel())
+
+			marshalledCiphertext, err := ciphertextWant.MarshalBinary()
+			check(t, err)
+
+			ciphertextTest := new(Ciphertext)
+			err = ciphertextTest.UnmarshalBinary(marshalledCiphertext)
+			check(t, err)
+
+			for i := range ciphertextWant.value {
+				if !params.bgvContext.contextQ.Equal(ciphertextWant.value[i], ciphertextTest.value[i]) {
+					t.Errorf("marshal Ciphertext")
+				}
+			}
+		})
+
+		t.Run(testString("Sk/", parameters), func(t *testing.T) {
+
+			marshalledSk, err := params.sk.MarshalBinary()
+			check(t, err)
+
+			sk := new(SecretKey)
+			err = sk.UnmarshalBinary(marshalledSk)
+			check(t, err)
+
+			if !contextQP.Equal(sk.sk, params.sk.sk) {
+				t.Errorf("marshal SecretKey")
+			}
+
+		})
+
+		t.Run(testString("Pk/", parameters), func(t *testing.T) {
+
+			marshalledPk, err := params.pk.MarshalBinary()
+			check(t, err)
+
+			pk := new(PublicKey)
+			err = pk.UnmarshalBinary(marshalledPk)
+			check(t, err)
+
+			for k := range params.pk.pk {
+				if !contextQP.Equal(pk.pk[k], params.pk.pk[k]) {
+					t.Errorf("marshal PublicKey element [%d]", k)
+				}
+			}
+		})
+
+		t.Run(testString("EvaluationKey/", parameters), func(t *testing.T) {
+
+			evalkey := params.kgen.GenRelinKey(params.sk, 2)
+			data, err := evalkey.MarshalBinary()
+			check(t, err)
+
+			resEvalKey := new(EvaluationKey)
+			err = resEvalKey.UnmarshalBinary(data)
+			check(t, err)
+
+			for deg := range evalkey.evakey {
+
+				evakeyWant := evalkey.evakey[deg].evakey
+				evakeyTest := resEvalKey.evakey[deg].evakey
+
+				for j := range evakeyWant {
+
+					for k := range evakeyWant[j] {
+						if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+							t.Errorf("marshal EvaluationKey deg %d element [%d][%d]", deg, j, k)
+						}
+					}
+				}
+			}
+		})
+
+		t.Run(testString("SwitchingKey/", parameters), func(t *testing.T) {
+
+			skOut := params.kgen.GenSecretKey()
+
+			switchingKey := params.kgen.GenSwitchingKey(params.sk, skOut)
+			data, err := switchingKey.MarshalBinary()
+			check(t, err)
+
+			resSwitchingKey := new(SwitchingKey)
+			err = resSwitchingKey.UnmarshalBinary(data)
+			check(t, err)
+
+			evakeyWant := switchingKey.evakey
+			evakeyTest := resSwitchingKey.evakey
+
+			for j := range evakeyWant {
+
+				for k := range evakeyWant[j] {
+					if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+						t.Errorf("marshal SwitchingKey element [%d][%d]", j, k)
+					}
+				}
+			}
+		})
+
+		t.Run(testString("RotationKey/", parameters), func(t *testing.T) {
+
+			rotationKey := NewRotationKeys()
+
+			params.kgen.GenRot(RotationRow, params.sk, 0, rotationKey)
+			params.kgen.GenRot(RotationLeft, params.sk, 1, rotationKey)
+			params.kgen.GenRot(RotationLeft, params.sk, 2, rotationKey)
+			params.kgen.GenRot(RotationRight, params.sk, 3, rotationKey)
+			params.kgen.GenRot(RotationRight, params.sk, 5, rotationKey)
+
+			data, err := rotationKey.MarshalBinary()
+			check(t, err)
+
+			resRotationKey := new(RotationKeys)
+			err = resRotationKey.UnmarshalBinary(data)
+			check(t, err)
+
+			for i := uint64(1); i < params.bgvContext.n>>1; i++ {
+
+				if rotationKey.evakeyRotColLeft[i] != nil {
+
+					evakeyWant := rotationKey.evakeyRotColLeft[i].evakey
+					evakeyTest := resRotationKey.evakeyRotColLeft[i].evakey
+
+					for j := range evakeyWant {
+
+						for k := range evakeyWant[j] {
+							if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+								t.Errorf("marshal RotationKey RotateLeft %d element [%d][%d]", i, j, k)
+							}
+						}
+					}
+
+					if !utils.EqualSliceUint64(rotationKey.permuteNTTLeftIndex[i], resRotationKey.permuteNTTLeftIndex[i]) {
+						t.Errorf("marshal RotationKey RotateLeft %d index", i)
+					}
+				}
+
+				if rotationKey.evakeyRotColRight[i] != nil {
+
+					evakeyWant := rotationKey.evakeyRotColRight[i].evakey
+					evakeyTest := resRotationKey.evakeyRotColRight[i].evakey
+
+					for j := range evakeyWant {
+
+						for k := range evakeyWant[j] {
+							if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+								t.Errorf("marshal RotationKey RotateRight %d element [%d][%d]", i, j, k)
+							}
+						}
+					}
+
+					if !utils.EqualSliceUint64(rotationKey.permuteNTTRightIndex[i], resRotationKey.permuteNTTRightIndex[i]) {
+						t.Errorf("marshal RotationKey RotateRight %d index", i)
+					}
+				}
+			}
+
+			if rotationKey.evakeyRotRow != nil {
+
+				evakeyWant := rotationKey.evakeyRotRow.evakey
+				evakeyTest := resRotationKey.evakeyRotRow.evakey
+
+				for j := range evakeyWant {
+
+					for k := range evakeyWant[j] {
+						if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+							t.Errorf("marshal RotationKey RotateRow element [%d][%d]", j, k)
+						}
+					}
+				}
+
+				if !utils.EqualSliceUint64(rotationKey.permuteNTTRowIndex, resRotationKey.permuteNTTRowIndex) {
+					t.Errorf("marshal RotationKey RotateRow index")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package bgv
+
+// Ciphertext is a *ring.Poly array representing a polynomial of degree > 0 with coefficients in R_Q.
+type Ciphertext struct {
+	*bgvElement
+}
+
+// NewCiphertext creates a new ciphertext parameterized by degree and level.
+func NewCiphertext(params *Parameters, degree, level uint64) (ciphertext *Ciphertext) {
+
+	if !params.isValid {
+		panic("cannot NewCiphertext: params not valid (check if they were generated properly)")
+	}
+
+	return &Ciphertext{newBgvElement(params, degree, level)}
+}
+
+// NewCiphertextRandom generates a new uniformly distributed ciphertext of degree and level.
+func NewCiphertextRandom(params *Parameters, degree, level uint64) (ciphertext *Ciphertext) {
+
+	if !params.isValid {
+		panic("cannot NewCiphertextRandom: params not valid (check if they were generated properly)")
+	}
+
+	return &Ciphertext{newBgvElementRandom(params, degree, level)}
+}
@@ -0,0 +1,78 @@
+package bgv
+
+// Decryptor is an interface for decryptors
+type Decryptor interface {
+	// DecryptNew decrypts the input ciphertext and returns the result on a new
+	// plaintext.
+	DecryptNew(ciphertext *Ciphertext) *Plaintext
+
+	// Decrypt decrypts the input ciphertext and returns the result on the
+	// provided receiver plaintext.
+	Decrypt(ciphertext *Ciphertext, plaintext *Plaintext)
+}
+
+// decryptor is a structure used to decrypt ciphertexts. It stores the secret-key.
+type decryptor struct {
+	params     *Parameters
+	bgvContext *bgvContext
+	sk         *SecretKey
+}
+
+// NewDecryptor creates a new Decryptor from the parameters with the secret-key
+// given as input.
+func NewDecryptor(params *Parameters, sk *SecretKey) Decryptor {
+	if !params.isValid {
+		panic("cannot NewDecryptor: params not valid (check if they were generated properly)")
+	}
+
+	if sk.sk.GetDegree() != int(1<<params.LogN) {
+		panic("cannot NewDecryptor: secret_key degree must match context degree")
+	}
+
+	return &decryptor{
+		params:     params.Copy(),
+		bgvContext: newBGVContext(params),
+		sk:         sk,
+	}
+}
+
+func (decryptor *decryptor) DecryptNew(ciphertext *Ciphertext) *Plaintext {
+	plaintext := NewPlaintext(decryptor.params, ciphertext.Level())
+
+	decryptor.Decrypt(ciphertext, plaintext)
+
+	return plaintext
+}
+
+// Decrypt decrypts the ciphertext and returns the result on the provided receiver plaintext,
+// at the level of the ciphertext. Horner method is used for evaluating the decryption.
+func (decryptor *decryptor) Decrypt(ciphertext *Ciphertext, plaintext *Plaintext) {
+
+	ringContext := decryptor.bgvContext.contextQ
+
+	level := ciphertext.Level()
+
+	if plaintext.Level() < level {
+		panic("cannot Decrypt: receiver plaintext level is smaller than the ciphertext level")
+	}
+
+	plaintext.value.Coeffs = plaintext.value.Coeffs[:level+1]
+
+	ringContext.CopyLvl(level, ciphertext.value[ciphertext.Degree()], plaintext.value)
+
+	for i := uint64(ciphertext.Degree()); i > 0; i-- {
+
+		ringContext.MulCoeffsMontgomeryLvl(level, plaintext.value, decryptor.sk.sk, plaintext.value)
+		ringContext.AddLvl(level, plaintext.value, ciphertext.value[i-1], plaintext.value)
+
+		if i&7 == 7 {
+			ringContext.ReduceLvl(level, plaintext.value, plaintext.value)
+		}
+	}
+
+	if (ciphertext.Degree())&7 != 7 {
+		ringContext.ReduceLvl(level, plaintext.value, plaintext.value)
+	}
+
+	plaintext.isNTT = true
+}
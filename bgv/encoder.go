@@ -0,0 +1,231 @@
+package bgv
+
+import (
+	"github.com/ldsec/lattigo/ring"
+	"github.com/ldsec/lattigo/utils"
+	"math/bits"
+)
+
+// Encoder is an interface implementing the encoder.
+type Encoder interface {
+	EncodeUint(coeffs []uint64, plaintext *Plaintext)
+	EncodeInt(coeffs []int64, plaintext *Plaintext)
+	DecodeUint(plaintext *Plaintext) (coeffs []uint64)
+	DecodeInt(plaintext *Plaintext) (coeffs []int64)
+}
+
+// Encoder is a structure that stores the parameters to encode values on a plaintext in a SIMD (Single-Instruction Multiple-Data) fashion.
+type encoder struct {
+	params      *Parameters
+	bgvContext  *bgvContext
+	indexMatrix []uint64
+	polypoolQ   *ring.Poly
+	polypoolT   *ring.Poly
+}
+
+// NewEncoder creates a new encoder from the provided parameters.
+func NewEncoder(params *Parameters) Encoder {
+
+	if !params.isValid {
+		panic("cannot NewEncoder: params not valid (check if they were generated properly)")
+	}
+
+	bgvContext := newBGVContext(params)
+
+	var m, pos, index1, index2 uint64
+
+	slots := bgvContext.n
+
+	indexMatrix := make([]uint64, slots)
+
+	logN := uint64(bits.Len64(bgvContext.n) - 1)
+
+	rowSize := bgvContext.n >> 1
+	m = (bgvContext.n << 1)
+	pos = 1
+
+	for i := uint64(0); i < rowSize; i++ {
+
+		index1 = (pos - 1) >> 1
+		index2 = (m - pos - 1) >> 1
+
+		indexMatrix[i] = utils.BitReverse64(index1, logN)
+		indexMatrix[i|rowSize] = utils.BitReverse64(index2, logN)
+
+		pos *= GaloisGen
+		pos &= (m - 1)
+	}
+
+	return &encoder{
+		params:      params.Copy(),
+		bgvContext:  bgvContext,
+		indexMatrix: indexMatrix,
+		polypoolQ:   bgvContext.contextQ.NewPoly(),
+		polypoolT:   bgvContext.contextT.NewPoly(),
+	}
+}
+
+// EncodeUint encodes an uint64 slice of size at most N on a plaintext.
+func (encoder *encoder) EncodeUint(coeffs []uint64, plaintext *Plaintext) {
+
+	if len(coeffs) > len(encoder.indexMatrix) {
+		panic("cannot EncodeUint: invalid input to encode (number of coefficients must be smaller or equal to the context)")
+	}
+
+	if len(plaintext.value.Coeffs[0]) != len(encoder.indexMatrix) {
+		panic("cannot EncodeUint: invalid plaintext to receive encoding (number of coefficients does not match the context of the encoder)")
+	}
+
+	for i := 0; i < len(coeffs); i++ {
+		encoder.polypoolT.Coeffs[0][encoder.indexMatrix[i]] = coeffs[i] % encoder.params.T
+	}
+
+	for i := len(coeffs); i < len(encoder.indexMatrix); i++ {
+		encoder.polypoolT.Coeffs[0][encoder.indexMatrix[i]] = 0
+	}
+
+	encoder.encodePlaintext(plaintext)
+}
+
+// EncodeInt encodes an int64 slice of size at most N on a plaintext. It also encodes the sign of the given integer (as its inverse modulo the plaintext modulus).
+// The sign will correctly decode as long as the absolute value of the coefficient does not exceed half of the plaintext modulus.
+func (encoder *encoder) EncodeInt(coeffs []int64, plaintext *Plaintext) {
+
+	if len(coeffs) > len(encoder.indexMatrix) {
+		panic("cannot EncodeInt: invalid input to encode (number of coefficients must be smaller or equal to the context)")
+	}
+
+	if len(plaintext.value.Coeffs[0]) != len(encoder.indexMatrix) {
+		panic("cannot EncodeInt: invalid plaintext to receive encoding (number of coefficients does not match the context of the encoder)")
+	}
+
+	for i := 0; i < len(coeffs); i++ {
+
+		if coeffs[i] < 0 {
+			encoder.polypoolT.Coeffs[0][encoder.indexMatrix[i]] = uint64(int64(encoder.params.T) + coeffs[i])
+		} else {
+			encoder.polypoolT.Coeffs[0][encoder.indexMatrix[i]] = uint64(coeffs[i])
+		}
+	}
+
+	for i := len(coeffs); i < len(encoder.indexMatrix); i++ {
+		encoder.polypoolT.Coeffs[0][encoder.indexMatrix[i]] = 0
+	}
+
+	encoder.encodePlaintext(plaintext)
+}
+
+// encodePlaintext maps the slots stored in polypoolT on the coefficients of a polynomial modulo T,
+// lifts this polynomial to the basis Q (at the level of the plaintext) by centering its coefficients
+// and puts the result in the NTT domain.
+func (encoder *encoder) encodePlaintext(p *Plaintext) {
+
+	encoder.bgvContext.contextT.InvNTT(encoder.polypoolT, encoder.polypoolT)
+
+	ringContext := encoder.bgvContext.contextQ
+
+	t := encoder.params.T
+	tHalf := t >> 1
+
+	level := p.Level()
+
+	coeffs := encoder.polypoolT.Coeffs[0]
+
+	for i := uint64(0); i < level+1; i++ {
+		qi := ringContext.Modulus[i]
+		tmp := p.value.Coeffs[i]
+		for j := uint64(0); j < ringContext.N; j++ {
+			if coeffs[j] > tHalf {
+				tmp[j] = qi - (t - coeffs[j])
+			} else {
+				tmp[j] = coeffs[j]
+			}
+		}
+	}
+
+	ringContext.NTTLvl(level, p.value, p.value)
+
+	p.isNTT = true
+}
+
+// DecodeUint decodes a batched plaintext and returns the coefficients in a uint64 slice.
+func (encoder *encoder) DecodeUint(plaintext *Plaintext) (coeffs []uint64) {
+
+	encoder.decodePlaintext(plaintext)
+
+	coeffs = make([]uint64, encoder.bgvContext.n)
+
+	for i := uint64(0); i < encoder.bgvContext.n; i++ {
+		coeffs[i] = encoder.polypoolT.Coeffs[0][encoder.indexMatrix[i]]
+	}
+
+	return
+}
+
+// DecodeInt decodes a batched plaintext and returns the coefficients in an int64 slice. It also decodes the sign (by centering the values around the plaintext
+// modulus).
+func (encoder *encoder) DecodeInt(plaintext *Plaintext) (coeffs []int64) {
+
+	var value int64
+
+	encoder.decodePlaintext(plaintext)
+
+	coeffs = make([]int64, encoder.bgvContext.n)
+
+	modulus := int64(encoder.params.T)
+
+	for i := uint64(0); i < encoder.bgvContext.n; i++ {
+
+		value = int64(encoder.polypoolT.Coeffs[0][encoder.indexMatrix[i]])
+
+		coeffs[i] = value
+
+		if value > modulus>>1 {
+			coeffs[i] -= modulus
+		}
+	}
+
+	return coeffs
+}
+
+// decodePlaintext switches the plaintext down to the level 0, reduces its centered coefficients
+// modulo T and maps them on the slots stored in polypoolT. The input plaintext is not modified.
+func (encoder *encoder) decodePlaintext(p *Plaintext) {
+
+	ringContext := encoder.bgvContext.contextQ
+
+	level := p.Level()
+
+	tmp := &ring.Poly{Coeffs: encoder.polypoolQ.Coeffs[:level+1]}
+
+	ringContext.CopyLvl(level, p.value, tmp)
+
+	if !p.isNTT {
+		ringContext.NTTLvl(level, tmp, tmp)
+	}
+
+	// The modulus switching preserves the plaintext, such that we only need to reduce the
+	// centered coefficients modulo Q0 to obtain the plaintext modulo T
+	for len(tmp.Coeffs) > 1 {
+		encoder.bgvContext.divRoundByLastModulusNTT(tmp)
+	}
+
+	ringContext.InvNTTLvl(0, tmp, tmp)
+
+	q0 := ringContext.Modulus[0]
+	q0Half := q0 >> 1
+	t := encoder.params.T
+
+	coeffsQ := tmp.Coeffs[0]
+	coeffsT := encoder.polypoolT.Coeffs[0]
+
+	for j := uint64(0); j < ringContext.N; j++ {
+		if coeffsQ[j] > q0Half {
+			coeffsT[j] = (t - ((q0 - coeffsQ[j]) % t)) % t
+		} else {
+			coeffsT[j] = coeffsQ[j] % t
+		}
+	}
+
+	encoder.bgvContext.contextT.NTT(encoder.polypoolT, encoder.polypoolT)
+}
@@ -0,0 +1,364 @@
+package bgv
+
+import (
+	"github.com/ldsec/lattigo/ring"
+)
+
+// Encryptor in an interface for encryptors
+//
+// encrypt with pk : ciphertext = [pk[0]*u + m + t*e_0, pk[1]*u + t*e_1]
+// encrypt with sk : ciphertext = [-a*sk + m + t*e, a]
+type Encryptor interface {
+	// EncryptNew encrypts the input plaintext using the stored key and returns
+	// the result on a newly created ciphertext. The encryption is done by first
+	// encrypting zero in QP, dividing by P and then adding the plaintext.
+	EncryptNew(plaintext *Plaintext) *Ciphertext
+
+	// Encrypt encrypts the input plaintext using the stored key, and returns
+	// the result on the receiver ciphertext. The encryption is done by first
+	// encrypting zero in QP, dividing by P and then adding the plaintext.
+	Encrypt(plaintext *Plaintext, ciphertext *Ciphertext)
+
+	// EncryptFastNew encrypts the input plaintext using the stored key and returns
+	// the result on a newly created ciphertext. The encryption is done by first
+	// encrypting zero in Q and then adding the plaintext.
+	EncryptFastNew(plaintext *Plaintext) *Ciphertext
+
+	// EncryptFast encrypts the input plaintext using the stored-key, and returns
+	// the result on the receiver ciphertext. The encryption is done by first
+	// encrypting zero in Q and then adding the plaintext.
+	EncryptFast(plaintext *Plaintext, ciphertext *Ciphertext)
+
+	// EncryptFromCRPNew encrypts the input plaintext using the stored key and returns
+	// the result on a newly created ciphertext. The encryption is done by first encrypting
+	// zero in QP, using the provided polynomial as the uniform polynomial, dividing by P and
+	// then adding the plaintext.
+	EncryptFromCRPNew(plaintext *Plaintext, crp *ring.Poly) *Ciphertext
+
+	// EncryptFromCRP encrypts the input plaintext using the stored key and returns
+	// the result on the receiver ciphertext. The encryption is done by first encrypting
+	// zero in QP, using the provided polynomial as the uniform polynomial, dividing by P and
+	// then adding the plaintext.
+	EncryptFromCRP(plaintext *Plaintext, ciphertetx *Ciphertext, crp *ring.Poly)
+
+	// EncryptFromCRPFastNew encrypts the input plaintext using the stored key and returns
+	// the result on a newly created ciphertext. The encryption is done by first encrypting
+	// zero in Q, using the provided polynomial as the uniform polynomial, and
+	// then adding the plaintext.
+	EncryptFromCRPFastNew(plaintext *Plaintext, crp *ring.Poly) *Ciphertext
+
+	// EncryptFromCRPFast encrypts the input plaintext using the stored key and returns
+	// the result on the receiver ciphertext. The encryption is done by first encrypting
+	// zero in Q, using the provided polynomial as the uniform polynomial, and
+	// then adding the plaintext.
+	EncryptFromCRPFast(plaintext *Plaintext, ciphertetx *Ciphertext, crp *ring.Poly)
+}
+
+// encryptor is a structure that holds the parameters needed to encrypt plaintexts.
+type encryptor struct {
+	params     *Parameters
+	bgvContext *bgvContext
+	polypool   [3]*ring.Poly
+
+	baseconverter *ring.FastBasisExtender
+}
+
+type pkEncryptor struct {
+	encryptor
+	pk *PublicKey
+}
+
+type skEncryptor struct {
+	encryptor
+	sk *SecretKey
+}
+
+// NewEncryptorFromPk creates a new Encryptor with the provided public-key.
+// This encryptor can be used to encrypt plaintexts, using the stored key.
+func NewEncryptorFromPk(params *Parameters, pk *PublicKey) Encryptor {
+	enc := newEncryptor(params)
+
+	if uint64(pk.pk[0].GetDegree()) != uint64(1<<params.LogN) || uint64(pk.pk[1].GetDegree()) != uint64(1<<params.LogN) {
+		panic("error: pk ring degree doesn't match bgvcontext ring degree")
+	}
+
+	return &pkEncryptor{enc, pk}
+}
+
+// NewEncryptorFromSk creates a new Encryptor with the provided secret-key.
+// This encryptor can be used to encrypt plaintexts, using the stored key.
+func NewEncryptorFromSk(params *Parameters, sk *SecretKey) Encryptor {
+	enc := newEncryptor(params)
+
+	if uint64(sk.sk.GetDegree()) != uint64(1<<params.LogN) {
+		panic("error: sk ring degree doesn't match bgvcontext ring degree")
+	}
+
+	return &skEncryptor{enc, sk}
+}
+
+func newEncryptor(params *Parameters) encryptor {
+	if !params.isValid {
+		panic("cannot NewEncryptor: params not valid (check if they were generated properly)")
+	}
+
+	ctx := newBGVContext(params)
+	qp := ctx.contextQP
+
+	var baseconverter *ring.FastBasisExtender
+	if len(params.Pi) != 0 {
+		baseconverter = ring.NewFastBasisExtender(ctx.contextQ, ctx.contextP)
+	}
+
+	return encryptor{
+		params:        params.Copy(),
+		bgvContext:    ctx,
+		polypool:      [3]*ring.Poly{qp.NewPoly(), qp.NewPoly(), qp.NewPoly()},
+		baseconverter: baseconverter,
+	}
+}
+
+// sampleErrorAndAdd samples a Gaussian polynomial, multiplies it by T and adds it on p, which must be outside of the NTT domain.
+func (encryptor *encryptor) sampleErrorAndAdd(ringContext *ring.Context, p *ring.Poly) {
+	tmp := encryptor.polypool[2]
+	encryptor.bgvContext.gaussianSampler.Sample(tmp)
+	ringContext.MulScalar(tmp, encryptor.params.T, tmp)
+	ringContext.Add(p, tmp, p)
+}
+
+// addPlaintext copies the encryption of zero stored in polypool[0] and polypool[1] on the ciphertext
+// at the level of the plaintext, and adds the plaintext on it.
+func (encryptor *encryptor) addPlaintext(plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	ringContext := encryptor.bgvContext.contextQ
+
+	level := plaintext.Level()
+
+	if ciphertext.Degree() != 1 {
+		panic("cannot Encrypt: receiver ciphertext must be of degree 1")
+	}
+
+	ciphertext.dropLevel(level)
+
+	// ct = [-a*s + t*e + m, a]
+	ringContext.AddLvl(level, encryptor.polypool[0], plaintext.value, ciphertext.value[0])
+	ringContext.CopyLvl(level, encryptor.polypool[1], ciphertext.value[1])
+
+	ciphertext.isNTT = true
+}
+
+func (encryptor *pkEncryptor) EncryptNew(plaintext *Plaintext) *Ciphertext {
+
+	if encryptor.baseconverter == nil {
+		panic("Cannot EncryptNew : modulus P is empty -> use instead EncryptFastNew")
+	}
+
+	ciphertext := NewCiphertext(encryptor.params, 1, plaintext.Level())
+	encryptor.encrypt(plaintext, ciphertext, false)
+
+	return ciphertext
+}
+
+func (encryptor *pkEncryptor) Encrypt(plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	if encryptor.baseconverter == nil {
+		panic("Cannot Encrypt : modulus P is empty -> use instead EncryptFast")
+	}
+
+	encryptor.encrypt(plaintext, ciphertext, false)
+}
+
+func (encryptor *pkEncryptor) EncryptFastNew(plaintext *Plaintext) *Ciphertext {
+	ciphertext := NewCiphertext(encryptor.params, 1, plaintext.Level())
+	encryptor.encrypt(plaintext, ciphertext, true)
+
+	return ciphertext
+}
+
+func (encryptor *pkEncryptor) EncryptFast(plaintext *Plaintext, ciphertext *Ciphertext) {
+	encryptor.encrypt(plaintext, ciphertext, true)
+}
+
+func (encryptor *pkEncryptor) EncryptFromCRP(plaintext *Plaintext, ciphertext *Ciphertext, crp *ring.Poly) {
+	panic("Cannot encrypt with CRP using an encryptor created with the public-key")
+}
+
+func (encryptor *pkEncryptor) EncryptFromCRPNew(plaintext *Plaintext, crp *ring.Poly) *Ciphertext {
+	panic("Cannot encrypt with CRP using an encryptor created with the public-key")
+}
+
+func (encryptor *pkEncryptor) EncryptFromCRPFast(plaintext *Plaintext, ciphertext *Ciphertext, crp *ring.Poly) {
+	panic("Cannot encrypt with CRP using an encryptor created with the public-key")
+}
+
+func (encryptor *pkEncryptor) EncryptFromCRPFastNew(plaintext *Plaintext, crp *ring.Poly) *Ciphertext {
+	panic("Cannot encrypt with CRP using an encryptor created with the public-key")
+}
+
+func (encryptor *pkEncryptor) encrypt(plaintext *Plaintext, ciphertext *Ciphertext, fast bool) {
+
+	var ringContext *ring.Context
+
+	if fast {
+		ringContext = encryptor.bgvContext.contextQ
+	} else {
+		ringContext = encryptor.bgvContext.contextQP
+	}
+
+	// u
+	ringContext.SampleTernaryMontgomeryNTT(encryptor.polypool[2], 0.5)
+
+	// ct[0] = pk[0]*u
+	// ct[1] = pk[1]*u
+	ringContext.MulCoeffsMontgomery(encryptor.polypool[2], encryptor.pk.pk[0], encryptor.polypool[0])
+	ringContext.MulCoeffsMontgomery(encryptor.polypool[2], encryptor.pk.pk[1], encryptor.polypool[1])
+
+	ringContext.InvNTT(encryptor.polypool[0], encryptor.polypool[0])
+	ringContext.InvNTT(encryptor.polypool[1], encryptor.polypool[1])
+
+	// ct[0] = pk[0]*u + t*e0
+	// ct[1] = pk[1]*u + t*e1
+	encryptor.sampleErrorAndAdd(ringContext, encryptor.polypool[0])
+	encryptor.sampleErrorAndAdd(ringContext, encryptor.polypool[1])
+
+	if !fast {
+		// We rescale the encryption of zero by the special prime, dividing the error by this prime
+		encryptor.bgvContext.modDownPQ(encryptor.baseconverter, encryptor.polypool[0], encryptor.polypool[0])
+		encryptor.bgvContext.modDownPQ(encryptor.baseconverter, encryptor.polypool[1], encryptor.polypool[1])
+	}
+
+	ringContext = encryptor.bgvContext.contextQ
+
+	ringContext.NTT(encryptor.polypool[0], encryptor.polypool[0])
+	ringContext.NTT(encryptor.polypool[1], encryptor.polypool[1])
+
+	// ct[0] = pk[0]*u + t*e0 + m
+	// ct[1] = pk[1]*u + t*e1
+	encryptor.addPlaintext(plaintext, ciphertext)
+}
+
+func (encryptor *skEncryptor) EncryptNew(plaintext *Plaintext) *Ciphertext {
+
+	if encryptor.baseconverter == nil {
+		panic("Cannot EncryptNew : modulus P is empty -> use instead EncryptFastNew")
+	}
+
+	ciphertext := NewCiphertext(encryptor.params, 1, plaintext.Level())
+	encryptor.Encrypt(plaintext, ciphertext)
+	return ciphertext
+}
+
+func (encryptor *skEncryptor) Encrypt(plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	if encryptor.baseconverter == nil {
+		panic("Cannot Encrypt : modulus P is empty -> use instead EncryptFast")
+	}
+
+	encryptor.encryptSample(plaintext, ciphertext, false)
+}
+
+func (encryptor *skEncryptor) EncryptFastNew(plaintext *Plaintext) *Ciphertext {
+	ciphertext := NewCiphertext(encryptor.params, 1, plaintext.Level())
+	encryptor.EncryptFast(plaintext, ciphertext)
+	return ciphertext
+}
+
+func (encryptor *skEncryptor) EncryptFast(plaintext *Plaintext, ciphertext *Ciphertext) {
+	encryptor.encryptSample(plaintext, ciphertext, true)
+}
+
+func (encryptor *skEncryptor) EncryptFromCRPNew(plaintext *Plaintext, crp *ring.Poly) *Ciphertext {
+
+	if encryptor.baseconverter == nil {
+		panic("Cannot EncryptFromCRPNew : modulus P is empty -> use instead EncryptFromCRPFastNew")
+	}
+
+	ciphertext := NewCiphertext(encryptor.params, 1, plaintext.Level())
+	encryptor.EncryptFromCRP(plaintext, ciphertext, crp)
+	return ciphertext
+}
+
+func (encryptor *skEncryptor) EncryptFromCRP(plaintext *Plaintext, ciphertext *Ciphertext, crp *ring.Poly) {
+
+	if encryptor.baseconverter == nil {
+		panic("Cannot EncryptFromCRP : modulus P is empty -> use instead EncryptFromCRPFast")
+	}
+
+	encryptor.encryptFromCRP(plaintext, ciphertext, crp, false)
+}
+
+func (encryptor *skEncryptor) EncryptFromCRPFastNew(plaintext *Plaintext, crp *ring.Poly) *Ciphertext {
+	ciphertext := NewCiphertext(encryptor.params, 1, plaintext.Level())
+	encryptor.EncryptFromCRPFast(plaintext, ciphertext, crp)
+	return ciphertext
+}
+
+func (encryptor *skEncryptor) EncryptFromCRPFast(plaintext *Plaintext, ciphertext *Ciphertext, crp *ring.Poly) {
+	encryptor.encryptFromCRP(plaintext, ciphertext, crp, true)
+}
+
+func (encryptor *skEncryptor) encryptSample(plaintext *Plaintext, ciphertext *Ciphertext, fast bool) {
+	if fast {
+		encryptor.bgvContext.contextQ.UniformPoly(encryptor.polypool[1])
+	} else {
+		encryptor.bgvContext.contextQP.UniformPoly(encryptor.polypool[1])
+	}
+
+	encryptor.encrypt(plaintext, ciphertext, fast)
+}
+
+func (encryptor *skEncryptor) encryptFromCRP(plaintext *Plaintext, ciphertext *Ciphertext, crp *ring.Poly, fast bool) {
+	if fast {
+		encryptor.bgvContext.contextQ.Copy(crp, encryptor.polypool[1])
+	} else {
+		encryptor.bgvContext.contextQP.Copy(crp, encryptor.polypool[1])
+	}
+
+	encryptor.encrypt(plaintext, ciphertext, fast)
+}
+
+// encrypt encrypts the plaintext using the uniform polynomial stored in polypool[1].
+func (encryptor *skEncryptor) encrypt(plaintext *Plaintext, ciphertext *Ciphertext, fast bool) {
+
+	var ringContext *ring.Context
+
+	if fast {
+		ringContext = encryptor.bgvContext.contextQ
+	} else {
+		ringContext = encryptor.bgvContext.contextQP
+	}
+
+	// ct = [-a*s, a]
+	ringContext.MulCoeffsMontgomery(encryptor.polypool[1], encryptor.sk.sk, encryptor.polypool[0])
+	ringContext.Neg(encryptor.polypool[0], encryptor.polypool[0])
+
+	if fast {
+
+		ringContext.InvNTT(encryptor.polypool[0], encryptor.polypool[0])
+
+		// ct = [-a*s + t*e, a]
+		encryptor.sampleErrorAndAdd(ringContext, encryptor.polypool[0])
+
+		ringContext.NTT(encryptor.polypool[0], encryptor.polypool[0])
+
+	} else {
+
+		ringContext.InvNTT(encryptor.polypool[0], encryptor.polypool[0])
+		ringContext.InvNTT(encryptor.polypool[1], encryptor.polypool[1])
+
+		// ct = [-a*s + t*e, a]
+		encryptor.sampleErrorAndAdd(ringContext, encryptor.polypool[0])
+
+		// We rescale the encryption of zero by the special prime, dividing the error by this prime
+		encryptor.bgvContext.modDownPQ(encryptor.baseconverter, encryptor.polypool[0], encryptor.polypool[0])
+		encryptor.bgvContext.modDownPQ(encryptor.baseconverter, encryptor.polypool[1], encryptor.polypool[1])
+
+		ringContext = encryptor.bgvContext.contextQ
+
+		ringContext.NTT(encryptor.polypool[0], encryptor.polypool[0])
+		ringContext.NTT(encryptor.polypool[1], encryptor.polypool[1])
+	}
+
+	// ct = [-a*s + t*e + m, a]
+	encryptor.addPlaintext(plaintext, ciphertext)
+}
@@ -0,0 +1,833 @@
+package bgv
+
+import (
+	"errors"
+	"github.com/ldsec/lattigo/ring"
+	"github.com/ldsec/lattigo/utils"
+	"math"
+)
+
+// Evaluator is an interface implementing the public methodes of the evaluator.
+type Evaluator interface {
+	Add(op0, op1 Operand, ctOut *Ciphertext)
+	AddNew(op0, op1 Operand) (ctOut *Ciphertext)
+	AddNoMod(op0, op1 Operand, ctOut *Ciphertext)
+	AddNoModNew(op0, op1 Operand) (ctOut *Ciphertext)
+	Sub(op0, op1 Operand, ctOut *Ciphertext)
+	SubNew(op0, op1 Operand) (ctOut *Ciphertext)
+	SubNoMod(op0, op1 Operand, ctOut *Ciphertext)
+	SubNoModNew(op0, op1 Operand) (ctOut *Ciphertext)
+	Neg(op Operand, ctOut *Ciphertext)
+	NegNew(op Operand) (ctOut *Ciphertext)
+	Reduce(op Operand, ctOut *Ciphertext)
+	ReduceNew(op Operand) (ctOut *Ciphertext)
+	MulScalar(op Operand, scalar uint64, ctOut *Ciphertext)
+	MulScalarNew(op Operand, scalar uint64) (ctOut *Ciphertext)
+	Mul(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext)
+	MulNew(op0 *Ciphertext, op1 Operand) (ctOut *Ciphertext)
+	Relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext)
+	RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (ctOut *Ciphertext)
+	SwitchKeys(ct0 *Ciphertext, switchKey *SwitchingKey, ctOut *Ciphertext)
+	SwitchKeysNew(ct0 *Ciphertext, switchkey *SwitchingKey) (ctOut *Ciphertext)
+	RotateColumnsNew(ct0 *Ciphertext, k uint64, evakey *RotationKeys) (ctOut *Ciphertext)
+	RotateColumns(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext)
+	RotateRows(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext)
+	RotateRowsNew(ct0 *Ciphertext, evakey *RotationKeys) (ctOut *Ciphertext)
+	InnerSum(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext)
+	DropLevelNew(ct0 *Ciphertext, levels uint64) (ctOut *Ciphertext)
+	DropLevel(ct0 *Ciphertext, levels uint64) (err error)
+	RescaleNew(ct0 *Ciphertext) (ctOut *Ciphertext, err error)
+	Rescale(ct0 *Ciphertext, ctOut *Ciphertext) (err error)
+}
+
+// evaluator is a struct that holds the necessary elements to perform the homomorphic operations between ciphertexts and/or plaintexts.
+// It also holds a small memory pool used to store intermediate computations.
+type evaluator struct {
+	params *Parameters
+
+	bgvContext *bgvContext
+
+	baseconverter *ring.FastBasisExtender
+	decomposer    *ring.Decomposer
+
+	poolQ [4]*ring.Poly
+	poolP [3]*ring.Poly
+
+	polypool   [2]*ring.Poly
+	tensorpool [2][]*ring.Poly
+}
+
+// NewEvaluator creates a new Evaluator, that can be used to do homomorphic
+// operations on ciphertexts and/or plaintexts. It stores a small pool of polynomials
+// and ciphertexts that will be used for intermediate values.
+func NewEvaluator(params *Parameters) Evaluator {
+
+	if !params.isValid {
+		panic("cannot NewEvaluator: params not valid (check if they were generated properly)")
+	}
+
+	bgvContext := newBGVContext(params)
+	q := bgvContext.contextQ
+	p := bgvContext.contextP
+
+	tensorpool := [2][]*ring.Poly{make([]*ring.Poly, 6), make([]*ring.Poly, 6)}
+	for i := 0; i < 6; i++ {
+		tensorpool[0][i] = q.NewPoly()
+		tensorpool[1][i] = q.NewPoly()
+	}
+
+	var baseconverter *ring.FastBasisExtender
+	var decomposer *ring.Decomposer
+	var poolP [3]*ring.Poly
+	if len(params.Pi) != 0 {
+		baseconverter = ring.NewFastBasisExtender(q, p)
+		decomposer = ring.NewDecomposer(q.Modulus, p.Modulus)
+		poolP = [3]*ring.Poly{p.NewPoly(), p.NewPoly(), p.NewPoly()}
+	}
+
+	return &evaluator{
+		params:        params.Copy(),
+		bgvContext:    bgvContext,
+		baseconverter: baseconverter,
+		decomposer:    decomposer,
+		poolQ:         [4]*ring.Poly{q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly()},
+		poolP:         poolP,
+		polypool:      [2]*ring.Poly{q.NewPoly(), q.NewPoly()},
+		tensorpool:    tensorpool,
+	}
+}
+
+func (evaluator *evaluator) getElemAndCheckBinary(op0, op1, opOut Operand, opOutMinDegree uint64) (el0, el1, elOut *bgvElement) {
+	if op0 == nil || op1 == nil || opOut == nil {
+		panic("cannot getElemAndCheckBinary: operands cannot be nil")
+	}
+
+	if op0.Degree()+op1.Degree() == 0 {
+		panic("cannot getElemAndCheckBinary: operands cannot be both plaintexts")
+	}
+
+	if opOut.Degree() < opOutMinDegree {
+		panic("cannot getElemAndCheckBinary: receiver operand degree is too small")
+	}
+
+	el0, el1, elOut = op0.Element(), op1.Element(), opOut.Element()
+
+	if !el0.IsNTT() || !el1.IsNTT() {
+		panic("cannot getElemAndCheckBinary: operands must be in the NTT domain")
+	}
+
+	return
+}
+
+func (evaluator *evaluator) getElemAndCheckUnary(op0, opOut Operand, opOutMinDegree uint64) (el0, elOut *bgvElement) {
+	if op0 == nil || opOut == nil {
+		panic("cannot getElemAndCheckUnary: operand cannot be nil")
+	}
+
+	if op0.Degree() == 0 {
+		panic("cannot getElemAndCheckUnary: operand cannot be plaintext")
+	}
+
+	if opOut.Degree() < opOutMinDegree {
+		panic("cannot getElemAndCheckUnary: receiver operand degree is too small")
+	}
+
+	el0, elOut = op0.Element(), opOut.Element()
+
+	if !el0.IsNTT() {
+		panic("cannot getElemAndCheckUnary: operand must be in the NTT domain")
+	}
+
+	return
+}
+
+// newCiphertextBinary returns a new ciphertext of the largest degree and the smallest level of the two operands.
+func (evaluator *evaluator) newCiphertextBinary(op0, op1 Operand) (ctOut *Ciphertext) {
+	return NewCiphertext(evaluator.params, utils.MaxUint64(op0.Degree(), op1.Degree()), utils.MinUint64(op0.Level(), op1.Level()))
+}
+
+// evaluateInPlaceBinary applies the provided function in place on el0 and el1 and returns the result in elOut,
+// at the smallest level of the three elements.
+func (evaluator *evaluator) evaluateInPlaceBinary(el0, el1, elOut *bgvElement, evaluate func(uint64, *ring.Poly, *ring.Poly, *ring.Poly)) {
+
+	level := utils.MinUint64(utils.MinUint64(el0.Level(), el1.Level()), elOut.Level())
+
+	maxDegree := utils.MaxUint64(el0.Degree(), el1.Degree())
+	minDegree := utils.MinUint64(el0.Degree(), el1.Degree())
+
+	elOut.dropLevel(level)
+
+	for i := uint64(0); i < minDegree+1; i++ {
+		evaluate(level, el0.value[i], el1.value[i], elOut.value[i])
+	}
+
+	// If the inputs degrees differ, it copies the remaining degree on the receiver.
+	var largest *bgvElement
+	if el0.Degree() > el1.Degree() {
+		largest = el0
+	} else if el1.Degree() > el0.Degree() {
+		largest = el1
+	}
+	if largest != nil && largest != elOut { // checks to avoid unnecessary work.
+		for i := minDegree + 1; i < maxDegree+1; i++ {
+			evaluator.bgvContext.contextQ.CopyLvl(level, largest.value[i], elOut.value[i])
+		}
+	}
+
+	elOut.isNTT = true
+}
+
+// evaluateInPlaceUnary applies the provided function in place on el0 and returns the result in elOut,
+// at the smallest level of the two elements.
+func (evaluator *evaluator) evaluateInPlaceUnary(el0, elOut *bgvElement, evaluate func(uint64, *ring.Poly, *ring.Poly)) {
+
+	level := utils.MinUint64(el0.Level(), elOut.Level())
+
+	elOut.dropLevel(level)
+
+	for i := range el0.value {
+		evaluate(level, el0.value[i], elOut.value[i])
+	}
+
+	elOut.isNTT = true
+}
+
+// Add adds op0 to op1 and returns the result in ctOut.
+func (evaluator *evaluator) Add(op0, op1 Operand, ctOut *Ciphertext) {
+	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	evaluator.evaluateInPlaceBinary(el0, el1, elOut, evaluator.bgvContext.contextQ.AddLvl)
+}
+
+// AddNew adds op0 to op1 and creates a new element ctOut to store the result.
+func (evaluator *evaluator) AddNew(op0, op1 Operand) (ctOut *Ciphertext) {
+	ctOut = evaluator.newCiphertextBinary(op0, op1)
+	evaluator.Add(op0, op1, ctOut)
+	return
+}
+
+// AddNoMod adds op0 to op1 without modular reduction, and returns the result in cOut.
+func (evaluator *evaluator) AddNoMod(op0, op1 Operand, ctOut *Ciphertext) {
+	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	evaluator.evaluateInPlaceBinary(el0, el1, elOut, evaluator.bgvContext.contextQ.AddNoModLvl)
+}
+
+// AddNoModNew adds op0 to op1 without modular reduction and creates a new element ctOut to store the result.
+func (evaluator *evaluator) AddNoModNew(op0, op1 Operand) (ctOut *Ciphertext) {
+	ctOut = evaluator.newCiphertextBinary(op0, op1)
+	evaluator.AddNoMod(op0, op1, ctOut)
+	return
+}
+
+// Sub subtracts op1 from op0 and returns the result in cOut.
+func (evaluator *evaluator) Sub(op0, op1 Operand, ctOut *Ciphertext) {
+	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	evaluator.evaluateInPlaceBinary(el0, el1, elOut, evaluator.bgvContext.contextQ.SubLvl)
+
+	if el0.Degree() < el1.Degree() {
+		for i := el0.Degree() + 1; i < el1.Degree()+1; i++ {
+			evaluator.bgvContext.contextQ.NegLvl(elOut.Level(), elOut.value[i], elOut.value[i])
+		}
+	}
+}
+
+// SubNew subtracts op1 from op0 and creates a new element ctOut to store the result.
+func (evaluator *evaluator) SubNew(op0, op1 Operand) (ctOut *Ciphertext) {
+	ctOut = evaluator.newCiphertextBinary(op0, op1)
+	evaluator.Sub(op0, op1, ctOut)
+	return
+}
+
+// SubNoMod subtracts op1 from op0 without modular reduction and returns the result on ctOut.
+func (evaluator *evaluator) SubNoMod(op0, op1 Operand, ctOut *Ciphertext) {
+	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	evaluator.evaluateInPlaceBinary(el0, el1, elOut, evaluator.bgvContext.contextQ.SubNoModLvl)
+
+	if el0.Degree() < el1.Degree() {
+		for i := el0.Degree() + 1; i < el1.Degree()+1; i++ {
+			evaluator.bgvContext.contextQ.NegLvl(elOut.Level(), elOut.value[i], elOut.value[i])
+		}
+	}
+}
+
+// SubNoModNew subtracts op1 from op0 without modular reduction and creates a new element ctOut to store the result.
+func (evaluator *evaluator) SubNoModNew(op0, op1 Operand) (ctOut *Ciphertext) {
+	ctOut = evaluator.newCiphertextBinary(op0, op1)
+	evaluator.SubNoMod(op0, op1, ctOut)
+	return
+}
+
+// Neg negates op and returns the result in ctOut.
+func (evaluator *evaluator) Neg(op Operand, ctOut *Ciphertext) {
+	el0, elOut := evaluator.getElemAndCheckUnary(op, ctOut, op.Degree())
+	evaluator.evaluateInPlaceUnary(el0, elOut, evaluator.bgvContext.contextQ.NegLvl)
+}
+
+// NegNew negates op and creates a new element to store the result.
+func (evaluator *evaluator) NegNew(op Operand) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, op.Degree(), op.Level())
+	evaluator.Neg(op, ctOut)
+	return ctOut
+}
+
+// Reduce applies a modular reduction to op and returns the result in ctOut.
+func (evaluator *evaluator) Reduce(op Operand, ctOut *Ciphertext) {
+	el0, elOut := evaluator.getElemAndCheckUnary(op, ctOut, op.Degree())
+	evaluator.evaluateInPlaceUnary(el0, elOut, evaluator.bgvContext.contextQ.ReduceLvl)
+}
+
+// ReduceNew applies a modular reduction to op and creates a new element ctOut to store the result.
+func (evaluator *evaluator) ReduceNew(op Operand) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, op.Degree(), op.Level())
+	evaluator.Reduce(op, ctOut)
+	return ctOut
+}
+
+// MulScalar multiplies op by a uint64 scalar and returns the result in ctOut. The scalar is first
+// reduced modulo T and centered, to minimize the growth of the noise.
+func (evaluator *evaluator) MulScalar(op Operand, scalar uint64, ctOut *Ciphertext) {
+
+	el0, elOut := evaluator.getElemAndCheckUnary(op, ctOut, op.Degree())
+
+	contextQ := evaluator.bgvContext.contextQ
+
+	t := evaluator.params.T
+
+	scalar %= t
+
+	var fun func(uint64, *ring.Poly, *ring.Poly)
+
+	if scalar > t>>1 {
+		scalar = t - scalar
+		fun = func(level uint64, el, elOut *ring.Poly) {
+			contextQ.MulScalarLvl(level, el, scalar, elOut)
+			contextQ.NegLvl(level, elOut, elOut)
+		}
+	} else {
+		fun = func(level uint64, el, elOut *ring.Poly) { contextQ.MulScalarLvl(level, el, scalar, elOut) }
+	}
+
+	evaluator.evaluateInPlaceUnary(el0, elOut, fun)
+}
+
+// MulScalarNew multiplies op by a uint64 scalar and creates a new element ctOut to store the result.
+func (evaluator *evaluator) MulScalarNew(op Operand, scalar uint64) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, op.Degree(), op.Level())
+	evaluator.MulScalar(op, scalar, ctOut)
+	return
+}
+
+// tensor computes (ct0 x ct1) in the NTT domain and stores the result in ctOut, at the smallest level of the three elements.
+// Contrary to BFV, no rescaling is applied: the noise is managed by calling Rescale on the result.
+func (evaluator *evaluator) tensor(ct0, ct1, ctOut *bgvElement) {
+
+	contextQ := evaluator.bgvContext.contextQ
+
+	level := utils.MinUint64(utils.MinUint64(ct0.Level(), ct1.Level()), ctOut.Level())
+
+	outDegree := ct0.Degree() + ct1.Degree()
+
+	if outDegree > uint64(len(evaluator.tensorpool[1])-1) {
+		panic("cannot Mul: degree of the output is too large")
+	}
+
+	c0 := evaluator.tensorpool[0]
+	c2 := evaluator.tensorpool[1]
+
+	for i := range ct0.value {
+		contextQ.MFormLvl(level, ct0.value[i], c0[i])
+	}
+
+	// Squaring case
+	if ct0 == ct1 {
+
+		for i := uint64(0); i < outDegree+1; i++ {
+			c2[i].Zero()
+		}
+
+		for i := uint64(0); i < ct0.Degree()+1; i++ {
+			for j := i + 1; j < ct0.Degree()+1; j++ {
+				contextQ.MulCoeffsMontgomeryLvl(level, c0[i], ct0.value[j], evaluator.poolQ[0])
+				contextQ.AddLvl(level, evaluator.poolQ[0], evaluator.poolQ[0], evaluator.poolQ[0])
+				contextQ.AddLvl(level, c2[i+j], evaluator.poolQ[0], c2[i+j])
+			}
+		}
+
+		for i := uint64(0); i < ct0.Degree()+1; i++ {
+			contextQ.MulCoeffsMontgomeryAndAddLvl(level, c0[i], ct0.value[i], c2[i<<1])
+		}
+
+		// Normal case
+	} else {
+
+		for i := uint64(0); i < outDegree+1; i++ {
+			c2[i].Zero()
+		}
+
+		for i := uint64(0); i < ct0.Degree()+1; i++ {
+			for j := uint64(0); j < ct1.Degree()+1; j++ {
+				contextQ.MulCoeffsMontgomeryAndAddLvl(level, c0[i], ct1.value[j], c2[i+j])
+			}
+		}
+	}
+
+	ctOut.dropLevel(level)
+	ctOut.Resize(evaluator.params, outDegree)
+
+	for i := uint64(0); i < outDegree+1; i++ {
+		contextQ.CopyLvl(level, c2[i], ctOut.value[i])
+	}
+
+	ctOut.isNTT = true
+}
+
+// Mul multiplies op0 by op1 and returns the result in ctOut.
+func (evaluator *evaluator) Mul(op0 *Ciphertext, op1 Operand, ctOut *Ciphertext) {
+	el0, el1, elOut := evaluator.getElemAndCheckBinary(op0, op1, ctOut, op0.Degree()+op1.Degree())
+	evaluator.tensor(el0, el1, elOut)
+}
+
+// MulNew multiplies op0 by op1 and creates a new element ctOut to store the result.
+func (evaluator *evaluator) MulNew(op0 *Ciphertext, op1 Operand) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, op0.Degree()+op1.Degree(), utils.MinUint64(op0.Level(), op1.Level()))
+	evaluator.Mul(op0, op1, ctOut)
+	return
+}
+
+// relinearize is a method common to Relinearize and RelinearizeNew. It applies the keyswitch on the elements of degree > 1 of ct0
+// and returns the result in ctOut.
+func (evaluator *evaluator) relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext) {
+
+	context := evaluator.bgvContext.contextQ
+
+	level := utils.MinUint64(ct0.Level(), ctOut.Level())
+
+	if ctOut != ct0 {
+		ctOut.dropLevel(level)
+		context.CopyLvl(level, ct0.value[0], ctOut.value[0])
+		context.CopyLvl(level, ct0.value[1], ctOut.value[1])
+	}
+
+	p0 := evaluator.poolQ[1]
+	p1 := evaluator.poolQ[2]
+
+	for deg := uint64(ct0.Degree()); deg > 1; deg-- {
+		evaluator.switchKeysInPlace(level, ct0.value[deg], evakey.evakey[deg-2], p0, p1)
+		context.AddLvl(level, ctOut.value[0], p0, ctOut.value[0])
+		context.AddLvl(level, ctOut.value[1], p1, ctOut.value[1])
+	}
+
+	ctOut.SetValue(ctOut.value[:2])
+	ctOut.isNTT = true
+}
+
+// Relinearize relinearizes the ciphertext ct0 of degree > 1 until it is of degree 1, and returns the result in cOut.
+//
+// It requires a correct evaluation key as additional input:
+//
+// - it must match the secret-key that was used to create the public key under which the current ct0 is encrypted.
+//
+// - it must be of degree high enough to relinearize the input ciphertext to degree 1 (e.g., a ciphertext
+// of degree 3 will require that the evaluation key stores the keys for both degree 3 and degree 2 ciphertexts).
+func (evaluator *evaluator) Relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext) {
+
+	if int(ct0.Degree()-1) > len(evakey.evakey) {
+		panic("cannot Relinearize: input ciphertext degree too large to allow relinearization")
+	}
+
+	if ct0.Degree() < 2 {
+		if ct0 != ctOut {
+			ctOut.Copy(ct0.Element())
+		}
+	} else {
+		evaluator.relinearize(ct0, evakey, ctOut)
+	}
+}
+
+// RelinearizeNew relinearizes the ciphertext ct0 of degree > 1 until it is of degree 1, and creates a new ciphertext to store the result.
+//
+// Requires a correct evaluation key as additional input:
+//
+// - it must match the secret-key that was used to create the public key under which the current ct0 is encrypted
+//
+// - it must be of degree high enough to relinearize the input ciphertext to degree 1 (e.g., a ciphertext
+// of degree 3 will require that the evaluation key stores the keys for both degree 3 and degree 2 ciphertexts).
+func (evaluator *evaluator) RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, 1, ct0.Level())
+	evaluator.Relinearize(ct0, evakey, ctOut)
+	return
+}
+
+// SwitchKeys applies the key-switching procedure to the ciphertext ct0 and returns the result in ctOut. It requires as an additional input a valid switching-key:
+// it must encrypt the target key under the public key under which ct0 is currently encrypted.
+func (evaluator *evaluator) SwitchKeys(ct0 *Ciphertext, switchKey *SwitchingKey, ctOut *Ciphertext) {
+
+	context := evaluator.bgvContext.contextQ
+
+	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
+		panic("cannot SwitchKeys: input and output must be of degree 1 to allow key switching")
+	}
+
+	level := utils.MinUint64(ct0.Level(), ctOut.Level())
+
+	p0 := evaluator.poolQ[1]
+	p1 := evaluator.poolQ[2]
+
+	evaluator.switchKeysInPlace(level, ct0.value[1], switchKey, p0, p1)
+
+	ctOut.dropLevel(level)
+
+	context.AddLvl(level, ct0.value[0], p0, ctOut.value[0])
+	context.CopyLvl(level, p1, ctOut.value[1])
+
+	ctOut.isNTT = true
+}
+
+// SwitchKeysNew applies the key-switching procedure to the ciphertext ct0 and creates a new ciphertext to store the result. It requires as an additional input a valid switching-key:
+// it must encrypt the target key under the public key under which ct0 is currently encrypted.
+func (evaluator *evaluator) SwitchKeysNew(ct0 *Ciphertext, switchkey *SwitchingKey) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, 1, ct0.Level())
+	evaluator.SwitchKeys(ct0, switchkey, ctOut)
+	return
+}
+
+// RotateColumnsNew applies RotateColumns and returns the result in a new Ciphertext.
+func (evaluator *evaluator) RotateColumnsNew(ct0 *Ciphertext, k uint64, evakey *RotationKeys) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, 1, ct0.Level())
+	evaluator.RotateColumns(ct0, k, evakey, ctOut)
+	return
+}
+
+// RotateColumns rotates the columns of ct0 by k positions to the left and returns the result in ctOut. As an additional input it requires a RotationKeys struct:
+//
+// - it must either store all the left and right power-of-2 rotations or the specific rotation that is requested.
+//
+// If only the power-of-two rotations are stored, the numbers k and n/2-k will be decomposed in base-2 and the rotation with the lowest
+// hamming weight will be chosen; then the specific rotation will be computed as a sum of powers of two rotations.
+func (evaluator *evaluator) RotateColumns(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
+
+	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
+		panic("cannot RotateColumns: input and or output must be of degree 1")
+	}
+
+	k &= ((evaluator.bgvContext.n >> 1) - 1)
+
+	if k == 0 {
+
+		ctOut.Copy(ct0.Element())
+
+	} else {
+
+		// Looks in the rotation key if the corresponding rotation has been generated or if the input is a plaintext
+		if evakey.evakeyRotColLeft[k] != nil {
+
+			evaluator.permuteNTT(ct0, evakey.permuteNTTLeftIndex[k], evakey.evakeyRotColLeft[k], ctOut)
+
+		} else {
+
+			// If the needed rotation key has not been generated, it looks if the left and right pow2 rotations have been generated
+			hasPow2Rotations := true
+			for i := uint64(1); i < evaluator.bgvContext.n>>1; i <<= 1 {
+				if evakey.evakeyRotColLeft[i] == nil || evakey.evakeyRotColRight[i] == nil {
+					hasPow2Rotations = false
+					break
+				}
+			}
+
+			// If they have been generated, it computes the least amount of rotation between k to the left and n/2-k to the right required to apply the requested rotation
+			if hasPow2Rotations {
+
+				if utils.HammingWeight64(k) <= utils.HammingWeight64((evaluator.bgvContext.n>>1)-k) {
+					evaluator.rotateColumnsLPow2(ct0, k, evakey, ctOut)
+				} else {
+					evaluator.rotateColumnsRPow2(ct0, (evaluator.bgvContext.n>>1)-k, evakey, ctOut)
+				}
+
+				// Otherwise, it returns an error indicating that the keys have not been generated
+			} else {
+				panic("cannot RotateColumns: specific rotation and pow2 rotations have not been generated")
+			}
+		}
+	}
+}
+
+// rotateColumnsLPow2 applies the Galois Automorphism on an element, rotating the element by k positions to the left, and returns the result in ctOut.
+func (evaluator *evaluator) rotateColumnsLPow2(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
+	evaluator.rotateColumnsPow2(ct0, k, evakey.permuteNTTLeftIndex, evakey.evakeyRotColLeft, ctOut)
+}
+
+// rotateColumnsRPow2 applies the Galois Endomorphism on an element, rotating the element by k positions to the right, returns the result in ctOut.
+func (evaluator *evaluator) rotateColumnsRPow2(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
+	evaluator.rotateColumnsPow2(ct0, k, evakey.permuteNTTRightIndex, evakey.evakeyRotColRight, ctOut)
+}
+
+// rotateColumnsPow2 rotates ct0 by k positions (left or right depending on the input), decomposing k as a sum of power-of-2 rotations, and returns the result in ctOut.
+func (evaluator *evaluator) rotateColumnsPow2(ct0 *Ciphertext, k uint64, permuteNTTIndex map[uint64][]uint64, evakeyRotCol map[uint64]*SwitchingKey, ctOut *Ciphertext) {
+
+	var evakeyIndex uint64
+
+	evakeyIndex = 1
+
+	ctOut.Copy(ct0.Element())
+
+	// Applies the Galois automorphism and the key-switching process
+	for k > 0 {
+
+		if k&1 == 1 {
+
+			evaluator.permuteNTT(ctOut, permuteNTTIndex[evakeyIndex], evakeyRotCol[evakeyIndex], ctOut)
+		}
+
+		evakeyIndex <<= 1
+		k >>= 1
+	}
+}
+
+// RotateRows rotates the rows of ct0 and returns the result in ctOut.
+func (evaluator *evaluator) RotateRows(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext) {
+
+	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
+		panic("cannot RotateRows: input and/or output must be of degree 1")
+	}
+
+	if evakey.evakeyRotRow == nil {
+		panic("cannot RotateRows: rotation key not generated")
+	}
+
+	evaluator.permuteNTT(ct0, evakey.permuteNTTRowIndex, evakey.evakeyRotRow, ctOut)
+}
+
+// RotateRowsNew rotates the rows of ct0 and returns the result a new Ciphertext.
+func (evaluator *evaluator) RotateRowsNew(ct0 *Ciphertext, evakey *RotationKeys) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(evaluator.params, 1, ct0.Level())
+	evaluator.RotateRows(ct0, evakey, ctOut)
+	return
+}
+
+// InnerSum computes the inner sum of ct0 and returns the result in ctOut. It requires a rotation key storing all the left powers of two rotations.
+// The resulting vector will be of the form [sum, sum, .., sum, sum].
+func (evaluator *evaluator) InnerSum(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext) {
+
+	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
+		panic("cannot InnerSum: input and output must be of degree 1")
+	}
+
+	cTmp := NewCiphertext(evaluator.params, 1, ct0.Level())
+
+	ctOut.Copy(ct0.Element())
+
+	for i := uint64(1); i < evaluator.bgvContext.n>>1; i <<= 1 {
+		evaluator.RotateColumns(ctOut, i, evakey, cTmp)
+		evaluator.Add(cTmp.bgvElement, ctOut, ctOut.Ciphertext())
+	}
+
+	evaluator.RotateRows(ctOut, evakey, cTmp)
+	evaluator.Add(ctOut, cTmp.bgvElement, ctOut)
+}
+
+// DropLevelNew reduces the level of ct0 by levels and returns the result in a newly created element.
+// No rescaling is applied during this procedure.
+func (evaluator *evaluator) DropLevelNew(ct0 *Ciphertext, levels uint64) (ctOut *Ciphertext) {
+	ctOut = ct0.CopyNew().Ciphertext()
+	evaluator.DropLevel(ctOut, levels)
+	return
+}
+
+// DropLevel reduces the level of ct0 by levels and returns the result in ct0.
+// No rescaling is applied during this procedure.
+func (evaluator *evaluator) DropLevel(ct0 *Ciphertext, levels uint64) (err error) {
+
+	if ct0.Level() < levels {
+		return errors.New("cannot DropLevel: Ciphertext level is too small")
+	}
+
+	ct0.dropLevel(ct0.Level() - levels)
+
+	return nil
+}
+
+// RescaleNew divides ct0 by the last modulus in the moduli chain and returns the result in a newly created element.
+// Since all the moduli are congruent to 1 modulo T, the plaintext is preserved and the noise is divided by the last modulus.
+func (evaluator *evaluator) RescaleNew(ct0 *Ciphertext) (ctOut *Ciphertext, err error) {
+
+	ctOut = NewCiphertext(evaluator.params, ct0.Degree(), ct0.Level())
+
+	return ctOut, evaluator.Rescale(ct0, ctOut)
+}
+
+// Rescale divides ct0 by the last modulus in the moduli chain and returns the result in ctOut.
+// Since all the moduli are congruent to 1 modulo T, the plaintext is preserved and the noise is divided by the last modulus.
+func (evaluator *evaluator) Rescale(ct0 *Ciphertext, ctOut *Ciphertext) (err error) {
+
+	if ct0.Level() == 0 {
+		return errors.New("cannot Rescale: input Ciphertext already at level 0")
+	}
+
+	if ct0.Level() != ctOut.Level() {
+		panic("cannot Rescale: levels of receiver Ciphertext and input Ciphertext do not match")
+	}
+
+	if !ct0.IsNTT() {
+		panic("cannot Rescale: input Ciphertext not in NTT")
+	}
+
+	ctOut.Copy(ct0.Element())
+
+	for i := range ctOut.Value() {
+		evaluator.bgvContext.divRoundByLastModulusNTT(ctOut.Value()[i])
+	}
+
+	return nil
+}
+
+// permuteNTT applies the Galois automorphism described by index on ct0, followed by a key-switching, and returns the result in ctOut.
+func (evaluator *evaluator) permuteNTT(ct0 *Ciphertext, index []uint64, evakey *SwitchingKey, ctOut *Ciphertext) {
+
+	level := utils.MinUint64(ct0.Level(), ctOut.Level())
+
+	el0 := &ring.Poly{Coeffs: evaluator.polypool[0].Coeffs[:level+1]}
+	el1 := &ring.Poly{Coeffs: evaluator.polypool[1].Coeffs[:level+1]}
+
+	ring.PermuteNTTWithIndex(&ring.Poly{Coeffs: ct0.value[0].Coeffs[:level+1]}, index, el0)
+	ring.PermuteNTTWithIndex(&ring.Poly{Coeffs: ct0.value[1].Coeffs[:level+1]}, index, el1)
+
+	context := evaluator.bgvContext.contextQ
+
+	evaluator.switchKeysInPlace(level, el1, evakey, evaluator.poolQ[1], evaluator.poolQ[2])
+
+	ctOut.dropLevel(level)
+
+	context.AddLvl(level, el0, evaluator.poolQ[1], ctOut.value[0])
+	context.CopyLvl(level, evaluator.poolQ[2], ctOut.value[1])
+
+	ctOut.isNTT = true
+}
+
+// switchKeysInPlace applies the general key-switching procedure of the form [cx*evakey[0], cx*evakey[1]] and returns the result on p0 and p1.
+// The division by P is done such that the residue modulo T of the result is preserved.
+func (evaluator *evaluator) switchKeysInPlace(level uint64, cx *ring.Poly, evakey *SwitchingKey, p0, p1 *ring.Poly) {
+
+	if evaluator.baseconverter == nil {
+		panic("cannot switchKeys: modulus P is empty")
+	}
+
+	var reduce uint64
+
+	contextQ := evaluator.bgvContext.contextQ
+	contextP := evaluator.bgvContext.contextP
+
+	for i := range evaluator.poolQ {
+		evaluator.poolQ[i].Zero()
+	}
+
+	for i := range evaluator.poolP {
+		evaluator.poolP[i].Zero()
+	}
+
+	c2QiQ := evaluator.poolQ[0]
+	c2QiP := evaluator.poolP[0]
+
+	pool2Q := p0
+	pool2P := evaluator.poolP[1]
+
+	pool3Q := p1
+	pool3P := evaluator.poolP[2]
+
+	c2 := evaluator.poolQ[3]
+
+	// We switch the element on which the switching key operation will be conducted out of the NTT domain
+	contextQ.InvNTTLvl(level, cx, c2)
+
+	reduce = 0
+
+	alpha := evaluator.params.Alpha()
+	beta := uint64(math.Ceil(float64(level+1) / float64(alpha)))
+
+	// Key switching with CRT decomposition for the Qi
+	for i := uint64(0); i < beta; i++ {
+
+		evaluator.decomposeAndSplitNTT(level, i, cx, c2, c2QiQ, c2QiP)
+
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakey[i][0], c2QiQ, pool2Q)
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakey[i][1], c2QiQ, pool3Q)
+
+		// We continue with the key-switch primes.
+		for j, keysindex := uint64(0), evaluator.bgvContext.levels; j < uint64(len(contextP.Modulus)); j, keysindex = j+1, keysindex+1 {
+
+			pj := contextP.Modulus[j]
+			mredParams := contextP.GetMredParams()[j]
+
+			key0 := evakey.evakey[i][0].Coeffs[keysindex]
+			key1 := evakey.evakey[i][1].Coeffs[keysindex]
+			c2tmp := c2QiP.Coeffs[j]
+			p2tmp := pool2P.Coeffs[j]
+			p3tmp := pool3P.Coeffs[j]
+
+			for y := uint64(0); y < contextP.N; y++ {
+				p2tmp[y] += ring.MRed(key0[y], c2tmp[y], pj, mredParams)
+				p3tmp[y] += ring.MRed(key1[y], c2tmp[y], pj, mredParams)
+			}
+		}
+
+		if reduce&7 == 1 {
+			contextQ.ReduceLvl(level, pool2Q, pool2Q)
+			contextQ.ReduceLvl(level, pool3Q, pool3Q)
+			contextP.Reduce(pool2P, pool2P)
+			contextP.Reduce(pool3P, pool3P)
+		}
+
+		reduce++
+	}
+
+	if (reduce-1)&7 != 1 {
+		contextQ.ReduceLvl(level, pool2Q, pool2Q)
+		contextQ.ReduceLvl(level, pool3Q, pool3Q)
+		contextP.Reduce(pool2P, pool2P)
+		contextP.Reduce(pool3P, pool3P)
+	}
+
+	// Computes pool2Q = T * (T^-1 * pool2Q)/pool2P and pool3Q = T * (T^-1 * pool3Q)/pool3P,
+	// which preserves the residue modulo T of the result.
+	contextQ.MulScalarBigintLvl(level, pool2Q, evaluator.bgvContext.tInv, pool2Q)
+	contextQ.MulScalarBigintLvl(level, pool3Q, evaluator.bgvContext.tInv, pool3Q)
+	contextP.MulScalarBigint(pool2P, evaluator.bgvContext.tInv, pool2P)
+	contextP.MulScalarBigint(pool3P, evaluator.bgvContext.tInv, pool3P)
+
+	evaluator.baseconverter.ModDownSplitedNTTPQ(level, pool2Q, pool2P, pool2Q)
+	evaluator.baseconverter.ModDownSplitedNTTPQ(level, pool3Q, pool3P, pool3Q)
+
+	contextQ.MulScalarLvl(level, pool2Q, evaluator.params.T, pool2Q)
+	contextQ.MulScalarLvl(level, pool3Q, evaluator.params.T, pool3Q)
+}
+
+// decomposeAndSplitNTT decomposes the input polynomial into the target CRT basis.
+func (evaluator *evaluator) decomposeAndSplitNTT(level, beta uint64, c2NTT, c2InvNTT, c2QiQ, c2QiP *ring.Poly) {
+
+	contextQ := evaluator.bgvContext.contextQ
+	contextP := evaluator.bgvContext.contextP
+
+	evaluator.decomposer.DecomposeAndSplit(level, beta, c2InvNTT, c2QiQ, c2QiP)
+
+	p0idxst := beta * evaluator.params.Alpha()
+	p0idxed := p0idxst + evaluator.decomposer.Xalpha()[beta]
+
+	// c2_qi = cx mod qi mod qi
+	for x := uint64(0); x < level+1; x++ {
+
+		qi := contextQ.Modulus[x]
+		nttPsi := contextQ.GetNttPsi()[x]
+		bredParams := contextQ.GetBredParams()[x]
+		mredParams := contextQ.GetMredParams()[x]
+
+		if p0idxst <= x && x < p0idxed {
+			p0tmp := c2NTT.Coeffs[x]
+			p1tmp := c2QiQ.Coeffs[x]
+			for j := uint64(0); j < contextQ.N; j++ {
+				p1tmp[j] = p0tmp[j]
+			}
+		} else {
+			ring.NTT(c2QiQ.Coeffs[x], c2QiQ.Coeffs[x], contextQ.N, nttPsi, qi, mredParams, bredParams)
+		}
+	}
+	// c2QiP = c2 mod qi mod pj
+	contextP.NTT(c2QiP, c2QiP)
+}
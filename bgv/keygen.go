@@ -0,0 +1,435 @@
+package bgv
+
+import (
+	"github.com/ldsec/lattigo/ring"
+)
+
+// KeyGenerator is an interface implementing the methods of the keyGenerator.
+type KeyGenerator interface {
+	GenSecretKey() (sk *SecretKey)
+	GenSecretkeyWithDistrib(p float64) (sk *SecretKey)
+	GenPublicKey(sk *SecretKey) (pk *PublicKey)
+	GenKeyPair() (sk *SecretKey, pk *PublicKey)
+	GenRelinKey(sk *SecretKey, maxDegree uint64) (evk *EvaluationKey)
+	GenSwitchingKey(skIn, skOut *SecretKey) (evk *SwitchingKey)
+	GenRot(rotType Rotation, sk *SecretKey, k uint64, rotKey *RotationKeys)
+	GenRotationKeysPow2(sk *SecretKey) (rotKey *RotationKeys)
+}
+
+// keyGenerator is a structure that stores the elements required to create new keys,
+// as well as a small memory pool for intermediate values.
+type keyGenerator struct {
+	params     *Parameters
+	bgvContext *bgvContext
+	polypool   [2]*ring.Poly
+}
+
+// SecretKey is a structure that stores the SecretKey.
+type SecretKey struct {
+	sk *ring.Poly
+}
+
+// PublicKey is a structure that stores the PublicKey.
+type PublicKey struct {
+	pk [2]*ring.Poly
+}
+
+// Rotation is a type used to represent the rotations types.
+type Rotation int
+
+// Constants for rotation types
+const (
+	RotationRight = iota + 1
+	RotationLeft
+	RotationRow
+)
+
+// RotationKeys is a structure that stores the switching-keys required during the homomorphic rotations.
+type RotationKeys struct {
+	permuteNTTRightIndex map[uint64][]uint64
+	permuteNTTLeftIndex  map[uint64][]uint64
+	permuteNTTRowIndex   []uint64
+
+	evakeyRotColLeft  map[uint64]*SwitchingKey
+	evakeyRotColRight map[uint64]*SwitchingKey
+	evakeyRotRow      *SwitchingKey
+}
+
+// EvaluationKey is a structure that stores the switching-keys required during the relinearization.
+type EvaluationKey struct {
+	evakey []*SwitchingKey
+}
+
+// SwitchingKey is a structure that stores the switching-keys required during the key-switching.
+type SwitchingKey struct {
+	evakey [][2]*ring.Poly
+}
+
+// Get returns the switching key backing slice.
+func (swk *SwitchingKey) Get() [][2]*ring.Poly {
+	return swk.evakey
+}
+
+// NewKeyGenerator creates a new KeyGenerator, from which the secret and public keys, as well as the evaluation,
+// rotation and switching keys can be generated.
+func NewKeyGenerator(params *Parameters) KeyGenerator {
+
+	if !params.isValid {
+		panic("cannot NewKeyGenerator: params not valid (check if they were generated properly)")
+	}
+
+	bgvContext := newBGVContext(params)
+
+	return &keyGenerator{
+		params:     params.Copy(),
+		bgvContext: bgvContext,
+		polypool:   [2]*ring.Poly{bgvContext.contextQP.NewPoly(), bgvContext.contextQP.NewPoly()},
+	}
+}
+
+// GenSecretKey creates a new SecretKey with the distribution [1/3, 1/3, 1/3].
+func (keygen *keyGenerator) GenSecretKey() (sk *SecretKey) {
+	return keygen.GenSecretkeyWithDistrib(1.0 / 3)
+}
+
+// GenSecretkeyWithDistrib creates a new SecretKey with the distribution [(1-p)/2, p, (1-p)/2].
+func (keygen *keyGenerator) GenSecretkeyWithDistrib(p float64) (sk *SecretKey) {
+	sk = new(SecretKey)
+	sk.sk = keygen.bgvContext.contextQP.SampleTernaryMontgomeryNTTNew(p)
+	return sk
+}
+
+// NewSecretKey generates a new SecretKey with zero values.
+func NewSecretKey(params *Parameters) *SecretKey {
+
+	if !params.isValid {
+		panic("cannot NewSecretKey: params not valid (check if they were generated properly)")
+	}
+
+	sk := new(SecretKey)
+	sk.sk = ring.NewPoly(uint64(1<<params.LogN), uint64(len(params.LogQi)+len(params.LogPi)))
+	return sk
+}
+
+// Get returns the polynomial of the target SecretKey.
+func (sk *SecretKey) Get() *ring.Poly {
+	return sk.sk
+}
+
+// Set sets the polynomial of the target secret key as the input polynomial.
+func (sk *SecretKey) Set(poly *ring.Poly) {
+	sk.sk = poly.CopyNew()
+}
+
+// GenPublicKey generates a new PublicKey from the provided SecretKey.
+func (keygen *keyGenerator) GenPublicKey(sk *SecretKey) (pk *PublicKey) {
+
+	pk = new(PublicKey)
+
+	ringContext := keygen.bgvContext.contextQP
+
+	//pk[0] = [-(a*s + t*e)]
+	//pk[1] = [a]
+	pk.pk[0] = keygen.sampleErrorNTTNew()
+	pk.pk[1] = ringContext.NewUniformPoly()
+
+	ringContext.MulCoeffsMontgomeryAndAdd(sk.sk, pk.pk[1], pk.pk[0])
+	ringContext.Neg(pk.pk[0], pk.pk[0])
+
+	return pk
+}
+
+// NewPublicKey returns a new PublicKey with zero values.
+func NewPublicKey(params *Parameters) (pk *PublicKey) {
+
+	if !params.isValid {
+		panic("cannot NewPublicKey: params not valid (check if they were generated properly)")
+	}
+
+	pk = new(PublicKey)
+
+	pk.pk[0] = ring.NewPoly(uint64(1<<params.LogN), uint64(len(params.LogQi)+len(params.LogPi)))
+	pk.pk[1] = ring.NewPoly(uint64(1<<params.LogN), uint64(len(params.LogQi)+len(params.LogPi)))
+
+	return
+}
+
+// Get returns the polynomials of the PublicKey.
+func (pk *PublicKey) Get() [2]*ring.Poly {
+	return pk.pk
+}
+
+// Set sets the polynomial of the PublicKey as the input polynomials.
+func (pk *PublicKey) Set(p [2]*ring.Poly) {
+	pk.pk[0] = p[0].CopyNew()
+	pk.pk[1] = p[1].CopyNew()
+}
+
+// GenKeyPair generates a new SecretKey with distribution [1/3, 1/3, 1/3] and a corresponding PublicKey.
+func (keygen *keyGenerator) GenKeyPair() (sk *SecretKey, pk *PublicKey) {
+	sk = keygen.GenSecretKey()
+	return sk, keygen.GenPublicKey(sk)
+}
+
+// GenRelinKey generates a new evaluation key from the provided SecretKey. It will be used to relinearize a ciphertext (encrypted under a PublicKey generated from the provided SecretKey)
+// of degree > 1 to a ciphertext of degree 1. Max degree is the maximum degree of the ciphertext allowed to relinearize.
+func (keygen *keyGenerator) GenRelinKey(sk *SecretKey, maxDegree uint64) (evk *EvaluationKey) {
+
+	if keygen.bgvContext.contextP == nil {
+		panic("Cannot GenRelinKey: modulus P is empty")
+	}
+
+	evk = new(EvaluationKey)
+
+	evk.evakey = make([]*SwitchingKey, maxDegree)
+
+	ringContext := keygen.bgvContext.contextQP
+
+	keygen.polypool[0].Copy(sk.Get())
+
+	for i := uint64(0); i < maxDegree; i++ {
+		ringContext.MulCoeffsMontgomery(keygen.polypool[0], sk.Get(), keygen.polypool[0])
+		evk.evakey[i] = keygen.newswitchingkey(keygen.polypool[0], sk.Get())
+	}
+
+	keygen.polypool[0].Zero()
+
+	return
+}
+
+// NewRelinKey creates a new EvaluationKey with zero values.
+func NewRelinKey(params *Parameters, maxDegree uint64) (evakey *EvaluationKey) {
+
+	if !params.isValid {
+		panic("cannot NewRelinKey: params not valid (check if they were generated properly)")
+	}
+
+	evakey = new(EvaluationKey)
+
+	evakey.evakey = make([]*SwitchingKey, maxDegree)
+
+	for w := uint64(0); w < maxDegree; w++ {
+		evakey.evakey[w] = NewSwitchingKey(params)
+	}
+
+	return
+}
+
+// Get returns the slice of SwitchingKeys of the target EvaluationKey.
+func (evk *EvaluationKey) Get() []*SwitchingKey {
+	return evk.evakey
+}
+
+// SetRelinKeys sets the polynomial of the target EvaluationKey as the input polynomials.
+func (evk *EvaluationKey) SetRelinKeys(rlk [][][2]*ring.Poly) {
+
+	evk.evakey = make([]*SwitchingKey, len(rlk))
+	for i := range rlk {
+		evk.evakey[i] = new(SwitchingKey)
+		evk.evakey[i].evakey = make([][2]*ring.Poly, len(rlk[i]))
+		for j := range rlk[i] {
+			evk.evakey[i].evakey[j][0] = rlk[i][j][0].CopyNew()
+			evk.evakey[i].evakey[j][1] = rlk[i][j][1].CopyNew()
+		}
+	}
+}
+
+// GenSwitchingKey generates a new key-switching key, that will allow to re-encrypt under the output-key a ciphertext encrypted under the input-key.
+func (keygen *keyGenerator) GenSwitchingKey(skIn, skOut *SecretKey) (evk *SwitchingKey) {
+
+	if keygen.bgvContext.contextP == nil {
+		panic("Cannot GenSwitchingKey: modulus P is empty")
+	}
+
+	return keygen.newswitchingkey(skIn.Get(), skOut.Get())
+}
+
+// NewSwitchingKey returns a new SwitchingKey with zero values.
+func NewSwitchingKey(params *Parameters) (evakey *SwitchingKey) {
+
+	if !params.isValid {
+		panic("cannot NewSwitchingKey: params not valid (check if they were generated properly)")
+	}
+
+	evakey = new(SwitchingKey)
+
+	beta := params.beta
+
+	evakey.evakey = make([][2]*ring.Poly, beta)
+
+	for i := uint64(0); i < beta; i++ {
+		evakey.evakey[i][0] = ring.NewPoly(uint64(1<<params.LogN), uint64(len(params.LogQi)+len(params.LogPi)))
+		evakey.evakey[i][1] = ring.NewPoly(uint64(1<<params.LogN), uint64(len(params.LogQi)+len(params.LogPi)))
+	}
+
+	return
+}
+
+// sampleErrorNTTNew samples a new Gaussian polynomial in the basis QP, multiplies it by T and returns it in the NTT domain.
+func (keygen *keyGenerator) sampleErrorNTTNew() (e *ring.Poly) {
+	ringContext := keygen.bgvContext.contextQP
+	e = keygen.bgvContext.gaussianSampler.SampleNew()
+	ringContext.MulScalar(e, keygen.params.T, e)
+	ringContext.NTT(e, e)
+	return
+}
+
+func (keygen *keyGenerator) newswitchingkey(skIn, skOut *ring.Poly) (switchkey *SwitchingKey) {
+
+	switchkey = new(SwitchingKey)
+
+	bgvContext := keygen.bgvContext
+	ringContext := bgvContext.contextQP
+
+	var index uint64
+
+	// P * skIn
+	ringContext.MulScalarBigint(skIn, bgvContext.contextP.ModulusBigint, keygen.polypool[1])
+
+	switchkey.evakey = make([][2]*ring.Poly, keygen.params.beta)
+
+	for i := uint64(0); i < keygen.params.beta; i++ {
+
+		// t * e
+		switchkey.evakey[i][0] = keygen.sampleErrorNTTNew()
+		ringContext.MForm(switchkey.evakey[i][0], switchkey.evakey[i][0])
+		// a (since a is uniform, we consider we already sample it in the NTT and Montgomery domain)
+		switchkey.evakey[i][1] = ringContext.NewUniformPoly()
+
+		// t * e + (skIn * P) * (q_star * q_tild) mod QP
+		//
+		// (q_star * q_tild) mod qi = 1 if qi is in the i-th decomposition basis, else 0
+		for j := uint64(0); j < keygen.params.alpha; j++ {
+
+			index = i*keygen.params.alpha + j
+
+			qi := ringContext.Modulus[index]
+			p0tmp := keygen.polypool[1].Coeffs[index]
+			p1tmp := switchkey.evakey[i][0].Coeffs[index]
+
+			for w := uint64(0); w < ringContext.N; w++ {
+				p1tmp[w] = ring.CRed(p1tmp[w]+p0tmp[w], qi)
+			}
+
+			// Handles the case where nb pj does not divide nb qi
+			if index >= bgvContext.levels-1 {
+				break
+			}
+		}
+
+		// (skIn * P) * (q_star * q_tild) - a * skOut + t * e mod QP
+		ringContext.MulCoeffsMontgomeryAndSub(switchkey.evakey[i][1], skOut, switchkey.evakey[i][0])
+	}
+
+	keygen.polypool[1].Zero()
+
+	return
+}
+
+// NewRotationKeys returns a new empty RotationKeys struct.
+func NewRotationKeys() (rotKey *RotationKeys) {
+	rotKey = new(RotationKeys)
+	return
+}
+
+// GenRot populates the target RotationKeys with a SwitchingKey for the desired rotation type and amount.
+func (keygen *keyGenerator) GenRot(rotType Rotation, sk *SecretKey, k uint64, rotKey *RotationKeys) {
+
+	if keygen.bgvContext.contextP == nil {
+		panic("Cannot GenRot: modulus P is empty")
+	}
+
+	N := keygen.bgvContext.n
+
+	k &= ((N >> 1) - 1)
+
+	switch rotType {
+	case RotationLeft:
+		if rotKey.evakeyRotColLeft == nil {
+			rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
+			rotKey.permuteNTTLeftIndex = make(map[uint64][]uint64)
+		}
+		if rotKey.evakeyRotColLeft[k] == nil && k != 0 {
+			rotKey.permuteNTTLeftIndex[k] = ring.PermuteNTTIndex(GaloisGen, k, N)
+			rotKey.evakeyRotColLeft[k] = keygen.genrotkey(sk.Get(), keygen.bgvContext.galElRotColLeft[k])
+		}
+	case RotationRight:
+		if rotKey.evakeyRotColRight == nil {
+			rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
+			rotKey.permuteNTTRightIndex = make(map[uint64][]uint64)
+		}
+		if rotKey.evakeyRotColRight[k] == nil && k != 0 {
+			rotKey.permuteNTTRightIndex[k] = ring.PermuteNTTIndex(GaloisGen, 2*N-k, N)
+			rotKey.evakeyRotColRight[k] = keygen.genrotkey(sk.Get(), keygen.bgvContext.galElRotColRight[k])
+		}
+	case RotationRow:
+		rotKey.permuteNTTRowIndex = ring.PermuteNTTIndex(keygen.bgvContext.galElRotRow, 1, N)
+		rotKey.evakeyRotRow = keygen.genrotkey(sk.Get(), keygen.bgvContext.galElRotRow)
+	}
+}
+
+// GenRotationKeysPow2 generates a new struct of RotationKeys that stores the keys of all the left and right powers of two rotations,
+// as well as the key of the row rotation. The provided SecretKey must be the SecretKey used to generate the PublicKey under
+// which the ciphertexts to rotate are encrypted.
+func (keygen *keyGenerator) GenRotationKeysPow2(sk *SecretKey) (rotKey *RotationKeys) {
+
+	rotKey = NewRotationKeys()
+
+	for n := uint64(1); n < keygen.bgvContext.n>>1; n <<= 1 {
+		keygen.GenRot(RotationLeft, sk, n, rotKey)
+		keygen.GenRot(RotationRight, sk, n, rotKey)
+	}
+
+	keygen.GenRot(RotationRow, sk, 0, rotKey)
+
+	return
+}
+
+// SetRotKey populates the target RotationKeys with a new SwitchingKey using the input polynomials.
+func (rotKey *RotationKeys) SetRotKey(params *Parameters, rotType Rotation, k uint64, evakey [][2]*ring.Poly) {
+
+	N := uint64(1 << params.LogN)
+
+	switchkey := new(SwitchingKey)
+	switchkey.evakey = make([][2]*ring.Poly, len(evakey))
+	for j := range evakey {
+		switchkey.evakey[j][0] = evakey[j][0].CopyNew()
+		switchkey.evakey[j][1] = evakey[j][1].CopyNew()
+	}
+
+	switch rotType {
+	case RotationLeft:
+		if rotKey.evakeyRotColLeft == nil {
+			rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
+			rotKey.permuteNTTLeftIndex = make(map[uint64][]uint64)
+		}
+		if rotKey.evakeyRotColLeft[k] == nil && k != 0 {
+			rotKey.permuteNTTLeftIndex[k] = ring.PermuteNTTIndex(GaloisGen, k, N)
+			rotKey.evakeyRotColLeft[k] = switchkey
+		}
+	case RotationRight:
+		if rotKey.evakeyRotColRight == nil {
+			rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
+			rotKey.permuteNTTRightIndex = make(map[uint64][]uint64)
+		}
+		if rotKey.evakeyRotColRight[k] == nil && k != 0 {
+			rotKey.permuteNTTRightIndex[k] = ring.PermuteNTTIndex(GaloisGen, 2*N-k, N)
+			rotKey.evakeyRotColRight[k] = switchkey
+		}
+	case RotationRow:
+		if rotKey.evakeyRotRow == nil {
+			rotKey.permuteNTTRowIndex = ring.PermuteNTTIndex(2*N-1, 1, N)
+			rotKey.evakeyRotRow = switchkey
+		}
+	}
+}
+
+func (keygen *keyGenerator) genrotkey(sk *ring.Poly, gen uint64) (switchkey *SwitchingKey) {
+
+	ring.PermuteNTT(sk, gen, keygen.polypool[0])
+
+	switchkey = keygen.newswitchingkey(keygen.polypool[0], sk)
+	keygen.polypool[0].Zero()
+
+	return
+}
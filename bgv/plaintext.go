@@ -0,0 +1,23 @@
+package bgv
+
+import (
+	"github.com/ldsec/lattigo/ring"
+)
+
+// Plaintext is a bgvElement with only one Poly.
+type Plaintext struct {
+	*bgvElement
+	value *ring.Poly
+}
+
+// NewPlaintext creates a new plaintext of the target level from the target context.
+func NewPlaintext(params *Parameters, level uint64) *Plaintext {
+
+	if !params.isValid {
+		panic("cannot NewPlaintext: params not valid (check if they were generated properly)")
+	}
+
+	plaintext := &Plaintext{newBgvElement(params, 0, level), nil}
+	plaintext.value = plaintext.bgvElement.value[0]
+	return plaintext
+}
@@ -0,0 +1,92 @@
+package bgv
+
+import (
+	"github.com/ldsec/lattigo/ring"
+	"math/bits"
+)
+
+// GenModuli generates the appropriate primes from the parameters using generateBGVPrimes such that all primes are different.
+// The ciphertext moduli Qi are congruent to 1 modulo 2N and T, while the keys additional moduli Pi only need to be
+// congruent to 1 modulo 2N.
+func GenModuli(params *Parameters) (Q []uint64, P []uint64) {
+
+	for _, qi := range params.LogQi {
+		if qi > 60 {
+			panic("cannot GenModuli: the provided LogQi must be smaller than 61")
+		}
+	}
+
+	for _, pj := range params.LogPi {
+		if pj > 60 {
+			panic("cannot GenModuli: the provided LogPi must be smaller than 61")
+		}
+	}
+
+	// Smallest common multiple of 2N and T (T is either odd or a power of two)
+	stepQ := uint64(2 << params.LogN)
+	if params.T&(params.T-1) == 0 {
+		if params.T > stepQ {
+			stepQ = params.T
+		}
+	} else {
+		stepQ *= params.T
+	}
+
+	// The Pi are chosen not congruent to 1 modulo T to ensure that they are different from the Qi
+	Q = assignPrimes(params.LogQi, stepQ, func(x uint64) bool { return true })
+	P = assignPrimes(params.LogPi, uint64(2<<params.LogN), func(x uint64) bool { return x%params.T != 1 })
+
+	return Q, P
+}
+
+// assignPrimes generates, for each bit-size of logModuli, the required number of different primes congruent to 1 modulo step
+// and satisfying the given condition, and assigns them to a moduli chain.
+func assignPrimes(logModuli []uint64, step uint64, condition func(uint64) bool) (moduli []uint64) {
+
+	// Extracts all the different primes bit-size and maps their number
+	primesbitlen := make(map[uint64]uint64)
+	for _, logqi := range logModuli {
+		primesbitlen[logqi]++
+	}
+
+	// For each bit-size, it finds that many primes
+	primes := make(map[uint64][]uint64)
+	for key, value := range primesbitlen {
+		primes[key] = generateBGVPrimes(key, step, value, condition)
+	}
+
+	moduli = make([]uint64, len(logModuli))
+	for i, logqi := range logModuli {
+		moduli[i] = primes[logqi][0]
+		primes[logqi] = primes[logqi][1:]
+	}
+
+	return
+}
+
+// generateBGVPrimes generates n primes of bit-size logQ+1 that are congruent to 1 modulo step and satisfy the given condition.
+// The step must be a multiple of 2N to enable the NTT; the ciphertext moduli also require it to be a multiple of T, so that
+// the modulus switching does not modify the plaintext.
+func generateBGVPrimes(logQ, step, n uint64, condition func(uint64) bool) (primes []uint64) {
+
+	if logQ > 60 {
+		panic("logQ must be between 1 and 60")
+	}
+
+	primes = []uint64{}
+
+	// Smallest integer larger than 2^logQ and congruent to 1 modulo step
+	x := ((uint64(1<<logQ)+step-1)/step)*step + 1
+
+	for ; uint64(bits.Len64(x)) == logQ+1; x += step {
+
+		if condition(x) && ring.IsPrime(x) {
+			primes = append(primes, x)
+			if uint64(len(primes)) == n {
+				return
+			}
+		}
+	}
+
+	panic("cannot generateBGVPrimes: not enough primes for the given bit-size")
+}
@@ -8,6 +8,8 @@ import (
 // key is necessary when logPow2 > 1.
 func (eval *evaluator) PowerOf2(op *Ciphertext, logPow2 uint64, evakey *EvaluationKey, opOut *Ciphertext) {
 
+	discardSeed(opOut)
+
 	if logPow2 == 0 {
 
 		if op != opOut {
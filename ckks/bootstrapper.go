@@ -0,0 +1,300 @@
+package ckks
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// Bootstrapper is a struct storing the elements (evaluator, keys and pre-computed plaintext matrices)
+// required to homomorphically refresh CKKS ciphertexts.
+type Bootstrapper struct {
+	params    *BootstrappingParameters
+	evaluator *evaluator
+	encoder   Encoder
+
+	relinkey *EvaluationKey
+	rotkeys  *RotationKeys
+
+	slots uint64 // Number of plaintext slots
+	gap   uint64 // N/(2*slots)
+
+	sinePoly *ChebyshevInterpolation // Chebyshev interpolant of the scaled cosine
+
+	pDFTInv []*LinearTransform // CoeffsToSlots matrices, in order of evaluation
+	pDFT    []*LinearTransform // SlotsToCoeffs matrices, in order of evaluation
+}
+
+// BootstrappingKey is a struct storing the relinearization key and the rotation keys
+// required by the bootstrapping.
+type BootstrappingKey struct {
+	relinkey *EvaluationKey
+	rotkeys  *RotationKeys
+}
+
+// NewBootstrappingKey creates a new BootstrappingKey from the provided relinearization and rotation keys.
+func NewBootstrappingKey(relinkey *EvaluationKey, rotkeys *RotationKeys) *BootstrappingKey {
+	return &BootstrappingKey{relinkey: relinkey, rotkeys: rotkeys}
+}
+
+// Get returns the relinearization key and the rotation keys of the BootstrappingKey.
+func (btpKey *BootstrappingKey) Get() (relinkey *EvaluationKey, rotkeys *RotationKeys) {
+	return btpKey.relinkey, btpKey.rotkeys
+}
+
+// NewBootstrapper creates a new Bootstrapper from the given parameters and bootstrapping key.
+// The bootstrapping key must have been generated from a sparse secret key of Hamming weight btpParams.H.
+func NewBootstrapper(btpParams *BootstrappingParameters, btpKey *BootstrappingKey) (btp *Bootstrapper) {
+
+	if err := btpParams.checkDepth(); err != nil {
+		panic("cannot NewBootstrapper: " + err.Error())
+	}
+
+	if btpKey == nil || btpKey.relinkey == nil || btpKey.rotkeys == nil {
+		panic("cannot NewBootstrapper: bootstrapping key is incomplete")
+	}
+
+	btp = new(Bootstrapper)
+
+	btp.params = btpParams.Copy()
+
+	// The evaluator uses SinScale as its default scale, so that the rescaling during the
+	// evaluation of the modular reduction consumes exactly one level per multiplication.
+	paramsSine := btp.params.Parameters.Copy()
+	paramsSine.Scale = btp.params.SinScale
+
+	btp.evaluator = NewEvaluator(paramsSine).(*evaluator)
+	btp.encoder = NewEncoder(paramsSine)
+
+	btp.relinkey = btpKey.relinkey
+	btp.rotkeys = btpKey.rotkeys
+
+	btp.slots = 1 << btp.params.LogSlots
+	btp.gap = 1 << (btp.params.LogN - 1 - btp.params.LogSlots)
+
+	K := complex(float64(btp.params.SinRange), 0)
+	sc := complex(math.Exp2(float64(btp.params.SinRescal)), 0)
+
+	btp.sinePoly = Approximate(func(x complex128) complex128 {
+		return cmplx.Cos(2 * math.Pi * (K*x - 0.25) / sc)
+	}, -1, 1, int(btp.params.SinDeg))
+
+	btp.genDFTMatrices()
+
+	return btp
+}
+
+// genDFTMatrices encodes the diagonals of the CoeffsToSlots and SlotsToCoeffs linear transforms.
+func (btp *Bootstrapper) genDFTMatrices() {
+
+	q0 := float64(btp.params.Qi[0])
+
+	// CoeffsToSlots: maps the coefficients (times SinScale/q0) on the slots and scales them by 1/(2K) to fit the
+	// interval of the modular reduction. The factor 1/gap compensates the trace applied on sparse plaintexts.
+	scaling := btp.params.SinScale / (q0 * 2 * float64(btp.params.SinRange) * float64(btp.gap*btp.slots))
+
+	btp.pDFTInv = make([]*LinearTransform, 0)
+	for i, diags := range genDFTDiagonals(btp.params.LogSlots, btp.params.CtSDepth, false, complex(scaling, 0)) {
+		btp.pDFTInv = append(btp.pDFTInv, btp.encodeDFTMatrix(diags, btp.params.CtSLevel()-uint64(i)))
+	}
+
+	// SlotsToCoeffs: maps the slots back on the coefficients and removes the factor 2pi*SinScale/q0 introduced
+	// by the modular reduction.
+	scaling = q0 / (2 * math.Pi * btp.params.SinScale)
+
+	btp.pDFT = make([]*LinearTransform, 0)
+	for i, diags := range genDFTDiagonals(btp.params.LogSlots, btp.params.StCDepth, true, complex(scaling, 0)) {
+		btp.pDFT = append(btp.pDFT, btp.encodeDFTMatrix(diags, btp.params.StCLevel()-uint64(i)))
+	}
+}
+
+// encodeDFTMatrix encodes the given diagonals on plaintexts at the given level, with a scale equal
+// to the modulus of this level, such that a subsequent rescaling preserves the scale of the ciphertext.
+func (btp *Bootstrapper) encodeDFTMatrix(diags map[uint64][]complex128, level uint64) *LinearTransform {
+	return NewLinearTransform(&btp.params.Parameters, btp.encoder, diags, level, float64(btp.params.Qi[level]))
+}
+
+// rotationsForBootstrapping returns the list of left rotations required by the bootstrapping.
+func (b *BootstrappingParameters) rotationsForBootstrapping() (rotations []uint64) {
+
+	slots := uint64(1 << b.LogSlots)
+
+	rotMap := make(map[uint64]bool)
+
+	// Rotations of the trace for sparse plaintexts
+	for i := b.LogSlots; i < b.LogN-1; i++ {
+		rotMap[1<<i] = true
+	}
+
+	for _, forward := range []bool{false, true} {
+
+		depth := b.CtSDepth
+		if forward {
+			depth = b.StCDepth
+		}
+
+		for _, diags := range genDFTDiagonals(b.LogSlots, depth, forward, 1) {
+
+			indexes := make([]uint64, 0, len(diags))
+			for i := range diags {
+				indexes = append(indexes, i)
+			}
+
+			for _, k := range bsgsRotations(indexes, findBestBSGSSplit(diags, slots)) {
+				rotMap[k] = true
+			}
+		}
+	}
+
+	rotations = make([]uint64, 0, len(rotMap))
+	for k := range rotMap {
+		rotations = append(rotations, k)
+	}
+
+	sort.Slice(rotations, func(i, j int) bool { return rotations[i] < rotations[j] })
+
+	return
+}
+
+// genDFTDiagonals returns the diagonals of the homomorphic (inverse) special DFT, split into depth matrices
+// given in their order of evaluation. The inverse DFT maps the coefficient domain to the bit-reversed slots
+// and the DFT maps the bit-reversed slots back to the coefficient domain, so the bit-reversal permutation
+// is never evaluated. The first matrix is multiplied by the given scaling factor.
+func genDFTDiagonals(logSlots, depth uint64, forward bool, scaling complex128) (matrices []map[uint64][]complex128) {
+
+	slots := uint64(1 << logSlots)
+
+	stages := genDFTStages(logSlots, forward)
+
+	if depth > logSlots {
+		depth = logSlots
+	}
+
+	for i := range stages[0] {
+		for j := range stages[0][i] {
+			stages[0][i][j] *= scaling
+		}
+	}
+
+	matrices = make([]map[uint64][]complex128, depth)
+
+	// Merges the stages into depth matrices of roughly the same number of stages
+	idx := 0
+	for i := uint64(0); i < depth; i++ {
+
+		nbStages := logSlots / depth
+		if i < logSlots%depth {
+			nbStages++
+		}
+
+		matrices[i] = stages[idx]
+		idx++
+
+		for j := uint64(1); j < nbStages; j++ {
+			matrices[i] = multiplyDiagMatrices(stages[idx], matrices[i], slots)
+			idx++
+		}
+	}
+
+	return
+}
+
+// genDFTStages returns the log2(slots) butterfly stages of the special DFT (or of its inverse), represented by
+// their non-zero generalized diagonals (diag_k[i] = M[i][i+k]) and given in their order of evaluation.
+func genDFTStages(logSlots uint64, forward bool) (stages []map[uint64][]complex128) {
+
+	slots := uint64(1 << logSlots)
+	m := slots << 2
+
+	rotGroup := make([]uint64, slots)
+	fivePows := uint64(1)
+	for i := uint64(0); i < slots; i++ {
+		rotGroup[i] = fivePows
+		fivePows *= GaloisGen
+		fivePows &= (m - 1)
+	}
+
+	roots := make([]complex128, m)
+	for i := uint64(0); i < m; i++ {
+		angle := 2 * math.Pi * float64(i) / float64(m)
+		roots[i] = complex(math.Cos(angle), math.Sin(angle))
+	}
+
+	stages = make([]map[uint64][]complex128, 0, logSlots)
+
+	for i := uint64(0); i < logSlots; i++ {
+
+		var lenh uint64
+		if forward {
+			lenh = 1 << i
+		} else {
+			lenh = slots >> (i + 1)
+		}
+
+		lenq := lenh << 3
+		gap := m / lenq
+
+		d0 := make([]complex128, slots)
+		dPlus := make([]complex128, slots)
+		dMinus := make([]complex128, slots)
+
+		for j := uint64(0); j < slots; j += lenh << 1 {
+			for k := uint64(0); k < lenh; k++ {
+
+				if forward {
+					w := roots[(rotGroup[k]%lenq)*gap]
+					d0[j+k] = 1
+					dPlus[j+k] = w
+					d0[j+k+lenh] = -w
+					dMinus[j+k+lenh] = 1
+				} else {
+					w := roots[(lenq-(rotGroup[k]%lenq))*gap]
+					d0[j+k] = 1
+					dPlus[j+k] = 1
+					d0[j+k+lenh] = -w
+					dMinus[j+k+lenh] = w
+				}
+			}
+		}
+
+		stage := make(map[uint64][]complex128)
+		stage[0] = d0
+
+		if lenh == slots>>1 {
+			for j := range dPlus {
+				dPlus[j] += dMinus[j]
+			}
+			stage[lenh] = dPlus
+		} else {
+			stage[lenh] = dPlus
+			stage[slots-lenh] = dMinus
+		}
+
+		stages = append(stages, stage)
+	}
+
+	return
+}
+
+// multiplyDiagMatrices returns the diagonals of the matrix product A * B.
+func multiplyDiagMatrices(A, B map[uint64][]complex128, slots uint64) (C map[uint64][]complex128) {
+
+	C = make(map[uint64][]complex128)
+
+	for a, va := range A {
+		for b, vb := range B {
+
+			k := (a + b) % slots
+
+			if C[k] == nil {
+				C[k] = make([]complex128, slots)
+			}
+
+			for i := uint64(0); i < slots; i++ {
+				C[k][i] += va[i] * vb[(i+a)%slots]
+			}
+		}
+	}
+
+	return
+}
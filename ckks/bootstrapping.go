@@ -0,0 +1,175 @@
+package ckks
+
+// Bootstrapp re-encrypts a ciphertext at level 0 to a ciphertext at level OutputLevel, with the same scale.
+// Only the level 0 of the input ciphertext is used, which is not modified. The scale of the input ciphertext
+// must be small compared to the first modulus of the moduli chain (Q0).
+func (btp *Bootstrapper) Bootstrapp(ct *Ciphertext) (ciphertext *Ciphertext) {
+
+	scale := ct.Scale()
+
+	// Brings the ciphertext from Q0 to Q
+	ciphertext = btp.modUp(ct)
+
+	// Zeroes the coefficients that are not multiples of N/(2*slots) (only for sparse plaintexts)
+	btp.subSum(ciphertext)
+
+	// Moves the coefficients on the slots and splits them in two ciphertexts
+	ct0, ct1 := btp.coeffsToSlots(ciphertext)
+
+	// Homomorphic modular reduction by Q0
+	ct0 = btp.evaluateSine(ct0)
+	ct1 = btp.evaluateSine(ct1)
+
+	// Moves the slots back on the coefficients
+	ciphertext = btp.slotsToCoeffs(ct0, ct1)
+
+	if ciphertext.Level() > btp.params.OutputLevel() {
+		btp.evaluator.DropLevel(ciphertext, ciphertext.Level()-btp.params.OutputLevel())
+	}
+
+	// The plaintext was implicitly scaled by SinScale after the ModUp
+	ciphertext.MulScale(scale / btp.params.SinScale)
+
+	return
+}
+
+// modUp reads the level 0 of the input ciphertext as an element of Z_Q0 and lifts it to a ciphertext at MaxLevel.
+// The result encrypts the original plaintext plus Q0 * I(X), where I(X) is a polynomial with small coefficients.
+func (btp *Bootstrapper) modUp(ct *Ciphertext) (ctOut *Ciphertext) {
+
+	contextQ := btp.evaluator.ckksContext.contextQ
+
+	ctOut = NewCiphertext(&btp.params.Parameters, ct.Degree(), btp.params.MaxLevel(), btp.params.SinScale)
+
+	q0 := contextQ.Modulus[0]
+	q0Half := q0 >> 1
+
+	for u := range ctOut.Value() {
+
+		contextQ.InvNTTLvl(0, ct.Value()[u], ctOut.Value()[u])
+
+		coeffs := ctOut.Value()[u].Coeffs
+
+		for j := uint64(0); j < contextQ.N; j++ {
+
+			coeff := coeffs[0][j]
+
+			// Centers the coefficient in [-Q0/2, Q0/2) and reduces it modulo each Qi
+			if coeff >= q0Half {
+				coeff = q0 - coeff
+				for i := 1; i < len(coeffs); i++ {
+					qi := contextQ.Modulus[i]
+					coeffs[i][j] = (qi - (coeff % qi)) % qi
+				}
+			} else {
+				for i := 1; i < len(coeffs); i++ {
+					coeffs[i][j] = coeff % contextQ.Modulus[i]
+				}
+			}
+		}
+
+		contextQ.NTT(ctOut.Value()[u], ctOut.Value()[u])
+	}
+
+	return
+}
+
+// subSum applies the trace from Z[X]/(X^N+1) to Z[X^gap]/(X^N+1), which zeroes the coefficients that are not
+// multiples of gap = N/(2*slots) and multiplies the others by gap.
+func (btp *Bootstrapper) subSum(ct *Ciphertext) {
+
+	eval := btp.evaluator
+
+	tmp := NewCiphertext(&btp.params.Parameters, 1, ct.Level(), ct.Scale())
+
+	for i := btp.params.LogSlots; i < btp.params.LogN-1; i++ {
+		eval.RotateColumns(ct, 1<<i, btp.rotkeys, tmp)
+		eval.Add(ct, tmp, ct)
+	}
+}
+
+// coeffsToSlots homomorphically evaluates the inverse special DFT, then splits the real and imaginary parts
+// of the result in two ciphertexts.
+func (btp *Bootstrapper) coeffsToSlots(vec *Ciphertext) (ct0, ct1 *Ciphertext) {
+
+	eval := btp.evaluator
+
+	zV := vec
+	for _, matrix := range btp.pDFTInv {
+		zV = btp.multiplyByDiagMatrix(zV, matrix)
+	}
+
+	// Aligns the level with the start of the modular reduction if the DFT has been merged in less matrices
+	if zV.Level() > btp.params.SineLevel() {
+		eval.DropLevel(zV, zV.Level()-btp.params.SineLevel())
+	}
+
+	zVconj := eval.ConjugateNew(zV, btp.rotkeys)
+
+	// Real part
+	ct0 = eval.AddNew(zV, zVconj)
+
+	// Imaginary part
+	ct1 = eval.SubNew(zV, zVconj)
+	eval.DivByi(ct1, ct1)
+
+	return
+}
+
+// slotsToCoeffs merges the two input ciphertexts into a single complex ciphertext and homomorphically evaluates
+// the special DFT on it.
+func (btp *Bootstrapper) slotsToCoeffs(ct0, ct1 *Ciphertext) (ct *Ciphertext) {
+
+	eval := btp.evaluator
+
+	eval.MultByi(ct1, ct1)
+	eval.Add(ct0, ct1, ct0)
+
+	ct = ct0
+	for _, matrix := range btp.pDFT {
+		ct = btp.multiplyByDiagMatrix(ct, matrix)
+	}
+
+	return
+}
+
+// evaluateSine homomorphically evaluates x -> sin(2*pi*K*x)/(2*pi) ~ x mod 1 on the input ciphertext by first
+// evaluating a Chebyshev interpolant of a scaled cosine and then applying the double angle formula.
+func (btp *Bootstrapper) evaluateSine(ct *Ciphertext) (res *Ciphertext) {
+
+	eval := btp.evaluator
+
+	res = eval.EvaluateChebyFast(ct, btp.sinePoly, btp.relinkey)
+
+	for i := uint64(0); i < btp.params.SinRescal; i++ {
+		// res = 2*res^2 - 1
+		eval.MulRelin(res, res, btp.relinkey, res)
+		eval.Rescale(res, eval.ckksContext.scale, res)
+		eval.Add(res, res, res)
+		eval.AddConst(res, -1, res)
+	}
+
+	if res.Level() < btp.params.StCLevel() {
+		panic("cannot Bootstrapp: the evaluation of the modular reduction consumed more levels than expected")
+	}
+
+	// Aligns the level with the start of the SlotsToCoeffs
+	if res.Level() > btp.params.StCLevel() {
+		eval.DropLevel(res, res.Level()-btp.params.StCLevel())
+	}
+
+	return
+}
+
+// multiplyByDiagMatrix homomorphically evaluates the plaintext linear transform on the input ciphertext using
+// the baby-step giant-step algorithm, and rescales the result.
+func (btp *Bootstrapper) multiplyByDiagMatrix(ct *Ciphertext, matrix *LinearTransform) (res *Ciphertext) {
+
+	eval := btp.evaluator
+
+	res = eval.LinearTransformNew(ct, matrix, btp.rotkeys)
+
+	eval.RescaleMany(res, 1, res)
+
+	return
+}
@@ -0,0 +1,143 @@
+package ckks
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+func init() {
+	for _, params := range DefaultBootstrappingParams {
+		params.GenFromLogModuli()
+	}
+}
+
+// BootstrappingParameters is a struct storing the parameters of the bootstrapping circuit, along with the
+// underlying CKKS parameters. The moduli chain must be ordered as follows (from level 0 to MaxLevel):
+//
+// [q0] [user levels] [SlotsToCoeffs levels] [EvalMod levels] [CoeffsToSlots levels]
+//
+// The moduli of the EvalMod levels should be as close as possible to SinScale.
+type BootstrappingParameters struct {
+	Parameters
+	H         uint64  // Hamming weight of the secret key
+	SinRange  uint64  // Interval [-K, K] on which the modular reduction is approximated
+	SinDeg    uint64  // Degree of the Chebyshev interpolant of the scaled cosine
+	SinRescal uint64  // Number of double angle formula evaluations
+	SinScale  float64 // Scale at which the modular reduction is evaluated
+	CtSDepth  uint64  // Depth of the homomorphic CoeffsToSlots (linear transform to the slots)
+	StCDepth  uint64  // Depth of the homomorphic SlotsToCoeffs (linear transform to the coefficients)
+}
+
+// DefaultBootstrappingParams is a set of default bootstrapping parameters, which use a sparse secret of Hamming weight H.
+// Neither their ring degree nor their sparse secret is covered by the Homomorphic Encryption Standard, hence their
+// security is not estimated by SecurityLevel.
+var DefaultBootstrappingParams = []*BootstrappingParameters{
+
+	// LogSlots = 15, LogQP = 1534
+	{
+		H:         192,
+		SinRange:  25,
+		SinDeg:    48,
+		SinRescal: 3,
+		SinScale:  1 << 60,
+		CtSDepth:  4,
+		StCDepth:  3,
+		Parameters: Parameters{
+			LogN:     16,
+			LogSlots: 15,
+			LogModuli: LogModuli{
+				LogQi: []uint64{55, 40, 40, 40, 40, 40, 40, 40, 45, 45, 45, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 56, 56, 56, 56},
+				LogPi: []uint64{60, 60, 60, 60},
+			},
+			Scale: 1 << 40,
+			Sigma: 3.2,
+		},
+	},
+
+	// LogSlots = 14, LogQP = 1513
+	{
+		H:         192,
+		SinRange:  25,
+		SinDeg:    48,
+		SinRescal: 3,
+		SinScale:  1 << 60,
+		CtSDepth:  3,
+		StCDepth:  2,
+		Parameters: Parameters{
+			LogN:     16,
+			LogSlots: 14,
+			LogModuli: LogModuli{
+				LogQi: []uint64{55, 40, 40, 40, 40, 40, 40, 40, 40, 40, 45, 45, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 56, 56, 56},
+				LogPi: []uint64{60, 60, 60, 60},
+			},
+			Scale: 1 << 40,
+			Sigma: 3.2,
+		},
+	},
+}
+
+// Copy creates a copy of the target BootstrappingParameters.
+func (b *BootstrappingParameters) Copy() *BootstrappingParameters {
+	paramsCopy := &BootstrappingParameters{
+		Parameters: *b.Parameters.Copy(),
+		H:          b.H,
+		SinRange:   b.SinRange,
+		SinDeg:     b.SinDeg,
+		SinRescal:  b.SinRescal,
+		SinScale:   b.SinScale,
+		CtSDepth:   b.CtSDepth,
+		StCDepth:   b.StCDepth,
+	}
+	return paramsCopy
+}
+
+// SinDepth returns the number of levels consumed by the homomorphic evaluation of the modular reduction.
+func (b *BootstrappingParameters) SinDepth() uint64 {
+	return uint64(bits.Len64(b.SinDeg-1)) + 1 + b.SinRescal
+}
+
+// CtSLevel returns the level at which the CoeffsToSlots step starts.
+func (b *BootstrappingParameters) CtSLevel() uint64 {
+	return b.MaxLevel()
+}
+
+// SineLevel returns the level at which the evaluation of the modular reduction starts.
+func (b *BootstrappingParameters) SineLevel() uint64 {
+	return b.CtSLevel() - b.CtSDepth
+}
+
+// StCLevel returns the level at which the SlotsToCoeffs step starts.
+func (b *BootstrappingParameters) StCLevel() uint64 {
+	return b.SineLevel() - b.SinDepth()
+}
+
+// OutputLevel returns the level of the ciphertexts output by the bootstrapping.
+func (b *BootstrappingParameters) OutputLevel() uint64 {
+	return b.StCLevel() - b.StCDepth
+}
+
+// checkDepth returns an error if the moduli chain cannot accommodate the depth of the bootstrapping circuit.
+func (b *BootstrappingParameters) checkDepth() error {
+
+	if !b.IsValid() {
+		return fmt.Errorf("parameters not generated or invalid")
+	}
+
+	if b.LogSlots == 0 {
+		return fmt.Errorf("LogSlots must be at least 1")
+	}
+
+	if b.CtSDepth == 0 || b.StCDepth == 0 {
+		return fmt.Errorf("CtSDepth and StCDepth must be at least 1")
+	}
+
+	if b.SinDeg < 2 {
+		return fmt.Errorf("SinDeg must be at least 2")
+	}
+
+	if b.MaxLevel() < b.CtSDepth+b.SinDepth()+b.StCDepth+1 {
+		return fmt.Errorf("#Qi (%d) is too small for a bootstrapping circuit of depth %d", len(b.Qi), b.CtSDepth+b.SinDepth()+b.StCDepth)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package ckks
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// testBootstrappParams are small (insecure) parameters used to test the correctness of the bootstrapping circuit.
+var testBootstrappParams = []*BootstrappingParameters{
+
+	{
+		H:         64,
+		SinRange:  12,
+		SinDeg:    30,
+		SinRescal: 3,
+		SinScale:  1 << 60,
+		CtSDepth:  3,
+		StCDepth:  2,
+		Parameters: Parameters{
+			LogN:     12,
+			LogSlots: 11,
+			LogModuli: LogModuli{
+				LogQi: []uint64{55, 40, 40, 45, 45, 60, 60, 60, 60, 60, 60, 60, 60, 60, 55, 55, 55},
+				LogPi: []uint64{60, 60},
+			},
+			Scale: 1 << 40,
+			Sigma: 3.2,
+		},
+	},
+
+	{
+		H:         64,
+		SinRange:  12,
+		SinDeg:    30,
+		SinRescal: 3,
+		SinScale:  1 << 60,
+		CtSDepth:  2,
+		StCDepth:  2,
+		Parameters: Parameters{
+			LogN:     12,
+			LogSlots: 8,
+			LogModuli: LogModuli{
+				LogQi: []uint64{55, 40, 40, 45, 45, 60, 60, 60, 60, 60, 60, 60, 60, 60, 55, 55},
+				LogPi: []uint64{60, 60},
+			},
+			Scale: 1 << 40,
+			Sigma: 3.2,
+		},
+	},
+}
+
+func init() {
+	for _, params := range testBootstrappParams {
+		params.GenFromLogModuli()
+	}
+}
+
+func TestBootstrapp(t *testing.T) {
+	t.Run("DefaultParameters", testBootstrappDefaultParameters)
+	t.Run("DFT", testBootstrappDFT)
+	t.Run("Bootstrapp", testBootstrapp)
+}
+
+func testBootstrappDefaultParameters(t *testing.T) {
+
+	for _, btpParams := range DefaultBootstrappingParams {
+
+		t.Run(testString(fmt.Sprintf("logSlots=%d/", btpParams.LogSlots), &btpParams.Parameters), func(t *testing.T) {
+
+			if err := btpParams.checkDepth(); err != nil {
+				t.Error(err)
+			}
+
+			if btpParams.OutputLevel() == 0 {
+				t.Errorf("no level left after the bootstrapping")
+			}
+
+			for _, qi := range btpParams.Qi[btpParams.StCLevel()+1 : btpParams.SineLevel()+1] {
+				if math.Abs(math.Log2(float64(qi))-math.Log2(btpParams.SinScale)) > 0.5 {
+					t.Errorf("EvalMod moduli must be close to SinScale")
+				}
+			}
+		})
+	}
+}
+
+func testBootstrappDFT(t *testing.T) {
+
+	// Checks that the product of the inverse DFT and the DFT matrices is the identity
+	for _, logSlots := range []uint64{1, 4, 7} {
+
+		t.Run(fmt.Sprintf("logSlots=%d", logSlots), func(t *testing.T) {
+
+			slots := uint64(1 << logSlots)
+
+			matrices := append(genDFTDiagonals(logSlots, 3, false, complex(1/float64(slots), 0)), genDFTDiagonals(logSlots, 2, true, 1)...)
+
+			prod := matrices[0]
+			for _, m := range matrices[1:] {
+				prod = multiplyDiagMatrices(m, prod, slots)
+			}
+
+			for k, diag := range prod {
+				for _, v := range diag {
+					if (k == 0 && math.Abs(real(v)-1) > 1e-9) || (k == 0 && math.Abs(imag(v)) > 1e-9) || (k != 0 && math.Abs(real(v))+math.Abs(imag(v)) > 1e-9) {
+						t.Errorf("DFT * invDFT is not the identity")
+						return
+					}
+				}
+			}
+		})
+	}
+}
+
+func testBootstrapp(t *testing.T) {
+
+	for _, btpParams := range testBootstrappParams {
+
+		params := &btpParams.Parameters
+
+		kgen := NewKeyGenerator(params)
+		sk := kgen.GenSecretKeySparse(btpParams.H)
+		encoder := NewEncoder(params)
+		encryptor := NewEncryptorFromSk(params, sk)
+		decryptor := NewDecryptor(params, sk)
+		evaluator := NewEvaluator(params)
+
+		btp := NewBootstrapper(btpParams, kgen.GenBootstrappingKey(btpParams, sk))
+
+		contextParams := &ckksParams{params: params, encoder: encoder}
+
+		t.Run(testString(fmt.Sprintf("logSlots=%d/", btpParams.LogSlots), params), func(t *testing.T) {
+
+			slots := uint64(1 << params.LogSlots)
+
+			values := make([]complex128, slots)
+			for i := range values {
+				values[i] = randomComplex(-1, 1)
+			}
+
+			plaintext := NewPlaintext(params, params.MaxLevel(), params.Scale)
+			encoder.Encode(plaintext, values, slots)
+
+			ciphertext := encryptor.EncryptNew(plaintext)
+			evaluator.DropLevel(ciphertext, ciphertext.Level())
+
+			ciphertext = btp.Bootstrapp(ciphertext)
+
+			if ciphertext.Level() != btpParams.OutputLevel() {
+				t.Errorf("output level is %d but should be %d", ciphertext.Level(), btpParams.OutputLevel())
+			}
+
+			verifyTestVectors(contextParams, decryptor, values, ciphertext, t)
+		})
+	}
+}
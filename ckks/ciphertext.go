@@ -7,6 +7,12 @@ import (
 // Ciphertext is *ring.Poly array representing a polynomial of degree > 0 with coefficients in R_Q.
 type Ciphertext struct {
 	*ckksElement
+
+	// seed is set for the ciphertexts encrypted by a seeded Encryptor, and is used
+	// in place of value[1] when marshaling the ciphertext. It is discarded by the
+	// evaluator when the ciphertext is used as a receiver, and ignored when marshaling
+	// if value[1] was modified through Value.
+	seed *ring.PolySeed
 }
 
 // NewCiphertext creates a new Ciphertext parameterized by degree, level and scale.
@@ -16,7 +22,7 @@ func NewCiphertext(params *Parameters, degree uint64, level uint64, scale float6
 		panic("cannot NewCiphertext: parameters are invalid (check if the generation was done properly)")
 	}
 
-	ciphertext = &Ciphertext{&ckksElement{}}
+	ciphertext = &Ciphertext{ckksElement: &ckksElement{}}
 
 	ciphertext.value = make([]*ring.Poly, degree+1)
 	for i := uint64(0); i < degree+1; i++ {
@@ -36,7 +42,7 @@ func NewCiphertextRandom(params *Parameters, degree, level uint64, scale float64
 		panic("cannot NewCiphertextRandom: parameters are invalid (check if the generation was done properly)")
 	}
 
-	ciphertext = &Ciphertext{&ckksElement{}}
+	ciphertext = &Ciphertext{ckksElement: &ckksElement{}}
 
 	ciphertext.value = make([]*ring.Poly, degree+1)
 	for i := uint64(0); i < degree+1; i++ {
@@ -48,3 +54,21 @@ func NewCiphertextRandom(params *Parameters, degree, level uint64, scale float64
 
 	return ciphertext
 }
+
+// SetValue assigns the input slice of polynomials to the target Ciphertext value and discards its seed.
+func (ciphertext *Ciphertext) SetValue(value []*ring.Poly) {
+	ciphertext.seed = nil
+	ciphertext.ckksElement.SetValue(value)
+}
+
+// Resize resizes the target Ciphertext degree to the degree given as input (see ckksElement.Resize) and discards its seed.
+func (ciphertext *Ciphertext) Resize(params *Parameters, degree uint64) {
+	ciphertext.seed = nil
+	ciphertext.ckksElement.Resize(params, degree)
+}
+
+// Copy copies the value and parameters of the input on the target Ciphertext and discards its seed.
+func (ciphertext *Ciphertext) Copy(ctxCopy *ckksElement) (err error) {
+	ciphertext.seed = nil
+	return ciphertext.ckksElement.Copy(ctxCopy)
+}
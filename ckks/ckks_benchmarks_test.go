@@ -1,7 +1,9 @@
 package ckks
 
 import (
+	"fmt"
 	"github.com/ldsec/lattigo/ring"
+	"runtime"
 	"testing"
 )
 
@@ -12,6 +14,7 @@ func BenchmarkCKKSScheme(b *testing.B) {
 	b.Run("Decrypt", benchDecrypt)
 	b.Run("Evaluator", benchEvaluator)
 	b.Run("HoistedRotations", benchHoistedRotations)
+	b.Run("WorkerPool", benchWorkerPool)
 }
 
 func benchEncoder(b *testing.B) {
@@ -238,3 +241,48 @@ func benchHoistedRotations(b *testing.B) {
 		})
 	}
 }
+
+func benchWorkerPool(b *testing.B) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		ciphertext1 := NewCiphertextRandom(parameters, 1, parameters.MaxLevel(), parameters.Scale)
+		ciphertext2 := NewCiphertextRandom(parameters, 1, parameters.MaxLevel(), parameters.Scale)
+		receiver := NewCiphertextRandom(parameters, 1, parameters.MaxLevel(), parameters.Scale)
+
+		rlk := params.kgen.GenRelinKey(params.sk)
+
+		for _, workers := range benchWorkers() {
+
+			evaluator := NewEvaluator(parameters)
+
+			var pool *ring.WorkerPool
+			if workers != 0 {
+				pool = ring.NewWorkerPool(workers)
+				evaluator.SetWorkerPool(pool)
+			}
+
+			b.Run(testString(fmt.Sprintf("MulRelin/workers=%d/", workers), parameters), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					evaluator.MulRelin(ciphertext1, ciphertext2, rlk, receiver)
+				}
+			})
+
+			if pool != nil {
+				pool.Close()
+			}
+		}
+	}
+}
+
+// benchWorkers returns the numbers of workers of the WorkerPools to benchmark: 0 (no WorkerPool), which is the
+// reference, then the powers of two up to the number of available cores.
+func benchWorkers() (workers []uint64) {
+	workers = []uint64{0}
+	for w := uint64(1); w <= uint64(runtime.NumCPU()); w <<= 1 {
+		workers = append(workers, w)
+	}
+	return
+}
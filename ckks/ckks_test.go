@@ -1,15 +1,21 @@
 package ckks
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"math"
+	"math/big"
+	"math/bits"
 	"math/cmplx"
 	"math/rand"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/ldsec/lattigo/ring"
 	"github.com/ldsec/lattigo/utils"
 )
 
@@ -20,6 +26,15 @@ func check(t *testing.T, err error) {
 	}
 }
 
+// panics returns true if f panics.
+func panics(f func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	f()
+	return
+}
+
 func testString(opname string, params *Parameters) string {
 	return fmt.Sprintf("%slogN=%d/logQ=%d/levels=%d/a=%d/b=%d",
 		opname,
@@ -65,6 +80,7 @@ func init() {
 
 func TestCKKS(t *testing.T) {
 	t.Run("Encoder", testEncoder)
+	t.Run("PrecisionStats", testPrecisionStats)
 	t.Run("Encryptor", testEncryptor)
 	t.Run("Evaluator/Add", testEvaluatorAdd)
 	t.Run("Evaluator/Sub", testEvaluatorSub)
@@ -76,10 +92,17 @@ func TestCKKS(t *testing.T) {
 	t.Run("Evaluator/Functions", testFunctions)
 	t.Run("Evaluator/EvaluatePoly", testEvaluatePoly)
 	t.Run("Evaluator/ChebyshevInterpolator", testChebyshevInterpolator)
+	t.Run("SignApproximation", testSignApproximation)
+	t.Run("Evaluator/Comparison", testComparison)
 	t.Run("Evaluator/SwitchKeys", testSwitchKeys)
 	t.Run("Evaluator/Conjugate", testConjugate)
 	t.Run("Evaluator/RotateColumns", testRotateColumns)
+	t.Run("Evaluator/LinearTransform", testLinearTransform)
 	t.Run("Marshalling", testMarshaller)
+	t.Run("Streaming", testStreaming)
+	t.Run("SeededKeys", testSeededKeys)
+	t.Run("SeededCiphertexts", testSeededCiphertexts)
+	t.Run("ShallowCopy", testShallowCopy)
 }
 
 func genCkksParams(contextParameters *Parameters) (params *ckksParams) {
@@ -168,115 +191,200 @@ func verifyTestVectors(contextParams *ckksParams, decryptor Decryptor, valuesWan
 
 	valuesTest = contextParams.encoder.Decode(plaintextTest, slots)
 
-	var deltaReal, deltaImag float64
+	precStats := GetPrecisionStats(valuesWant, valuesTest)
 
-	var minprec, maxprec, meanprec, medianprec complex128
+	if testParams.verbose {
+		t.Log(precStats.String())
+	}
 
-	diff := make([]complex128, slots)
+	if real(precStats.MedianPrecision) < testParams.medianprec || imag(precStats.MedianPrecision) < testParams.medianprec {
+		t.Errorf("Median precision error: target (%.2f, %.2f) > result (%.2f, %.2f)", testParams.medianprec, testParams.medianprec, real(precStats.MedianPrecision), imag(precStats.MedianPrecision))
+	}
+}
 
-	minprec = complex(0, 0)
-	maxprec = complex(1, 1)
+func verifyTestVectorsFloat64(valuesWant, valuesTest []float64, t *testing.T) {
 
-	meanprec = complex(0, 0)
+	diff := make([]float64, len(valuesWant))
+	for i := range valuesWant {
+		diff[i] = math.Abs(valuesTest[i] - valuesWant[i])
+	}
 
-	distribReal := make(map[uint64]uint64)
-	distribImag := make(map[uint64]uint64)
+	sort.Float64s(diff)
 
-	for i := range valuesWant {
+	medianprec := math.Log2(1 / diff[len(diff)/2])
 
-		deltaReal = math.Abs(real(valuesTest[i]) - real(valuesWant[i]))
-		deltaImag = math.Abs(imag(valuesTest[i]) - imag(valuesWant[i]))
+	if testParams.verbose {
+		t.Logf("Median  precision : %.2f bits \n", medianprec)
+	}
 
-		diff[i] += complex(deltaReal, 0)
-		diff[i] += complex(0, deltaImag)
+	if medianprec < testParams.medianprec {
+		t.Errorf("Median precision error: target %.2f > result %.2f", testParams.medianprec, medianprec)
+	}
+}
 
-		meanprec += diff[i]
+func testEncoder(t *testing.T) {
 
-		if real(diff[i]) > real(minprec) {
-			minprec = complex(real(diff[i]), 0)
-		}
+	for _, parameters := range testParams.ckksParameters {
 
-		if imag(diff[i]) > imag(minprec) {
-			minprec = complex(real(minprec), imag(diff[i]))
-		}
+		params := genCkksParams(parameters)
 
-		if real(diff[i]) < real(maxprec) {
-			maxprec = complex(real(diff[i]), 0)
-		}
+		t.Run(testString("Encode/", parameters), func(t *testing.T) {
 
-		if imag(diff[i]) < imag(maxprec) {
-			maxprec = complex(real(maxprec), imag(diff[i]))
-		}
+			values, plaintext, _ := newTestVectors(params, nil, 1, t)
 
-		distribReal[uint64(math.Floor(math.Log2(1/real(diff[i]))))]++
-		distribImag[uint64(math.Floor(math.Log2(1/imag(diff[i]))))]++
-	}
+			verifyTestVectors(params, params.decryptor, values, plaintext, t)
+		})
 
-	meanprec /= complex(float64(slots), 0)
-	medianprec = calcmedian(diff)
+		t.Run(testString("EncodeBigComplex/", parameters), func(t *testing.T) {
 
-	if testParams.verbose {
-		t.Logf("Minimum precision : (%.2f, %.2f) bits \n", math.Log2(1/real(minprec)), math.Log2(1/imag(minprec)))
-		t.Logf("Maximum precision : (%.2f, %.2f) bits \n", math.Log2(1/real(maxprec)), math.Log2(1/imag(maxprec)))
-		t.Logf("Mean    precision : (%.2f, %.2f) bits \n", math.Log2(1/real(meanprec)), math.Log2(1/imag(meanprec)))
-		t.Logf("Median  precision : (%.2f, %.2f) bits \n", math.Log2(1/real(medianprec)), math.Log2(1/imag(medianprec)))
-		t.Log()
-	}
+			logPrecision := uint64(128)
 
-	if math.Log2(1/real(medianprec)) < testParams.medianprec || math.Log2(1/imag(medianprec)) < testParams.medianprec {
-		t.Errorf("Mean precision error: target (%.2f, %.2f) > result (%.2f, %.2f)", testParams.medianprec, testParams.medianprec, math.Log2(1/real(medianprec)), math.Log2(1/imag(medianprec)))
-	}
-}
+			encoder := NewEncoderBigComplex(params.params, logPrecision)
+
+			slots := uint64(1 << params.params.LogSlots)
 
-func calcmedian(values []complex128) (median complex128) {
+			values := make([]*ring.Complex, slots)
+			for i := range values {
+				values[i] = ring.NewComplexFromComplex128(randomComplex(-1, 1), uint(logPrecision))
+				// Adds a perturbation below the precision of a float64
+				values[i][0].Add(values[i][0], new(big.Float).SetMantExp(big.NewFloat(randomFloat(-1, 1)), -60))
+			}
 
-	tmp := make([]float64, len(values))
+			// A scale of 2^80 cannot be reached through float64 values
+			plaintext := NewPlaintext(params.params, params.params.MaxLevel(), math.Exp2(80))
+			encoder.Encode(plaintext, values, slots)
 
-	for i := range values {
-		tmp[i] = real(values[i])
-	}
+			valuesTest := encoder.Decode(plaintext, slots)
 
-	sort.Float64s(tmp)
+			// The error must be of the order of the inverse of the scale
+			bound := new(big.Float).SetMantExp(big.NewFloat(1), -64)
+			diff := new(big.Float)
 
-	for i := range values {
-		values[i] = complex(tmp[i], imag(values[i]))
-	}
+			for i := range values {
+				for k := 0; k < 2; k++ {
+					if diff.Abs(diff.Sub(valuesTest[i][k], values[i][k])).Cmp(bound) > 0 {
+						t.Errorf("error : EncodeBigComplex precision, have %v - want %v", valuesTest[i][k], values[i][k])
+						return
+					}
+				}
+			}
+		})
 
-	for i := range values {
-		tmp[i] = imag(values[i])
-	}
+		t.Run(testString("EncodeBigReal/", parameters), func(t *testing.T) {
 
-	sort.Float64s(tmp)
+			logPrecision := uint64(128)
 
-	for i := range values {
-		values[i] = complex(real(values[i]), tmp[i])
-	}
+			encoder := NewEncoderBigComplex(params.params, logPrecision)
+
+			slots := uint64(1 << params.params.LogSlots)
+
+			values := make([]*big.Float, slots)
+			for i := range values {
+				values[i] = new(big.Float).SetPrec(uint(logPrecision)).SetFloat64(randomFloat(-1, 1))
+				// Adds a perturbation below the precision of a float64
+				values[i].Add(values[i], new(big.Float).SetMantExp(big.NewFloat(randomFloat(-1, 1)), -60))
+			}
+
+			// A scale of 2^80 cannot be reached through float64 values
+			plaintext := NewPlaintext(params.params, params.params.MaxLevel(), math.Exp2(80))
+			encoder.EncodeReal(plaintext, values, slots)
+
+			// The imaginary part of the slots must be zero
+			bound := new(big.Float).SetMantExp(big.NewFloat(1), -64)
+			diff := new(big.Float)
+
+			for _, value := range encoder.Decode(plaintext, slots) {
+				if diff.Abs(value.Imag()).Cmp(bound) > 0 {
+					t.Errorf("error : EncodeBigReal imaginary part, have %v - want 0", value.Imag())
+					return
+				}
+			}
+
+			valuesTest := encoder.DecodeReal(plaintext, slots)
+
+			for i := range values {
+				if diff.Abs(diff.Sub(valuesTest[i], values[i])).Cmp(bound) > 0 {
+					t.Errorf("error : EncodeBigReal precision, have %v - want %v", valuesTest[i], values[i])
+					return
+				}
+			}
+		})
+
+		t.Run(testString("EncodeCoeffs/", parameters), func(t *testing.T) {
+
+			N := params.ckkscontext.n
+
+			values1 := make([]float64, N)
+			values2 := make([]float64, N)
+			for i := range values1 {
+				values1[i] = randomFloat(-1, 1)
+				values2[i] = randomFloat(-1, 1)
+			}
 
-	index := len(values) / 2
+			plaintext1 := NewPlaintext(params.params, params.params.MaxLevel(), params.params.Scale)
+			plaintext2 := NewPlaintext(params.params, params.params.MaxLevel(), params.params.Scale)
+			params.encoder.EncodeCoeffs(plaintext1, values1)
+			params.encoder.EncodeCoeffs(plaintext2, values2)
+
+			verifyTestVectorsFloat64(values1, params.encoder.DecodeCoeffs(plaintext1), t)
+
+			// The product of coefficient-encoded ciphertexts is the negacyclic convolution of their values
+			ciphertext1 := params.encryptorSk.EncryptNew(plaintext1)
+			ciphertext2 := params.encryptorSk.EncryptNew(plaintext2)
+			params.evaluator.MulRelin(ciphertext1, ciphertext2, nil, ciphertext1)
+
+			valuesWant := make([]float64, N)
+			for i := uint64(0); i < N; i++ {
+				for j := uint64(0); j < N; j++ {
+					if i+j < N {
+						valuesWant[i+j] += values1[i] * values2[j]
+					} else {
+						valuesWant[i+j-N] -= values1[i] * values2[j]
+					}
+				}
+			}
 
-	if len(values)&1 == 1 {
-		return values[index]
+			verifyTestVectorsFloat64(valuesWant, params.encoder.DecodeCoeffs(params.decryptor.DecryptNew(ciphertext1)), t)
+		})
 	}
+}
+
+func testPrecisionStats(t *testing.T) {
 
-	if index+1 == len(values) {
-		return values[index]
+	// Errors of 2^-10, 2^-20, ..., 2^-50 on the real part and no error on the imaginary part
+	valuesWant := make([]complex128, 5)
+	valuesTest := make([]complex128, 5)
+	for i := range valuesWant {
+		valuesWant[i] = complex(1, 0.5)
+		valuesTest[i] = complex(1+math.Exp2(-float64(10*(i+1))), 0.5)
 	}
 
-	return (values[index] + values[index+1]) / 2
-}
+	precStats := GetPrecisionStats(valuesWant, valuesTest)
 
-func testEncoder(t *testing.T) {
+	if real(precStats.MinPrecision) != 10 || real(precStats.MaxPrecision) != 50 || real(precStats.MedianPrecision) != 30 {
+		t.Errorf("error : PrecisionStats min/max/median, have (%f, %f, %f) - want (10, 50, 30)", real(precStats.MinPrecision), real(precStats.MaxPrecision), real(precStats.MedianPrecision))
+	}
 
-	for _, parameters := range testParams.ckksParameters {
+	if real(precStats.MinDelta) != math.Exp2(-50) || real(precStats.MaxDelta) != math.Exp2(-10) {
+		t.Errorf("error : PrecisionStats min/max delta, have (%g, %g) - want (%g, %g)", real(precStats.MinDelta), real(precStats.MaxDelta), math.Exp2(-50), math.Exp2(-10))
+	}
 
-		params := genCkksParams(parameters)
+	if math.Abs(real(precStats.MeanDelta)-(math.Exp2(-10)+math.Exp2(-20)+math.Exp2(-30)+math.Exp2(-40)+math.Exp2(-50))/5) > 1e-15 {
+		t.Errorf("error : PrecisionStats mean")
+	}
 
-		t.Run(testString("Encode/", parameters), func(t *testing.T) {
+	if !math.IsInf(imag(precStats.MinPrecision), 1) || !math.IsInf(imag(precStats.MedianPrecision), 1) {
+		t.Errorf("error : PrecisionStats exact imaginary part")
+	}
 
-			values, plaintext, _ := newTestVectors(params, nil, 1, t)
+	if len(precStats.RealDist) != 5 || len(precStats.ImagDist) != 1 || precStats.ImagDist[0].Count != 5 {
+		t.Errorf("error : PrecisionStats distribution")
+	}
 
-			verifyTestVectors(params, params.decryptor, values, plaintext, t)
-		})
+	for i, bin := range precStats.RealDist {
+		if bin.Prec != float64(10*(i+1)) || bin.Count != 1 {
+			t.Errorf("error : PrecisionStats distribution, have bin (%f, %d)", bin.Prec, bin.Count)
+		}
 	}
 }
 
@@ -662,6 +770,42 @@ func testEvaluatorMul(t *testing.T) {
 			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
 		})
 
+		t.Run(testString("CtPlainMul/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorSk, 1, t)
+			values2, _, ciphertext2 := newTestVectors(params, params.encryptorSk, 1, t)
+
+			valuesMul, _, _ := newTestVectors(params, nil, 1, t)
+
+			plaintextMul := NewPlaintextMul(parameters, parameters.MaxLevel(), parameters.Scale)
+			params.encoder.EncodeMul(plaintextMul, valuesMul, 1<<parameters.LogSlots)
+
+			for i := range values1 {
+				values1[i] *= valuesMul[i]
+				values2[i] *= valuesMul[i]
+			}
+
+			params.evaluator.MulRelin(ciphertext1, plaintextMul, nil, ciphertext1)
+
+			verifyTestVectors(params, params.decryptor, values1, ciphertext1, t)
+
+			params.evaluator.MultByConst(ciphertext2, plaintextMul, ciphertext2)
+
+			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+
+			// A PlaintextMul can only be multiplied
+			for _, op := range []func(){
+				func() { params.evaluator.Add(ciphertext1, plaintextMul, ciphertext1) },
+				func() { params.evaluator.Sub(plaintextMul, ciphertext1, ciphertext1) },
+				func() { params.evaluator.AddNoMod(ciphertext1, plaintextMul, ciphertext1) },
+				func() { params.evaluator.SubNoMod(ciphertext1, plaintextMul, ciphertext1) },
+			} {
+				if !panics(op) {
+					t.Errorf("error : PlaintextMul accepted by an operation other than the multiplication")
+				}
+			}
+		})
+
 		t.Run(testString("Relinearize/", parameters), func(t *testing.T) {
 
 			rlk := params.kgen.GenRelinKey(params.sk)
@@ -683,6 +827,61 @@ func testEvaluatorMul(t *testing.T) {
 
 			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
 		})
+
+		t.Run(testString("MulAndAdd/", parameters), func(t *testing.T) {
+
+			rlk := params.kgen.GenRelinKey(params.sk)
+
+			valuesWant := make([]complex128, 1<<parameters.LogSlots)
+
+			// The receiver starts at degree 1 and is resized by the first product of two ciphertexts
+			receiver := NewCiphertext(parameters, 1, parameters.MaxLevel(), parameters.Scale*parameters.Scale)
+
+			for k := 0; k < 4; k++ {
+
+				values1, plaintext1, ciphertext1 := newTestVectors(params, params.encryptorSk, 1, t)
+				values2, _, ciphertext2 := newTestVectors(params, params.encryptorSk, 1, t)
+
+				for i := range valuesWant {
+					valuesWant[i] += values1[i] * values2[i]
+				}
+
+				// Products of a ciphertext with a plaintext and of two ciphertexts
+				if k == 0 {
+					params.evaluator.MulAndAdd(ciphertext2, plaintext1, receiver)
+				} else {
+					params.evaluator.MulAndAdd(ciphertext1, ciphertext2, receiver)
+				}
+			}
+
+			if receiver.Degree() != 2 {
+				t.Errorf("invalid degree: %d", receiver.Degree())
+			}
+
+			params.evaluator.Relinearize(receiver, rlk, receiver)
+			params.evaluator.Rescale(receiver, parameters.Scale, receiver)
+
+			verifyTestVectors(params, params.decryptor, valuesWant, receiver, t)
+		})
+
+		t.Run(testString("MulAndAdd/Scale/", parameters), func(t *testing.T) {
+
+			_, _, ciphertext1 := newTestVectors(params, params.encryptorSk, 1, t)
+			_, _, ciphertext2 := newTestVectors(params, params.encryptorSk, 1, t)
+
+			// A scale that only differs from the product of the input scales by rounding errors is accepted
+			receiver := NewCiphertext(parameters, 2, parameters.MaxLevel(), parameters.Scale*parameters.Scale*(1+1e-12))
+
+			if panics(func() { params.evaluator.MulAndAdd(ciphertext1, ciphertext2, receiver) }) {
+				t.Errorf("MulAndAdd panicked on a scale within the tolerance")
+			}
+
+			receiver = NewCiphertext(parameters, 2, parameters.MaxLevel(), parameters.Scale)
+
+			if !panics(func() { params.evaluator.MulAndAdd(ciphertext1, ciphertext2, receiver) }) {
+				t.Errorf("MulAndAdd did not panic on a scale different from the product of the input scales")
+			}
+		})
 	}
 }
 
@@ -787,167 +986,594 @@ func testEvaluatePoly(t *testing.T) {
 
 			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
-	}
-}
-
-func testChebyshevInterpolator(t *testing.T) {
-
-	for _, parameters := range testParams.ckksParameters {
 
-		params := genCkksParams(parameters)
-
-		rlk := params.kgen.GenRelinKey(params.sk)
-
-		t.Run(testString("Fast/Sin/", parameters), func(t *testing.T) {
+		t.Run(testString("TargetScale/Odd/Sin/", parameters), func(t *testing.T) {
 
 			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
-			cheby := Approximate(cmplx.Sin, complex(-1, 0), complex(1, 0), 16)
+			coeffs := []float64{0, 1.0, 0, -1.0 / 6, 0, 1.0 / 120, 0, -1.0 / 5040}
 
 			for i := range values {
 				values[i] = cmplx.Sin(values[i])
 			}
 
-			ciphertext = params.evaluator.EvaluateChebyFast(ciphertext, cheby, rlk)
+			targetScale := 1.5 * parameters.Scale
+
+			ciphertext = params.evaluator.EvaluatePoly(ciphertext, coeffs, targetScale, rlk)
+
+			// The odd polynomial of degree 7 must be evaluated with 3 levels and exactly at the target scale
+			if ciphertext.Level() != parameters.MaxLevel()-3 || ciphertext.Scale() != targetScale {
+				t.Errorf("invalid output level or scale: %d, %f", ciphertext.Level(), ciphertext.Scale())
+			}
 
 			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
 
-		t.Run(testString("Eco/Sin/", parameters), func(t *testing.T) {
+		t.Run(testString("SmallCoeffs/", parameters), func(t *testing.T) {
 
 			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
-			cheby := Approximate(cmplx.Sin, complex(-1, 0), complex(1, 0), 16)
+			// The small coefficient of x^3 is not zero, hence it must be taken into account
+			coeffs := []float64{0, 1.0, 0, 1e-20}
 
 			for i := range values {
-				values[i] = cmplx.Sin(values[i])
+				values[i] += 1e-20 * values[i] * values[i] * values[i]
 			}
 
-			ciphertext = params.evaluator.EvaluateChebyEco(ciphertext, cheby, rlk)
+			ciphertext = params.evaluator.EvaluatePolyFast(ciphertext, coeffs, rlk)
+
+			if ciphertext.Level() != parameters.MaxLevel()-2 {
+				t.Errorf("invalid output level: %d", ciphertext.Level())
+			}
 
 			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
 	}
 }
 
-func testSwitchKeys(t *testing.T) {
+func testChebyshevInterpolator(t *testing.T) {
 
 	for _, parameters := range testParams.ckksParameters {
 
 		params := genCkksParams(parameters)
 
-		sk2 := params.kgen.GenSecretKey()
-		decryptorSk2 := NewDecryptor(parameters, sk2)
-		switchingKey := params.kgen.GenSwitchingKey(params.sk, sk2)
+		rlk := params.kgen.GenRelinKey(params.sk)
 
-		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+		t.Run(testString("Fast/Sin/", parameters), func(t *testing.T) {
 
 			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
-			params.evaluator.SwitchKeys(ciphertext, switchingKey, ciphertext)
+			cheby := Approximate(cmplx.Sin, complex(-1, 0), complex(1, 0), 16)
 
-			verifyTestVectors(params, decryptorSk2, values, ciphertext, t)
+			for i := range values {
+				values[i] = cmplx.Sin(values[i])
+			}
+
+			ciphertext = params.evaluator.EvaluateChebyFast(ciphertext, cheby, rlk)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
 
-		t.Run(testString("New/", parameters), func(t *testing.T) {
+		t.Run(testString("Eco/Sin/", parameters), func(t *testing.T) {
 
 			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
-			ciphertext = params.evaluator.SwitchKeysNew(ciphertext, switchingKey)
+			cheby := Approximate(cmplx.Sin, complex(-1, 0), complex(1, 0), 16)
 
-			verifyTestVectors(params, decryptorSk2, values, ciphertext, t)
+			for i := range values {
+				values[i] = cmplx.Sin(values[i])
+			}
+
+			ciphertext = params.evaluator.EvaluateChebyEco(ciphertext, cheby, rlk)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
-	}
-}
 
-func testConjugate(t *testing.T) {
+		t.Run(testString("Scaled/Sin/", parameters), func(t *testing.T) {
 
-	for _, parameters := range testParams.ckksParameters {
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
-		params := genCkksParams(parameters)
+			scaling := complex(math.Exp2(-20), 0)
 
-		rotKey := NewRotationKeys()
-		params.kgen.GenRot(Conjugate, params.sk, 0, rotKey)
+			cheby := Approximate(cmplx.Sin, complex(-1, 0), complex(1, 0), 15)
+			chebyScaled := Approximate(func(x complex128) complex128 { return scaling * cmplx.Sin(x) }, complex(-1, 0), complex(1, 0), 15)
 
-		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+			// The coefficients smaller than the resolution of the result are discarded, hence the scaled polynomial
+			// must be evaluated with fewer coefficients, and consume at most as many levels, as the original one,
+			// without loss of precision
+			nbCoeffs := len(nonZeroCoeffs(cheby.coeffs, true, parameters.Scale))
+			nbCoeffsScaled := len(nonZeroCoeffs(chebyScaled.coeffs, true, parameters.Scale))
 
-			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+			if nbCoeffsScaled >= nbCoeffs {
+				t.Errorf("invalid number of coefficients: %d >= %d", nbCoeffsScaled, nbCoeffs)
+			}
 
 			for i := range values {
-				values[i] = complex(real(values[i]), -imag(values[i]))
+				values[i] = scaling * cmplx.Sin(values[i])
 			}
 
-			params.evaluator.Conjugate(ciphertext, rotKey, ciphertext)
+			levelWant := params.evaluator.EvaluateChebyFast(ciphertext, cheby, rlk).Level()
+
+			ciphertext = params.evaluator.EvaluateChebyFast(ciphertext, chebyScaled, rlk)
+
+			if ciphertext.Level() < levelWant {
+				t.Errorf("invalid output level: %d < %d", ciphertext.Level(), levelWant)
+			}
 
 			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
 
-		t.Run(testString("New/", parameters), func(t *testing.T) {
+		t.Run(testString("TargetScale/Sin/", parameters), func(t *testing.T) {
 
 			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
+			cheby := Approximate(cmplx.Sin, complex(-1, 0), complex(1, 0), 15)
+
 			for i := range values {
-				values[i] = complex(real(values[i]), -imag(values[i]))
+				values[i] = cmplx.Sin(values[i])
 			}
 
-			ciphertext = params.evaluator.ConjugateNew(ciphertext, rotKey)
+			targetScale := 1.5 * parameters.Scale
+
+			ciphertext = params.evaluator.EvaluateCheby(ciphertext, cheby, targetScale, rlk)
+
+			if ciphertext.Scale() != targetScale {
+				t.Errorf("invalid output scale: %f != %f", ciphertext.Scale(), targetScale)
+			}
 
 			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
-	}
-}
 
-func testRotateColumns(t *testing.T) {
+		t.Run(testString("Minimax/Sigmoid/", parameters), func(t *testing.T) {
 
-	for _, parameters := range testParams.ckksParameters {
+			sigmoid := func(x float64) float64 { return 1 / (1 + math.Exp(-x)) }
 
-		params := genCkksParams(parameters)
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -8, 8, t)
 
-		rotKey := params.kgen.GenRotationKeysPow2(params.sk)
+			cheby, maxErr := ApproximateMinimax(sigmoid, -8, 8, 15)
 
-		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+			interp := Approximate(func(x complex128) complex128 { return complex(sigmoid(real(x)), 0) }, complex(-8, 0), complex(8, 0), 15)
 
-			values1, _, ciphertext1 := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+			coeffs := make([]complex128, cheby.degree+1)
+			coeffsInterp := make([]complex128, interp.degree+1)
+			for i := range coeffs {
+				coeffs[i] = cheby.coeffs[uint64(i)]
+				coeffsInterp[i] = interp.coeffs[uint64(i)]
+			}
 
-			values2 := make([]complex128, len(values1))
-			ciphertext2 := NewCiphertext(parameters, ciphertext1.Degree(), ciphertext1.Level(), ciphertext1.Scale())
+			// The minimax approximation must have a smaller maximum error than the interpolant, and its
+			// maximum error must not be underestimated
+			var errMinimax, errInterp float64
+			for i := 0; i <= 1<<14; i++ {
+				x := complex(-8+16*float64(i)/(1<<14), 0)
+				errMinimax = math.Max(errMinimax, cmplx.Abs(complex(sigmoid(real(x)), 0)-evaluateChebyshevPolynomial(coeffs, x, cheby.a, cheby.b)))
+				errInterp = math.Max(errInterp, cmplx.Abs(complex(sigmoid(real(x)), 0)-evaluateChebyshevPolynomial(coeffsInterp, x, interp.a, interp.b)))
+			}
 
-			for n := 1; n < len(values1); n <<= 1 {
+			if errMinimax > maxErr {
+				t.Errorf("maximum error underestimated: %e > %e", errMinimax, maxErr)
+			}
 
-				// Applies the column rotation to the values
-				for i := range values1 {
-					values2[i] = values1[(i+n)%len(values1)]
-				}
+			// The estimate only adds a small safety margin to the measured error
+			if maxErr > errMinimax*(1+2*minimaxErrorMargin) {
+				t.Errorf("maximum error overestimated: %e > %e", maxErr, errMinimax)
+			}
 
-				params.evaluator.RotateColumns(ciphertext1, uint64(n), rotKey, ciphertext2)
+			if errMinimax >= errInterp {
+				t.Errorf("minimax error larger than the interpolation error: %e >= %e", errMinimax, errInterp)
+			}
 
-				verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+			if testParams.verbose {
+				t.Logf("maximum error : minimax %e, interpolation %e", errMinimax, errInterp)
+			}
+
+			valuesWant := make([]complex128, len(values))
+			for i := range values {
+				valuesWant[i] = evaluateChebyshevPolynomial(coeffs, values[i], cheby.a, cheby.b)
 			}
 
+			ciphertext = params.evaluator.EvaluateChebyEco(ciphertext, cheby, rlk)
+
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext, t)
 		})
 
-		t.Run(testString("New/", parameters), func(t *testing.T) {
+		t.Run(testString("MinimaxOdd/Sign/", parameters), func(t *testing.T) {
 
-			values1, _, ciphertext1 := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+			sign := func(x float64) float64 { return math.Copysign(1, x) }
 
-			values2 := make([]complex128, len(values1))
-			ciphertext2 := NewCiphertext(parameters, ciphertext1.Degree(), ciphertext1.Level(), ciphertext1.Scale())
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
-			for n := 1; n < len(values1); n <<= 1 {
+			cheby, maxErr := ApproximateMinimaxOdd(sign, 0.25, 1, 15)
 
-				// Applies the column rotation to the values
-				for i := range values1 {
-					values2[i] = values1[(i+n)%len(values1)]
+			coeffs := make([]complex128, cheby.degree+1)
+			for i := range coeffs {
+				coeffs[i] = cheby.coeffs[uint64(i)]
+				if i&1 == 0 && coeffs[i] != 0 {
+					t.Errorf("non-zero even coefficient %d: %v", i, coeffs[i])
 				}
+			}
 
-				ciphertext2 = params.evaluator.RotateColumnsNew(ciphertext1, uint64(n), rotKey)
+			// The maximum error on [-1, -1/4] U [1/4, 1] must not be underestimated, and the estimate only adds
+			// a small safety margin to the measured error
+			var errMinimax float64
+			for i := 0; i <= 1<<14; i++ {
+				x := 0.25 + 0.75*float64(i)/(1<<14)
+				for _, x := range []complex128{complex(x, 0), complex(-x, 0)} {
+					errMinimax = math.Max(errMinimax, cmplx.Abs(complex(sign(real(x)), 0)-evaluateChebyshevPolynomial(coeffs, x, cheby.a, cheby.b)))
+				}
+			}
 
-				verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+			if errMinimax > maxErr {
+				t.Errorf("maximum error underestimated: %e > %e", errMinimax, maxErr)
 			}
 
-		})
+			if maxErr > errMinimax*(1+2*minimaxErrorMargin) {
+				t.Errorf("maximum error overestimated: %e > %e", maxErr, errMinimax)
+			}
 
-		t.Run(testString("Random/", parameters), func(t *testing.T) {
+			valuesWant := make([]complex128, len(values))
+			for i := range values {
+				valuesWant[i] = evaluateChebyshevPolynomial(coeffs, values[i], cheby.a, cheby.b)
+			}
+
+			ciphertext = params.evaluator.EvaluateChebyEco(ciphertext, cheby, rlk)
+
+			verifyTestVectors(params, params.decryptor, valuesWant, ciphertext, t)
+		})
+	}
+}
+
+func testSignApproximation(t *testing.T) {
+
+	// evaluate evaluates the composite polynomial of the approximation in the clear
+	evaluate := func(approx *SignApproximation, x float64) float64 {
+		for _, poly := range approx.Polynomials() {
+			y := 0.0
+			for j := len(poly) - 1; j >= 0; j-- {
+				y = y*x + poly[j]
+			}
+			x = y
+		}
+		return x
+	}
+
+	for _, logGap := range []uint64{1, 4, 8, 16, 24} {
+
+		for n := uint64(1); n <= 7; n++ {
+
+			approx := NewSignApproximation(n, logGap, 30)
+
+			if approx.Precision() < 30 {
+				t.Errorf("n = %d, gap 2^-%d : estimated precision %.2f < 30 bits", n, logGap, approx.Precision())
+			}
+
+			if approx.Depth() != uint64(len(approx.Polynomials()))*uint64(bits.Len64(2*n+1)) {
+				t.Errorf("n = %d, gap 2^-%d : invalid depth %d", n, logGap, approx.Depth())
+			}
+
+			// The estimated precision must not be larger than the one measured on [-1, -2^-logGap] U [2^-logGap, 1],
+			// sampled on a logarithmic scale
+			var maxErr float64
+			for i := 0; i <= 1<<12; i++ {
+				x := math.Exp2(-float64(logGap) * float64(i) / (1 << 12))
+				maxErr = math.Max(maxErr, math.Abs(1-evaluate(approx, x)))
+				maxErr = math.Max(maxErr, math.Abs(-1-evaluate(approx, -x)))
+			}
+
+			if -math.Log2(maxErr) < approx.Precision() {
+				t.Errorf("n = %d, gap 2^-%d : precision overestimated, %.2f < %.2f bits", n, logGap, -math.Log2(maxErr), approx.Precision())
+			}
+		}
+	}
+}
+
+func testComparison(t *testing.T) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk)
+
+		slots := uint64(1 << parameters.LogSlots)
+
+		logGap := uint64(2)
+		gap := math.Exp2(-float64(logGap))
+
+		// Most precise approximation that leaves one level for ReLU, Max and Min
+		var approx *SignApproximation
+		for n := uint64(1); n <= 4; n++ {
+			if tmp := NewSignApproximationWithDepth(n, logGap, parameters.MaxLevel()-1); approx == nil || tmp.Precision() > approx.Precision() {
+				approx = tmp
+			}
+		}
+
+		// step evaluates (p(x) + 1)/2 in the clear, p being the composite polynomial of the approximation
+		step := func(x float64) float64 {
+			for _, poly := range approx.Polynomials() {
+				y := 0.0
+				for j := len(poly) - 1; j >= 0; j-- {
+					y = y*x + poly[j]
+				}
+				x = y
+			}
+			return (x + 1) / 2
+		}
+
+		encrypt := func(values []complex128) *Ciphertext {
+			plaintext := NewPlaintext(parameters, parameters.MaxLevel(), parameters.Scale)
+			params.encoder.Encode(plaintext, values, slots)
+			return params.encryptorSk.EncryptNew(plaintext)
+		}
+
+		// Checks the result against the approximation evaluated in the clear, and checks that its precision with
+		// respect to the exact function is, up to one bit, at least the smallest of logBound, the precision of the
+		// approximation for the function, and of the precision of the homomorphic evaluation
+		verify := func(valuesApprox, valuesExact []complex128, logBound float64, ciphertext *Ciphertext, depth uint64, t *testing.T) {
+
+			if ciphertext.Level() != parameters.MaxLevel()-depth {
+				t.Errorf("invalid output level: %d != %d", ciphertext.Level(), parameters.MaxLevel()-depth)
+			}
+
+			verifyTestVectors(params, params.decryptor, valuesApprox, ciphertext, t)
+
+			valuesTest := params.encoder.Decode(params.decryptor.DecryptNew(ciphertext), slots)
+
+			precStatsApprox := GetPrecisionStats(valuesApprox, valuesTest)
+			precStats := GetPrecisionStats(valuesExact, valuesTest)
+
+			if want := math.Min(logBound, real(precStatsApprox.MinPrecision)) - 1; real(precStats.MinPrecision) < want {
+				t.Errorf("depth %d, gap 2^-%d : minimum precision %.2f bits < %.2f bits", approx.Depth(), logGap, real(precStats.MinPrecision), want)
+			}
+
+			if testParams.verbose {
+				t.Logf("depth %d, gap 2^-%d, estimated precision %.2f bits : minimum precision %.2f bits, median precision %.2f bits",
+					approx.Depth(), logGap, approx.Precision(), real(precStats.MinPrecision), real(precStats.MedianPrecision))
+			}
+		}
+
+		t.Run(testString("Sign/", parameters), func(t *testing.T) {
+
+			values := make([]complex128, slots)
+			valuesApprox := make([]complex128, slots)
+			valuesExact := make([]complex128, slots)
+
+			for i := range values {
+				x := randomFloat(gap, 1)
+				if i&1 == 1 {
+					x = -x
+				}
+				values[i] = complex(x, 0)
+				valuesApprox[i] = complex(2*step(x)-1, 0)
+				valuesExact[i] = complex(math.Copysign(1, x), 0)
+			}
+
+			ciphertext := params.evaluator.SignNew(encrypt(values), approx, rlk)
+
+			verify(valuesApprox, valuesExact, approx.Precision(), ciphertext, approx.Depth(), t)
+		})
+
+		t.Run(testString("Compare/", parameters), func(t *testing.T) {
+
+			values0 := make([]complex128, slots)
+			values1 := make([]complex128, slots)
+			valuesApprox := make([]complex128, slots)
+			valuesExact := make([]complex128, slots)
+
+			for i := range values0 {
+				a := randomFloat(-0.5, 0.5)
+				d := randomFloat(gap, 0.5)
+				if i&1 == 1 {
+					d = -d
+				}
+				values0[i] = complex(a, 0)
+				values1[i] = complex(a-d, 0)
+				valuesApprox[i] = complex(step(d), 0)
+				if d > 0 {
+					valuesExact[i] = 1
+				}
+			}
+
+			ciphertext := params.evaluator.CompareNew(encrypt(values0), encrypt(values1), approx, rlk)
+
+			// (sign + 1)/2 halves the error of the approximation
+			verify(valuesApprox, valuesExact, approx.Precision()+1, ciphertext, approx.Depth(), t)
+		})
+
+		t.Run(testString("ReLU/", parameters), func(t *testing.T) {
+
+			values := make([]complex128, slots)
+			valuesApprox := make([]complex128, slots)
+			valuesExact := make([]complex128, slots)
+
+			for i := range values {
+				x := randomFloat(gap, 1)
+				if i&1 == 1 {
+					x = -x
+				}
+				values[i] = complex(x, 0)
+				valuesApprox[i] = complex(x*step(x), 0)
+				valuesExact[i] = complex(math.Max(x, 0), 0)
+			}
+
+			ciphertext := params.evaluator.ReLUNew(encrypt(values), approx, rlk)
+
+			// The error is the one of (sign(x) + 1)/2 multiplied by |x| <= 1
+			verify(valuesApprox, valuesExact, approx.Precision()+1, ciphertext, approx.Depth()+1, t)
+		})
+
+		t.Run(testString("Max/", parameters), func(t *testing.T) {
+
+			values0 := make([]complex128, slots)
+			values1 := make([]complex128, slots)
+			valuesApprox := make([]complex128, slots)
+			valuesExact := make([]complex128, slots)
+
+			for i := range values0 {
+				a := randomFloat(-0.5, 0.5)
+				d := randomFloat(gap, 0.5)
+				if i&1 == 1 {
+					d = -d
+				}
+				b := a - d
+				values0[i] = complex(a, 0)
+				values1[i] = complex(b, 0)
+				valuesApprox[i] = complex(b+(a-b)*step(a-b), 0)
+				valuesExact[i] = complex(math.Max(a, b), 0)
+			}
+
+			ciphertext := params.evaluator.MaxNew(encrypt(values0), encrypt(values1), approx, rlk)
+
+			// The error is the one of (sign(a - b) + 1)/2 multiplied by |a - b| <= 1
+			verify(valuesApprox, valuesExact, approx.Precision()+1, ciphertext, approx.Depth()+1, t)
+		})
+
+		t.Run(testString("Min/", parameters), func(t *testing.T) {
+
+			values0 := make([]complex128, slots)
+			values1 := make([]complex128, slots)
+			valuesApprox := make([]complex128, slots)
+			valuesExact := make([]complex128, slots)
+
+			for i := range values0 {
+				a := randomFloat(-0.5, 0.5)
+				d := randomFloat(gap, 0.5)
+				if i&1 == 1 {
+					d = -d
+				}
+				b := a - d
+				values0[i] = complex(a, 0)
+				values1[i] = complex(b, 0)
+				valuesApprox[i] = complex(a-(a-b)*step(a-b), 0)
+				valuesExact[i] = complex(math.Min(a, b), 0)
+			}
+
+			ciphertext := params.evaluator.MinNew(encrypt(values0), encrypt(values1), approx, rlk)
+
+			// The error is the one of (sign(a - b) + 1)/2 multiplied by |a - b| <= 1
+			verify(valuesApprox, valuesExact, approx.Precision()+1, ciphertext, approx.Depth()+1, t)
+		})
+	}
+}
+
+func testSwitchKeys(t *testing.T) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		sk2 := params.kgen.GenSecretKey()
+		decryptorSk2 := NewDecryptor(parameters, sk2)
+		switchingKey := params.kgen.GenSwitchingKey(params.sk, sk2)
+
+		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+
+			params.evaluator.SwitchKeys(ciphertext, switchingKey, ciphertext)
+
+			verifyTestVectors(params, decryptorSk2, values, ciphertext, t)
+		})
+
+		t.Run(testString("New/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+
+			ciphertext = params.evaluator.SwitchKeysNew(ciphertext, switchingKey)
+
+			verifyTestVectors(params, decryptorSk2, values, ciphertext, t)
+		})
+	}
+}
+
+func testConjugate(t *testing.T) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		rotKey := NewRotationKeys()
+		params.kgen.GenRot(Conjugate, params.sk, 0, rotKey)
+
+		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+
+			for i := range values {
+				values[i] = complex(real(values[i]), -imag(values[i]))
+			}
+
+			params.evaluator.Conjugate(ciphertext, rotKey, ciphertext)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+
+		t.Run(testString("New/", parameters), func(t *testing.T) {
+
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+
+			for i := range values {
+				values[i] = complex(real(values[i]), -imag(values[i]))
+			}
+
+			ciphertext = params.evaluator.ConjugateNew(ciphertext, rotKey)
+
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+	}
+}
+
+func testRotateColumns(t *testing.T) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		rotKey := params.kgen.GenRotationKeysPow2(params.sk)
+
+		t.Run(testString("InPlace/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+
+			values2 := make([]complex128, len(values1))
+			ciphertext2 := NewCiphertext(parameters, ciphertext1.Degree(), ciphertext1.Level(), ciphertext1.Scale())
+
+			for n := 1; n < len(values1); n <<= 1 {
+
+				// Applies the column rotation to the values
+				for i := range values1 {
+					values2[i] = values1[(i+n)%len(values1)]
+				}
+
+				params.evaluator.RotateColumns(ciphertext1, uint64(n), rotKey, ciphertext2)
+
+				verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+			}
+
+		})
+
+		t.Run(testString("New/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+
+			values2 := make([]complex128, len(values1))
+			ciphertext2 := NewCiphertext(parameters, ciphertext1.Degree(), ciphertext1.Level(), ciphertext1.Scale())
+
+			for n := 1; n < len(values1); n <<= 1 {
+
+				// Applies the column rotation to the values
+				for i := range values1 {
+					values2[i] = values1[(i+n)%len(values1)]
+				}
+
+				ciphertext2 = params.evaluator.RotateColumnsNew(ciphertext1, uint64(n), rotKey)
+
+				verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+			}
+
+		})
+
+		t.Run(testString("Random/", parameters), func(t *testing.T) {
 
 			values1, _, ciphertext1 := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
@@ -988,14 +1614,346 @@ func testRotateColumns(t *testing.T) {
 					values2[i] = values1[(i+int(n))%len(values1)]
 				}
 
-				verifyTestVectors(params, params.decryptor, values2, ciphertexts[n], t)
-			}
+				verifyTestVectors(params, params.decryptor, values2, ciphertexts[n], t)
+			}
+
+		})
+
+		t.Run(testString("ForRotations/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
+
+			slots := len(values1)
+
+			rotations := []int{3, -5, 7, 11, -(int(params.ckkscontext.n>>1) - 3)}
+
+			rotKeyDirect := params.kgen.GenRotationKeysForRotations(rotations, params.sk)
+
+			// The rotation by N/2-3 positions to the right is the rotation by 3 positions to the left
+			if len(rotKeyDirect.keys) != len(rotations)-1 {
+				t.Errorf("invalid number of rotation keys : %d", len(rotKeyDirect.keys))
+			}
+
+			values2 := make([]complex128, slots)
+			ciphertext2 := NewCiphertext(parameters, ciphertext1.Degree(), ciphertext1.Level(), ciphertext1.Scale())
+
+			for _, k := range rotations {
+
+				n := uint64(k) & ((params.ckkscontext.n >> 1) - 1)
+
+				// Applies the column rotation to the values
+				for i := range values1 {
+					values2[i] = values1[(i+int(n%uint64(slots)))%slots]
+				}
+
+				// Only the keys of the requested rotations are available, so that each rotation is done with a single key-switching
+				params.evaluator.RotateColumns(ciphertext1, n, rotKeyDirect, ciphertext2)
+
+				verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+			}
+		})
+	}
+}
+
+func testLinearTransform(t *testing.T) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		slots := uint64(1 << parameters.LogSlots)
+
+		level := parameters.MaxLevel()
+		scale := float64(parameters.Qi[level])
+
+		t.Run(testString("Diagonals/", parameters), func(t *testing.T) {
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorSk, 1, t)
+
+			diags := make(map[uint64][]complex128)
+			for _, k := range []uint64{0, 1, 2, 3, 15, slots >> 1, slots - 1} {
+				diags[k] = make([]complex128, slots)
+				for i := range diags[k] {
+					diags[k][i] = randomComplex(-0.1, 0.1)
+				}
+			}
+
+			linearTransform := NewLinearTransform(parameters, params.encoder, diags, level, scale)
+
+			rotKey := NewRotationKeys()
+			for _, k := range linearTransform.Rotations() {
+				params.kgen.GenRot(RotationLeft, params.sk, k, rotKey)
+			}
+
+			// Applies the linear transform to the values
+			values2 := make([]complex128, slots)
+			for k, diag := range diags {
+				for i := uint64(0); i < slots; i++ {
+					values2[i] += diag[i] * values1[(i+k)%slots]
+				}
+			}
+
+			ciphertext2 := params.evaluator.LinearTransformNew(ciphertext1, linearTransform, rotKey)
+
+			if ciphertext2.Level() != level || ciphertext2.Scale() != ciphertext1.Scale()*scale {
+				t.Errorf("LinearTransform: wrong level or scale")
+			}
+
+			params.evaluator.Rescale(ciphertext2, parameters.Scale, ciphertext2)
+
+			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+		})
+
+		t.Run(testString("Matrix/", parameters), func(t *testing.T) {
+
+			// Uses a small number of slots to keep the size of the matrix reasonable
+			parameters := parameters.Copy()
+			parameters.LogSlots = 6
+
+			params := genCkksParams(parameters)
+
+			slots := uint64(1 << parameters.LogSlots)
+
+			values1, _, ciphertext1 := newTestVectors(params, params.encryptorSk, 1, t)
+
+			// Banded matrix with 2*width+1 non-zero diagonals
+			width := 8
+			matrix := make([][]complex128, slots)
+			for i := range matrix {
+				matrix[i] = make([]complex128, slots)
+				for j := -width; j <= width; j++ {
+					matrix[i][(i+j+int(slots))%int(slots)] = randomComplex(-0.05, 0.05)
+				}
+			}
+
+			linearTransform := NewLinearTransformFromMatrix(parameters, params.encoder, matrix, level-1, scale)
+
+			if linearTransform.Level() != level-1 {
+				t.Errorf("LinearTransform: wrong level")
+			}
+
+			rotKey := NewRotationKeys()
+			for _, k := range linearTransform.Rotations() {
+				params.kgen.GenRot(RotationLeft, params.sk, k, rotKey)
+			}
+
+			// Applies the matrix to the values
+			values2 := make([]complex128, slots)
+			for i := range matrix {
+				for j := range matrix[i] {
+					values2[i] += matrix[i][j] * values1[j]
+				}
+			}
+
+			ciphertext2 := NewCiphertext(parameters, 1, level-1, ciphertext1.Scale()*scale)
+
+			params.evaluator.LinearTransform(ciphertext1, linearTransform, rotKey, ciphertext2)
+
+			if ciphertext1.Level() != level {
+				t.Errorf("LinearTransform: the input ciphertext has been modified")
+			}
+
+			params.evaluator.Rescale(ciphertext2, parameters.Scale, ciphertext2)
+
+			verifyTestVectors(params, params.decryptor, values2, ciphertext2, t)
+		})
+	}
+}
+
+func testMarshaller(t *testing.T) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		contextQP := params.ckkscontext.contextQP
+
+		t.Run(testString("Ciphertext/", parameters), func(t *testing.T) {
+
+			ciphertextWant := NewCiphertextRandom(parameters, 2, parameters.MaxLevel(), parameters.Scale)
+
+			marshalledCiphertext, err := ciphertextWant.MarshalBinary()
+			check(t, err)
+
+			ciphertextTest := new(Ciphertext)
+			err = ciphertextTest.UnmarshalBinary(marshalledCiphertext)
+			check(t, err)
+
+			if ciphertextWant.Degree() != ciphertextTest.Degree() {
+				t.Errorf("Marshal Ciphertext Degree")
+			}
+
+			if ciphertextWant.Level() != ciphertextTest.Level() {
+				t.Errorf("Marshal Ciphertext Level")
+			}
+
+			if ciphertextWant.Scale() != ciphertextTest.Scale() {
+				t.Errorf("Marshal Ciphertext Scale")
+			}
+
+			for i := range ciphertextWant.value {
+				if !params.ckkscontext.contextQ.EqualLvl(ciphertextWant.Level(), ciphertextWant.Value()[i], ciphertextTest.Value()[i]) {
+					t.Errorf("Marshal Ciphertext Coefficients")
+				}
+			}
+		})
+
+		t.Run(testString("Sk", parameters), func(t *testing.T) {
+
+			marshalledSk, err := params.sk.MarshalBinary()
+			check(t, err)
+
+			sk := new(SecretKey)
+			err = sk.UnmarshalBinary(marshalledSk)
+			check(t, err)
+
+			if !contextQP.Equal(sk.sk, params.sk.sk) {
+				t.Errorf("Marshal SecretKey")
+			}
+
+		})
+
+		t.Run(testString("Pk", parameters), func(t *testing.T) {
+
+			marshalledPk, err := params.pk.MarshalBinary()
+			check(t, err)
+
+			pk := new(PublicKey)
+			err = pk.UnmarshalBinary(marshalledPk)
+			check(t, err)
+
+			for k := range params.pk.pk {
+				if !contextQP.Equal(pk.pk[k], params.pk.pk[k]) {
+					t.Errorf("Marshal PublicKey element [%d]", k)
+				}
+			}
+		})
+
+		t.Run(testString("EvaluationKey", parameters), func(t *testing.T) {
+
+			evalKey := params.kgen.GenRelinKey(params.sk)
+			data, err := evalKey.MarshalBinary()
+			check(t, err)
+
+			resEvalKey := new(EvaluationKey)
+			err = resEvalKey.UnmarshalBinary(data)
+			check(t, err)
+
+			evakeyWant := evalKey.evakey.evakey
+			evakeyTest := resEvalKey.evakey.evakey
+
+			for j := range evakeyWant {
+
+				for k := range evakeyWant[j] {
+					if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+						t.Errorf("Marshal EvaluationKey element [%d][%d]", j, k)
+					}
+				}
+			}
+		})
+
+		t.Run(testString("SwitchingKey", parameters), func(t *testing.T) {
+
+			skOut := params.kgen.GenSecretKey()
+
+			switchingKey := params.kgen.GenSwitchingKey(params.sk, skOut)
+			data, err := switchingKey.MarshalBinary()
+			check(t, err)
+
+			resSwitchingKey := new(SwitchingKey)
+			err = resSwitchingKey.UnmarshalBinary(data)
+			check(t, err)
+
+			evakeyWant := switchingKey.evakey
+			evakeyTest := resSwitchingKey.evakey
+
+			for j := range evakeyWant {
+
+				for k := range evakeyWant[j] {
+					if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+						t.Errorf("Marshal Switchingkey element [%d][%d]", j, k)
+					}
+				}
+			}
+		})
+
+		t.Run(testString("RotationKey", parameters), func(t *testing.T) {
+
+			rotationKey := NewRotationKeys()
+
+			params.kgen.GenRot(Conjugate, params.sk, 0, rotationKey)
+			params.kgen.GenRot(RotationLeft, params.sk, 1, rotationKey)
+			params.kgen.GenRot(RotationLeft, params.sk, 2, rotationKey)
+			params.kgen.GenRot(RotationRight, params.sk, 3, rotationKey)
+			params.kgen.GenRot(RotationRight, params.sk, 5, rotationKey)
+
+			data, err := rotationKey.MarshalBinary()
+			check(t, err)
+
+			resRotationKey := new(RotationKeys)
+			err = resRotationKey.UnmarshalBinary(data)
+			check(t, err)
+
+			if len(resRotationKey.keys) != len(rotationKey.keys) {
+				t.Errorf("Marshal RotationKey number of keys")
+			}
+
+			for galEl, switchkey := range rotationKey.keys {
+
+				if resRotationKey.keys[galEl] == nil {
+					t.Errorf("Marshal RotationKey Galois element %d missing", galEl)
+					continue
+				}
+
+				if !utils.EqualSliceUint64(rotationKey.permuteNTTIndex[galEl], resRotationKey.permuteNTTIndex[galEl]) {
+					t.Errorf("Marshal RotationKey Galois element %d PermuteNTTIndex", galEl)
+				}
+
+				evakeyWant := switchkey.evakey
+				evakeyTest := resRotationKey.keys[galEl].evakey
+
+				for j := range evakeyWant {
+
+					for k := range evakeyWant[j] {
+						if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
+							t.Errorf("Marshal RotationKey Galois element %d element [%d][%d]", galEl, j, k)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+type streamable interface {
+	io.WriterTo
+	MarshalBinary() ([]byte, error)
+	GetDataLen(WithMetaData bool) uint64
+}
+
+// writeAndCompare writes the object with WriteTo and checks that the result matches MarshalBinary.
+func writeAndCompare(t *testing.T, object streamable) []byte {
+
+	buffer := new(bytes.Buffer)
+
+	n, err := object.WriteTo(buffer)
+	check(t, err)
+
+	if uint64(n) != object.GetDataLen(true) || n != int64(buffer.Len()) {
+		t.Errorf("invalid number of bytes written")
+	}
+
+	data, err := object.MarshalBinary()
+	check(t, err)
 
-		})
+	if !bytes.Equal(data, buffer.Bytes()) {
+		t.Errorf("WriteTo does not match MarshalBinary")
 	}
+
+	return buffer.Bytes()
 }
 
-func testMarshaller(t *testing.T) {
+func testStreaming(t *testing.T) {
 
 	for _, parameters := range testParams.ckksParameters {
 
@@ -1003,200 +1961,524 @@ func testMarshaller(t *testing.T) {
 
 		contextQP := params.ckkscontext.contextQP
 
+		equalSwitchingKey := func(swkWant, swkTest *SwitchingKey) bool {
+			for j := range swkWant.evakey {
+				for k := range swkWant.evakey[j] {
+					if !contextQP.Equal(swkWant.evakey[j][k], swkTest.evakey[j][k]) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+
 		t.Run(testString("Ciphertext/", parameters), func(t *testing.T) {
 
 			ciphertextWant := NewCiphertextRandom(parameters, 2, parameters.MaxLevel(), parameters.Scale)
 
-			marshalledCiphertext, err := ciphertextWant.MarshalBinary()
-			check(t, err)
+			data := writeAndCompare(t, ciphertextWant)
 
 			ciphertextTest := new(Ciphertext)
-			err = ciphertextTest.UnmarshalBinary(marshalledCiphertext)
+			_, err := ciphertextTest.ReadFrom(bytes.NewReader(data))
 			check(t, err)
 
 			if ciphertextWant.Degree() != ciphertextTest.Degree() {
-				t.Errorf("Marshal Ciphertext Degree")
+				t.Errorf("Stream Ciphertext Degree")
 			}
 
 			if ciphertextWant.Level() != ciphertextTest.Level() {
-				t.Errorf("Marshal Ciphertext Level")
+				t.Errorf("Stream Ciphertext Level")
 			}
 
 			if ciphertextWant.Scale() != ciphertextTest.Scale() {
-				t.Errorf("Marshal Ciphertext Scale")
+				t.Errorf("Stream Ciphertext Scale")
 			}
 
 			for i := range ciphertextWant.value {
 				if !params.ckkscontext.contextQ.EqualLvl(ciphertextWant.Level(), ciphertextWant.Value()[i], ciphertextTest.Value()[i]) {
-					t.Errorf("Marshal Ciphertext Coefficients")
+					t.Errorf("Stream Ciphertext Coefficients")
 				}
 			}
 		})
 
-		t.Run(testString("Sk", parameters), func(t *testing.T) {
+		t.Run(testString("Ciphertext/InvalidHeader/", parameters), func(t *testing.T) {
 
-			marshalledSk, err := params.sk.MarshalBinary()
-			check(t, err)
+			ciphertext := NewCiphertextRandom(parameters, 2, parameters.MaxLevel(), parameters.Scale)
+
+			data := writeAndCompare(t, ciphertext)
+
+			// Offset of the header of the first polynomial, which follows the header of the ciphertext
+			offset := len(data) - len(ciphertext.value)*int(ciphertext.value[0].GetDataLen(true))
+
+			corrupt := func(i int, b byte) []byte {
+				corrupted := append([]byte{}, data...)
+				corrupted[i] = b
+				return corrupted
+			}
+
+			for name, invalid := range map[string][]byte{
+				"logN=0":       corrupt(offset, 0),
+				"logN>MaxLogN": corrupt(offset, MaxLogN+1),
+				"logN=255":     corrupt(offset, 255),
+				"moduli=0":     corrupt(offset+1, 0),
+				"moduli=255":   corrupt(offset+1, 255),
+				"truncated":    data[:len(data)/2],
+				"headerOnly":   data[:offset+2],
+			} {
+				if _, err := new(Ciphertext).ReadFrom(bytes.NewReader(invalid)); err == nil {
+					t.Errorf("invalid stream (%s) has been accepted", name)
+				}
+			}
+		})
+
+		t.Run(testString("Sk/", parameters), func(t *testing.T) {
+
+			data := writeAndCompare(t, params.sk)
 
 			sk := new(SecretKey)
-			err = sk.UnmarshalBinary(marshalledSk)
+			_, err := sk.ReadFrom(bytes.NewReader(data))
 			check(t, err)
 
 			if !contextQP.Equal(sk.sk, params.sk.sk) {
-				t.Errorf("Marshal SecretKey")
+				t.Errorf("Stream SecretKey")
 			}
-
 		})
 
-		t.Run(testString("Pk", parameters), func(t *testing.T) {
+		t.Run(testString("Pk/", parameters), func(t *testing.T) {
 
-			marshalledPk, err := params.pk.MarshalBinary()
-			check(t, err)
+			data := writeAndCompare(t, params.pk)
 
 			pk := new(PublicKey)
-			err = pk.UnmarshalBinary(marshalledPk)
+			_, err := pk.ReadFrom(bytes.NewReader(data))
 			check(t, err)
 
 			for k := range params.pk.pk {
 				if !contextQP.Equal(pk.pk[k], params.pk.pk[k]) {
-					t.Errorf("Marshal PublicKey element [%d]", k)
+					t.Errorf("Stream PublicKey element [%d]", k)
 				}
 			}
 		})
 
-		t.Run(testString("EvaluationKey", parameters), func(t *testing.T) {
+		t.Run(testString("EvaluationKey/", parameters), func(t *testing.T) {
 
 			evalKey := params.kgen.GenRelinKey(params.sk)
-			data, err := evalKey.MarshalBinary()
-			check(t, err)
+
+			data := writeAndCompare(t, evalKey)
 
 			resEvalKey := new(EvaluationKey)
-			err = resEvalKey.UnmarshalBinary(data)
+			_, err := resEvalKey.ReadFrom(bytes.NewReader(data))
 			check(t, err)
 
-			evakeyWant := evalKey.evakey.evakey
-			evakeyTest := resEvalKey.evakey.evakey
+			if !equalSwitchingKey(evalKey.evakey, resEvalKey.evakey) {
+				t.Errorf("Stream EvaluationKey")
+			}
+		})
 
-			for j := range evakeyWant {
+		t.Run(testString("SwitchingKey/", parameters), func(t *testing.T) {
 
-				for k := range evakeyWant[j] {
-					if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-						t.Errorf("Marshal EvaluationKey element [%d][%d]", j, k)
-					}
-				}
+			switchingKey := params.kgen.GenSwitchingKey(params.sk, params.kgen.GenSecretKey())
+
+			data := writeAndCompare(t, switchingKey)
+
+			resSwitchingKey := new(SwitchingKey)
+			_, err := resSwitchingKey.ReadFrom(bytes.NewReader(data))
+			check(t, err)
+
+			if !equalSwitchingKey(switchingKey, resSwitchingKey) {
+				t.Errorf("Stream SwitchingKey")
 			}
 		})
 
-		t.Run(testString("SwitchingKey", parameters), func(t *testing.T) {
+		t.Run(testString("RotationKey/", parameters), func(t *testing.T) {
 
-			skOut := params.kgen.GenSecretKey()
+			rotationKey := NewRotationKeys()
 
-			switchingKey := params.kgen.GenSwitchingKey(params.sk, skOut)
-			data, err := switchingKey.MarshalBinary()
+			params.kgen.GenRot(Conjugate, params.sk, 0, rotationKey)
+			params.kgen.GenRot(RotationLeft, params.sk, 1, rotationKey)
+			params.kgen.GenRot(RotationRight, params.sk, 3, rotationKey)
+
+			buffer := new(bytes.Buffer)
+
+			n, err := rotationKey.WriteTo(buffer)
 			check(t, err)
 
-			resSwitchingKey := new(SwitchingKey)
-			err = resSwitchingKey.UnmarshalBinary(data)
+			if uint64(n) != rotationKey.GetDataLen(true) {
+				t.Errorf("invalid number of bytes written")
+			}
+
+			resRotationKey := new(RotationKeys)
+			_, err = resRotationKey.ReadFrom(buffer)
 			check(t, err)
 
-			evakeyWant := switchingKey.evakey
-			evakeyTest := resSwitchingKey.evakey
+			if !equalSwitchingKey(rotationKey.keys[params.ckkscontext.galElRotColLeft[1]], resRotationKey.keys[params.ckkscontext.galElRotColLeft[1]]) ||
+				!utils.EqualSliceUint64(rotationKey.permuteNTTIndex[params.ckkscontext.galElRotColLeft[1]], resRotationKey.permuteNTTIndex[params.ckkscontext.galElRotColLeft[1]]) {
+				t.Errorf("Stream RotationKey RotateLeft")
+			}
 
-			for j := range evakeyWant {
+			if !equalSwitchingKey(rotationKey.keys[params.ckkscontext.galElRotColRight[3]], resRotationKey.keys[params.ckkscontext.galElRotColRight[3]]) ||
+				!utils.EqualSliceUint64(rotationKey.permuteNTTIndex[params.ckkscontext.galElRotColRight[3]], resRotationKey.permuteNTTIndex[params.ckkscontext.galElRotColRight[3]]) {
+				t.Errorf("Stream RotationKey RotateRight")
+			}
 
-				for k := range evakeyWant[j] {
-					if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-						t.Errorf("Marshal Switchingkey element [%d][%d]", j, k)
+			if !equalSwitchingKey(rotationKey.keys[params.ckkscontext.galElConjugate], resRotationKey.keys[params.ckkscontext.galElConjugate]) ||
+				!utils.EqualSliceUint64(rotationKey.permuteNTTIndex[params.ckkscontext.galElConjugate], resRotationKey.permuteNTTIndex[params.ckkscontext.galElConjugate]) {
+				t.Errorf("Stream RotationKey Conjugate")
+			}
+
+			// A truncated stream must not be accepted
+			data, err := rotationKey.MarshalBinary()
+			check(t, err)
+
+			if _, err = new(RotationKeys).ReadFrom(bytes.NewReader(data[:len(data)-1])); err == nil {
+				t.Errorf("Stream RotationKey truncated")
+			}
+
+			// Only the keys announced in the header must be read from the stream
+			buffer = bytes.NewBuffer(append(data, 0xFF))
+
+			n, err = new(RotationKeys).ReadFrom(buffer)
+			check(t, err)
+
+			if n != int64(len(data)) || buffer.Len() != 1 {
+				t.Errorf("Stream RotationKey trailing data")
+			}
+		})
+	}
+}
+
+func testSeededKeys(t *testing.T) {
+
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		contextQP := params.ckkscontext.contextQP
+
+		kgen := NewKeyGeneratorSeeded(parameters)
+
+		equalSwitchingKey := func(swkWant, swkTest *SwitchingKey) bool {
+			for j := range swkWant.evakey {
+				for k := range swkWant.evakey[j] {
+					if !contextQP.Equal(swkWant.evakey[j][k], swkTest.evakey[j][k]) {
+						return false
 					}
 				}
 			}
+			return true
+		}
+
+		t.Run(testString("Pk/", parameters), func(t *testing.T) {
+
+			pk := kgen.GenPublicKey(params.sk)
+
+			if !pk.IsSeeded() || params.pk.IsSeeded() {
+				t.Errorf("Seeded PublicKey")
+			}
+
+			data, err := pk.MarshalBinary()
+			check(t, err)
+
+			dataUnseeded, err := params.pk.MarshalBinary()
+			check(t, err)
+
+			if uint64(len(data)) != uint64(len(dataUnseeded))-pk.pk[1].GetDataLen(true)+pk.seed.GetDataLen(true) {
+				t.Errorf("Seeded PublicKey is not compressed")
+			}
+
+			pkTest := new(PublicKey)
+			check(t, pkTest.UnmarshalBinary(data))
+
+			pkStream := new(PublicKey)
+			_, err = pkStream.ReadFrom(bytes.NewReader(writeAndCompare(t, pk)))
+			check(t, err)
+
+			for k := range pk.pk {
+				if !contextQP.Equal(pk.pk[k], pkTest.pk[k]) || !contextQP.Equal(pk.pk[k], pkStream.pk[k]) {
+					t.Errorf("Seeded PublicKey element [%d]", k)
+				}
+			}
+
+			// The seeded PublicKey must be a valid encryption key
+			values, _, ciphertext := newTestVectors(params, NewEncryptorFromPk(parameters, pkTest), 1, t)
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
 		})
 
-		t.Run(testString("RotationKey", parameters), func(t *testing.T) {
+		t.Run(testString("EvaluationKey/", parameters), func(t *testing.T) {
+
+			evalKey := kgen.GenRelinKey(params.sk)
+
+			data, err := evalKey.MarshalBinary()
+			check(t, err)
+
+			evalKeyTest := new(EvaluationKey)
+			check(t, evalKeyTest.UnmarshalBinary(data))
+
+			evalKeyStream := new(EvaluationKey)
+			_, err = evalKeyStream.ReadFrom(bytes.NewReader(writeAndCompare(t, evalKey)))
+			check(t, err)
+
+			if !evalKeyTest.evakey.IsSeeded() || !equalSwitchingKey(evalKey.evakey, evalKeyTest.evakey) || !equalSwitchingKey(evalKey.evakey, evalKeyStream.evakey) {
+				t.Errorf("Seeded EvaluationKey")
+			}
+		})
+
+		t.Run(testString("SwitchingKey/", parameters), func(t *testing.T) {
+
+			switchingKey := kgen.GenSwitchingKey(params.sk, kgen.GenSecretKey())
+
+			data, err := switchingKey.MarshalBinary()
+			check(t, err)
+
+			switchingKeyTest := new(SwitchingKey)
+			check(t, switchingKeyTest.UnmarshalBinary(data))
+
+			switchingKeyStream := new(SwitchingKey)
+			_, err = switchingKeyStream.ReadFrom(bytes.NewReader(writeAndCompare(t, switchingKey)))
+			check(t, err)
+
+			if !switchingKeyTest.IsSeeded() || !equalSwitchingKey(switchingKey, switchingKeyTest) || !equalSwitchingKey(switchingKey, switchingKeyStream) {
+				t.Errorf("Seeded SwitchingKey")
+			}
+		})
+
+		t.Run(testString("RotationKey/", parameters), func(t *testing.T) {
 
 			rotationKey := NewRotationKeys()
 
-			params.kgen.GenRot(Conjugate, params.sk, 0, rotationKey)
-			params.kgen.GenRot(RotationLeft, params.sk, 1, rotationKey)
-			params.kgen.GenRot(RotationLeft, params.sk, 2, rotationKey)
-			params.kgen.GenRot(RotationRight, params.sk, 3, rotationKey)
-			params.kgen.GenRot(RotationRight, params.sk, 5, rotationKey)
+			kgen.GenRot(Conjugate, params.sk, 0, rotationKey)
+			kgen.GenRot(RotationLeft, params.sk, 1, rotationKey)
 
 			data, err := rotationKey.MarshalBinary()
 			check(t, err)
 
-			resRotationKey := new(RotationKeys)
-			err = resRotationKey.UnmarshalBinary(data)
-			check(t, err)
+			rotationKeyTest := new(RotationKeys)
+			check(t, rotationKeyTest.UnmarshalBinary(data))
+
+			if !equalSwitchingKey(rotationKey.keys[params.ckkscontext.galElRotColLeft[1]], rotationKeyTest.keys[params.ckkscontext.galElRotColLeft[1]]) {
+				t.Errorf("Seeded RotationKey RotateLeft")
+			}
+
+			if !equalSwitchingKey(rotationKey.keys[params.ckkscontext.galElConjugate], rotationKeyTest.keys[params.ckkscontext.galElConjugate]) {
+				t.Errorf("Seeded RotationKey Conjugate")
+			}
+
+			// The unmarshaled seeded RotationKeys must be valid rotation keys
+			values, _, ciphertext := newTestVectorsReals(params, params.encryptorSk, -1, 1, t)
 
-			for i := uint64(1); i < params.ckkscontext.n>>1; i++ {
+			for i := range values {
+				values[i] = complex(real(values[i]), -imag(values[i]))
+			}
 
-				if rotationKey.evakeyRotColLeft[i] != nil {
+			params.evaluator.Conjugate(ciphertext, rotationKeyTest, ciphertext)
 
-					evakeyWant := rotationKey.evakeyRotColLeft[i].evakey
-					evakeyTest := resRotationKey.evakeyRotColLeft[i].evakey
+			verifyTestVectors(params, params.decryptor, values, ciphertext, t)
+		})
+	}
+}
 
-					evakeyNTTIndexWant := rotationKey.permuteNTTLeftIndex[i]
-					evakeyNTTIndexTest := resRotationKey.permuteNTTLeftIndex[i]
+func testSeededCiphertexts(t *testing.T) {
 
-					if !utils.EqualSliceUint64(evakeyNTTIndexWant, evakeyNTTIndexTest) {
-						t.Errorf("Marshal RotationKey RotateLeft PermuteNTTIndex")
-					}
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
 
-					for j := range evakeyWant {
+		contextQ := params.ckkscontext.contextQ
 
-						for k := range evakeyWant[j] {
-							if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-								t.Errorf("Marshal RotationKey RotateLeft %d element [%d][%d]", i, j, k)
-							}
-						}
+		encryptor := NewEncryptorFromSkSeeded(parameters, params.sk)
+
+		t.Run(testString("Marshalling/", parameters), func(t *testing.T) {
+
+			for _, level := range []uint64{parameters.MaxLevel(), parameters.MaxLevel() - 1} {
+
+				values, _, _ := newTestVectors(params, nil, 1, t)
+
+				plaintext := NewPlaintext(parameters, level, parameters.Scale)
+				params.encoder.Encode(plaintext, values, 1<<parameters.LogSlots)
+
+				ciphertext := encryptor.EncryptNew(plaintext)
+
+				data, err := ciphertext.MarshalBinary()
+				check(t, err)
+
+				dataUnseeded, err := (&Ciphertext{ckksElement: ciphertext.ckksElement}).MarshalBinary()
+				check(t, err)
+
+				if uint64(len(data)) != uint64(len(dataUnseeded))-ciphertext.value[1].GetDataLen(true)+ciphertext.seed.GetDataLen(true) {
+					t.Errorf("Seeded Ciphertext is not compressed")
+				}
+
+				ciphertextTest := new(Ciphertext)
+				check(t, ciphertextTest.UnmarshalBinary(data))
+
+				ciphertextStream := new(Ciphertext)
+				_, err = ciphertextStream.ReadFrom(bytes.NewReader(writeAndCompare(t, ciphertext)))
+				check(t, err)
+
+				if ciphertextTest.Level() != level || ciphertextTest.Scale() != ciphertext.Scale() {
+					t.Errorf("Seeded Ciphertext metadata")
+				}
+
+				for i := range ciphertext.value {
+					if !contextQ.EqualLvl(level, ciphertext.value[i], ciphertextTest.value[i]) || !contextQ.EqualLvl(level, ciphertext.value[i], ciphertextStream.value[i]) {
+						t.Errorf("Seeded Ciphertext element [%d]", i)
 					}
 				}
 
-				if rotationKey.evakeyRotColRight[i] != nil {
+				verifyTestVectors(params, params.decryptor, values, ciphertextTest, t)
+			}
+		})
+
+		t.Run(testString("Modified/", parameters), func(t *testing.T) {
+
+			values, plaintext, _ := newTestVectors(params, nil, 1, t)
+
+			ciphertext := encryptor.EncryptNew(plaintext)
 
-					evakeyWant := rotationKey.evakeyRotColRight[i].evakey
-					evakeyTest := resRotationKey.evakeyRotColRight[i].evakey
+			params.evaluator.Add(ciphertext, ciphertext, ciphertext)
+			for i := range values {
+				values[i] *= 2
+			}
+
+			// The modified Ciphertext can no longer be regenerated from its seed and must be marshaled in full
+			data, err := ciphertext.MarshalBinary()
+			check(t, err)
+
+			if uint64(len(data)) != 11+ciphertext.value[0].GetDataLen(true)+ciphertext.value[1].GetDataLen(true) {
+				t.Errorf("Modified seeded Ciphertext is compressed")
+			}
+
+			ciphertextTest := new(Ciphertext)
+			check(t, ciphertextTest.UnmarshalBinary(data))
+
+			verifyTestVectors(params, params.decryptor, values, ciphertextTest, t)
+		})
+
+		t.Run(testString("ModifiedValue/", parameters), func(t *testing.T) {
+
+			values, plaintext, _ := newTestVectors(params, nil, 1, t)
+
+			ciphertext := encryptor.EncryptNew(plaintext)
+
+			// Reading the Value of the Ciphertext does not discard its seed
+			if ciphertext.Value()[1] != ciphertext.value[1] || ciphertext.validSeed() == nil {
+				t.Errorf("Seeded Ciphertext discarded its seed on read")
+			}
+
+			// The Ciphertext is modified through its Value, as done by the multiparty protocols
+			for _, pol := range ciphertext.Value() {
+				contextQ.Add(pol, pol, pol)
+			}
+
+			for i := range values {
+				values[i] *= 2
+			}
+
+			data, err := ciphertext.MarshalBinary()
+			check(t, err)
+
+			if uint64(len(data)) != 11+ciphertext.value[0].GetDataLen(true)+ciphertext.value[1].GetDataLen(true) {
+				t.Errorf("Modified seeded Ciphertext is compressed")
+			}
+
+			ciphertextTest := new(Ciphertext)
+			check(t, ciphertextTest.UnmarshalBinary(data))
+
+			verifyTestVectors(params, params.decryptor, values, ciphertextTest, t)
+		})
+	}
+}
+
+// testShallowCopy uses shallow copies of the encoder, encryptors, decryptor and evaluator concurrently,
+// and is meant to be run with the race detector (go test -race).
+func testShallowCopy(t *testing.T) {
 
-					evakeyNTTIndexWant := rotationKey.permuteNTTRightIndex[i]
-					evakeyNTTIndexTest := resRotationKey.permuteNTTRightIndex[i]
+	for _, parameters := range testParams.ckksParameters {
+
+		params := genCkksParams(parameters)
+
+		rlk := params.kgen.GenRelinKey(params.sk)
+
+		t.Run(testString("Concurrent/", parameters), func(t *testing.T) {
+
+			var wg sync.WaitGroup
+
+			for i := 0; i < 4; i++ {
 
-					if !utils.EqualSliceUint64(evakeyNTTIndexWant, evakeyNTTIndexTest) {
-						t.Errorf("Marshal RotationKey RotateRight PermuteNTTIndex")
+				paramsCopy := *params
+				paramsCopy.encoder = params.encoder.ShallowCopy()
+				paramsCopy.encryptorPk = params.encryptorPk.ShallowCopy()
+				paramsCopy.encryptorSk = params.encryptorSk.ShallowCopy()
+				paramsCopy.decryptor = params.decryptor.ShallowCopy()
+				paramsCopy.evaluator = params.evaluator.ShallowCopy()
+
+				encryptor := paramsCopy.encryptorPk
+				if i&1 == 1 {
+					encryptor = paramsCopy.encryptorSk
+				}
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					values, _, ciphertext := newTestVectors(&paramsCopy, encryptor, 1, t)
+
+					for j := range values {
+						values[j] *= values[j]
 					}
 
-					for j := range evakeyWant {
+					paramsCopy.evaluator.MulRelin(ciphertext, ciphertext, rlk, ciphertext)
 
-						for k := range evakeyWant[j] {
-							if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-								t.Errorf("Marshal RotationKey RotateRight %d element [%d][%d]", i, j, k)
-							}
-						}
+					if err := paramsCopy.evaluator.Rescale(ciphertext, parameters.Scale, ciphertext); err != nil {
+						t.Error(err)
 					}
-				}
+
+					verifyTestVectors(&paramsCopy, paramsCopy.decryptor, values, ciphertext, t)
+				}()
 			}
 
-			if rotationKey.evakeyConjugate != nil {
+			wg.Wait()
+		})
 
-				evakeyWant := rotationKey.evakeyConjugate.evakey
-				evakeyTest := resRotationKey.evakeyConjugate.evakey
+		t.Run(testString("WorkerPool/", parameters), func(t *testing.T) {
 
-				evakeyNTTIndexWant := rotationKey.permuteNTTConjugateIndex
-				evakeyNTTIndexTest := resRotationKey.permuteNTTConjugateIndex
+			pool := ring.NewWorkerPool(4)
+			defer pool.Close()
 
-				if !utils.EqualSliceUint64(evakeyNTTIndexWant, evakeyNTTIndexTest) {
-					t.Errorf("Marshal RotationKey Conjugate PermuteNTTIndex")
-				}
+			// The shallow copies share the WorkerPool of the evaluator they are copied from
+			evaluator := NewEvaluator(parameters)
+			evaluator.SetWorkerPool(pool)
 
-				for j := range evakeyWant {
+			var wg sync.WaitGroup
 
-					for k := range evakeyWant[j] {
-						if !contextQP.Equal(evakeyWant[j][k], evakeyTest[j][k]) {
-							t.Errorf("Marshal RotationKey RotateRow element [%d][%d]", j, k)
-						}
+			for i := 0; i < 2; i++ {
+
+				paramsCopy := *params
+				paramsCopy.encoder = params.encoder.ShallowCopy()
+				paramsCopy.encryptorPk = params.encryptorPk.ShallowCopy()
+				paramsCopy.decryptor = params.decryptor.ShallowCopy()
+				paramsCopy.evaluator = evaluator.ShallowCopy()
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					values, _, ciphertext := newTestVectors(&paramsCopy, paramsCopy.encryptorPk, 1, t)
+
+					for j := range values {
+						values[j] *= values[j]
 					}
-				}
+
+					paramsCopy.evaluator.MulRelin(ciphertext, ciphertext, rlk, ciphertext)
+
+					if err := paramsCopy.evaluator.Rescale(ciphertext, parameters.Scale, ciphertext); err != nil {
+						t.Error(err)
+					}
+
+					verifyTestVectors(&paramsCopy, paramsCopy.decryptor, values, ciphertext, t)
+				}()
 			}
+
+			wg.Wait()
 		})
 	}
 }
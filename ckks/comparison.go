@@ -0,0 +1,297 @@
+package ckks
+
+import (
+	"math"
+	"math/bits"
+)
+
+// SignApproximation is a struct storing a composite polynomial approximation p_k o ... o p_1 of the sign function on
+// [-1, -2^-logGap] U [2^-logGap, 1], where each p_i is an odd polynomial of degree 2n+1.
+//
+// The composition follows the composite minimax approach of Lee et al. (Minimax Approximation of Sign Function by
+// Composite Polynomial for Homomorphic Comparison, IEEE TDSC 2021): p_1 is the minimax odd approximation of the sign
+// function on [-1, -2^-logGap] U [2^-logGap, 1], computed with ApproximateMinimaxOdd, and p_{i+1} is the minimax odd
+// approximation of the sign function on [-hi_i, -lo_i] U [lo_i, hi_i], where [lo_i, hi_i] is the estimated image of
+// [2^-logGap, 1] by p_i o ... o p_1. The error of the composition is max(1 - lo_k, hi_k - 1).
+type SignApproximation struct {
+	n     uint64
+	polys [][]float64
+	lo    float64
+	hi    float64
+}
+
+// NewSignApproximation returns the composite minimax approximation of the sign function on [-1, -2^-logGap] U
+// [2^-logGap, 1] (logGap in [1, 24]) by odd polynomials of degree 2n+1 (n in [1, 7]) with the fewest polynomials whose
+// estimated error (see Precision) is at most 2^-logPrecision. The composition stalls at about 2^-30 to 2^-48 depending
+// on n and logGap, as the minimax polynomials become ill-conditioned in float64, hence logPrecision is at most 30.
+func NewSignApproximation(n, logGap, logPrecision uint64) (approx *SignApproximation) {
+
+	if logPrecision > 30 {
+		panic("cannot NewSignApproximation : logPrecision cannot be larger than 30")
+	}
+
+	approx = newSignApproximation(n, logGap)
+
+	for len(approx.polys) == 0 || approx.Precision() < float64(logPrecision) {
+		if !approx.compose() {
+			panic("cannot NewSignApproximation : the precision cannot be reached with float64 coefficients")
+		}
+	}
+
+	return
+}
+
+// NewSignApproximationWithDepth returns the composite minimax approximation of the sign function on
+// [-1, -2^-logGap] U [2^-logGap, 1] (logGap in [1, 24]) by odd polynomials of degree 2n+1 (n in [1, 7]) with as many
+// polynomials as allowed by the depth, stopping earlier if an additional polynomial does not improve the approximation.
+func NewSignApproximationWithDepth(n, logGap, depth uint64) (approx *SignApproximation) {
+
+	approx = newSignApproximation(n, logGap)
+
+	if depth < approx.polyDepth() {
+		panic("cannot NewSignApproximationWithDepth : depth is too small to evaluate a polynomial of degree 2n+1")
+	}
+
+	for approx.Depth()+approx.polyDepth() <= depth && approx.compose() {
+	}
+
+	return
+}
+
+func newSignApproximation(n, logGap uint64) (approx *SignApproximation) {
+
+	if n < 1 || n > 7 {
+		panic("cannot NewSignApproximation : n must be in [1, 7]")
+	}
+
+	if logGap < 1 || logGap > 24 {
+		panic("cannot NewSignApproximation : logGap must be in [1, 24]")
+	}
+
+	approx = new(SignApproximation)
+	approx.n = n
+	approx.lo = math.Exp2(-float64(logGap))
+	approx.hi = 1
+
+	return
+}
+
+// Depth returns the number of levels consumed by the evaluation of the approximation (see SignNew).
+func (approx *SignApproximation) Depth() uint64 {
+	return uint64(len(approx.polys)) * approx.polyDepth()
+}
+
+// Precision returns -log2 of the estimated maximum error of the approximation on [-1, -2^-logGap] U [2^-logGap, 1],
+// not taking into account the error of the homomorphic evaluation. The error of each polynomial is measured with the
+// Remez algorithm on its monomial coefficients, which are the ones evaluated, and is an estimate as for
+// ApproximateMinimax.
+func (approx *SignApproximation) Precision() float64 {
+	return -math.Log2(math.Max(1-approx.lo, approx.hi-1))
+}
+
+// Polynomials returns the coefficients of the odd polynomials of the approximation, in the order of their evaluation.
+func (approx *SignApproximation) Polynomials() (polys [][]float64) {
+	polys = make([][]float64, len(approx.polys))
+	for i := range approx.polys {
+		polys[i] = make([]float64, len(approx.polys[i]))
+		copy(polys[i], approx.polys[i])
+	}
+	return
+}
+
+// polyDepth returns the number of levels consumed by the evaluation of a polynomial of degree 2n+1 with EvaluatePolyEco.
+func (approx *SignApproximation) polyDepth() uint64 {
+	return uint64(bits.Len64(2*approx.n + 1))
+}
+
+// compose appends to the approximation the minimax odd approximation of the sign function on [-hi, -lo] U [lo, hi],
+// and returns false, leaving the approximation unchanged, if it does not increase the ratio lo/hi of the image of the
+// input interval, which is the ratio that determines the error of the next polynomials.
+func (approx *SignApproximation) compose() bool {
+
+	one := func(x float64) float64 { return 1 }
+
+	cheby, _ := ApproximateMinimaxOdd(one, approx.lo, approx.hi, int(2*approx.n+1))
+
+	coeffs := chebyshevToMonomial(cheby)
+
+	// The polynomial is evaluated from its monomial coefficients, hence its image is measured on them. The error
+	// does not exactly equioscillate, so the largest deviations below and above 1 are measured separately.
+	extrema, _ := remezExtrema(one, coeffs, approx.lo, approx.hi, monomialBasis)
+
+	var errLo, errHi float64
+	for _, x := range extrema {
+		e := remezError(one, coeffs, x, monomialBasis)
+		errLo = math.Max(errLo, e)
+		errHi = math.Max(errHi, -e)
+	}
+
+	// The deviations are estimated as for ApproximateMinimax. For small gaps, errLo is close to 1 and its margin
+	// would exceed 1-errLo, hence the lower bound of the image, which is reached at lo, is then estimated with the
+	// same relative margin from 1-errLo.
+	lo := math.Max(1-minimaxErrorEstimate(errLo, coeffs), (1-errLo)*(1-minimaxErrorMargin))
+	hi := 1 + minimaxErrorEstimate(errHi, coeffs)
+
+	if !(lo/hi > approx.lo/approx.hi) {
+		return false
+	}
+
+	approx.polys = append(approx.polys, coeffs)
+	approx.lo, approx.hi = lo, hi
+
+	return true
+}
+
+// chebyshevToMonomial returns the monomial coefficients of a Chebyshev interpolation on an interval [-b, b].
+func chebyshevToMonomial(cheby *ChebyshevInterpolation) (coeffs []float64) {
+
+	degree := int(cheby.degree)
+
+	coeffs = make([]float64, degree+1)
+
+	// Monomial coefficients of T_{j-1}(u) and T_j(u)
+	Tprev := make([]float64, degree+1)
+	T := make([]float64, degree+1)
+	Tprev[0] = 1
+	T[1] = 1
+
+	for j := 0; j <= degree; j++ {
+
+		c := real(cheby.coeffs[uint64(j)])
+		for i := range coeffs {
+			coeffs[i] += c * Tprev[i]
+		}
+
+		// T_{j+1}(u) = 2u * T_j(u) - T_{j-1}(u)
+		Tnext := make([]float64, degree+1)
+		for i := 0; i < degree; i++ {
+			Tnext[i+1] = 2 * T[i]
+		}
+		for i := range Tnext {
+			Tnext[i] -= Tprev[i]
+		}
+
+		Tprev, T = T, Tnext
+	}
+
+	// p(x) = sum_j c_j * T_j(x/b)
+	scale := 1.0
+	for i := range coeffs {
+		coeffs[i] *= scale
+		scale /= real(cheby.b)
+	}
+
+	return
+}
+
+// SignNew homomorphically evaluates the composite approximation of the sign function on the input Ciphertext and
+// returns the result on a new Ciphertext. The values of ct0 must be real and in [-1, -2^-logGap] U [2^-logGap, 1],
+// and ct0 must be at a level at least approx.Depth(), which is the number of levels consumed.
+func (eval *evaluator) SignNew(ct0 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext) {
+	return eval.evaluateSign(ct0, approx, 1, 0, evakey)
+}
+
+// CompareNew homomorphically compares ct0 and ct1 and returns on a new Ciphertext an approximation of 1 for the slots
+// where ct0 > ct1 and of 0 for the slots where ct0 < ct1, which is (sign(ct0 - ct1) + 1)/2. The values of ct0 - ct1
+// must be real and in [-1, -2^-logGap] U [2^-logGap, 1]. It consumes approx.Depth() levels.
+func (eval *evaluator) CompareNew(ct0, ct1 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext) {
+	return eval.evaluateSign(eval.SubNew(ct0, ct1), approx, 0.5, 0.5, evakey)
+}
+
+// ReLUNew homomorphically evaluates max(x, 0) = x * (sign(x) + 1)/2 on the input Ciphertext and returns the result on a
+// new Ciphertext. The values of ct0 must be real and in [-1, 1]. The error is bounded by the error of the approximation
+// for the values of absolute value larger than 2^-logGap and by the values themselves otherwise. It consumes
+// approx.Depth() + 1 levels.
+func (eval *evaluator) ReLUNew(ct0 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext) {
+
+	if ct0.Level() < approx.Depth()+1 {
+		panic("cannot ReLUNew : input level is smaller than the depth of the approximation + 1")
+	}
+
+	ctOut = eval.evaluateSign(ct0, approx, 0.5, 0.5, evakey)
+
+	eval.MulRelin(ctOut, ct0, evakey, ctOut)
+
+	eval.Rescale(ctOut, eval.ckksContext.scale, ctOut)
+
+	return
+}
+
+// MaxNew homomorphically evaluates max(ct0, ct1) = ct0 * s + ct1 * (1 - s), with s = (sign(ct0 - ct1) + 1)/2, and
+// returns the result on a new Ciphertext. The values of ct0 - ct1 must be real and in [-1, 1], and the error is
+// bounded as for ReLUNew. The two input Ciphertexts must have the same scale. It consumes approx.Depth() + 1 levels.
+func (eval *evaluator) MaxNew(ct0, ct1 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext) {
+	return eval.selectNew(ct0, ct1, approx, true, evakey)
+}
+
+// MinNew homomorphically evaluates min(ct0, ct1) = ct1 * s + ct0 * (1 - s), with s = (sign(ct0 - ct1) + 1)/2, and
+// returns the result on a new Ciphertext. The values of ct0 - ct1 must be real and in [-1, 1], and the error is
+// bounded as for ReLUNew. The two input Ciphertexts must have the same scale. It consumes approx.Depth() + 1 levels.
+func (eval *evaluator) MinNew(ct0, ct1 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext) {
+	return eval.selectNew(ct0, ct1, approx, false, evakey)
+}
+
+// selectNew homomorphically evaluates max(ct0, ct1) if max is true and min(ct0, ct1) otherwise. Both products by
+// s and 1 - s have the same scale, so that they are added exactly before a single relinearization and rescaling.
+func (eval *evaluator) selectNew(ct0, ct1 *Ciphertext, approx *SignApproximation, max bool, evakey *EvaluationKey) (ctOut *Ciphertext) {
+
+	if ct0.Level() < approx.Depth()+1 || ct1.Level() < approx.Depth()+1 {
+		panic("cannot MaxNew/MinNew : input level is smaller than the depth of the approximation + 1")
+	}
+
+	if ct0.Scale() != ct1.Scale() {
+		panic("cannot MaxNew/MinNew : input Ciphertexts must have the same scale")
+	}
+
+	step := eval.evaluateSign(eval.SubNew(ct0, ct1), approx, 0.5, 0.5, evakey)
+
+	stepNeg := eval.NegNew(step)
+	eval.AddConst(stepNeg, 1, stepNeg)
+
+	if !max {
+		step, stepNeg = stepNeg, step
+	}
+
+	ctOut = NewCiphertext(eval.params, 2, step.Level(), ct0.Scale()*step.Scale())
+
+	eval.MulAndAdd(ct0, step, ctOut)
+	eval.MulAndAdd(ct1, stepNeg, ctOut)
+
+	eval.Relinearize(ctOut, evakey, ctOut)
+
+	eval.Rescale(ctOut, eval.ckksContext.scale, ctOut)
+
+	return
+}
+
+// evaluateSign homomorphically evaluates a * sign(x) + b on the input Ciphertext, a and b being merged into the last
+// polynomial of the approximation, and returns the result on a new Ciphertext.
+func (eval *evaluator) evaluateSign(ct0 *Ciphertext, approx *SignApproximation, a, b float64, evakey *EvaluationKey) (ctOut *Ciphertext) {
+
+	if len(approx.polys) == 0 {
+		panic("cannot evaluate the sign approximation : the approximation is empty")
+	}
+
+	if ct0.Level() < approx.Depth() {
+		panic("cannot evaluate the sign approximation : input level is smaller than the depth of the approximation")
+	}
+
+	ctOut = ct0
+
+	for i, poly := range approx.polys {
+
+		coeffs := poly
+
+		if i == len(approx.polys)-1 {
+			coeffs = make([]float64, len(poly))
+			for j := range poly {
+				coeffs[j] = a * poly[j]
+			}
+			coeffs[0] += b
+		}
+
+		ctOut = eval.EvaluatePolyEco(ctOut, coeffs, evakey)
+	}
+
+	return
+}
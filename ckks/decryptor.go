@@ -10,6 +10,10 @@ type Decryptor interface {
 	// receiver plaintext. A Horner method is used for evaluating the
 	// decryption.
 	Decrypt(ciphertext *Ciphertext, plaintext *Plaintext)
+
+	// ShallowCopy creates a shallow copy of the Decryptor, which shares the read-only
+	// precomputations and the secret-key of the original.
+	ShallowCopy() Decryptor
 }
 
 // decryptor is a structure used to decrypt ciphertext. It stores the secret-key.
@@ -37,6 +41,11 @@ func NewDecryptor(params *Parameters, sk *SecretKey) Decryptor {
 	}
 }
 
+func (decryptor *decryptor) ShallowCopy() Decryptor {
+	decryptorCopy := *decryptor
+	return &decryptorCopy
+}
+
 // DecryptNew decrypts the Ciphertext and returns a newly created Plaintext.
 // Horner method is used for evaluating the decryption.
 func (decryptor *decryptor) DecryptNew(ciphertext *Ciphertext) (plaintext *Plaintext) {
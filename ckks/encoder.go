@@ -10,7 +10,11 @@ import (
 type Encoder interface {
 	Encode(plaintext *Plaintext, values []complex128, slots uint64)
 	EncodeNew(values []complex128, slots uint64) (plaintext *Plaintext)
+	EncodeMul(plaintext *PlaintextMul, values []complex128, slots uint64)
 	Decode(plaintext *Plaintext, slots uint64) (res []complex128)
+	EncodeCoeffs(plaintext *Plaintext, values []float64)
+	DecodeCoeffs(plaintext *Plaintext) (res []float64)
+	ShallowCopy() Encoder
 }
 
 // encoder is a struct storing the necessary parameters to encode a slice of complex number on a Plaintext.
@@ -68,6 +72,22 @@ func NewEncoder(params *Parameters) Encoder {
 	}
 }
 
+// ShallowCopy creates a shallow copy of the target encoder, which shares the read-only precomputations of the
+// original (roots of unity and rotation group) but has its own memory pool. The copy and the original can then
+// be used concurrently.
+func (encoder *encoder) ShallowCopy() Encoder {
+
+	encoderCopy := *encoder
+
+	encoderCopy.values = make([]complex128, encoder.m>>2)
+	encoderCopy.valuesfloat = make([]float64, encoder.m>>1)
+	encoderCopy.bigintCoeffs = make([]*big.Int, encoder.m>>1)
+	encoderCopy.qHalf = ring.NewUint(0)
+	encoderCopy.polypool = encoder.ckksContext.contextQ.NewPoly()
+
+	return &encoderCopy
+}
+
 func (encoder *encoder) EncodeNew(values []complex128, slots uint64) (plaintext *Plaintext) {
 	plaintext = NewPlaintext(encoder.params, encoder.params.MaxLevel(), encoder.params.Scale)
 	encoder.Encode(plaintext, values, slots)
@@ -93,6 +113,19 @@ func (encoder *encoder) Encode(plaintext *Plaintext, values []complex128, slots
 		encoder.values[i] = values[i]
 	}
 
+	encoder.encodeValues(plaintext, slots)
+}
+
+// EncodeMul encodes a slice of complex128 of length slots = 2^{n} on a PlaintextMul, at the level and scale of the
+// plaintext, such that it can be multiplied with ciphertexts without further conversion.
+func (encoder *encoder) EncodeMul(plaintext *PlaintextMul, values []complex128, slots uint64) {
+	encoder.Encode(plaintext.Plaintext, values, slots)
+	encoder.ckksContext.contextQ.MFormLvl(plaintext.Level(), plaintext.value, plaintext.value)
+}
+
+// encodeValues encodes the first slots values of the encoder's pool in the receiver Plaintext and resets the pool.
+func (encoder *encoder) encodeValues(plaintext *Plaintext, slots uint64) {
+
 	encoder.invfft(encoder.values, slots)
 
 	gap := encoder.ckksContext.maxSlots / slots
@@ -115,58 +148,107 @@ func (encoder *encoder) Encode(plaintext *Plaintext, values []complex128, slots
 	}
 }
 
+// EncodeCoeffs takes a slice of float64 values of size at most N and encodes it directly on the coefficients of
+// the receiver Plaintext, without the slot transform. The multiplication of two such plaintexts (or of the
+// ciphertexts encrypting them) is the negacyclic convolution of their values, i.e. the product modulo X^N+1.
+func (encoder *encoder) EncodeCoeffs(plaintext *Plaintext, values []float64) {
+
+	if uint64(len(values)) > encoder.ckksContext.n {
+		panic("cannot EncodeCoeffs: too many values (maximum is N)")
+	}
+
+	copy(encoder.valuesfloat, values)
+
+	scaleUpVecExact(encoder.valuesfloat, plaintext.scale, encoder.ckksContext.contextQ.Modulus[:plaintext.Level()+1], plaintext.value.Coeffs)
+
+	encoder.ckksContext.contextQ.NTTLvl(plaintext.Level(), plaintext.value, plaintext.value)
+
+	for i := uint64(0); i < encoder.ckksContext.n; i++ {
+		encoder.valuesfloat[i] = 0
+	}
+}
+
 // Decode decodes the Plaintext values to a slice of complex128 values of size at most N/2.
 func (encoder *encoder) Decode(plaintext *Plaintext, slots uint64) (res []complex128) {
 
-	encoder.ckksContext.contextQ.InvNTTLvl(plaintext.Level(), plaintext.value, encoder.polypool)
-	encoder.ckksContext.contextQ.PolyToBigint(encoder.polypool, encoder.bigintCoeffs)
+	encoder.decodeValues(plaintext, slots)
 
-	Q := encoder.ckksContext.bigintChain[plaintext.Level()]
+	res = make([]complex128, slots)
 
-	maxSlots := encoder.ckksContext.maxSlots
+	for i := range res {
+		res[i] = encoder.values[i]
 
-	encoder.qHalf.Set(Q)
-	encoder.qHalf.Rsh(encoder.qHalf, 1)
+	}
 
-	gap := encoder.ckksContext.maxSlots / slots
+	for i := uint64(0); i < encoder.ckksContext.maxSlots; i++ {
+		encoder.values[i] = 0
+	}
 
-	var sign int
+	return
+}
 
-	for i, idx := uint64(0), uint64(0); i < slots; i, idx = i+1, idx+gap {
+// decodeValues decodes the Plaintext on the first slots values of the encoder's pool.
+func (encoder *encoder) decodeValues(plaintext *Plaintext, slots uint64) {
 
-		// Centers the value around the current modulus
-		encoder.bigintCoeffs[idx].Mod(encoder.bigintCoeffs[idx], Q)
-		sign = encoder.bigintCoeffs[idx].Cmp(encoder.qHalf)
-		if sign == 1 || sign == 0 {
-			encoder.bigintCoeffs[idx].Sub(encoder.bigintCoeffs[idx], Q)
-		}
+	Q := encoder.decodeBigint(plaintext)
+
+	maxSlots := encoder.ckksContext.maxSlots
+
+	gap := encoder.ckksContext.maxSlots / slots
+
+	for i, idx := uint64(0), uint64(0); i < slots; i, idx = i+1, idx+gap {
 
 		// Centers the value around the current modulus
-		encoder.bigintCoeffs[idx+maxSlots].Mod(encoder.bigintCoeffs[idx+maxSlots], Q)
-		sign = encoder.bigintCoeffs[idx+maxSlots].Cmp(encoder.qHalf)
-		if sign == 1 || sign == 0 {
-			encoder.bigintCoeffs[idx+maxSlots].Sub(encoder.bigintCoeffs[idx+maxSlots], Q)
-		}
+		encoder.centerBigint(idx, Q)
+		encoder.centerBigint(idx+maxSlots, Q)
 
 		encoder.values[i] = complex(scaleDown(encoder.bigintCoeffs[idx], plaintext.scale), scaleDown(encoder.bigintCoeffs[idx+maxSlots], plaintext.scale))
 	}
 
 	encoder.fft(encoder.values, slots)
+}
 
-	res = make([]complex128, slots)
+// DecodeCoeffs decodes the coefficients of a Plaintext encoded with EncodeCoeffs to a slice of N float64 values.
+func (encoder *encoder) DecodeCoeffs(plaintext *Plaintext) (res []float64) {
+
+	Q := encoder.decodeBigint(plaintext)
+
+	res = make([]float64, encoder.ckksContext.n)
 
 	for i := range res {
-		res[i] = encoder.values[i]
 
-	}
+		// Centers the value around the current modulus
+		encoder.centerBigint(uint64(i), Q)
 
-	for i := uint64(0); i < encoder.ckksContext.maxSlots; i++ {
-		encoder.values[i] = 0
+		res[i] = scaleDown(encoder.bigintCoeffs[i], plaintext.scale)
 	}
 
 	return
 }
 
+// decodeBigint reconstructs the coefficients of the Plaintext on the encoder's pool of big.Int and returns the modulus
+// of the level of the Plaintext.
+func (encoder *encoder) decodeBigint(plaintext *Plaintext) (Q *big.Int) {
+
+	encoder.ckksContext.contextQ.InvNTTLvl(plaintext.Level(), plaintext.value, encoder.polypool)
+	encoder.ckksContext.contextQ.PolyToBigint(encoder.polypool, encoder.bigintCoeffs)
+
+	Q = encoder.ckksContext.bigintChain[plaintext.Level()]
+
+	encoder.qHalf.Set(Q)
+	encoder.qHalf.Rsh(encoder.qHalf, 1)
+
+	return
+}
+
+// centerBigint centers the i-th coefficient of the encoder's pool of big.Int around the modulus Q.
+func (encoder *encoder) centerBigint(i uint64, Q *big.Int) {
+	encoder.bigintCoeffs[i].Mod(encoder.bigintCoeffs[i], Q)
+	if encoder.bigintCoeffs[i].Cmp(encoder.qHalf) >= 0 {
+		encoder.bigintCoeffs[i].Sub(encoder.bigintCoeffs[i], Q)
+	}
+}
+
 func (encoder *encoder) invfftlazy(values []complex128, N uint64) {
 
 	var lenh, lenq, gap, idx uint64
@@ -0,0 +1,419 @@
+package ckks
+
+import (
+	"github.com/ldsec/lattigo/ring"
+	"math/big"
+)
+
+// EncoderBigComplex is an interface implementing the encoding algorithms with arbitrary precision.
+type EncoderBigComplex interface {
+	Encode(plaintext *Plaintext, values []*ring.Complex, slots uint64)
+	EncodeNew(values []*ring.Complex, slots uint64) (plaintext *Plaintext)
+	Decode(plaintext *Plaintext, slots uint64) (res []*ring.Complex)
+	EncodeReal(plaintext *Plaintext, values []*big.Float, slots uint64)
+	EncodeRealNew(values []*big.Float, slots uint64) (plaintext *Plaintext)
+	DecodeReal(plaintext *Plaintext, slots uint64) (res []*big.Float)
+	FFT(values []*ring.Complex, N uint64)
+	InvFFT(values []*ring.Complex, N uint64)
+	ShallowCopy() EncoderBigComplex
+}
+
+// encoderBigComplex is a struct storing the necessary parameters to encode a slice of arbitrary precision complex
+// numbers on a Plaintext.
+type encoderBigComplex struct {
+	params       *Parameters
+	ckksContext  *Context
+	prec         uint
+	zero         *big.Float
+	cMul         *ring.ComplexMultiplier
+	values       []*ring.Complex
+	valuesfloat  []*big.Float
+	bigintCoeffs []*big.Int
+	qHalf        *big.Int
+	polypool     *ring.Poly
+	m            uint64
+	roots        []*ring.Complex
+	rotGroup     []uint64
+}
+
+// NewEncoderBigComplex creates a new EncoderBigComplex that is used to encode a slice of arbitrary precision complex
+// values of size at most N/2 (the number of slots) on a Plaintext. The FFT, the scaling and the CRT reconstruction
+// are done with logPrecision bits of precision instead of the 53 bits of a float64, so that the precision of the
+// encoding is only bounded by the scale and the moduli of the parameters.
+func NewEncoderBigComplex(params *Parameters, logPrecision uint64) EncoderBigComplex {
+
+	if !params.isValid {
+		panic("cannot NewEncoderBigComplex: parameters are invalid (check if the generation was done properly)")
+	}
+
+	if logPrecision < 53 {
+		panic("cannot NewEncoderBigComplex: logPrecision must be at least 53")
+	}
+
+	prec := uint(logPrecision)
+
+	m := uint64(2 << params.LogN)
+
+	rotGroup := make([]uint64, m>>1)
+	fivePows := uint64(1)
+	for i := uint64(0); i < m>>2; i++ {
+		rotGroup[i] = fivePows
+		fivePows *= GaloisGen
+		fivePows &= (m - 1)
+	}
+
+	ckksContext := newContext(params)
+
+	encoder := &encoderBigComplex{
+		params:      params.Copy(),
+		ckksContext: ckksContext,
+		prec:        prec,
+		m:           m,
+		rotGroup:    rotGroup,
+		roots:       genRootsBigComplex(m, prec),
+	}
+
+	encoder.allocatePools()
+
+	return encoder
+}
+
+// genRootsBigComplex returns the m+1 powers of the primitive m-th root of unity exp(2*Pi*i/m), with prec bits of precision.
+func genRootsBigComplex(m uint64, prec uint) (roots []*ring.Complex) {
+
+	// The powers are computed with guard bits to absorb the error of the successive multiplications
+	guardPrec := prec + 64
+
+	newFloat := func(x int64) *big.Float {
+		return new(big.Float).SetPrec(guardPrec).SetInt64(x)
+	}
+
+	// Starting from exp(2*Pi*i/4) = i, the primitive m-th root is obtained with the half-angle formulas
+	// cos(t/2) = sqrt((1+cos(t))/2) and sin(t/2) = sin(t)/(2*cos(t/2)), which are numerically stable for small t.
+	cos, sin := newFloat(0), newFloat(1)
+	half := new(big.Float).SetPrec(guardPrec).SetFloat64(0.5)
+
+	for k := uint64(4); k < m; k <<= 1 {
+		cos.Add(cos, newFloat(1))
+		cos.Mul(cos, half)
+		cos.Sqrt(cos)
+		sin.Quo(sin, cos)
+		sin.Mul(sin, half)
+	}
+
+	cMul := ring.NewComplexMultiplier()
+
+	root := ring.NewComplex(cos, sin)
+
+	quarter := m >> 2
+
+	powers := make([]*ring.Complex, m+1)
+	powers[0] = ring.NewComplex(newFloat(1), newFloat(0))
+	for i := uint64(1); i <= quarter; i++ {
+		powers[i] = ring.NewComplex(nil, nil)
+		cMul.Mul(powers[i-1], root, powers[i])
+	}
+
+	// The other quadrants are obtained from the first one by exact rotations of Pi/2
+	for i := quarter + 1; i <= m; i++ {
+		prev := powers[i-quarter]
+		powers[i] = ring.NewComplex(new(big.Float).Neg(prev[1]), prev[0])
+	}
+
+	roots = make([]*ring.Complex, m+1)
+	for i := range powers {
+		roots[i] = ring.NewComplex(nil, nil)
+		roots[i].SetPrec(prec)
+		roots[i].Set(powers[i])
+	}
+
+	return
+}
+
+// allocatePools allocates the memory pools of the encoder.
+func (encoder *encoderBigComplex) allocatePools() {
+
+	encoder.zero = new(big.Float).SetPrec(encoder.prec)
+	encoder.cMul = ring.NewComplexMultiplier()
+
+	encoder.values = make([]*ring.Complex, encoder.m>>2)
+	for i := range encoder.values {
+		encoder.values[i] = ring.NewComplex(encoder.zero, encoder.zero)
+	}
+
+	encoder.valuesfloat = make([]*big.Float, encoder.m>>1)
+	for i := range encoder.valuesfloat {
+		encoder.valuesfloat[i] = new(big.Float).SetPrec(encoder.prec)
+	}
+
+	encoder.bigintCoeffs = make([]*big.Int, encoder.m>>1)
+	for i := range encoder.bigintCoeffs {
+		encoder.bigintCoeffs[i] = new(big.Int)
+	}
+
+	encoder.qHalf = ring.NewUint(0)
+	encoder.polypool = encoder.ckksContext.contextQ.NewPoly()
+}
+
+// ShallowCopy creates a shallow copy of the target encoder, which shares the read-only precomputations of the
+// original (roots of unity and rotation group) but has its own memory pool. The copy and the original can then
+// be used concurrently.
+func (encoder *encoderBigComplex) ShallowCopy() EncoderBigComplex {
+
+	encoderCopy := *encoder
+
+	encoderCopy.allocatePools()
+
+	return &encoderCopy
+}
+
+func (encoder *encoderBigComplex) EncodeNew(values []*ring.Complex, slots uint64) (plaintext *Plaintext) {
+	plaintext = NewPlaintext(encoder.params, encoder.params.MaxLevel(), encoder.params.Scale)
+	encoder.Encode(plaintext, values, slots)
+	return
+}
+
+// Encode takes a slice of arbitrary precision complex values of size at most N/2 (the number of slots) and encodes
+// it in the receiver Plaintext.
+func (encoder *encoderBigComplex) Encode(plaintext *Plaintext, values []*ring.Complex, slots uint64) {
+
+	if slots == 0 || slots&(slots-1) != 0 || slots > encoder.ckksContext.maxSlots {
+		panic("cannot Encode: slots must be a power of two between 1 and N/2")
+	}
+
+	if uint64(len(values)) != slots {
+		panic("cannot Encode: number of values must be equal to slots")
+	}
+
+	for i := uint64(0); i < slots; i++ {
+		encoder.values[i].Set(values[i])
+	}
+
+	encoder.encodeValues(plaintext, slots)
+}
+
+// EncodeRealNew encodes a slice of arbitrary precision real values of size at most N/2 (the number of slots) on a
+// new Plaintext at the maximum level and default scale of the parameters.
+func (encoder *encoderBigComplex) EncodeRealNew(values []*big.Float, slots uint64) (plaintext *Plaintext) {
+	plaintext = NewPlaintext(encoder.params, encoder.params.MaxLevel(), encoder.params.Scale)
+	encoder.EncodeReal(plaintext, values, slots)
+	return
+}
+
+// EncodeReal takes a slice of arbitrary precision real values of size at most N/2 (the number of slots) and encodes
+// it in the receiver Plaintext, with a zero imaginary part.
+func (encoder *encoderBigComplex) EncodeReal(plaintext *Plaintext, values []*big.Float, slots uint64) {
+
+	if slots == 0 || slots&(slots-1) != 0 || slots > encoder.ckksContext.maxSlots {
+		panic("cannot EncodeReal: slots must be a power of two between 1 and N/2")
+	}
+
+	if uint64(len(values)) != slots {
+		panic("cannot EncodeReal: number of values must be equal to slots")
+	}
+
+	for i := uint64(0); i < slots; i++ {
+		encoder.values[i][0].Set(values[i])
+	}
+
+	encoder.encodeValues(plaintext, slots)
+}
+
+// encodeValues encodes the first slots values of the encoder's pool in the receiver Plaintext and resets the pool.
+func (encoder *encoderBigComplex) encodeValues(plaintext *Plaintext, slots uint64) {
+
+	encoder.InvFFT(encoder.values, slots)
+
+	gap := encoder.ckksContext.maxSlots / slots
+
+	for i, jdx, idx := uint64(0), encoder.ckksContext.maxSlots, uint64(0); i < slots; i, jdx, idx = i+1, jdx+gap, idx+gap {
+		encoder.valuesfloat[idx].Set(encoder.values[i].Real())
+		encoder.valuesfloat[jdx].Set(encoder.values[i].Imag())
+	}
+
+	// Scales up and rounds the coefficients to the nearest integer
+	scale := new(big.Float).SetPrec(encoder.prec).SetFloat64(plaintext.scale)
+	half := new(big.Float).SetPrec(encoder.prec).SetFloat64(0.5)
+
+	for i := range encoder.valuesfloat {
+
+		encoder.valuesfloat[i].Mul(encoder.valuesfloat[i], scale)
+
+		if encoder.valuesfloat[i].Sign() < 0 {
+			encoder.valuesfloat[i].Sub(encoder.valuesfloat[i], half)
+		} else {
+			encoder.valuesfloat[i].Add(encoder.valuesfloat[i], half)
+		}
+
+		encoder.valuesfloat[i].Int(encoder.bigintCoeffs[i])
+	}
+
+	encoder.ckksContext.contextQ.SetCoefficientsBigintLvl(plaintext.Level(), encoder.bigintCoeffs, plaintext.value)
+
+	encoder.ckksContext.contextQ.NTTLvl(plaintext.Level(), plaintext.value, plaintext.value)
+
+	for i := uint64(0); i < encoder.ckksContext.maxSlots; i++ {
+		encoder.values[i][0].Set(encoder.zero)
+		encoder.values[i][1].Set(encoder.zero)
+	}
+
+	for i := uint64(0); i < encoder.ckksContext.n; i++ {
+		encoder.valuesfloat[i].Set(encoder.zero)
+	}
+}
+
+// Decode decodes the Plaintext values to a slice of arbitrary precision complex values of size at most N/2.
+func (encoder *encoderBigComplex) Decode(plaintext *Plaintext, slots uint64) (res []*ring.Complex) {
+
+	encoder.decodeValues(plaintext, slots)
+
+	res = make([]*ring.Complex, slots)
+
+	for i := range res {
+		res[i] = encoder.values[i].Copy()
+	}
+
+	for i := uint64(0); i < encoder.ckksContext.maxSlots; i++ {
+		encoder.values[i][0].Set(encoder.zero)
+		encoder.values[i][1].Set(encoder.zero)
+	}
+
+	return
+}
+
+// DecodeReal decodes the Plaintext values to a slice of arbitrary precision real values of size at most N/2, the
+// real parts of the slots, discarding their imaginary parts.
+func (encoder *encoderBigComplex) DecodeReal(plaintext *Plaintext, slots uint64) (res []*big.Float) {
+
+	encoder.decodeValues(plaintext, slots)
+
+	res = make([]*big.Float, slots)
+
+	for i := range res {
+		res[i] = new(big.Float).Set(encoder.values[i].Real())
+	}
+
+	for i := uint64(0); i < encoder.ckksContext.maxSlots; i++ {
+		encoder.values[i][0].Set(encoder.zero)
+		encoder.values[i][1].Set(encoder.zero)
+	}
+
+	return
+}
+
+// decodeValues decodes the Plaintext on the first slots values of the encoder's pool.
+func (encoder *encoderBigComplex) decodeValues(plaintext *Plaintext, slots uint64) {
+
+	encoder.ckksContext.contextQ.InvNTTLvl(plaintext.Level(), plaintext.value, encoder.polypool)
+	encoder.ckksContext.contextQ.PolyToBigint(encoder.polypool, encoder.bigintCoeffs)
+
+	Q := encoder.ckksContext.bigintChain[plaintext.Level()]
+
+	maxSlots := encoder.ckksContext.maxSlots
+
+	encoder.qHalf.Set(Q)
+	encoder.qHalf.Rsh(encoder.qHalf, 1)
+
+	scale := new(big.Float).SetPrec(encoder.prec).SetFloat64(plaintext.scale)
+
+	gap := encoder.ckksContext.maxSlots / slots
+
+	for i, idx := uint64(0), uint64(0); i < slots; i, idx = i+1, idx+gap {
+
+		for k, jdx := range []uint64{idx, idx + maxSlots} {
+
+			// Centers the value around the current modulus
+			encoder.bigintCoeffs[jdx].Mod(encoder.bigintCoeffs[jdx], Q)
+			if encoder.bigintCoeffs[jdx].Cmp(encoder.qHalf) >= 0 {
+				encoder.bigintCoeffs[jdx].Sub(encoder.bigintCoeffs[jdx], Q)
+			}
+
+			encoder.values[i][k].SetInt(encoder.bigintCoeffs[jdx])
+			encoder.values[i][k].Quo(encoder.values[i][k], scale)
+		}
+	}
+
+	encoder.FFT(encoder.values, slots)
+}
+
+// InvFFT evaluates the decoding matrix on a slice of arbitrary precision complex values of size N (a power of two
+// at most N/2 of the parameters), in place.
+func (encoder *encoderBigComplex) InvFFT(values []*ring.Complex, N uint64) {
+
+	var lenh, lenq, gap, idx uint64
+
+	u := ring.NewComplex(nil, nil)
+	v := ring.NewComplex(nil, nil)
+
+	for len := N; len >= 1; len >>= 1 {
+		for i := uint64(0); i < N; i += len {
+			lenh = len >> 1
+			lenq = len << 2
+			gap = encoder.m / lenq
+			for j := uint64(0); j < lenh; j++ {
+				idx = (lenq - (encoder.rotGroup[j] % lenq)) * gap
+				u.Add(values[i+j], values[i+j+lenh])
+				v.Sub(values[i+j], values[i+j+lenh])
+				encoder.cMul.Mul(v, encoder.roots[idx], v)
+				values[i+j].Set(u)
+				values[i+j+lenh].Set(v)
+			}
+		}
+	}
+
+	NBig := new(big.Float).SetUint64(N)
+	for i := uint64(0); i < N; i++ {
+		values[i][0].Quo(values[i][0], NBig)
+		values[i][1].Quo(values[i][1], NBig)
+	}
+
+	sliceBitReverseInPlaceBigComplex(values, N)
+}
+
+// FFT evaluates the encoding matrix on a slice of arbitrary precision complex values of size N (a power of two
+// at most N/2 of the parameters), in place.
+func (encoder *encoderBigComplex) FFT(values []*ring.Complex, N uint64) {
+
+	var lenh, lenq, gap, idx uint64
+
+	u := ring.NewComplex(nil, nil)
+	v := ring.NewComplex(nil, nil)
+
+	sliceBitReverseInPlaceBigComplex(values, N)
+
+	for len := uint64(2); len <= N; len <<= 1 {
+		for i := uint64(0); i < N; i += len {
+			lenh = len >> 1
+			lenq = len << 2
+			gap = encoder.m / lenq
+			for j := uint64(0); j < lenh; j++ {
+				idx = (encoder.rotGroup[j] % lenq) * gap
+				u.Set(values[i+j])
+				encoder.cMul.Mul(values[i+j+lenh], encoder.roots[idx], v)
+				values[i+j].Add(u, v)
+				values[i+j+lenh].Sub(u, v)
+			}
+		}
+	}
+}
+
+func sliceBitReverseInPlaceBigComplex(slice []*ring.Complex, N uint64) {
+
+	var bit, j uint64
+
+	for i := uint64(1); i < N; i++ {
+
+		bit = N >> 1
+
+		for j >= bit {
+			j -= bit
+			bit >>= 1
+		}
+
+		j += bit
+
+		if i < j {
+			slice[i], slice[j] = slice[j], slice[i]
+		}
+	}
+}
@@ -52,6 +52,10 @@ type Encryptor interface {
 	// zero in Q, using the provided polynomial as the uniform polynomial, and
 	// then adding the plaintext.
 	EncryptFromCRPFast(plaintext *Plaintext, ciphertetx *Ciphertext, crp *ring.Poly)
+
+	// ShallowCopy creates a shallow copy of the Encryptor, which shares the read-only
+	// precomputations and the key of the original but has its own memory pool.
+	ShallowCopy() Encryptor
 }
 
 // encryptor is a struct used to encrypt Plaintexts. It stores the public-key and/or secret-key.
@@ -70,7 +74,8 @@ type pkEncryptor struct {
 
 type skEncryptor struct {
 	encryptor
-	sk *SecretKey
+	sk     *SecretKey
+	seeded bool
 }
 
 // NewEncryptorFromPk creates a new Encryptor with the provided public-key.
@@ -94,7 +99,22 @@ func NewEncryptorFromSk(params *Parameters, sk *SecretKey) Encryptor {
 		panic("cannot newEncryptor: sk ring degree does not match params ring degree")
 	}
 
-	return &skEncryptor{enc, sk}
+	return &skEncryptor{encryptor: enc, sk: sk}
+}
+
+// NewEncryptorFromSkSeeded creates a new Encryptor with the provided secret-key, generating seeded Ciphertexts :
+// the uniform polynomial a of each Ciphertext [-a*sk + m + e, a] is generated from a fresh random seed, which is
+// stored in the Ciphertext. When marshaled, these Ciphertexts only store the seed in place of a, which is regenerated
+// from it on unmarshal, halving their size. The seeded Ciphertexts are always encrypted modulo Q (as with EncryptFast).
+// The encryptions from a CRP are not seeded.
+func NewEncryptorFromSkSeeded(params *Parameters, sk *SecretKey) Encryptor {
+	enc := newEncryptor(params)
+
+	if uint64(sk.sk.GetDegree()) != uint64(1<<params.LogN) {
+		panic("cannot newEncryptor: sk ring degree does not match params ring degree")
+	}
+
+	return &skEncryptor{encryptor: enc, sk: sk, seeded: true}
 }
 
 func newEncryptor(params *Parameters) encryptor {
@@ -118,6 +138,29 @@ func newEncryptor(params *Parameters) encryptor {
 	}
 }
 
+// shallowCopy returns a copy of the target encryptor sharing its read-only precomputations, with a new memory pool.
+func (encryptor *encryptor) shallowCopy() encryptor {
+
+	qp := encryptor.ckksContext.contextQP
+
+	encryptorCopy := *encryptor
+	encryptorCopy.polypool = [3]*ring.Poly{qp.NewPoly(), qp.NewPoly(), qp.NewPoly()}
+
+	if encryptor.baseconverter != nil {
+		encryptorCopy.baseconverter = encryptor.baseconverter.ShallowCopy()
+	}
+
+	return encryptorCopy
+}
+
+func (encryptor *pkEncryptor) ShallowCopy() Encryptor {
+	return &pkEncryptor{encryptor.encryptor.shallowCopy(), encryptor.pk}
+}
+
+func (encryptor *skEncryptor) ShallowCopy() Encryptor {
+	return &skEncryptor{encryptor: encryptor.encryptor.shallowCopy(), sk: encryptor.sk, seeded: encryptor.seeded}
+}
+
 // EncryptNew encrypts the input Plaintext using the stored key and returns
 // the result on a newly created Ciphertext.
 //
@@ -178,6 +221,8 @@ func (encryptor *pkEncryptor) EncryptFromCRPFastNew(plaintext *Plaintext, crp *r
 // encrypt with sk: ciphertext = [-a*sk + m + e, a]
 func (encryptor *pkEncryptor) encrypt(plaintext *Plaintext, ciphertext *Ciphertext, fast bool) {
 
+	ciphertext.seed = nil
+
 	// We sample a R-WLE instance (encryption of zero) over the keys context (ciphertext context + special prime)
 
 	contextQ := encryptor.ckksContext.contextQ
@@ -298,6 +343,11 @@ func (encryptor *skEncryptor) EncryptFromCRPFast(plaintext *Plaintext, ciphertex
 }
 
 func (encryptor *skEncryptor) encryptSample(plaintext *Plaintext, ciphertext *Ciphertext, fast bool) {
+	if encryptor.seeded {
+		encryptor.encryptSeeded(plaintext, ciphertext)
+		return
+	}
+
 	if fast {
 		encryptor.ckksContext.contextQ.UniformPoly(encryptor.polypool[1])
 	} else {
@@ -317,6 +367,8 @@ func (encryptor *skEncryptor) encryptFromCRP(plaintext *Plaintext, ciphertext *C
 
 func (encryptor *skEncryptor) encrypt(plaintext *Plaintext, ciphertext *Ciphertext, crp *ring.Poly, fast bool) {
 
+	ciphertext.seed = nil
+
 	contextQ := encryptor.ckksContext.contextQ
 
 	if fast {
@@ -360,3 +412,31 @@ func (encryptor *skEncryptor) encrypt(plaintext *Plaintext, ciphertext *Cipherte
 
 	ciphertext.isNTT = true
 }
+
+// encryptSeeded encrypts the Plaintext modulo Q, generating the uniform polynomial a of the Ciphertext from a
+// fresh random seed, which is stored in the Ciphertext.
+func (encryptor *skEncryptor) encryptSeeded(plaintext *Plaintext, ciphertext *Ciphertext) {
+
+	contextQ := encryptor.ckksContext.contextQ
+
+	level := plaintext.Level()
+
+	seed := ring.NewPolySeed(contextQ.Modulus[:level+1])
+
+	// ct1 = a, uniform in the NTT domain and generated from the seed
+	seed.CRPGenerator(contextQ.N).Clock(ciphertext.value[1])
+
+	// ct0 = -s*a
+	contextQ.MulCoeffsMontgomeryLvl(level, ciphertext.value[1], encryptor.sk.sk, ciphertext.value[0])
+	contextQ.NegLvl(level, ciphertext.value[0], ciphertext.value[0])
+
+	// ct0 = -s*a + e
+	encryptor.ckksContext.gaussianSampler.SampleNTT(encryptor.polypool[0])
+	contextQ.AddLvl(level, ciphertext.value[0], encryptor.polypool[0], ciphertext.value[0])
+
+	// ct0 = -s*a + m + e
+	contextQ.AddLvl(level, ciphertext.value[0], plaintext.value, ciphertext.value[0])
+
+	ciphertext.isNTT = true
+	ciphertext.seed = seed
+}
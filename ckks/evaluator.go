@@ -40,6 +40,7 @@ type Evaluator interface {
 	RescaleMany(ct0 *Ciphertext, nbRescales uint64, c1 *Ciphertext) (err error)
 	MulRelinNew(op0, op1 Operand, evakey *EvaluationKey) (ctOut *Ciphertext)
 	MulRelin(op0, op1 Operand, evakey *EvaluationKey, ctOut *Ciphertext)
+	MulAndAdd(op0, op1 Operand, ctOut *Ciphertext)
 	RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (ctOut *Ciphertext)
 	Relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext)
 	SwitchKeysNew(ct0 *Ciphertext, switchingKey *SwitchingKey) (ctOut *Ciphertext)
@@ -47,6 +48,8 @@ type Evaluator interface {
 	RotateColumnsNew(ct0 *Ciphertext, k uint64, evakey *RotationKeys) (ctOut *Ciphertext)
 	RotateColumns(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext)
 	RotateHoisted(ctIn *Ciphertext, rotations []uint64, rotkeys *RotationKeys) (cOut map[uint64]*Ciphertext)
+	LinearTransformNew(ct0 *Ciphertext, linearTransform *LinearTransform, rotKeys *RotationKeys) (ctOut *Ciphertext)
+	LinearTransform(ct0 *Ciphertext, linearTransform *LinearTransform, rotKeys *RotationKeys, ctOut *Ciphertext)
 	ConjugateNew(ct0 *Ciphertext, evakey *RotationKeys) (ctOut *Ciphertext)
 	Conjugate(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext)
 	PowerOf2(el0 *Ciphertext, logPow2 uint64, evakey *EvaluationKey, elOut *Ciphertext)
@@ -55,8 +58,17 @@ type Evaluator interface {
 	InverseNew(ct0 *Ciphertext, steps uint64, evakey *EvaluationKey) (res *Ciphertext)
 	EvaluatePolyFast(ct *Ciphertext, coeffs interface{}, evakey *EvaluationKey) (res *Ciphertext)
 	EvaluatePolyEco(ct *Ciphertext, coeffs interface{}, evakey *EvaluationKey) (res *Ciphertext)
+	EvaluatePoly(ct *Ciphertext, coeffs interface{}, targetScale float64, evakey *EvaluationKey) (res *Ciphertext)
 	EvaluateChebyFast(ct *Ciphertext, cheby *ChebyshevInterpolation, evakey *EvaluationKey) (res *Ciphertext)
 	EvaluateChebyEco(ct *Ciphertext, cheby *ChebyshevInterpolation, evakey *EvaluationKey) (res *Ciphertext)
+	EvaluateCheby(ct *Ciphertext, cheby *ChebyshevInterpolation, targetScale float64, evakey *EvaluationKey) (res *Ciphertext)
+	SignNew(ct0 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext)
+	CompareNew(ct0, ct1 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext)
+	ReLUNew(ct0 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext)
+	MaxNew(ct0, ct1 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext)
+	MinNew(ct0, ct1 *Ciphertext, approx *SignApproximation, evakey *EvaluationKey) (ctOut *Ciphertext)
+	SetWorkerPool(pool *ring.WorkerPool)
+	ShallowCopy() Evaluator
 }
 
 // evaluator is a struct that holds the necessary elements to execute the homomorphic operations between Ciphertexts and/or Plaintexts.
@@ -90,26 +102,90 @@ func NewEvaluator(params *Parameters) Evaluator {
 
 	var baseconverter *ring.FastBasisExtender
 	var decomposer *ring.Decomposer
-	var poolP [3]*ring.Poly
 	if len(params.Pi) != 0 {
 		baseconverter = ring.NewFastBasisExtender(q, p)
 		decomposer = ring.NewDecomposer(q.Modulus, p.Modulus)
-		poolP = [3]*ring.Poly{p.NewPoly(), p.NewPoly(), p.NewPoly()}
 	}
 
-	return &evaluator{
+	eval := &evaluator{
 		params:        params.Copy(),
 		ckksContext:   ckksContext,
-		ringpool:      [6]*ring.Poly{q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly()},
-		poolQ:         [4]*ring.Poly{q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly()},
-		poolP:         poolP,
-		ctxpool:       NewCiphertext(params, 1, params.MaxLevel(), params.Scale),
 		baseconverter: baseconverter,
 		decomposer:    decomposer,
 	}
+
+	eval.allocatePools()
+
+	return eval
+}
+
+// ShallowCopy creates a shallow copy of the target evaluator, which shares the read-only precomputations of the
+// original (contexts, basis extension and decomposition parameters) but has its own memory pool. The copy and the
+// original can then be used concurrently, e.g. one per goroutine.
+func (eval *evaluator) ShallowCopy() Evaluator {
+
+	evalCopy := *eval
+
+	if eval.baseconverter != nil {
+		evalCopy.baseconverter = eval.baseconverter.ShallowCopy()
+	}
+
+	evalCopy.allocatePools()
+
+	return &evalCopy
+}
+
+// SetWorkerPool attaches the WorkerPool to the polynomial contexts of the target evaluator, so that the per-modulus
+// work of its operations (NTT, basis extensions, key-switching, ...) is split across the workers of the pool. A nil
+// WorkerPool restores the sequential execution. The contexts are shared with the shallow copies of the evaluator,
+// which therefore use the same WorkerPool, hence this method must not be called while one of them is being used.
+func (eval *evaluator) SetWorkerPool(pool *ring.WorkerPool) {
+
+	eval.ckksContext.contextQ.SetWorkerPool(pool)
+	eval.ckksContext.contextQP.SetWorkerPool(pool)
+
+	if eval.ckksContext.contextP != nil {
+		eval.ckksContext.contextP.SetWorkerPool(pool)
+	}
+}
+
+// allocatePools allocates the memory pool of the target evaluator.
+func (eval *evaluator) allocatePools() {
+
+	q := eval.ckksContext.contextQ
+	p := eval.ckksContext.contextP
+
+	eval.ringpool = [6]*ring.Poly{q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly()}
+	eval.poolQ = [4]*ring.Poly{q.NewPoly(), q.NewPoly(), q.NewPoly(), q.NewPoly()}
+	eval.ctxpool = NewCiphertext(eval.params, 1, eval.params.MaxLevel(), eval.params.Scale)
+
+	if len(eval.params.Pi) != 0 {
+		eval.poolP = [3]*ring.Poly{p.NewPoly(), p.NewPoly(), p.NewPoly()}
+	}
+}
+
+// discardSeed discards the seed of the receiver if it is a seeded Ciphertext, since its second polynomial can no
+// longer be regenerated from the seed once it is modified by the evaluator.
+func discardSeed(opOut Operand) {
+	if ct, isCiphertext := opOut.(*Ciphertext); isCiphertext {
+		ct.seed = nil
+	}
 }
 
+// getElemAndCheckBinary checks the operands of the binary operations other than the multiplications, which do not
+// accept a PlaintextMul, and returns their elements.
 func (eval *evaluator) getElemAndCheckBinary(op0, op1, opOut Operand, opOutMinDegree uint64) (el0, el1, elOut *ckksElement) {
+
+	if isPlaintextMul(op0) || isPlaintextMul(op1) || isPlaintextMul(opOut) {
+		panic("a PlaintextMul can only be multiplied with a Ciphertext (see MulRelin)")
+	}
+
+	return eval.getElemAndCheckBinaryMul(op0, op1, opOut, opOutMinDegree)
+}
+
+// getElemAndCheckBinaryMul checks the operands of the multiplications, which accept a PlaintextMul, and returns their
+// elements.
+func (eval *evaluator) getElemAndCheckBinaryMul(op0, op1, opOut Operand, opOutMinDegree uint64) (el0, el1, elOut *ckksElement) {
 	if op0 == nil || op1 == nil || opOut == nil {
 		panic("operands cannot be nil")
 	}
@@ -121,10 +197,19 @@ func (eval *evaluator) getElemAndCheckBinary(op0, op1, opOut Operand, opOutMinDe
 	if opOut.Degree() < opOutMinDegree {
 		panic("receiver operand degree is too small")
 	}
+
+	discardSeed(opOut)
+
 	el0, el1, elOut = op0.Element(), op1.Element(), opOut.Element()
 	return // TODO: more checks on elements
 }
 
+// isPlaintextMul returns true if the operand is a PlaintextMul, which can only be multiplied with a Ciphertext.
+func isPlaintextMul(op Operand) bool {
+	_, isPtMul := op.(*PlaintextMul)
+	return isPtMul
+}
+
 func (eval *evaluator) getElemAndCheckUnary(op0, opOut Operand, opOutMinDegree uint64) (el0, elOut *ckksElement) {
 	if op0 == nil || opOut == nil {
 		panic("operand cannot be nil")
@@ -137,6 +222,9 @@ func (eval *evaluator) getElemAndCheckUnary(op0, opOut Operand, opOutMinDegree u
 	if opOut.Degree() < opOutMinDegree {
 		panic("receiver operand degree is too small")
 	}
+
+	discardSeed(opOut)
+
 	el0, elOut = op0.Element(), opOut.Element()
 	return // TODO: more checks on elements
 }
@@ -344,6 +432,8 @@ func (eval *evaluator) evaluateInPlace(c0, c1, ctOut *ckksElement, evaluate func
 // Neg negates the value of ct0 and returns the result in ctOut.
 func (eval *evaluator) Neg(ct0 *Ciphertext, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	level := utils.MinUint64(ct0.Level(), ctOut.Level())
 
 	if ct0.Degree() != ctOut.Degree() {
@@ -372,6 +462,8 @@ func (eval *evaluator) AddConstNew(ct0 *Ciphertext, constant interface{}) (ctOut
 // AddConst adds the input constant (which can be a uint64, int64, float64 or complex128) to ct0 and returns the result in ctOut.
 func (eval *evaluator) AddConst(ct0 *Ciphertext, constant interface{}, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	var level uint64
 
 	level = utils.MinUint64(ct0.Level(), ctOut.Level())
@@ -513,10 +605,6 @@ func (eval *evaluator) MultByConstAndAdd(ct0 *Ciphertext, constant interface{},
 		cImag = float64(0)
 	}
 
-	var scaledConst, scaledConstReal, scaledConstImag uint64
-
-	context := eval.ckksContext.contextQ
-
 	// If a scaling would be required to multiply by the constant,
 	// it equalizes scales such that the scales match in the end.
 	if scale != 1 {
@@ -557,6 +645,17 @@ func (eval *evaluator) MultByConstAndAdd(ct0 *Ciphertext, constant interface{},
 		}
 	}
 
+	eval.multByConstAndAdd(level, ct0, cReal, cImag, scale, ctOut)
+}
+
+// multByConstAndAdd adds ct0 times the constant cReal + i*cImag, scaled by scale, to ctOut on the moduli up to level,
+// without modifying the scale of ctOut.
+func (eval *evaluator) multByConstAndAdd(level uint64, ct0 *Ciphertext, cReal, cImag, scale float64, ctOut *Ciphertext) {
+
+	var scaledConst, scaledConstReal, scaledConstImag uint64
+
+	context := eval.ckksContext.contextQ
+
 	// Component-wise multiplication of the following vector to the ciphertext:
 	// [a + b*psi_qi^2, ....., a + b*psi_qi^2, a - b*psi_qi^2, ...., a - b*psi_qi^2] mod Qi
 	// [{                  N/2                }{                N/2               }]
@@ -609,7 +708,8 @@ func (eval *evaluator) MultByConstAndAdd(ct0 *Ciphertext, constant interface{},
 
 // MultByConstNew multiplies ct0 by the input constant and returns the result in a newly created element.
 // The scale of the output element will depend on the scale of the input element and the constant (if the constant
-// needs to be scaled (its rational part is not zero)). The constant can be a uint64, int64, float64 or complex128.
+// needs to be scaled (its rational part is not zero)). The constant can be a uint64, int64, float64 or complex128,
+// or a PlaintextMul, in which case the slots of ct0 are multiplied by the slots of the plaintext (see MulRelin).
 func (eval *evaluator) MultByConstNew(ct0 *Ciphertext, constant interface{}) (ctOut *Ciphertext) {
 	ctOut = NewCiphertext(eval.params, ct0.Degree(), ct0.Level(), ct0.Scale())
 	eval.MultByConst(ct0, constant, ctOut)
@@ -618,9 +718,15 @@ func (eval *evaluator) MultByConstNew(ct0 *Ciphertext, constant interface{}) (ct
 
 // MultByConst multiplies ct0 by the input constant and returns the result in ctOut.
 // The scale of the output element will depend on the scale of the input element and the constant (if the constant
-// needs to be scaled (its rational part is not zero)). The constant can be a uint64, int64, float64 or complex128.
+// needs to be scaled (its rational part is not zero)). The constant can be a uint64, int64, float64 or complex128,
+// or a PlaintextMul, in which case the slots of ct0 are multiplied by the slots of the plaintext (see MulRelin).
 func (eval *evaluator) MultByConst(ct0 *Ciphertext, constant interface{}, ctOut *Ciphertext) {
 
+	if ptMul, isPtMul := constant.(*PlaintextMul); isPtMul {
+		eval.MulRelin(ct0, ptMul, nil, ctOut)
+		return
+	}
+
 	var level uint64
 
 	level = utils.MinUint64(ct0.Level(), ctOut.Level())
@@ -745,6 +851,8 @@ func (eval *evaluator) MultByiNew(ct0 *Ciphertext) (ctOut *Ciphertext) {
 // It does not change the scale.
 func (eval *evaluator) MultByi(ct0 *Ciphertext, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	var level uint64
 
 	level = utils.MinUint64(ct0.Level(), ctOut.Level())
@@ -794,6 +902,8 @@ func (eval *evaluator) DivByiNew(ct0 *Ciphertext) (ctOut *Ciphertext) {
 // It does not change the scale.
 func (eval *evaluator) DivByi(ct0 *Ciphertext, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	var level uint64
 
 	level = utils.MinUint64(ct0.Level(), ctOut.Level())
@@ -877,6 +987,8 @@ func (eval *evaluator) ReduceNew(ct0 *Ciphertext) (ctOut *Ciphertext) {
 // To be used in conjunction with functions that do not apply modular reduction.
 func (eval *evaluator) Reduce(ct0 *Ciphertext, ctOut *Ciphertext) error {
 
+	discardSeed(ctOut)
+
 	if ct0.Degree() != ctOut.Degree() {
 		return errors.New("cannot Reduce: degrees of receiver Ciphertext and input Ciphertext do not match")
 	}
@@ -900,6 +1012,8 @@ func (eval *evaluator) DropLevelNew(ct0 *Ciphertext, levels uint64) (ctOut *Ciph
 // No rescaling is applied during this procedure.
 func (eval *evaluator) DropLevel(ct0 *Ciphertext, levels uint64) (err error) {
 
+	discardSeed(ct0)
+
 	if ct0.Level() == 0 {
 		return errors.New("cannot DropLevel: Ciphertext already at level 0")
 	}
@@ -932,6 +1046,8 @@ func (eval *evaluator) RescaleNew(ct0 *Ciphertext, threshold float64) (ctOut *Ci
 // some error.
 func (eval *evaluator) Rescale(ct0 *Ciphertext, threshold float64, ctOut *Ciphertext) (err error) {
 
+	discardSeed(ctOut)
+
 	ringContext := eval.ckksContext.contextQ
 
 	if ct0.Level() == 0 {
@@ -970,6 +1086,8 @@ func (eval *evaluator) Rescale(ct0 *Ciphertext, threshold float64, ctOut *Cipher
 // RescaleMany applies Rescale several times in a row on the input Ciphertext.
 func (eval *evaluator) RescaleMany(ct0 *Ciphertext, nbRescales uint64, ctOut *Ciphertext) (err error) {
 
+	discardSeed(ctOut)
+
 	if ct0.Level() < nbRescales {
 		return errors.New("cannot RescaleMany: input Ciphertext level too low")
 	}
@@ -1013,9 +1131,10 @@ func (eval *evaluator) MulRelinNew(op0, op1 Operand, evakey *EvaluationKey) (ctO
 // key can be provided to apply a relinearization step to reduce the degree of the output element. This evaluation key is only
 // required when the two input elements are Ciphertexts. If no evaluation key is provided and the input elements are two Ciphertexts,
 // the resulting Ciphertext will be of degree two. This function only accepts Plaintexts (degree zero) and/or Ciphertexts of degree one.
+// A PlaintextMul is multiplied without being converted to the Montgomery form.
 func (eval *evaluator) MulRelin(op0, op1 Operand, evakey *EvaluationKey, ctOut *Ciphertext) {
 
-	el0, el1, elOut := eval.getElemAndCheckBinary(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
+	el0, el1, elOut := eval.getElemAndCheckBinaryMul(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
 
 	level := utils.MinUint64(utils.MinUint64(el0.Level(), el1.Level()), elOut.Level())
 
@@ -1123,15 +1242,107 @@ func (eval *evaluator) MulRelin(op0, op1 Operand, evakey *EvaluationKey, ctOut *
 			tmp0, tmp1 = el0, el1
 		}
 
-		c00 := eval.ringpool[0]
-		c00.Zero()
+		// A PlaintextMul is already in the Montgomery form
+		_, isPtMul0 := op0.(*PlaintextMul)
+		_, isPtMul1 := op1.(*PlaintextMul)
+
+		c00 := tmp0.value[0]
+
+		if !isPtMul0 && !isPtMul1 {
+			c00 = eval.ringpool[0]
+			c00.Zero()
+			context.MFormLvl(level, tmp0.value[0], c00)
+		}
 
-		context.MFormLvl(level, tmp0.value[0], c00)
 		context.MulCoeffsMontgomeryLvl(level, c00, tmp1.value[0], elOut.value[0])
 		context.MulCoeffsMontgomeryLvl(level, c00, tmp1.value[1], elOut.value[1])
 	}
 }
 
+// mulAndAddScaleTolerance is the maximum relative difference accepted by MulAndAdd between the scale of ctOut and the
+// product of the scales of its inputs. The products are accumulated as is, so that this difference is an additional
+// relative error on the result, which is negligible compared to the precision of the scheme.
+const mulAndAddScaleTolerance = 1e-9
+
+// MulAndAdd multiplies op0 by op1 and adds the result to ctOut, without relinearization nor rescaling, so that the sum
+// of several products (e.g. an inner product) can be accumulated on ctOut and relinearized and rescaled only once.
+// The product of two ciphertexts is of degree two, hence ctOut is resized to degree two if needed. The scale of ctOut
+// must be equal to the product of the scales of op0 and op1, up to a relative difference of mulAndAddScaleTolerance
+// which absorbs the rounding errors of scales computed differently (e.g. ctOut can be created with
+// NewCiphertext(params, 2, level, op0.Scale()*op1.Scale())), and ctOut cannot be one of the inputs.
+func (eval *evaluator) MulAndAdd(op0, op1 Operand, ctOut *Ciphertext) {
+
+	el0, el1, elOut := eval.getElemAndCheckBinaryMul(op0, op1, ctOut, utils.MaxUint64(op0.Degree(), op1.Degree()))
+
+	if el0.Degree() > 1 || el1.Degree() > 1 {
+		panic("cannot MulAndAdd: input elements must be of degree 0 or 1")
+	}
+
+	if elOut == el0 || elOut == el1 {
+		panic("cannot MulAndAdd: ctOut cannot be one of the inputs")
+	}
+
+	if !el0.IsNTT() || !el1.IsNTT() || !elOut.IsNTT() {
+		panic("cannot MulAndAdd: all the elements must be in NTT")
+	}
+
+	if scale := el0.Scale() * el1.Scale(); math.Abs(elOut.Scale()-scale) > mulAndAddScaleTolerance*scale {
+		panic("cannot MulAndAdd: the scale of ctOut must be equal to the product of the scales of op0 and op1")
+	}
+
+	level := utils.MinUint64(utils.MinUint64(el0.Level(), el1.Level()), elOut.Level())
+
+	if elOut.Level() > level {
+		eval.DropLevel(elOut.Ciphertext(), elOut.Level()-level)
+	}
+
+	context := eval.ckksContext.contextQ
+
+	// Case Ciphertext (x) Ciphertext
+	if el0.Degree()+el1.Degree() == 2 {
+
+		if elOut.Degree() < 2 {
+			elOut.Resize(eval.params, 2)
+		}
+
+		c00 := eval.ringpool[0]
+		c01 := eval.ringpool[1]
+
+		context.MFormLvl(level, el0.value[0], c00)
+		context.MFormLvl(level, el0.value[1], c01)
+
+		context.MulCoeffsMontgomeryAndAddLvl(level, c00, el1.value[0], elOut.value[0]) // c0 += c0[0]*c1[0]
+		context.MulCoeffsMontgomeryAndAddLvl(level, c00, el1.value[1], elOut.value[1])
+		context.MulCoeffsMontgomeryAndAddLvl(level, c01, el1.value[0], elOut.value[1]) // c1 += c0[0]*c1[1] + c0[1]*c1[0]
+		context.MulCoeffsMontgomeryAndAddLvl(level, c01, el1.value[1], elOut.value[2]) // c2 += c0[1]*c1[1]
+
+		// Case Plaintext (x) Ciphertext or Ciphertext (x) Plaintext
+	} else {
+
+		var tmp0, tmp1 *ckksElement
+
+		if el0.Degree() == 1 {
+			tmp0, tmp1 = el1, el0
+		} else {
+			tmp0, tmp1 = el0, el1
+		}
+
+		// A PlaintextMul is already in the Montgomery form
+		_, isPtMul0 := op0.(*PlaintextMul)
+		_, isPtMul1 := op1.(*PlaintextMul)
+
+		c00 := tmp0.value[0]
+
+		if !isPtMul0 && !isPtMul1 {
+			c00 = eval.ringpool[0]
+			context.MFormLvl(level, tmp0.value[0], c00)
+		}
+
+		context.MulCoeffsMontgomeryAndAddLvl(level, c00, tmp1.value[0], elOut.value[0])
+		context.MulCoeffsMontgomeryAndAddLvl(level, c00, tmp1.value[1], elOut.value[1])
+	}
+}
+
 // RelinearizeNew applies the relinearization procedure on ct0 and returns the result in a newly
 // created Ciphertext. The input Ciphertext must be of degree two.
 func (eval *evaluator) RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (ctOut *Ciphertext) {
@@ -1142,6 +1353,8 @@ func (eval *evaluator) RelinearizeNew(ct0 *Ciphertext, evakey *EvaluationKey) (c
 
 // Relinearize applies the relinearization procedure on ct0 and returns the result in ctOut. The input Ciphertext must be of degree two.
 func (eval *evaluator) Relinearize(ct0 *Ciphertext, evakey *EvaluationKey, ctOut *Ciphertext) {
+	discardSeed(ctOut)
+
 	if ct0.Degree() != 2 {
 		panic("cannot Relinearize: input Ciphertext is not of degree 2")
 	}
@@ -1175,6 +1388,8 @@ func (eval *evaluator) SwitchKeysNew(ct0 *Ciphertext, switchingKey *SwitchingKey
 // and the key under which the Ciphertext will be re-encrypted.
 func (eval *evaluator) SwitchKeys(ct0 *Ciphertext, switchingKey *SwitchingKey, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
 		panic("cannot SwitchKeys: input and output Ciphertext must be of degree 1")
 	}
@@ -1200,6 +1415,8 @@ func (eval *evaluator) RotateColumnsNew(ct0 *Ciphertext, k uint64, evakey *Rotat
 // If the provided element is a Ciphertext, a key-switching operation is necessary and a rotation key for the specific rotation needs to be provided.
 func (eval *evaluator) RotateColumns(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
 		panic("cannot RotateColumns: input and output Ciphertext must be of degree 1")
 	}
@@ -1214,17 +1431,17 @@ func (eval *evaluator) RotateColumns(ct0 *Ciphertext, k uint64, evakey *Rotation
 
 		ctOut.SetScale(ct0.Scale())
 
-		// It checks in the RotationKeys if the corresponding rotation has been generated
-		if evakey.evakeyRotColLeft[k] != nil {
+		// It checks in the RotationKeys if the corresponding rotation has been generated, either to the left or to the right
+		if galEl := eval.ckksContext.galElRotColLeft[k]; evakey.keys[galEl] != nil {
 
-			eval.permuteNTT(ct0, evakey.permuteNTTLeftIndex[k], evakey.evakeyRotColLeft[k], ctOut)
+			eval.permuteNTT(ct0, evakey.permuteNTTIndex[galEl], evakey.keys[galEl], ctOut)
 
 		} else {
 
 			// If not, it checks if the left and right pow2 rotations have been generated
 			hasPow2Rotations := true
 			for i := uint64(1); i < eval.ckksContext.n>>1; i <<= 1 {
-				if evakey.evakeyRotColLeft[i] == nil || evakey.evakeyRotColRight[i] == nil {
+				if evakey.keys[eval.ckksContext.galElRotColLeft[i]] == nil || evakey.keys[eval.ckksContext.galElRotColRight[i]] == nil {
 					hasPow2Rotations = false
 					break
 				}
@@ -1296,10 +1513,15 @@ func (eval *evaluator) switchKeyHoisted(ct0 *Ciphertext, c2QiQDecomp, c2QiPDecom
 
 	k &= (1 << (eval.ckksContext.logN - 1)) - 1
 
-	if evakey.permuteNTTLeftIndex[k] == nil {
+	galEl := eval.ckksContext.galElRotColLeft[k]
+
+	if evakey.keys[galEl] == nil {
 		panic("cannot switchKeyHoisted: specific rotation has not been generated")
 	}
 
+	permuteNTTIndex := evakey.permuteNTTIndex[galEl]
+	switchKey := evakey.keys[galEl]
+
 	ctOut.SetScale(ct0.Scale())
 
 	var level, reduce uint64
@@ -1310,10 +1532,10 @@ func (eval *evaluator) switchKeyHoisted(ct0 *Ciphertext, c2QiQDecomp, c2QiPDecom
 	contextP := eval.ckksContext.contextP
 
 	if ct0 != ctOut {
-		ring.PermuteNTTWithIndex(ct0.value[0], evakey.permuteNTTLeftIndex[k], eval.ringpool[0])
+		ring.PermuteNTTWithIndex(ct0.value[0], permuteNTTIndex, eval.ringpool[0])
 		contextQ.CopyLvl(level, eval.ringpool[0], ctOut.value[0])
 	} else {
-		ring.PermuteNTTWithIndex(ct0.value[0], evakey.permuteNTTLeftIndex[k], ctOut.value[0])
+		ring.PermuteNTTWithIndex(ct0.value[0], permuteNTTIndex, ctOut.value[0])
 	}
 
 	for i := range eval.poolQ {
@@ -1341,11 +1563,11 @@ func (eval *evaluator) switchKeyHoisted(ct0 *Ciphertext, c2QiQDecomp, c2QiPDecom
 	// Key switching with CRT decomposition for the Qi
 	for i := uint64(0); i < beta; i++ {
 
-		ring.PermuteNTTWithIndex(c2QiQDecomp[i], evakey.permuteNTTLeftIndex[k], c2QiQPermute)
-		ring.PermuteNTTWithIndex(c2QiPDecomp[i], evakey.permuteNTTLeftIndex[k], c2QiPPermute)
+		ring.PermuteNTTWithIndex(c2QiQDecomp[i], permuteNTTIndex, c2QiQPermute)
+		ring.PermuteNTTWithIndex(c2QiPDecomp[i], permuteNTTIndex, c2QiPPermute)
 
-		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakeyRotColLeft[k].evakey[i][0], c2QiQPermute, pool2Q)
-		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, evakey.evakeyRotColLeft[k].evakey[i][1], c2QiQPermute, pool3Q)
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, switchKey.evakey[i][0], c2QiQPermute, pool2Q)
+		contextQ.MulCoeffsMontgomeryAndAddNoModLvl(level, switchKey.evakey[i][1], c2QiQPermute, pool3Q)
 
 		// We continue with the key-switch primes.
 		for j, keysindex := uint64(0), eval.ckksContext.levels; j < uint64(len(contextP.Modulus)); j, keysindex = j+1, keysindex+1 {
@@ -1353,8 +1575,8 @@ func (eval *evaluator) switchKeyHoisted(ct0 *Ciphertext, c2QiQDecomp, c2QiPDecom
 			pj := contextP.Modulus[j]
 			mredParams := contextP.GetMredParams()[j]
 
-			key0 := evakey.evakeyRotColLeft[k].evakey[i][0].Coeffs[keysindex]
-			key1 := evakey.evakeyRotColLeft[k].evakey[i][1].Coeffs[keysindex]
+			key0 := switchKey.evakey[i][0].Coeffs[keysindex]
+			key1 := switchKey.evakey[i][1].Coeffs[keysindex]
 			p2tmp := pool2P.Coeffs[j]
 			p3tmp := pool3P.Coeffs[j]
 			c2tmp := c2QiPPermute.Coeffs[j]
@@ -1392,18 +1614,17 @@ func (eval *evaluator) switchKeyHoisted(ct0 *Ciphertext, c2QiQDecomp, c2QiPDecom
 }
 
 func (eval *evaluator) rotateColumnsLPow2(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
-	eval.rotateColumnsPow2(ct0, k, evakey.permuteNTTLeftIndex, evakey.evakeyRotColLeft, ctOut)
+	eval.rotateColumnsPow2(ct0, GaloisGen, k, evakey, ctOut)
 }
 
 func (eval *evaluator) rotateColumnsRPow2(ct0 *Ciphertext, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
-	eval.rotateColumnsPow2(ct0, k, evakey.permuteNTTRightIndex, evakey.evakeyRotColRight, ctOut)
+	eval.rotateColumnsPow2(ct0, eval.ckksContext.galElRotColRight[1], k, evakey, ctOut)
 }
 
-func (eval *evaluator) rotateColumnsPow2(ct0 *Ciphertext, k uint64, permuteNTTIndex map[uint64][]uint64, evakeyRotCol map[uint64]*SwitchingKey, ctOut *Ciphertext) {
-
-	var evakeyIndex uint64
+// rotateColumnsPow2 rotates ct0 by k positions (left or right depending on the generator), decomposing k as a sum of power-of-2 rotations, and returns the result in ctOut.
+func (eval *evaluator) rotateColumnsPow2(ct0 *Ciphertext, generator, k uint64, evakey *RotationKeys, ctOut *Ciphertext) {
 
-	evakeyIndex = 1
+	mask := (eval.ckksContext.n << 1) - 1
 
 	level := utils.MinUint64(ct0.Level(), ctOut.Level())
 	context := eval.ckksContext.contextQ
@@ -1415,10 +1636,12 @@ func (eval *evaluator) rotateColumnsPow2(ct0 *Ciphertext, k uint64, permuteNTTIn
 
 		if k&1 == 1 {
 
-			eval.permuteNTT(ctOut, permuteNTTIndex[evakeyIndex], evakeyRotCol[evakeyIndex], ctOut)
+			eval.permuteNTT(ctOut, evakey.permuteNTTIndex[generator], evakey.keys[generator], ctOut)
 		}
 
-		evakeyIndex <<= 1
+		generator *= generator
+		generator &= mask
+
 		k >>= 1
 	}
 }
@@ -1436,17 +1659,21 @@ func (eval *evaluator) ConjugateNew(ct0 *Ciphertext, evakey *RotationKeys) (ctOu
 // If the provided element is a Ciphertext, a key-switching operation is necessary and a rotation key for the row rotation needs to be provided.
 func (eval *evaluator) Conjugate(ct0 *Ciphertext, evakey *RotationKeys, ctOut *Ciphertext) {
 
+	discardSeed(ctOut)
+
 	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
 		panic("cannot Conjugate: input and output Ciphertext must be of degree 1")
 	}
 
-	if evakey.evakeyConjugate == nil {
+	galEl := eval.ckksContext.galElConjugate
+
+	if evakey.keys[galEl] == nil {
 		panic("cannot Conjugate: rows rotation key not generated")
 	}
 
 	ctOut.SetScale(ct0.Scale())
 
-	eval.permuteNTT(ct0, evakey.permuteNTTConjugateIndex, evakey.evakeyConjugate, ctOut)
+	eval.permuteNTT(ct0, evakey.permuteNTTIndex[galEl], evakey.keys[galEl], ctOut)
 }
 
 func (eval *evaluator) permuteNTT(ct0 *Ciphertext, index []uint64, evakey *SwitchingKey, ctOut *Ciphertext) {
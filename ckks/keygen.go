@@ -17,15 +17,18 @@ type KeyGenerator interface {
 	GenSwitchingKey(skInput, skOutput *SecretKey) (newevakey *SwitchingKey)
 	GenRotationKeysPow2(skOutput *SecretKey) (rotKey *RotationKeys)
 	GenRot(rotType Rotation, sk *SecretKey, k uint64, rotKey *RotationKeys)
+	GenRotationKeysForRotations(ks []int, sk *SecretKey) (rotKey *RotationKeys)
+	GenBootstrappingKey(btpParams *BootstrappingParameters, sk *SecretKey) (btpKey *BootstrappingKey)
 }
 
 // KeyGenerator is a structure that stores the elements required to create new keys,
 // as well as a small memory pool for intermediate values.
 type keyGenerator struct {
-	params      *Parameters
-	ckksContext *Context
-	ringContext *ring.Context
-	polypool    *ring.Poly
+	params       *Parameters
+	ckksContext  *Context
+	ringContext  *ring.Context
+	polypool     *ring.Poly
+	crpGenerator *ring.CRPGenerator
 }
 
 // SecretKey is a structure that stores the SecretKey
@@ -35,7 +38,8 @@ type SecretKey struct {
 
 // PublicKey is a structure that stores the PublicKey
 type PublicKey struct {
-	pk [2]*ring.Poly
+	pk   [2]*ring.Poly
+	seed *ring.PolySeed
 }
 
 // Rotation is a type used to represent the rotations types.
@@ -48,15 +52,13 @@ const (
 	Conjugate
 )
 
-// RotationKeys is a structure that stores the switching-keys required during the homomorphic rotations.
+// RotationKeys is a structure that stores the switching-keys required during the homomorphic rotations, indexed
+// by the Galois element of their automorphism, along with the permutation of the NTT coefficients of each automorphism.
+// Since a rotation of the columns by k positions to the right is a rotation by N/2-k positions to the left, both
+// rotations share the same switching-key.
 type RotationKeys struct {
-	permuteNTTRightIndex     map[uint64][]uint64
-	permuteNTTLeftIndex      map[uint64][]uint64
-	permuteNTTConjugateIndex []uint64
-
-	evakeyRotColLeft  map[uint64]*SwitchingKey
-	evakeyRotColRight map[uint64]*SwitchingKey
-	evakeyConjugate   *SwitchingKey
+	permuteNTTIndex map[uint64][]uint64
+	keys            map[uint64]*SwitchingKey
 }
 
 // EvaluationKey is a structure that stores the switching-keys required during the relinearization.
@@ -67,6 +69,7 @@ type EvaluationKey struct {
 // SwitchingKey is a structure that stores the switching-keys required during the key-switching.
 type SwitchingKey struct {
 	evakey [][2]*ring.Poly
+	seed   *ring.PolySeed
 }
 
 // Get returns the switching key backing slice
@@ -74,6 +77,11 @@ func (swk *SwitchingKey) Get() [][2]*ring.Poly {
 	return swk.evakey
 }
 
+// IsSeeded returns true if the uniform polynomials of the SwitchingKey are generated from a seed.
+func (swk *SwitchingKey) IsSeeded() bool {
+	return swk.seed != nil
+}
+
 // NewKeyGenerator creates a new KeyGenerator, from which the secret and public keys, as well as the evaluation,
 // rotation and switching keys can be generated.
 func NewKeyGenerator(params *Parameters) KeyGenerator {
@@ -93,6 +101,50 @@ func NewKeyGenerator(params *Parameters) KeyGenerator {
 	}
 }
 
+// NewKeyGeneratorSeeded creates a new KeyGenerator generating seeded keys : the uniform polynomials of the public,
+// evaluation, rotation and switching keys are generated from a random seed, which is stored in the keys. When marshaled,
+// these keys only store the seed in place of the uniform polynomials, which are regenerated from it on unmarshal.
+func NewKeyGeneratorSeeded(params *Parameters) KeyGenerator {
+
+	if !params.isValid {
+		panic("cannot NewKeyGeneratorSeeded: parameters are invalid (check if the generation was done properly)")
+	}
+
+	ckksContext := newContext(params)
+	ringContext := ckksContext.contextQP
+
+	return &keyGenerator{
+		params:       params.Copy(),
+		ckksContext:  ckksContext,
+		ringContext:  ringContext,
+		polypool:     ringContext.NewPoly(),
+		crpGenerator: ring.NewCRPGenerator(nil, ringContext),
+	}
+}
+
+// genUniformPolys returns nbPolys new uniform polynomials in R_QP. If the keyGenerator is seeded, the polynomials are
+// generated from a new random seed, which is also returned, else the returned seed is nil.
+func (keygen *keyGenerator) genUniformPolys(nbPolys uint64) (polys []*ring.Poly, seed *ring.PolySeed) {
+
+	polys = make([]*ring.Poly, nbPolys)
+
+	if keygen.crpGenerator == nil {
+		for i := range polys {
+			polys[i] = keygen.ringContext.NewUniformPoly()
+		}
+		return polys, nil
+	}
+
+	seed = ring.NewPolySeed(keygen.ringContext.Modulus)
+
+	keygen.crpGenerator.Seed(seed.Seed())
+	for i := range polys {
+		polys[i] = keygen.crpGenerator.ClockNew()
+	}
+
+	return polys, seed
+}
+
 // GenSecretKey generates a new SecretKey with the distribution [1/3, 1/3, 1/3].
 func (keygen *keyGenerator) GenSecretKey() (sk *SecretKey) {
 	return keygen.GenSecretKeyWithDistrib(1.0 / 3)
@@ -142,7 +194,10 @@ func (keygen *keyGenerator) GenPublicKey(sk *SecretKey) (pk *PublicKey) {
 	//pk[0] = [-(a*s + e)]
 	//pk[1] = [a]
 	pk.pk[0] = keygen.ckksContext.gaussianSampler.SampleNTTNew()
-	pk.pk[1] = keygen.ringContext.NewUniformPoly()
+
+	a, seed := keygen.genUniformPolys(1)
+	pk.pk[1] = a[0]
+	pk.seed = seed
 
 	keygen.ringContext.MulCoeffsMontgomeryAndAdd(sk.sk, pk.pk[1], pk.pk[0])
 	keygen.ringContext.Neg(pk.pk[0], pk.pk[0])
@@ -174,6 +229,12 @@ func (pk *PublicKey) Get() [2]*ring.Poly {
 func (pk *PublicKey) Set(poly [2]*ring.Poly) {
 	pk.pk[0] = poly[0].CopyNew()
 	pk.pk[1] = poly[1].CopyNew()
+	pk.seed = nil
+}
+
+// IsSeeded returns true if the uniform polynomial of the PublicKey is generated from a seed.
+func (pk *PublicKey) IsSeeded() bool {
+	return pk.seed != nil
 }
 
 // GenKeyPair generates a new SecretKey with distribution [1/3, 1/3, 1/3] and a corresponding public key.
@@ -296,6 +357,9 @@ func (keygen *keyGenerator) newSwitchingKey(skIn, skOut *ring.Poly) (switchingke
 
 	switchingkey.evakey = make([][2]*ring.Poly, beta)
 
+	a, seed := keygen.genUniformPolys(beta)
+	switchingkey.seed = seed
+
 	for i := uint64(0); i < beta; i++ {
 
 		// e
@@ -303,7 +367,7 @@ func (keygen *keyGenerator) newSwitchingKey(skIn, skOut *ring.Poly) (switchingke
 		context.MForm(switchingkey.evakey[i][0], switchingkey.evakey[i][0])
 
 		// a (since a is uniform, we consider we already sample it in the NTT and Montgomery domain)
-		switchingkey.evakey[i][1] = keygen.ringContext.NewUniformPoly()
+		switchingkey.evakey[i][1] = a[i]
 
 		// e + (skIn * P) * (q_star * q_tild) mod QP
 		//
@@ -337,9 +401,11 @@ func (keygen *keyGenerator) newSwitchingKey(skIn, skOut *ring.Poly) (switchingke
 	return
 }
 
-// NewRotationKeys generates a new instance of RotationKeys, with the provided rotation to the left, right and conjugation if requested.
+// NewRotationKeys generates a new empty instance of RotationKeys.
 func NewRotationKeys() (rotKey *RotationKeys) {
 	rotKey = new(RotationKeys)
+	rotKey.permuteNTTIndex = make(map[uint64][]uint64)
+	rotKey.keys = make(map[uint64]*SwitchingKey)
 	return
 }
 
@@ -350,70 +416,64 @@ func (keygen *keyGenerator) GenRot(rotType Rotation, sk *SecretKey, k uint64, ro
 		panic("Cannot GenRot: modulus P is empty")
 	}
 
-	switch rotType {
-	case RotationLeft:
+	k &= (keygen.ckksContext.n >> 1) - 1
 
-		if rotKey.evakeyRotColLeft == nil {
-			rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
-		}
+	if k == 0 && rotType != Conjugate {
+		return
+	}
 
-		if rotKey.permuteNTTLeftIndex == nil {
-			rotKey.permuteNTTLeftIndex = make(map[uint64][]uint64)
-		}
+	keygen.genRotKey(sk, galoisElementForRotation(rotType, k, keygen.ckksContext.n), rotKey)
+}
 
-		if rotKey.evakeyRotColLeft[k] == nil && k != 0 {
-			rotKey.permuteNTTLeftIndex[k] = ring.PermuteNTTIndex(GaloisGen, k, keygen.ringContext.N)
-			rotKey.evakeyRotColLeft[k] = keygen.genrotKey(sk.Get(), keygen.ckksContext.galElRotColLeft[k])
-		}
+// GenRotationKeysPow2 generates a new rotation key with all the power-of-two rotations to the left and right, as well as the conjugation.
+func (keygen *keyGenerator) GenRotationKeysPow2(skOutput *SecretKey) (rotKey *RotationKeys) {
 
-	case RotationRight:
+	if keygen.ckksContext.contextP == nil {
+		panic("Cannot GenRotationKeysPow2: modulus P is empty")
+	}
 
-		if rotKey.evakeyRotColRight == nil {
-			rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
-		}
+	rotKey = NewRotationKeys()
 
-		if rotKey.permuteNTTRightIndex == nil {
-			rotKey.permuteNTTRightIndex = make(map[uint64][]uint64)
-		}
+	for n := uint64(1); n < 1<<(keygen.params.LogN-1); n <<= 1 {
+		keygen.genRotKey(skOutput, keygen.ckksContext.galElRotColLeft[n], rotKey)
+		keygen.genRotKey(skOutput, keygen.ckksContext.galElRotColRight[n], rotKey)
+	}
 
-		if rotKey.evakeyRotColRight[k] == nil && k != 0 {
-			rotKey.permuteNTTRightIndex[k] = ring.PermuteNTTIndex(GaloisGen, 2*keygen.ringContext.N-k, keygen.ringContext.N)
-			rotKey.evakeyRotColRight[k] = keygen.genrotKey(sk.Get(), keygen.ckksContext.galElRotColRight[k])
-		}
+	keygen.genRotKey(skOutput, keygen.ckksContext.galElConjugate, rotKey)
 
-	case Conjugate:
-		rotKey.permuteNTTConjugateIndex = ring.PermuteNTTIndex(2*keygen.ringContext.N-1, 1, keygen.ringContext.N)
-		rotKey.evakeyConjugate = keygen.genrotKey(sk.Get(), keygen.ckksContext.galElConjugate)
-	}
+	return
 }
 
-// GenRotationKeysPow2 generates a new rotation key with all the power-of-two rotations to the left and right, as well as the conjugation.
-func (keygen *keyGenerator) GenRotationKeysPow2(skOutput *SecretKey) (rotKey *RotationKeys) {
+// GenRotationKeysForRotations generates a new struct of RotationKeys that stores one key for each of the given
+// rotations of the columns, so that each of them is done with a single key-switching by RotateColumns. A positive
+// k is a rotation by k positions to the left, and a negative k a rotation by -k positions to the right. The key of
+// the conjugation can be added with GenRot.
+func (keygen *keyGenerator) GenRotationKeysForRotations(ks []int, sk *SecretKey) (rotKey *RotationKeys) {
 
 	if keygen.ckksContext.contextP == nil {
-		panic("Cannot GenRotationKeysPow2: modulus P is empty")
+		panic("Cannot GenRotationKeysForRotations: modulus P is empty")
 	}
 
-	rotKey = new(RotationKeys)
+	rotKey = NewRotationKeys()
 
-	rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
-	rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
+	for _, k := range ks {
 
-	rotKey.permuteNTTLeftIndex = make(map[uint64][]uint64)
-	rotKey.permuteNTTRightIndex = make(map[uint64][]uint64)
+		// The two's complement of a negative k is, modulo N/2, the equivalent rotation to the left
+		if kLeft := uint64(k) & ((keygen.ckksContext.n >> 1) - 1); kLeft != 0 {
+			keygen.genRotKey(sk, keygen.ckksContext.galElRotColLeft[kLeft], rotKey)
+		}
+	}
 
-	for n := uint64(1); n < 1<<(keygen.params.LogN-1); n <<= 1 {
+	return
+}
 
-		rotKey.permuteNTTLeftIndex[n] = ring.PermuteNTTIndex(GaloisGen, n, keygen.ringContext.N)
-		rotKey.permuteNTTRightIndex[n] = ring.PermuteNTTIndex(GaloisGen, 2*keygen.ringContext.N-n, keygen.ringContext.N)
+// genRotKey generates the SwitchingKey of the automorphism of Galois element galEl in the target RotationKeys,
+// unless it already stores it.
+func (keygen *keyGenerator) genRotKey(sk *SecretKey, galEl uint64, rotKey *RotationKeys) {
 
-		rotKey.evakeyRotColLeft[n] = keygen.genrotKey(skOutput.Get(), keygen.ckksContext.galElRotColLeft[n])
-		rotKey.evakeyRotColRight[n] = keygen.genrotKey(skOutput.Get(), keygen.ckksContext.galElRotColRight[n])
+	if rotKey.keys[galEl] == nil {
+		rotKey.setKey(galEl, keygen.genrotKey(sk.Get(), galEl))
 	}
-
-	rotKey.permuteNTTConjugateIndex = ring.PermuteNTTIndex(2*keygen.ringContext.N-1, 1, keygen.ringContext.N)
-	rotKey.evakeyConjugate = keygen.genrotKey(skOutput.Get(), keygen.ckksContext.galElConjugate)
-	return
 }
 
 // SetRotKey sets the target RotationKeys' SwitchingKey for the specified rotation type and amount with the input polynomials.
@@ -423,65 +483,58 @@ func (rotKey *RotationKeys) SetRotKey(params *Parameters, evakey [][2]*ring.Poly
 		panic("cannot SetRotKey: parameters are invalid (check if the generation was done properly)")
 	}
 
-	switch rotType {
-	case RotationLeft:
+	N := uint64(1 << params.LogN)
 
-		if rotKey.evakeyRotColLeft == nil {
-			rotKey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
-		}
+	k &= (N >> 1) - 1
 
-		if rotKey.permuteNTTLeftIndex == nil {
-			rotKey.permuteNTTLeftIndex = make(map[uint64][]uint64)
-		}
+	if k == 0 && rotType != Conjugate {
+		return
+	}
 
-		if rotKey.evakeyRotColLeft[k] == nil && k != 0 {
+	galEl := galoisElementForRotation(rotType, k, N)
 
-			rotKey.permuteNTTLeftIndex[k] = ring.PermuteNTTIndex(GaloisGen, k, 1<<params.LogN)
+	if rotKey.keys[galEl] == nil {
 
-			rotKey.evakeyRotColLeft[k] = new(SwitchingKey)
-			rotKey.evakeyRotColLeft[k].evakey = make([][2]*ring.Poly, len(evakey))
-			for j := range evakey {
-				rotKey.evakeyRotColLeft[k].evakey[j][0] = evakey[j][0].CopyNew()
-				rotKey.evakeyRotColLeft[k].evakey[j][1] = evakey[j][1].CopyNew()
-			}
+		switchkey := new(SwitchingKey)
+		switchkey.evakey = make([][2]*ring.Poly, len(evakey))
+		for j := range evakey {
+			switchkey.evakey[j][0] = evakey[j][0].CopyNew()
+			switchkey.evakey[j][1] = evakey[j][1].CopyNew()
 		}
 
-	case RotationRight:
+		rotKey.setKey(galEl, switchkey)
+	}
+}
 
-		if rotKey.evakeyRotColRight == nil {
-			rotKey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
-		}
+// setKey stores the SwitchingKey of the automorphism of Galois element galEl in the target RotationKeys, along with
+// the permutation of the NTT coefficients of this automorphism.
+func (rotKey *RotationKeys) setKey(galEl uint64, switchkey *SwitchingKey) {
 
-		if rotKey.permuteNTTRightIndex == nil {
-			rotKey.permuteNTTRightIndex = make(map[uint64][]uint64)
-		}
+	if rotKey.keys == nil {
+		rotKey.keys = make(map[uint64]*SwitchingKey)
+	}
 
-		if rotKey.evakeyRotColRight[k] == nil && k != 0 {
+	if rotKey.permuteNTTIndex == nil {
+		rotKey.permuteNTTIndex = make(map[uint64][]uint64)
+	}
 
-			rotKey.permuteNTTRightIndex[k] = ring.PermuteNTTIndex(GaloisGen, (2<<params.LogN)-1-k, 1<<params.LogN)
+	rotKey.keys[galEl] = switchkey
+	rotKey.permuteNTTIndex[galEl] = ring.PermuteNTTIndex(galEl, 1, uint64(switchkey.evakey[0][0].GetDegree()))
+}
 
-			rotKey.evakeyRotColRight[k] = new(SwitchingKey)
-			rotKey.evakeyRotColRight[k].evakey = make([][2]*ring.Poly, len(evakey))
-			for j := range evakey {
-				rotKey.evakeyRotColRight[k].evakey[j][0] = evakey[j][0].CopyNew()
-				rotKey.evakeyRotColRight[k].evakey[j][1] = evakey[j][1].CopyNew()
-			}
-		}
+// galoisElementForRotation returns the Galois element of the given rotation type and amount, for a ring of degree N.
+func galoisElementForRotation(rotType Rotation, k, N uint64) uint64 {
 
+	switch rotType {
+	case RotationLeft:
+		return ring.ModExp(GaloisGen, k&((N>>1)-1), N<<1)
+	case RotationRight:
+		return ring.ModExp(GaloisGen, (N>>1)-(k&((N>>1)-1)), N<<1)
 	case Conjugate:
-
-		if rotKey.evakeyConjugate == nil {
-
-			rotKey.permuteNTTConjugateIndex = ring.PermuteNTTIndex((2<<params.LogN)-1, 1, 1<<params.LogN)
-
-			rotKey.evakeyConjugate = new(SwitchingKey)
-			rotKey.evakeyConjugate.evakey = make([][2]*ring.Poly, len(evakey))
-			for j := range evakey {
-				rotKey.evakeyConjugate.evakey[j][0] = evakey[j][0].CopyNew()
-				rotKey.evakeyConjugate.evakey[j][1] = evakey[j][1].CopyNew()
-			}
-		}
+		return (N << 1) - 1
 	}
+
+	panic("cannot galoisElementForRotation: invalid rotation type")
 }
 
 func (keygen *keyGenerator) genrotKey(skOutput *ring.Poly, gen uint64) (switchingkey *SwitchingKey) {
@@ -492,3 +545,26 @@ func (keygen *keyGenerator) genrotKey(skOutput *ring.Poly, gen uint64) (switchin
 
 	return
 }
+
+// GenBootstrappingKey generates the relinearization key and the rotation keys (left rotations and conjugation)
+// required by the bootstrapping for the given parameters. The secret key should be sparse with Hamming weight btpParams.H.
+func (keygen *keyGenerator) GenBootstrappingKey(btpParams *BootstrappingParameters, sk *SecretKey) (btpKey *BootstrappingKey) {
+
+	if keygen.ckksContext.contextP == nil {
+		panic("Cannot GenBootstrappingKey: modulus P is empty")
+	}
+
+	btpKey = new(BootstrappingKey)
+
+	btpKey.relinkey = keygen.GenRelinKey(sk)
+
+	btpKey.rotkeys = NewRotationKeys()
+
+	for _, k := range btpParams.rotationsForBootstrapping() {
+		keygen.GenRot(RotationLeft, sk, k, btpKey.rotkeys)
+	}
+
+	keygen.GenRot(Conjugate, sk, 0, btpKey.rotkeys)
+
+	return
+}
@@ -0,0 +1,261 @@
+package ckks
+
+import (
+	"github.com/ldsec/lattigo/utils"
+	"math"
+	"sort"
+)
+
+// LinearTransform is a struct storing a plaintext linear transform of the slots (a slots x slots matrix M), encoded by
+// its non-zero generalized diagonals diag_k[i] = M[i][(i+k) % slots]. The diagonals are pre-rotated to be evaluated
+// with the baby-step giant-step algorithm, which requires the rotations (to the left) given by Rotations.
+type LinearTransform struct {
+	slots uint64
+	level uint64
+	scale float64
+	n1    uint64
+	vec   map[uint64]*PlaintextMul
+}
+
+// NewLinearTransform encodes the given diagonals of a linear transform of the slots, indexed by k in [0, slots) for
+// diag_k[i] = M[i][(i+k) % slots], on plaintexts pre-computed for the multiplication (see PlaintextMul) at the given
+// level and scale. The size of the baby-steps is chosen to minimize the number of rotations.
+func NewLinearTransform(params *Parameters, encoder Encoder, diags map[uint64][]complex128, level uint64, scale float64) (linearTransform *LinearTransform) {
+
+	slots := uint64(1 << params.LogSlots)
+
+	if level > params.MaxLevel() {
+		panic("cannot NewLinearTransform : level is larger than the maximum level of the parameters")
+	}
+
+	linearTransform = new(LinearTransform)
+	linearTransform.slots = slots
+	linearTransform.level = level
+	linearTransform.scale = scale
+	linearTransform.n1 = findBestBSGSSplit(diags, slots)
+	linearTransform.vec = make(map[uint64]*PlaintextMul)
+
+	n1 := linearTransform.n1
+
+	for i, diag := range diags {
+
+		if i >= slots || uint64(len(diag)) != slots {
+			panic("cannot NewLinearTransform : the diagonals must be indexed by [0, slots) and be of length slots")
+		}
+
+		// Pre-rotates the diagonal by the giant step (to the right)
+		giant := i - (i % n1)
+		values := make([]complex128, slots)
+		for j := uint64(0); j < slots; j++ {
+			values[(j+giant)%slots] = diag[j]
+		}
+
+		linearTransform.vec[i] = NewPlaintextMul(params, level, scale)
+		encoder.EncodeMul(linearTransform.vec[i], values, slots)
+	}
+
+	return
+}
+
+// NewLinearTransformFromMatrix encodes the slots x slots matrix M, given by its rows, as a LinearTransform at the given
+// level and scale (see NewLinearTransform). The diagonals of M that are zero are not encoded.
+func NewLinearTransformFromMatrix(params *Parameters, encoder Encoder, matrix [][]complex128, level uint64, scale float64) (linearTransform *LinearTransform) {
+
+	slots := uint64(1 << params.LogSlots)
+
+	if uint64(len(matrix)) != slots {
+		panic("cannot NewLinearTransformFromMatrix : the matrix must have slots rows")
+	}
+
+	for i := range matrix {
+		if uint64(len(matrix[i])) != slots {
+			panic("cannot NewLinearTransformFromMatrix : the matrix must have slots columns")
+		}
+	}
+
+	diags := make(map[uint64][]complex128)
+
+	for k := uint64(0); k < slots; k++ {
+
+		diag := make([]complex128, slots)
+
+		isZero := true
+		for i := uint64(0); i < slots; i++ {
+			diag[i] = matrix[i][(i+k)%slots]
+			isZero = isZero && diag[i] == 0
+		}
+
+		if !isZero {
+			diags[k] = diag
+		}
+	}
+
+	return NewLinearTransform(params, encoder, diags, level, scale)
+}
+
+// Level returns the level of the plaintexts of the LinearTransform, which is the level of its output.
+func (linearTransform *LinearTransform) Level() uint64 {
+	return linearTransform.level
+}
+
+// Scale returns the scale of the plaintexts of the LinearTransform, by which the scale of its input is multiplied.
+func (linearTransform *LinearTransform) Scale() float64 {
+	return linearTransform.scale
+}
+
+// Rotations returns the list, in ascending order, of the rotations (to the left) required to evaluate the
+// LinearTransform. The corresponding rotation keys can be generated with GenRot.
+func (linearTransform *LinearTransform) Rotations() (rotations []uint64) {
+	return bsgsRotations(linearTransform.diagonals(), linearTransform.n1)
+}
+
+// diagonals returns the indexes of the non-zero diagonals of the LinearTransform.
+func (linearTransform *LinearTransform) diagonals() (diags []uint64) {
+	diags = make([]uint64, 0, len(linearTransform.vec))
+	for i := range linearTransform.vec {
+		diags = append(diags, i)
+	}
+	return
+}
+
+// LinearTransformNew evaluates the plaintext linear transform on the input ciphertext and returns the result on a new
+// ciphertext (see LinearTransform).
+func (eval *evaluator) LinearTransformNew(ct0 *Ciphertext, linearTransform *LinearTransform, rotKeys *RotationKeys) (ctOut *Ciphertext) {
+	ctOut = NewCiphertext(eval.params, 1, utils.MinUint64(ct0.Level(), linearTransform.level), ct0.Scale()*linearTransform.scale)
+	eval.LinearTransform(ct0, linearTransform, rotKeys, ctOut)
+	return
+}
+
+// LinearTransform evaluates the plaintext linear transform on the input ciphertext with the baby-step giant-step
+// algorithm, the baby-step rotations being hoisted, and returns the result on ctOut. The result is at the level
+// min(ct0.Level(), linearTransform.Level()) and is not rescaled: its scale is the scale of ct0 times the scale of
+// the LinearTransform. The RotationKeys must store the rotations given by linearTransform.Rotations().
+func (eval *evaluator) LinearTransform(ct0 *Ciphertext, linearTransform *LinearTransform, rotKeys *RotationKeys, ctOut *Ciphertext) {
+
+	discardSeed(ctOut)
+
+	if ct0.Degree() != 1 || ctOut.Degree() != 1 {
+		panic("cannot LinearTransform: input and output Ciphertext must be of degree 1")
+	}
+
+	ctIn := ct0
+	if ct0.Level() > linearTransform.level {
+		ctIn = eval.DropLevelNew(ct0, ct0.Level()-linearTransform.level)
+	}
+
+	level := ctIn.Level()
+
+	n1 := linearTransform.n1
+
+	// Maps each giant step to its baby steps
+	index, babies, giants := bsgsIndex(linearTransform.diagonals(), n1)
+
+	// Pre-computes the baby-step rotations using hoisting
+	ctRot := eval.RotateHoisted(ctIn, babies, rotKeys)
+
+	scale := ctIn.Scale() * linearTransform.scale
+
+	acc := NewCiphertext(eval.params, 1, level, scale)
+	tmp := NewCiphertext(eval.params, 1, level, scale)
+	res := NewCiphertext(eval.params, 1, level, scale)
+
+	for j, giant := range giants {
+
+		for k, baby := range index[giant] {
+			if k == 0 {
+				eval.MulRelin(ctRot[baby], linearTransform.vec[giant+baby], nil, acc)
+			} else {
+				eval.MulRelin(ctRot[baby], linearTransform.vec[giant+baby], nil, tmp)
+				eval.Add(acc, tmp, acc)
+			}
+		}
+
+		if giant != 0 {
+			eval.RotateColumns(acc, giant, rotKeys, acc)
+		}
+
+		if j == 0 {
+			res.Copy(acc.Element())
+		} else {
+			eval.Add(res, acc, res)
+		}
+	}
+
+	ctOut.Copy(res.Element())
+}
+
+// bsgsIndex maps each giant step of the baby-step giant-step evaluation of the diagonals of given indexes to its
+// baby steps, and returns the sorted lists of the baby steps and of the giant steps.
+func bsgsIndex(diags []uint64, n1 uint64) (index map[uint64][]uint64, babies, giants []uint64) {
+
+	index = make(map[uint64][]uint64)
+	babyMap := make(map[uint64]bool)
+	for _, i := range diags {
+		giant := i - (i % n1)
+		index[giant] = append(index[giant], i%n1)
+		babyMap[i%n1] = true
+	}
+
+	babies = make([]uint64, 0, len(babyMap))
+	for baby := range babyMap {
+		babies = append(babies, baby)
+	}
+	sort.Slice(babies, func(i, j int) bool { return babies[i] < babies[j] })
+
+	giants = make([]uint64, 0, len(index))
+	for giant := range index {
+		sort.Slice(index[giant], func(i, j int) bool { return index[giant][i] < index[giant][j] })
+		giants = append(giants, giant)
+	}
+	sort.Slice(giants, func(i, j int) bool { return giants[i] < giants[j] })
+
+	return
+}
+
+// bsgsRotations returns the sorted list of the non-zero rotations required by the baby-step giant-step evaluation
+// of the diagonals of given indexes with baby steps of size n1.
+func bsgsRotations(diags []uint64, n1 uint64) (rotations []uint64) {
+
+	_, babies, giants := bsgsIndex(diags, n1)
+
+	rotMap := make(map[uint64]bool)
+	for _, k := range append(babies, giants...) {
+		if k != 0 {
+			rotMap[k] = true
+		}
+	}
+
+	rotations = make([]uint64, 0, len(rotMap))
+	for k := range rotMap {
+		rotations = append(rotations, k)
+	}
+
+	sort.Slice(rotations, func(i, j int) bool { return rotations[i] < rotations[j] })
+
+	return
+}
+
+// findBestBSGSSplit returns the power of two n1 that minimizes the number of rotations (baby-steps + giant-steps)
+// required to evaluate the linear transform represented by the given diagonals.
+func findBestBSGSSplit(diags map[uint64][]complex128, slots uint64) (n1 uint64) {
+
+	minRot := uint64(math.MaxUint64)
+
+	for N1 := uint64(1); N1 <= slots; N1 <<= 1 {
+
+		babies := make(map[uint64]bool)
+		giants := make(map[uint64]bool)
+
+		for i := range diags {
+			babies[i%N1] = true
+			giants[i-(i%N1)] = true
+		}
+
+		if nbRot := uint64(len(babies) + len(giants)); nbRot < minRot {
+			minRot = nbRot
+			n1 = N1
+		}
+	}
+
+	return
+}
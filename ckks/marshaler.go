@@ -2,17 +2,31 @@ package ckks
 
 import (
 	"encoding/binary"
+	"errors"
 	"github.com/ldsec/lattigo/ring"
+	"io"
 	"math"
+	"math/bits"
+	"sort"
 )
 
 // GetDataLen returns the length in bytes of the target Ciphertext.
 func (ciphertext *Ciphertext) GetDataLen(WithMetaData bool) (dataLen uint64) {
+	return ciphertext.getDataLen(WithMetaData, ciphertext.validSeed())
+}
+
+func (ciphertext *Ciphertext) getDataLen(WithMetaData bool, seed *ring.PolySeed) (dataLen uint64) {
 	if WithMetaData {
 		dataLen += 11
 	}
 
-	for _, el := range ciphertext.value {
+	values := ciphertext.value
+	if seed != nil {
+		values = values[:1]
+		dataLen += seed.GetDataLen(WithMetaData)
+	}
+
+	for _, el := range values {
 		dataLen += el.GetDataLen(WithMetaData)
 	}
 
@@ -20,15 +34,24 @@ func (ciphertext *Ciphertext) GetDataLen(WithMetaData bool) (dataLen uint64) {
 }
 
 // MarshalBinary encodes a Ciphertext on a byte slice. The total size
-// in byte is 4 + 8* N * numberModuliQ * (degree + 1).
+// in byte is 4 + 8* N * numberModuliQ * (degree + 1). If the Ciphertext was encrypted
+// by a seeded Encryptor and was not modified since, its second polynomial is replaced by its seed.
 func (ciphertext *Ciphertext) MarshalBinary() (data []byte, err error) {
 
-	data = make([]byte, ciphertext.GetDataLen(true))
+	seed := ciphertext.validSeed()
+
+	data = make([]byte, ciphertext.getDataLen(true, seed))
 
 	data[0] = uint8(ciphertext.Degree() + 1)
 
 	binary.LittleEndian.PutUint64(data[1:9], math.Float64bits(ciphertext.Scale()))
 
+	values := ciphertext.value
+	if seed != nil {
+		data[9] = 1
+		values = values[:1]
+	}
+
 	if ciphertext.isNTT {
 		data[10] = 1
 	}
@@ -37,7 +60,7 @@ func (ciphertext *Ciphertext) MarshalBinary() (data []byte, err error) {
 
 	pointer = 11
 
-	for _, el := range ciphertext.value {
+	for _, el := range values {
 
 		if inc, err = el.WriteTo(data[pointer:]); err != nil {
 			return nil, err
@@ -46,20 +69,36 @@ func (ciphertext *Ciphertext) MarshalBinary() (data []byte, err error) {
 		pointer += inc
 	}
 
+	if seed != nil {
+		if _, err = seed.Encode(data[pointer:]); err != nil {
+			return nil, err
+		}
+	}
+
 	return data, nil
 }
 
 // UnmarshalBinary decodes a previously marshaled Ciphertext on the target Ciphertext.
 // The target Ciphertext must be of the appropriate format and size, it can be created with the
-// method NewCiphertext(uint64).
+// method NewCiphertext(uint64). If the Ciphertext was marshaled with a seed, its second polynomial
+// is regenerated from it.
 func (ciphertext *Ciphertext) UnmarshalBinary(data []byte) (err error) {
 
 	ciphertext.ckksElement = new(ckksElement)
+	ciphertext.seed = nil
 
 	ciphertext.value = make([]*ring.Poly, uint8(data[0]))
 
 	ciphertext.scale = math.Float64frombits(binary.LittleEndian.Uint64(data[1:9]))
 
+	values := ciphertext.value
+	if uint8(data[9]) == 1 {
+		if len(values) != 2 {
+			return errors.New("cannot UnmarshalBinary : invalid seeded Ciphertext")
+		}
+		values = values[:1]
+	}
+
 	if uint8(data[10]) == 1 {
 		ciphertext.isNTT = true
 	}
@@ -67,7 +106,7 @@ func (ciphertext *Ciphertext) UnmarshalBinary(data []byte) (err error) {
 	var pointer, inc uint64
 	pointer = 11
 
-	for i := range ciphertext.value {
+	for i := range values {
 
 		ciphertext.value[i] = new(ring.Poly)
 
@@ -78,9 +117,46 @@ func (ciphertext *Ciphertext) UnmarshalBinary(data []byte) (err error) {
 		pointer += inc
 	}
 
+	if len(values) != len(ciphertext.value) {
+
+		ciphertext.seed = new(ring.PolySeed)
+		if _, err = ciphertext.seed.Decode(data[pointer:]); err != nil {
+			return err
+		}
+
+		ciphertext.expandSeed()
+	}
+
 	return nil
 }
 
+// validSeed returns the seed of the Ciphertext if its second polynomial can still be regenerated from it, else it
+// returns nil. Since the polynomials returned by Value can be modified in place, the seed is expanded and compared
+// with the second polynomial.
+func (ciphertext *Ciphertext) validSeed() *ring.PolySeed {
+
+	if ciphertext.seed == nil || len(ciphertext.value) != 2 || len(ciphertext.value[1].Coeffs) != len(ciphertext.seed.Moduli()) {
+		return nil
+	}
+
+	expanded := ciphertext.seed.Expand(uint64(ciphertext.value[0].GetDegree()), 1)[0]
+
+	for i := range expanded.Coeffs {
+		for j, coeff := range expanded.Coeffs[i] {
+			if ciphertext.value[1].Coeffs[i][j] != coeff {
+				return nil
+			}
+		}
+	}
+
+	return ciphertext.seed
+}
+
+// expandSeed regenerates the second polynomial of the Ciphertext from its seed.
+func (ciphertext *Ciphertext) expandSeed() {
+	ciphertext.value[1] = ciphertext.seed.Expand(uint64(ciphertext.value[0].GetDegree()), 1)[0]
+}
+
 // GetDataLen returns the length in bytes of the target SecretKey.
 func (sk *SecretKey) GetDataLen(WithMetaData bool) (dataLen uint64) {
 	return sk.sk.GetDataLen(WithMetaData)
@@ -113,14 +189,23 @@ func (sk *SecretKey) UnmarshalBinary(data []byte) (err error) {
 // GetDataLen returns the length in bytes of the target PublicKey.
 func (pk *PublicKey) GetDataLen(WithMetaData bool) (dataLen uint64) {
 
-	for _, el := range pk.pk {
-		dataLen += el.GetDataLen(WithMetaData)
+	if WithMetaData {
+		dataLen++
+	}
+
+	dataLen += pk.pk[0].GetDataLen(WithMetaData)
+
+	if pk.seed != nil {
+		dataLen += pk.seed.GetDataLen(WithMetaData)
+	} else {
+		dataLen += pk.pk[1].GetDataLen(WithMetaData)
 	}
 
 	return
 }
 
-// MarshalBinary encodes a PublicKey in a byte slice.
+// MarshalBinary encodes a PublicKey in a byte slice. If the PublicKey is seeded, its
+// uniform polynomial is replaced by its seed.
 func (pk *PublicKey) MarshalBinary() (data []byte, err error) {
 
 	dataLen := pk.GetDataLen(true)
@@ -129,11 +214,23 @@ func (pk *PublicKey) MarshalBinary() (data []byte, err error) {
 
 	var pointer, inc uint64
 
+	pointer = 1
+
 	if inc, err = pk.pk[0].WriteTo(data[pointer:]); err != nil {
 		return nil, err
 	}
 
-	if _, err = pk.pk[1].WriteTo(data[pointer+inc:]); err != nil {
+	pointer += inc
+
+	if pk.seed != nil {
+
+		data[0] = 1
+
+		if _, err = pk.seed.Encode(data[pointer:]); err != nil {
+			return nil, err
+		}
+
+	} else if _, err = pk.pk[1].WriteTo(data[pointer:]); err != nil {
 		return nil, err
 	}
 
@@ -142,18 +239,38 @@ func (pk *PublicKey) MarshalBinary() (data []byte, err error) {
 }
 
 // UnmarshalBinary decodes a previously marshaled PublicKey in the target PublicKey.
+// If the PublicKey was seeded, its uniform polynomial is regenerated from its seed.
 func (pk *PublicKey) UnmarshalBinary(data []byte) (err error) {
 
 	var pointer, inc uint64
 
+	pointer = 1
+
 	pk.pk[0] = new(ring.Poly)
-	pk.pk[1] = new(ring.Poly)
 
 	if inc, err = pk.pk[0].DecodePolyNew(data[pointer:]); err != nil {
 		return err
 	}
 
-	if _, err = pk.pk[1].DecodePolyNew(data[pointer+inc:]); err != nil {
+	pointer += inc
+
+	if uint8(data[0]) == 1 {
+
+		pk.seed = new(ring.PolySeed)
+
+		if _, err = pk.seed.Decode(data[pointer:]); err != nil {
+			return err
+		}
+
+		pk.pk[1] = pk.seed.Expand(uint64(pk.pk[0].GetDegree()), 1)[0]
+
+		return nil
+	}
+
+	pk.seed = nil
+	pk.pk[1] = new(ring.Poly)
+
+	if _, err = pk.pk[1].DecodePolyNew(data[pointer:]); err != nil {
 		return err
 	}
 
@@ -194,18 +311,25 @@ func (evaluationkey *EvaluationKey) UnmarshalBinary(data []byte) (err error) {
 func (switchkey *SwitchingKey) GetDataLen(WithMetaData bool) (dataLen uint64) {
 
 	if WithMetaData {
-		dataLen++
+		dataLen += 2
+	}
+
+	if switchkey.seed != nil {
+		dataLen += switchkey.seed.GetDataLen(WithMetaData)
 	}
 
 	for j := uint64(0); j < uint64(len(switchkey.evakey)); j++ {
 		dataLen += switchkey.evakey[j][0].GetDataLen(WithMetaData)
-		dataLen += switchkey.evakey[j][1].GetDataLen(WithMetaData)
+		if switchkey.seed == nil {
+			dataLen += switchkey.evakey[j][1].GetDataLen(WithMetaData)
+		}
 	}
 
 	return
 }
 
-// MarshalBinary encodes an SwitchingKey in a byte slice.
+// MarshalBinary encodes an SwitchingKey in a byte slice. If the SwitchingKey is seeded, its
+// uniform polynomials are replaced by their seed.
 func (switchkey *SwitchingKey) MarshalBinary() (data []byte, err error) {
 
 	data = make([]byte, switchkey.GetDataLen(true))
@@ -218,6 +342,7 @@ func (switchkey *SwitchingKey) MarshalBinary() (data []byte, err error) {
 }
 
 // UnmarshalBinary decode a previously marshaled SwitchingKey in the target SwitchingKey.
+// If the SwitchingKey was seeded, its uniform polynomials are regenerated from their seed.
 func (switchkey *SwitchingKey) UnmarshalBinary(data []byte) (err error) {
 
 	if _, err = switchkey.decode(data); err != nil {
@@ -237,15 +362,35 @@ func (switchkey *SwitchingKey) encode(pointer uint64, data []byte) (uint64, erro
 
 	pointer++
 
+	if switchkey.seed != nil {
+
+		data[pointer] = 1
+
+		pointer++
+
+		if inc, err = switchkey.seed.Encode(data[pointer:]); err != nil {
+			return pointer, err
+		}
+
+		pointer += inc
+
+	} else {
+		pointer++
+	}
+
 	for j := uint64(0); j < uint64(len(switchkey.evakey)); j++ {
 
-		if inc, err = switchkey.evakey[j][0].WriteTo(data[pointer:]); err != nil {
+		if inc, err = switchkey.evakey[j][0].WriteTo(data[pointer : pointer+switchkey.evakey[j][0].GetDataLen(true)]); err != nil {
 			return pointer, err
 		}
 
 		pointer += inc
 
-		if inc, err = switchkey.evakey[j][1].WriteTo(data[pointer:]); err != nil {
+		if switchkey.seed != nil {
+			continue
+		}
+
+		if inc, err = switchkey.evakey[j][1].WriteTo(data[pointer : pointer+switchkey.evakey[j][1].GetDataLen(true)]); err != nil {
 			return pointer, err
 		}
 
@@ -259,12 +404,27 @@ func (switchkey *SwitchingKey) decode(data []byte) (pointer uint64, err error) {
 
 	decomposition := uint64(data[0])
 
-	pointer = uint64(1)
+	seeded := uint8(data[1]) == 1
 
-	switchkey.evakey = make([][2]*ring.Poly, decomposition)
+	pointer = uint64(2)
 
 	var inc uint64
 
+	switchkey.seed = nil
+
+	if seeded {
+
+		switchkey.seed = new(ring.PolySeed)
+
+		if inc, err = switchkey.seed.Decode(data[pointer:]); err != nil {
+			return pointer, err
+		}
+
+		pointer += inc
+	}
+
+	switchkey.evakey = make([][2]*ring.Poly, decomposition)
+
 	for j := uint64(0); j < decomposition; j++ {
 
 		switchkey.evakey[j][0] = new(ring.Poly)
@@ -273,6 +433,10 @@ func (switchkey *SwitchingKey) decode(data []byte) (pointer uint64, err error) {
 		}
 		pointer += inc
 
+		if seeded {
+			continue
+		}
+
 		switchkey.evakey[j][1] = new(ring.Poly)
 		if inc, err = switchkey.evakey[j][1].DecodePolyNew(data[pointer:]); err != nil {
 			return pointer, err
@@ -281,158 +445,548 @@ func (switchkey *SwitchingKey) decode(data []byte) (pointer uint64, err error) {
 
 	}
 
+	if seeded && decomposition != 0 {
+		switchkey.expandSeed()
+	}
+
 	return pointer, nil
 }
 
+// expandSeed regenerates the uniform polynomials of a seeded SwitchingKey from its seed.
+func (switchkey *SwitchingKey) expandSeed() {
+	a := switchkey.seed.Expand(uint64(switchkey.evakey[0][0].GetDegree()), uint64(len(switchkey.evakey)))
+	for j := range switchkey.evakey {
+		switchkey.evakey[j][1] = a[j]
+	}
+}
+
 // GetDataLen returns the length in bytes of the target RotationKeys.
 func (rotationkey *RotationKeys) GetDataLen(WithMetaData bool) (dataLen uint64) {
-	for i := range rotationkey.evakeyRotColLeft {
-		if WithMetaData {
-			dataLen += 4
-		}
-		dataLen += rotationkey.evakeyRotColLeft[i].GetDataLen(WithMetaData)
+
+	if WithMetaData {
+		dataLen += 4
 	}
 
-	for i := range rotationkey.evakeyRotColRight {
+	for _, switchkey := range rotationkey.keys {
 		if WithMetaData {
 			dataLen += 4
 		}
-		dataLen += rotationkey.evakeyRotColRight[i].GetDataLen(WithMetaData)
+		dataLen += switchkey.GetDataLen(WithMetaData)
 	}
 
-	if rotationkey.evakeyConjugate != nil {
-		if WithMetaData {
-			dataLen += 4
-		}
-		dataLen += rotationkey.evakeyConjugate.GetDataLen(WithMetaData)
+	return
+}
+
+// galoisElements returns the Galois elements of the keys of the target RotationKeys, in ascending order.
+func (rotationkey *RotationKeys) galoisElements() (galEls []uint64) {
+
+	galEls = make([]uint64, 0, len(rotationkey.keys))
+
+	for galEl := range rotationkey.keys {
+		galEls = append(galEls, galEl)
 	}
 
+	sort.Slice(galEls, func(i, j int) bool { return galEls[i] < galEls[j] })
+
 	return
 }
 
-// MarshalBinary encodes a RotationKeys structure in a byte slice.
+// validGaloisElement checks that galEl is the Galois element of an automorphism of the ring of the SwitchingKey,
+// i.e. an odd integer smaller than 2N, so that the permutation of its NTT coefficients can be computed.
+func validGaloisElement(galEl uint64, switchkey *SwitchingKey) bool {
+	return len(switchkey.evakey) != 0 && galEl&1 == 1 && galEl < uint64(switchkey.evakey[0][0].GetDegree())<<1
+}
+
+// MarshalBinary encodes a RotationKeys structure in a byte slice. The number of keys is written on the first 4 bytes,
+// and each key is preceded by the Galois element of its automorphism, on 4 bytes.
 func (rotationkey *RotationKeys) MarshalBinary() (data []byte, err error) {
 
 	data = make([]byte, rotationkey.GetDataLen(true))
 
-	mappingColL := []uint64{}
-	mappingColR := []uint64{}
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(rotationkey.keys)))
+
+	pointer := uint64(4)
 
-	for i := range rotationkey.evakeyRotColLeft {
-		mappingColL = append(mappingColL, i)
+	for _, galEl := range rotationkey.galoisElements() {
+
+		binary.BigEndian.PutUint32(data[pointer:pointer+4], uint32(galEl))
+		pointer += 4
+
+		if pointer, err = rotationkey.keys[galEl].encode(pointer, data); err != nil {
+			return nil, err
+		}
 	}
 
-	for i := range rotationkey.evakeyRotColRight {
-		mappingColR = append(mappingColR, i)
+	return data, nil
+}
+
+// UnmarshalBinary decodes a previously marshaled RotationKeys in the target RotationKeys.
+func (rotationkey *RotationKeys) UnmarshalBinary(data []byte) (err error) {
+
+	var galEl uint64
+
+	if len(data) < 4 {
+		return errors.New("cannot UnmarshalBinary : invalid RotationKeys encoding")
 	}
 
-	var pointer uint64
+	nbKeys := binary.BigEndian.Uint32(data[0:4])
+
+	pointer := uint64(4)
+	var inc uint64
+
+	for i := uint32(0); i < nbKeys; i++ {
+
+		if uint64(len(data)) < pointer+4 {
+			return errors.New("cannot UnmarshalBinary : invalid RotationKeys encoding")
+		}
 
-	for _, i := range mappingColL {
+		galEl = uint64(binary.BigEndian.Uint32(data[pointer : pointer+4]))
 
-		binary.BigEndian.PutUint32(data[pointer:pointer+4], uint32(i))
-		data[pointer] = uint8(RotationLeft)
 		pointer += 4
 
-		pointer, _ = rotationkey.evakeyRotColLeft[i].encode(pointer, data)
+		switchkey := new(SwitchingKey)
+		if inc, err = switchkey.decode(data[pointer:]); err != nil {
+			return err
+		}
+
+		if !validGaloisElement(galEl, switchkey) {
+			return errors.New("cannot UnmarshalBinary : invalid Galois element")
+		}
+
+		rotationkey.setKey(galEl, switchkey)
+
+		pointer += inc
 	}
 
-	for _, i := range mappingColR {
+	if pointer != uint64(len(data)) {
+		return errors.New("cannot UnmarshalBinary : invalid RotationKeys encoding")
+	}
 
-		binary.BigEndian.PutUint32(data[pointer:pointer+4], uint32(i))
-		data[pointer] = uint8(RotationRight)
-		pointer += 4
+	return nil
+}
+
+// WriteTo writes the target Ciphertext on w, with the same format as MarshalBinary, without allocating
+// the full byte slice. It returns the number of bytes written.
+func (ciphertext *Ciphertext) WriteTo(w io.Writer) (n int64, err error) {
+
+	seed := ciphertext.validSeed()
+
+	header := make([]byte, 11)
 
-		pointer, _ = rotationkey.evakeyRotColRight[i].encode(pointer, data)
+	header[0] = uint8(ciphertext.Degree() + 1)
+
+	binary.LittleEndian.PutUint64(header[1:9], math.Float64bits(ciphertext.Scale()))
+
+	values := ciphertext.value
+	if seed != nil {
+		header[9] = 1
+		values = values[:1]
 	}
 
-	if rotationkey.evakeyConjugate != nil {
+	if ciphertext.isNTT {
+		header[10] = 1
+	}
 
-		data[pointer] = uint8(Conjugate)
-		pointer += 4
+	if n, err = writeBytes(w, header); err != nil {
+		return n, err
+	}
 
-		_, _ = rotationkey.evakeyConjugate.encode(pointer, data)
+	var inc int64
+	for _, el := range values {
+		inc, err = writePoly(w, el)
+		n += inc
+		if err != nil {
+			return n, err
+		}
 	}
 
-	return data, nil
+	if seed != nil {
+		inc, err = seed.WriteTo(w)
+		n += inc
+	}
+
+	return n, err
 }
 
-// UnmarshalBinary decodes a previously marshaled RotationKeys in the target RotationKeys.
-func (rotationkey *RotationKeys) UnmarshalBinary(data []byte) (err error) {
+// ReadFrom reads on the target Ciphertext a Ciphertext written by WriteTo or MarshalBinary from r.
+// It returns the number of bytes read.
+func (ciphertext *Ciphertext) ReadFrom(r io.Reader) (n int64, err error) {
 
-	var rotationType int
-	var rotationNumber uint64
+	header := make([]byte, 11)
+	if n, err = readBytes(r, header); err != nil {
+		return n, err
+	}
 
-	pointer := uint64(0)
-	var inc uint64
+	ciphertext.ckksElement = new(ckksElement)
+	ciphertext.seed = nil
 
-	dataLen := len(data)
+	ciphertext.value = make([]*ring.Poly, uint8(header[0]))
 
-	for dataLen > 0 {
+	ciphertext.scale = math.Float64frombits(binary.LittleEndian.Uint64(header[1:9]))
 
-		rotationType = int(data[pointer])
-		rotationNumber = (uint64(data[pointer+1]) << 16) | (uint64(data[pointer+2]) << 8) | (uint64(data[pointer+3]))
+	values := ciphertext.value
+	if uint8(header[9]) == 1 {
+		if len(values) != 2 {
+			return n, errors.New("cannot ReadFrom : invalid seeded Ciphertext")
+		}
+		values = values[:1]
+	}
 
-		pointer += 4
+	if uint8(header[10]) == 1 {
+		ciphertext.isNTT = true
+	}
 
-		if rotationType == RotationLeft {
+	var inc int64
+	for i := range values {
+		ciphertext.value[i] = new(ring.Poly)
+		inc, err = readPoly(r, ciphertext.value[i])
+		n += inc
+		if err != nil {
+			return n, err
+		}
+	}
 
-			if rotationkey.evakeyRotColLeft == nil {
-				rotationkey.evakeyRotColLeft = make(map[uint64]*SwitchingKey)
-			}
+	if len(values) != len(ciphertext.value) {
+
+		ciphertext.seed = new(ring.PolySeed)
+		inc, err = ciphertext.seed.ReadFrom(r)
+		n += inc
+		if err != nil {
+			return n, err
+		}
+
+		ciphertext.expandSeed()
+	}
+
+	return n, nil
+}
+
+// WriteTo writes the target SecretKey on w, with the same format as MarshalBinary. It returns the number of bytes written.
+func (sk *SecretKey) WriteTo(w io.Writer) (n int64, err error) {
+	return writePoly(w, sk.sk)
+}
+
+// ReadFrom reads on the target SecretKey a SecretKey written by WriteTo or MarshalBinary from r.
+// It returns the number of bytes read.
+func (sk *SecretKey) ReadFrom(r io.Reader) (n int64, err error) {
+	sk.sk = new(ring.Poly)
+	return readPoly(r, sk.sk)
+}
+
+// WriteTo writes the target PublicKey on w, with the same format as MarshalBinary. It returns the number of bytes written.
+func (pk *PublicKey) WriteTo(w io.Writer) (n int64, err error) {
+
+	header := []byte{0}
+	if pk.seed != nil {
+		header[0] = 1
+	}
+
+	if n, err = writeBytes(w, header); err != nil {
+		return n, err
+	}
+
+	var inc int64
 
-			if rotationkey.permuteNTTLeftIndex == nil {
-				rotationkey.permuteNTTLeftIndex = make(map[uint64][]uint64)
+	inc, err = writePoly(w, pk.pk[0])
+	n += inc
+	if err != nil {
+		return n, err
+	}
+
+	if pk.seed != nil {
+		inc, err = pk.seed.WriteTo(w)
+	} else {
+		inc, err = writePoly(w, pk.pk[1])
+	}
+
+	return n + inc, err
+}
+
+// ReadFrom reads on the target PublicKey a PublicKey written by WriteTo or MarshalBinary from r.
+// It returns the number of bytes read.
+func (pk *PublicKey) ReadFrom(r io.Reader) (n int64, err error) {
+
+	header := make([]byte, 1)
+	if n, err = readBytes(r, header); err != nil {
+		return n, err
+	}
+
+	var inc int64
+
+	pk.pk[0] = new(ring.Poly)
+	inc, err = readPoly(r, pk.pk[0])
+	n += inc
+	if err != nil {
+		return n, err
+	}
+
+	if uint8(header[0]) == 1 {
+
+		pk.seed = new(ring.PolySeed)
+		inc, err = pk.seed.ReadFrom(r)
+		n += inc
+		if err != nil {
+			return n, err
+		}
+
+		pk.pk[1] = pk.seed.Expand(uint64(pk.pk[0].GetDegree()), 1)[0]
+
+		return n, nil
+	}
+
+	pk.seed = nil
+	pk.pk[1] = new(ring.Poly)
+	inc, err = readPoly(r, pk.pk[1])
+
+	return n + inc, err
+}
+
+// WriteTo writes the target EvaluationKey on w, with the same format as MarshalBinary, one polynomial
+// at a time. It returns the number of bytes written.
+func (evaluationkey *EvaluationKey) WriteTo(w io.Writer) (n int64, err error) {
+	return evaluationkey.evakey.WriteTo(w)
+}
+
+// ReadFrom reads on the target EvaluationKey an EvaluationKey written by WriteTo or MarshalBinary from r.
+// It returns the number of bytes read.
+func (evaluationkey *EvaluationKey) ReadFrom(r io.Reader) (n int64, err error) {
+	evaluationkey.evakey = new(SwitchingKey)
+	return evaluationkey.evakey.ReadFrom(r)
+}
+
+// WriteTo writes the target SwitchingKey on w, with the same format as MarshalBinary, one polynomial
+// at a time. It returns the number of bytes written.
+func (switchkey *SwitchingKey) WriteTo(w io.Writer) (n int64, err error) {
+
+	header := []byte{uint8(len(switchkey.evakey)), 0}
+	if switchkey.seed != nil {
+		header[1] = 1
+	}
+
+	if n, err = writeBytes(w, header); err != nil {
+		return n, err
+	}
+
+	var inc int64
+
+	if switchkey.seed != nil {
+		inc, err = switchkey.seed.WriteTo(w)
+		n += inc
+		if err != nil {
+			return n, err
+		}
+	}
+
+	for j := range switchkey.evakey {
+		for k := range switchkey.evakey[j] {
+
+			if k == 1 && switchkey.seed != nil {
+				continue
 			}
 
-			rotationkey.evakeyRotColLeft[rotationNumber] = new(SwitchingKey)
-			if inc, err = rotationkey.evakeyRotColLeft[rotationNumber].decode(data[pointer:]); err != nil {
-				return err
+			inc, err = writePoly(w, switchkey.evakey[j][k])
+			n += inc
+			if err != nil {
+				return n, err
 			}
+		}
+	}
 
-			N := uint64(len(rotationkey.evakeyRotColLeft[rotationNumber].evakey[0][0].Coeffs[0]))
+	return n, nil
+}
 
-			rotationkey.permuteNTTLeftIndex[rotationNumber] = ring.PermuteNTTIndex(GaloisGen, rotationNumber, N)
+// ReadFrom reads on the target SwitchingKey a SwitchingKey written by WriteTo or MarshalBinary from r.
+// It returns the number of bytes read.
+func (switchkey *SwitchingKey) ReadFrom(r io.Reader) (n int64, err error) {
 
-		} else if rotationType == RotationRight {
+	header := make([]byte, 2)
+	if n, err = readBytes(r, header); err != nil {
+		return n, err
+	}
 
-			if rotationkey.evakeyRotColRight == nil {
-				rotationkey.evakeyRotColRight = make(map[uint64]*SwitchingKey)
-			}
+	seeded := uint8(header[1]) == 1
+
+	var inc int64
+
+	switchkey.seed = nil
 
-			rotationkey.evakeyRotColRight[rotationNumber] = new(SwitchingKey)
-			if inc, err = rotationkey.evakeyRotColRight[rotationNumber].decode(data[pointer:]); err != nil {
-				return err
+	if seeded {
+		switchkey.seed = new(ring.PolySeed)
+		inc, err = switchkey.seed.ReadFrom(r)
+		n += inc
+		if err != nil {
+			return n, err
+		}
+	}
+
+	switchkey.evakey = make([][2]*ring.Poly, uint8(header[0]))
+
+	for j := range switchkey.evakey {
+		for k := range switchkey.evakey[j] {
+
+			if k == 1 && seeded {
+				continue
 			}
 
-			if rotationkey.permuteNTTRightIndex == nil {
-				rotationkey.permuteNTTRightIndex = make(map[uint64][]uint64)
+			switchkey.evakey[j][k] = new(ring.Poly)
+			inc, err = readPoly(r, switchkey.evakey[j][k])
+			n += inc
+			if err != nil {
+				return n, err
 			}
+		}
+	}
+
+	if seeded && len(switchkey.evakey) != 0 {
+		switchkey.expandSeed()
+	}
 
-			N := uint64(len(rotationkey.evakeyRotColRight[rotationNumber].evakey[0][0].Coeffs[0]))
+	return n, nil
+}
 
-			rotationkey.permuteNTTRightIndex[rotationNumber] = ring.PermuteNTTIndex(GaloisGen, (2*N)-rotationNumber, N)
+// WriteTo writes the target RotationKeys on w, with the same format as MarshalBinary, one polynomial
+// at a time. It returns the number of bytes written.
+func (rotationkey *RotationKeys) WriteTo(w io.Writer) (n int64, err error) {
 
-		} else if rotationType == Conjugate {
+	header := make([]byte, 4)
 
-			rotationkey.evakeyConjugate = new(SwitchingKey)
-			if inc, err = rotationkey.evakeyConjugate.decode(data[pointer:]); err != nil {
-				return err
-			}
+	binary.BigEndian.PutUint32(header, uint32(len(rotationkey.keys)))
 
-			N := uint64(len(rotationkey.evakeyConjugate.evakey[0][0].Coeffs[0]))
+	if n, err = writeBytes(w, header); err != nil {
+		return n, err
+	}
 
-			rotationkey.permuteNTTConjugateIndex = ring.PermuteNTTIndex((2*N)-1, 1, N)
+	var inc int64
 
-		} else {
+	for _, galEl := range rotationkey.galoisElements() {
 
-			return err
+		binary.BigEndian.PutUint32(header, uint32(galEl))
+
+		inc, err = writeBytes(w, header)
+		n += inc
+		if err != nil {
+			return n, err
 		}
 
-		pointer += inc
+		inc, err = rotationkey.keys[galEl].WriteTo(w)
+		n += inc
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// ReadFrom reads on the target RotationKeys the RotationKeys written by WriteTo or MarshalBinary from r.
+// Exactly the number of keys written in the header is read. It returns the number of bytes read.
+func (rotationkey *RotationKeys) ReadFrom(r io.Reader) (n int64, err error) {
+
+	header := make([]byte, 4)
 
-		dataLen -= int(4 + inc)
+	if n, err = readBytes(r, header); err != nil {
+		return n, err
 	}
 
-	return nil
+	nbKeys := binary.BigEndian.Uint32(header)
+
+	var inc int64
+
+	for i := uint32(0); i < nbKeys; i++ {
+
+		inc, err = readBytes(r, header)
+		n += inc
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return n, err
+		}
+
+		switchkey := new(SwitchingKey)
+
+		inc, err = switchkey.ReadFrom(r)
+		n += inc
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return n, err
+		}
+
+		galEl := uint64(binary.BigEndian.Uint32(header))
+
+		if !validGaloisElement(galEl, switchkey) {
+			return n, errors.New("cannot ReadFrom : invalid Galois element")
+		}
+
+		rotationkey.setKey(galEl, switchkey)
+	}
+
+	return n, nil
+}
+
+// writePoly writes the polynomial on w, with the same format as ring.Poly.MarshalBinary. Only
+// the polynomial is buffered, so that large keys can be written without allocating their full size.
+func writePoly(w io.Writer, pol *ring.Poly) (int64, error) {
+
+	data := make([]byte, pol.GetDataLen(true))
+
+	data[0] = uint8(bits.Len64(uint64(pol.GetDegree())) - 1)
+	data[1] = uint8(pol.GetLenModuli())
+
+	if _, err := pol.WriteCoeffs(data[2:]); err != nil {
+		return 0, err
+	}
+
+	return writeBytes(w, data)
+}
+
+// readPoly reads on the target polynomial a polynomial written by writePoly or ring.Poly.MarshalBinary from r.
+func readPoly(r io.Reader, pol *ring.Poly) (n int64, err error) {
+
+	header := make([]byte, 2)
+	if n, err = readBytes(r, header); err != nil {
+		return n, err
+	}
+
+	// The header is validated before any allocation, so that a malformed stream cannot force a huge allocation
+	if header[0] == 0 || header[0] > MaxLogN {
+		return n, errors.New("cannot ReadFrom : invalid polynomial degree")
+	}
+
+	// The polynomials of the keys have coefficients in QP, which has at most 2*MaxModuliCount moduli
+	if header[1] == 0 || header[1] > 2*MaxModuliCount {
+		return n, errors.New("cannot ReadFrom : invalid number of moduli")
+	}
+
+	N := uint64(1 << header[0])
+	numberModuli := uint64(header[1])
+
+	data := make([]byte, (N*numberModuli)<<3)
+
+	var inc int64
+	inc, err = readBytes(r, data)
+	n += inc
+	if err != nil {
+		return n, err
+	}
+
+	pol.Coeffs = make([][]uint64, numberModuli)
+
+	_, err = ring.DecodeCoeffsNew(0, N, numberModuli, pol.Coeffs, data)
+
+	return n, err
+}
+
+func writeBytes(w io.Writer, data []byte) (int64, error) {
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// readBytes fills data from r. It returns io.EOF only if no byte could be read, and io.ErrUnexpectedEOF
+// if the end of the stream was reached before data was filled.
+func readBytes(r io.Reader, data []byte) (int64, error) {
+	n, err := io.ReadFull(r, data)
+	return int64(n), err
 }
@@ -0,0 +1,398 @@
+package ckks
+
+import (
+	"math"
+)
+
+// minimaxErrorMargin is the relative safety margin added to the maximum error measured by ApproximateMinimax, to
+// account for the extrema of the error that the sampling and the refinement may locate imprecisely.
+const minimaxErrorMargin = 1.0 / 64
+
+// ApproximateMinimax computes, with the Remez algorithm, the minimax approximation of degree degree of the real
+// function on the range [a, b], which is the polynomial of degree degree that minimizes the maximum error
+// max_{x in [a, b]} |function(x) - p(x)|. It returns the approximation as a Chebyshev interpolation, to be used in
+// conjunction with EvaluateChebyFast or EvaluateChebyEco, along with an estimate of its maximum error on [a, b].
+// The function must be continuous on [a, b].
+//
+// The returned maxErr is an estimate, not a certified bound: the error is measured on a dense grid of [a, b] refined
+// around its extrema by a golden-section search, then increased by a relative margin of 1/64 and by a bound on the
+// rounding errors of the evaluation of the polynomial in float64. A function that varies much faster than the
+// polynomial between the points of the grid can still have a larger error.
+func ApproximateMinimax(function func(float64) float64, a, b float64, degree int) (cheby *ChebyshevInterpolation, maxErr float64) {
+
+	if degree < 1 {
+		panic("cannot ApproximateMinimax : degree must be at least 1")
+	}
+
+	if a >= b {
+		panic("cannot ApproximateMinimax : a must be smaller than b")
+	}
+
+	basis := chebyshevBasis(a, b)
+
+	coeffs, maxErr, alternates := remez(function, a, b, degree+1, basis)
+
+	// If the error does not alternate enough on the initial reference, which happens when the function has the
+	// parity of the degree on a range symmetric around zero, the minimax approximation of degree degree is also the
+	// one of degree degree+1, whose Chebyshev coefficient of degree degree+1 is zero.
+	if !alternates {
+
+		coeffsNext, _, _ := remez(function, a, b, degree+2, basis)
+
+		coeffsNext = coeffsNext[:degree+1]
+
+		if _, errNext := remezExtrema(function, coeffsNext, a, b, basis); errNext < maxErr {
+			coeffs, maxErr = coeffsNext, errNext
+		}
+	}
+
+	maxErr = minimaxErrorEstimate(maxErr, coeffs)
+
+	cheby = new(ChebyshevInterpolation)
+	cheby.coeffs = make(map[uint64]complex128)
+	cheby.a = complex(a, 0)
+	cheby.b = complex(b, 0)
+	cheby.degree = uint64(degree)
+
+	for i := range coeffs {
+		cheby.coeffs[uint64(i)] = complex(coeffs[i], 0)
+	}
+
+	return
+}
+
+// ApproximateMinimaxOdd computes, with the Remez algorithm, the minimax approximation by an odd polynomial of degree
+// degree of the real odd function on [-b, -a] U [a, b], with 0 < a < b, which is the odd polynomial that minimizes the
+// maximum error max_{a <= |x| <= b} |function(x) - p(x)|. The values of the function on (-a, a) are ignored, which
+// makes it suited to the approximation of discontinuous functions such as the sign function. It returns the
+// approximation as a Chebyshev interpolation on [-b, b], whose even coefficients are zero, along with an estimate of
+// its maximum error on [-b, -a] U [a, b], computed as for ApproximateMinimax. The function must be continuous on [a, b].
+func ApproximateMinimaxOdd(function func(float64) float64, a, b float64, degree int) (cheby *ChebyshevInterpolation, maxErr float64) {
+
+	if degree < 1 || degree&1 == 0 {
+		panic("cannot ApproximateMinimaxOdd : degree must be odd")
+	}
+
+	if a <= 0 || a >= b {
+		panic("cannot ApproximateMinimaxOdd : a and b must satisfy 0 < a < b")
+	}
+
+	// The odd polynomials of degree at most degree form a Haar space on [a, b], so that the Remez algorithm applies
+	// on [a, b], the error on [-b, -a] being its opposite
+	coeffs, maxErr, _ := remez(function, a, b, (degree+1)>>1, oddChebyshevBasis(b))
+
+	maxErr = minimaxErrorEstimate(maxErr, coeffs)
+
+	cheby = new(ChebyshevInterpolation)
+	cheby.coeffs = make(map[uint64]complex128)
+	cheby.a = complex(-b, 0)
+	cheby.b = complex(b, 0)
+	cheby.degree = uint64(degree)
+
+	for i := 0; i <= degree; i++ {
+		cheby.coeffs[uint64(i)] = 0
+	}
+
+	for i := range coeffs {
+		cheby.coeffs[uint64(2*i+1)] = complex(coeffs[i], 0)
+	}
+
+	return
+}
+
+// minimaxErrorEstimate returns the maximum error measured for the approximation of the given Chebyshev coefficients
+// increased by the safety margin minimaxErrorMargin and by a bound on the rounding errors of its evaluation in float64,
+// which grow with the degree and the magnitude of the coefficients.
+func minimaxErrorEstimate(maxErr float64, coeffs []float64) float64 {
+
+	var sumAbs float64
+	for _, c := range coeffs {
+		sumAbs += math.Abs(c)
+	}
+
+	return maxErr*(1+minimaxErrorMargin) + float64(len(coeffs))*sumAbs*0x1p-52
+}
+
+// remezBasis evaluates at x the functions of the basis in which an approximation is computed, one per value.
+type remezBasis func(x float64, values []float64)
+
+// chebyshevBasis returns the basis of the Chebyshev polynomials T_j((2x - a - b)/(b - a)) on [a, b].
+func chebyshevBasis(a, b float64) remezBasis {
+	return func(x float64, values []float64) {
+		u := (2*x - a - b) / (b - a)
+		Tprev, T := 1.0, u
+		for j := range values {
+			values[j] = Tprev
+			Tprev, T = T, 2*u*T-Tprev
+		}
+	}
+}
+
+// oddChebyshevBasis returns the basis of the odd Chebyshev polynomials T_{2j+1}(x/b) on [-b, b].
+func oddChebyshevBasis(b float64) remezBasis {
+	return func(x float64, values []float64) {
+		u := x / b
+		Tprev, T := 1.0, u
+		for j := 0; j < len(values)<<1; j++ {
+			if j&1 == 1 {
+				values[j>>1] = Tprev
+			}
+			Tprev, T = T, 2*u*T-Tprev
+		}
+	}
+}
+
+// monomialBasis is the basis of the monomials x^j.
+func monomialBasis(x float64, values []float64) {
+	y := 1.0
+	for j := range values {
+		values[j] = y
+		y *= x
+	}
+}
+
+// remez runs the Remez algorithm for the approximation of the function on [a, b] by a linear combination of the
+// nbCoeffs first functions of the basis, and returns the coefficients of the approximation of smallest maximum error
+// among its iterations along with this error. It also returns false if the error of the approximation on the initial
+// reference does not alternate enough for the algorithm to proceed.
+func remez(function func(float64) float64, a, b float64, nbCoeffs int, basis remezBasis) (coeffs []float64, maxErr float64, alternates bool) {
+
+	n := nbCoeffs + 1
+
+	// The initial reference is given by the extrema of the Chebyshev polynomial of degree n-1, which is close to
+	// optimal for smooth functions
+	reference := make([]float64, n)
+	for i := range reference {
+		reference[i] = 0.5*(a+b) - 0.5*(b-a)*math.Cos(float64(i)*math.Pi/float64(n-1))
+	}
+
+	maxErr = math.Inf(1)
+
+	for iter := 0; iter < 64; iter++ {
+
+		// Solves sum_{j=0}^{nbCoeffs-1} c_j * B_j(x_i) + (-1)^i * E = function(x_i) for the reference points x_i
+		coeffsIter, _ := remezSolve(function, reference, basis)
+
+		extrema, errIter := remezExtrema(function, coeffsIter, a, b, basis)
+
+		// Keeps the best approximation, since the rounding errors can make the iterations diverge once the error
+		// is close to the precision of float64
+		if errIter < maxErr {
+			coeffs, maxErr = coeffsIter, errIter
+		}
+
+		if len(extrema) < n {
+			return coeffs, maxErr, iter != 0
+		}
+
+		// Keeps n alternating extrema, discarding the extrema of smallest error at the ends
+		for len(extrema) > n {
+			if math.Abs(remezError(function, coeffsIter, extrema[0], basis)) < math.Abs(remezError(function, coeffsIter, extrema[len(extrema)-1], basis)) {
+				extrema = extrema[1:]
+			} else {
+				extrema = extrema[:len(extrema)-1]
+			}
+		}
+
+		reference = extrema
+
+		// Stops when the error equioscillates on the reference
+		minErr := errIter
+		for _, x := range reference {
+			minErr = math.Min(minErr, math.Abs(remezError(function, coeffsIter, x, basis)))
+		}
+
+		if errIter-minErr <= 1e-6*errIter {
+			break
+		}
+	}
+
+	return coeffs, maxErr, true
+}
+
+// ApproximateMinimaxWithError computes the minimax approximation of smallest degree, up to maxDegree, of the real
+// function on the range [a, b] whose estimated maximum error on [a, b] is at most maxErr. The estimate includes a
+// safety margin but is not a certified bound (see ApproximateMinimax). It panics if no such approximation exists.
+func ApproximateMinimaxWithError(function func(float64) float64, a, b, maxErr float64, maxDegree int) (cheby *ChebyshevInterpolation) {
+
+	for degree := 1; degree <= maxDegree; degree++ {
+		if approx, err := ApproximateMinimax(function, a, b, degree); err <= maxErr {
+			return approx
+		}
+	}
+
+	panic("cannot ApproximateMinimaxWithError : no approximation of degree at most maxDegree reaches the target error")
+}
+
+// remezSolve solves the linear system sum_{j=0}^{n-2} c_j * B_j(x_i) + (-1)^i * E = function(x_i) for the n points
+// x_i of the reference, B_j being the functions of the basis, and returns the coefficients c_j and the levelled error E.
+func remezSolve(function func(float64) float64, reference []float64, basis remezBasis) (coeffs []float64, E float64) {
+
+	n := len(reference)
+
+	matrix := make([][]float64, n)
+	vector := make([]float64, n)
+
+	for i, x := range reference {
+
+		matrix[i] = make([]float64, n)
+
+		basis(x, matrix[i][:n-1])
+
+		if i&1 == 0 {
+			matrix[i][n-1] = 1
+		} else {
+			matrix[i][n-1] = -1
+		}
+
+		vector[i] = function(x)
+	}
+
+	solution := solveLinearSystem(matrix, vector)
+
+	return solution[:n-1], solution[n-1]
+}
+
+// remezExtrema returns the points of largest absolute error in each interval of [a, b] on which the error of the
+// approximation has a constant sign, which are alternating extrema of the error, along with the maximum absolute error.
+func remezExtrema(function func(float64) float64, coeffs []float64, a, b float64, basis remezBasis) (extrema []float64, maxErr float64) {
+
+	// Dense grid of [a, b], with more points close to the ends where the extrema concentrate
+	samples := 64 * (len(coeffs) + 1)
+
+	grid := make([]float64, samples+1)
+	for i := range grid {
+		grid[i] = 0.5*(a+b) - 0.5*(b-a)*math.Cos(float64(i)*math.Pi/float64(samples))
+	}
+
+	errors := make([]float64, len(grid))
+	for i, x := range grid {
+		errors[i] = remezError(function, coeffs, x, basis)
+	}
+
+	// Index of the point of largest absolute error in the current interval of constant sign
+	best := 0
+
+	for i := 1; i <= len(grid); i++ {
+
+		if i == len(grid) || (errors[i] > 0) != (errors[best] > 0) {
+
+			x, e := remezRefine(function, coeffs, grid, best, basis)
+
+			extrema = append(extrema, x)
+			maxErr = math.Max(maxErr, e)
+
+			best = i
+
+		} else if math.Abs(errors[i]) > math.Abs(errors[best]) {
+			best = i
+		}
+	}
+
+	return
+}
+
+// remezRefine refines, with a golden-section search between the neighbors of the point of index i of the grid,
+// the position of a local maximum of the absolute error and returns it along with the absolute error at this point.
+func remezRefine(function func(float64) float64, coeffs []float64, grid []float64, i int, basis remezBasis) (x, maxErr float64) {
+
+	lo := grid[i]
+	if i > 0 {
+		lo = grid[i-1]
+	}
+
+	hi := grid[i]
+	if i < len(grid)-1 {
+		hi = grid[i+1]
+	}
+
+	absErr := func(x float64) float64 {
+		return math.Abs(remezError(function, coeffs, x, basis))
+	}
+
+	invPhi := (math.Sqrt(5) - 1) / 2
+
+	x0 := hi - invPhi*(hi-lo)
+	x1 := lo + invPhi*(hi-lo)
+	e0, e1 := absErr(x0), absErr(x1)
+
+	for k := 0; k < 32; k++ {
+		if e0 > e1 {
+			hi, x1, e1 = x1, x0, e0
+			x0 = hi - invPhi*(hi-lo)
+			e0 = absErr(x0)
+		} else {
+			lo, x0, e0 = x0, x1, e1
+			x1 = lo + invPhi*(hi-lo)
+			e1 = absErr(x1)
+		}
+	}
+
+	// The maximum can be at the grid point itself (e.g. at the ends of [a, b])
+	x, maxErr = grid[i], absErr(grid[i])
+
+	if e0 > maxErr {
+		x, maxErr = x0, e0
+	}
+
+	if e1 > maxErr {
+		x, maxErr = x1, e1
+	}
+
+	return
+}
+
+// remezError returns function(x) - p(x), where p is the linear combination of the functions of the basis with the
+// given coefficients.
+func remezError(function func(float64) float64, coeffs []float64, x float64, basis remezBasis) float64 {
+
+	values := make([]float64, len(coeffs))
+	basis(x, values)
+
+	y := 0.0
+	for j := range coeffs {
+		y += coeffs[j] * values[j]
+	}
+
+	return function(x) - y
+}
+
+// solveLinearSystem solves the square linear system matrix * x = vector with a Gaussian elimination with partial
+// pivoting. The inputs are modified.
+func solveLinearSystem(matrix [][]float64, vector []float64) (x []float64) {
+
+	n := len(vector)
+
+	for i := 0; i < n; i++ {
+
+		pivot := i
+		for j := i + 1; j < n; j++ {
+			if math.Abs(matrix[j][i]) > math.Abs(matrix[pivot][i]) {
+				pivot = j
+			}
+		}
+
+		matrix[i], matrix[pivot] = matrix[pivot], matrix[i]
+		vector[i], vector[pivot] = vector[pivot], vector[i]
+
+		for j := i + 1; j < n; j++ {
+			factor := matrix[j][i] / matrix[i][i]
+			for k := i; k < n; k++ {
+				matrix[j][k] -= factor * matrix[i][k]
+			}
+			vector[j] -= factor * vector[i]
+		}
+	}
+
+	x = make([]float64, n)
+
+	for i := n - 1; i >= 0; i-- {
+		x[i] = vector[i]
+		for j := i + 1; j < n; j++ {
+			x[i] -= matrix[i][j] * x[j]
+		}
+		x[i] /= matrix[i][i]
+	}
+
+	return
+}
@@ -158,7 +158,7 @@ func (el *ckksElement) Element() *ckksElement {
 
 // Ciphertext sets the target element type to Ciphertext.
 func (el *ckksElement) Ciphertext() *Ciphertext {
-	return &Ciphertext{el}
+	return &Ciphertext{ckksElement: el}
 }
 
 // Plaintext sets the target element type to Plaintext.
@@ -32,7 +32,10 @@ const (
 	PN16QP1761
 )
 
-// DefaultParams is a set of default CKKS parameters ensuring 128 bit security.
+// DefaultParams is a set of default CKKS parameters. The parameters up to PN15QP880 ensure 128 bit security according
+// to the Homomorphic Encryption Standard (see SecurityLevel). The ring degree of PN16QP1761 is not covered by the
+// standard, hence its security is not estimated (SecurityLevel returns ring.ErrSecurityNotEstimated). Parameters sized
+// for a given circuit can instead be generated with GenParameters.
 var DefaultParams = []*Parameters{
 
 	//LogQi = 109
@@ -1,6 +1,7 @@
 package ckks
 
 import (
+	"github.com/ldsec/lattigo/ring"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -26,3 +27,60 @@ func TestParams_BinaryMarshaller(t *testing.T) {
 		}
 	})
 }
+
+func TestSecurityLevel(t *testing.T) {
+
+	for _, params := range DefaultParams {
+		securityLevel, err := SecurityLevel(params)
+		if params.LogN <= ring.MaxSecurityLogN {
+			assert.Nil(t, err)
+			assert.Equal(t, uint64(128), securityLevel)
+		} else {
+			assert.Equal(t, ring.ErrSecurityNotEstimated, err)
+		}
+	}
+}
+
+func TestGenParameters(t *testing.T) {
+
+	for _, depth := range []uint64{0, 1, 4} {
+
+		for _, logPrecision := range []uint64{10, 20} {
+
+			parameters, err := GenParameters(depth, logPrecision, 128)
+			assert.Nil(t, err)
+			securityLevel, err := SecurityLevel(parameters)
+			assert.Nil(t, err)
+			assert.True(t, securityLevel >= 128)
+			assert.Equal(t, depth, parameters.MaxLevel())
+
+			params := genCkksParams(parameters)
+			rlk := params.kgen.GenRelinKey(params.sk)
+
+			values, _, ciphertext := newTestVectors(params, params.encryptorPk, 0.7, t)
+
+			for i := uint64(0); i < depth; i++ {
+				params.evaluator.MulRelin(ciphertext, ciphertext, rlk, ciphertext)
+				if err := params.evaluator.Rescale(ciphertext, parameters.Scale, ciphertext); err != nil {
+					t.Error(err)
+				}
+				for j := range values {
+					values[j] *= values[j]
+				}
+			}
+
+			valuesTest := params.encoder.Decode(params.decryptor.DecryptNew(ciphertext), 1<<parameters.LogSlots)
+
+			precStats := GetPrecisionStats(values, valuesTest)
+
+			assert.True(t, real(precStats.MedianPrecision) >= float64(logPrecision))
+			assert.True(t, imag(precStats.MedianPrecision) >= float64(logPrecision))
+		}
+	}
+
+	_, err := GenParameters(1, 20, 100)
+	assert.NotNil(t, err)
+
+	_, err = GenParameters(1, 60, 128)
+	assert.NotNil(t, err)
+}
@@ -28,3 +28,15 @@ func NewPlaintext(params *Parameters, level uint64, scale float64) *Plaintext {
 
 	return plaintext
 }
+
+// PlaintextMul is a Plaintext pre-computed for the multiplication with ciphertexts: it is stored in the NTT domain
+// and in the Montgomery form, so that MulRelin, MultByConst and MulAndAdd multiply it directly with the ciphertexts.
+// It can only be used as an operand of these methods: the other methods of the Evaluator panic on a PlaintextMul.
+type PlaintextMul struct {
+	*Plaintext
+}
+
+// NewPlaintextMul creates a new PlaintextMul of level level and scale scale.
+func NewPlaintextMul(params *Parameters, level uint64, scale float64) *PlaintextMul {
+	return &PlaintextMul{NewPlaintext(params, level, scale)}
+}
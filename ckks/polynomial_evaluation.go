@@ -3,54 +3,41 @@ package ckks
 import (
 	"math"
 	"math/bits"
+	"math/cmplx"
+
+	"github.com/ldsec/lattigo/utils"
 )
 
-// EvaluatePolyFast evaluates the polynomial a + bx + cx^2... on the input Ciphertext.
-// It is faster than EvaluatePolyEco, but consumes ceil(log2(deg)) + 1 levels.
+// EvaluatePolyFast evaluates the polynomial a + bx + cx^2... on the input Ciphertext and returns the result at the
+// default scale of the parameters (see EvaluatePoly).
+// It is faster than EvaluatePolyEco, since it uses 2^(ceil(log2(deg))/2) baby steps instead of 2, which reduces the
+// number of ciphertext multiplications, but stores more intermediate ciphertexts.
 func (eval *evaluator) EvaluatePolyFast(ct0 *Ciphertext, coeffs interface{}, evakey *EvaluationKey) (ctOut *Ciphertext) {
-
-	degree, coeffsMap := convertCoeffs(coeffs)
-
-	C := make(map[uint64]*Ciphertext)
-
-	C[1] = ct0.CopyNew().Ciphertext()
-
-	M := uint64(bits.Len64(degree - 1))
-	L := uint64(M >> 1)
-
-	for i := uint64(2); i <= (1 << L); i++ {
-		computePowerBasis(i, C, eval, evakey)
-	}
-
-	for i := L + 1; i < M; i++ {
-		computePowerBasis(1<<i, C, eval, evakey)
-	}
-
-	return recurse(degree, L, M, coeffsMap, C, eval, evakey)
+	return eval.EvaluatePoly(ct0, coeffs, eval.ckksContext.scale, evakey)
 }
 
-// EvaluatePolyEco evaluates the polynomial a + bx + cx^2... on the input Ciphertext.
-// It is slower than EvaluatePolyFast, but it consumes one less level (ceil(log2(deg))).
+// EvaluatePolyEco evaluates the polynomial a + bx + cx^2... on the input Ciphertext and returns the result at the
+// default scale of the parameters (see EvaluatePoly).
+// It is slower than EvaluatePolyFast, since it uses only 2 baby steps, but it stores less intermediate ciphertexts.
 func (eval *evaluator) EvaluatePolyEco(ct0 *Ciphertext, coeffs interface{}, evakey *EvaluationKey) (ctOut *Ciphertext) {
 
 	degree, coeffsMap := convertCoeffs(coeffs)
 
-	C := make(map[uint64]*Ciphertext)
-
-	C[1] = ct0.CopyNew().Ciphertext()
-
-	M := uint64(bits.Len64(degree - 1))
-	L := uint64(1)
+	return eval.evaluatePoly(ct0.CopyNew().Ciphertext(), degree, coeffsMap, 1, false, eval.ckksContext.scale, evakey)
+}
 
-	for i := uint64(2); i <= (1 << L); i++ {
-		computePowerBasis(i, C, eval, evakey)
-	}
+// EvaluatePoly evaluates the polynomial a + bx + cx^2... on the input Ciphertext with the baby-step giant-step
+// algorithm and returns the result at exactly the scale targetScale, so that it can be added to other ciphertexts at
+// this scale without error. The scales of the intermediate ciphertexts are chosen accordingly.
+//
+// It consumes ceil(log2(deg+1)) levels, deg being the degree of the polynomial. The evaluation detects the parity of
+// the polynomial: the powers of the input that are only multiplied by zero coefficients, such as the even powers of
+// an odd polynomial, are neither computed nor used.
+func (eval *evaluator) EvaluatePoly(ct0 *Ciphertext, coeffs interface{}, targetScale float64, evakey *EvaluationKey) (ctOut *Ciphertext) {
 
-	for i := L + 1; i < M; i++ {
-		computePowerBasis(1<<i, C, eval, evakey)
-	}
+	degree, coeffsMap := convertCoeffs(coeffs)
 
-	return recurse(degree, L, M, coeffsMap, C, eval, evakey)
+	return eval.evaluatePoly(ct0.CopyNew().Ciphertext(), degree, coeffsMap, optimalBabySteps(degree), false, targetScale, evakey)
 }
 
 func convertCoeffs(coeffs interface{}) (degree uint64, coeffsMap map[uint64]complex128) {
@@ -73,23 +60,132 @@ func convertCoeffs(coeffs interface{}) (degree uint64, coeffsMap map[uint64]comp
 	return uint64(len(coeffsMap)) - 1, coeffsMap
 }
 
-func computePowerBasis(n uint64, C map[uint64]*Ciphertext, evaluator *evaluator, evakey *EvaluationKey) {
+// nonZeroCoeffs returns the coefficients that have to be taken into account by the polynomial evaluation: the
+// non-zero coefficients in the monomial basis, and in the Chebyshev basis the coefficients of absolute value at least
+// 1/targetScale, which is the resolution of the result. Since |T_i(x)| <= 1 on the interpolation interval, a smaller
+// coefficient contributes less than the rounding error of the result. This discards the coefficients of the other
+// parity given by the interpolation of an odd or even function, which are of the order of its rounding errors.
+func nonZeroCoeffs(coeffs map[uint64]complex128, cheby bool, targetScale float64) (nonZero map[uint64]complex128) {
+
+	var threshold float64
+	if cheby {
+		threshold = 1 / targetScale
+	}
+
+	nonZero = make(map[uint64]complex128)
+	for i, c := range coeffs {
+		if c != 0 && cmplx.Abs(c) >= threshold {
+			nonZero[i] = c
+		}
+	}
+
+	return
+}
+
+// optimalBabySteps returns the log2 of the number of baby steps that minimizes the number of ciphertext
+// multiplications for a polynomial of the given degree. For a degree of the form 2^k-1, the baby step of highest
+// degree has the same depth as the giant steps and would cost one additional level, so only 2 baby steps are used.
+func optimalBabySteps(degree uint64) (L uint64) {
+	if degree > 1 && degree&(degree+1) != 0 {
+		L = uint64(bits.Len64(degree-1)) >> 1
+	}
+	return utils.MaxUint64(L, 1)
+}
+
+// polynomialEvaluator is a struct storing the power basis, in the monomial or in the Chebyshev basis, of the
+// evaluation of a polynomial with the baby-step giant-step algorithm, along with the log2 of the number of baby steps.
+type polynomialEvaluator struct {
+	*evaluator
+	evakey *EvaluationKey
+	C      map[uint64]*Ciphertext
+	L      uint64
+	cheby  bool
+}
+
+// evaluatePoly evaluates the polynomial of the given coefficients, in the monomial basis or in the Chebyshev basis,
+// on the Ciphertext C1, which is used as the first element of the power basis, and returns the result at targetScale.
+func (eval *evaluator) evaluatePoly(C1 *Ciphertext, degree uint64, coeffs map[uint64]complex128, L uint64, cheby bool, targetScale float64, evakey *EvaluationKey) (ctOut *Ciphertext) {
+
+	// Keeps only the non-zero coefficients, so that the powers that are only multiplied by zero coefficients, such as
+	// the powers of the other parity for an odd or even polynomial, are neither computed nor used
+	coeffs = nonZeroCoeffs(coeffs, cheby, targetScale)
+
+	for degree > 0 && coeffs[degree] == 0 {
+		degree--
+	}
+
+	polyEval := &polynomialEvaluator{
+		evaluator: eval,
+		evakey:    evakey,
+		C:         map[uint64]*Ciphertext{1: C1},
+		L:         L,
+		cheby:     cheby,
+	}
+
+	var M uint64
+	if degree > 0 {
+		M = uint64(bits.Len64(degree - 1))
+	}
 
-	if C[n] == nil {
+	// Computes the required powers and the largest level at which the result can be obtained before the last rescaling
+	level := polyEval.maxLevel(degree, M, coeffs)
+
+	if level == 0 {
+		panic("cannot EvaluatePoly : input level is too small for the degree of the polynomial")
+	}
+
+	ctOut = polyEval.recurse(level, targetScale*float64(eval.ckksContext.contextQ.Modulus[level]), degree, M, coeffs)
+
+	eval.RescaleMany(ctOut, 1, ctOut)
+
+	ctOut.SetScale(targetScale)
+
+	return
+}
+
+// computePowerBasis computes the n-th element of the power basis, C[n] = C[a] * C[b] in the monomial basis and
+// C[n] = 2 * C[a] * C[b] - C[|a-b|] in the Chebyshev basis, with a = ceil(n/2) and b = floor(n/2).
+func (polyEval *polynomialEvaluator) computePowerBasis(n uint64) {
+
+	if polyEval.C[n] == nil {
+
+		C := polyEval.C
 
 		// Computes the index required to compute the asked ring evaluation
 		a := uint64(math.Ceil(float64(n) / 2))
 		b := n >> 1
+		c := a - b
 
 		// Recurses on the given indexes
-		computePowerBasis(a, C, evaluator, evakey)
-		computePowerBasis(b, C, evaluator, evakey)
+		polyEval.computePowerBasis(a)
+		polyEval.computePowerBasis(b)
 
 		// Computes C[n] = C[a]*C[b]
-		C[n] = evaluator.MulRelinNew(C[a], C[b], evakey)
+		C[n] = polyEval.MulRelinNew(C[a], C[b], polyEval.evakey)
+
+		if polyEval.cheby {
 
-		evaluator.Rescale(C[n], evaluator.ckksContext.scale, C[n])
+			// Computes C[n] = 2*C[a]*C[b]
+			polyEval.Add(C[n], C[n], C[n])
+
+			// Computes C[n] = 2*C[a]*C[b] - C[c] before the rescaling, C[c] being scaled to the scale of C[n]
+			if c == 0 {
+				polyEval.AddConst(C[n], -1, C[n])
+			} else {
+				polyEval.multByConstAndAdd(C[n].Level(), C[c], -1, 0, C[n].Scale()/C[c].Scale(), C[n])
+			}
+		}
+
+		polyEval.Rescale(C[n], polyEval.ckksContext.scale, C[n])
+	}
+}
+
+// splitCoeffs splits a polynomial p such that p = q*C[degree] + r, in the monomial or in the Chebyshev basis.
+func (polyEval *polynomialEvaluator) splitCoeffs(coeffs map[uint64]complex128, degree, maxDegree uint64) (coeffsq, coeffsr map[uint64]complex128) {
+	if polyEval.cheby {
+		return splitCoeffsCheby(coeffs, degree, maxDegree)
 	}
+	return splitCoeffs(coeffs, degree, maxDegree)
 }
 
 func splitCoeffs(coeffs map[uint64]complex128, degree, maxDegree uint64) (coeffsq, coeffsr map[uint64]complex128) {
@@ -112,48 +208,93 @@ func splitCoeffs(coeffs map[uint64]complex128, degree, maxDegree uint64) (coeffs
 	return coeffsq, coeffsr
 }
 
-func recurse(maxDegree, L, M uint64, coeffs map[uint64]complex128, C map[uint64]*Ciphertext, evaluator *evaluator, evakey *EvaluationKey) (res *Ciphertext) {
+// maxLevel computes the elements of the power basis required to evaluate the polynomial and returns the largest
+// level at which the evaluation of the polynomial can be obtained (see recurse).
+func (polyEval *polynomialEvaluator) maxLevel(maxDegree, M uint64, coeffs map[uint64]complex128) (level uint64) {
+
+	if maxDegree <= (1 << polyEval.L) {
+
+		level = polyEval.C[1].Level()
+
+		for key := range coeffs {
+			if key != 0 && coeffs[key] != 0 {
+				polyEval.computePowerBasis(key)
+				level = utils.MinUint64(level, polyEval.C[key].Level())
+			}
+		}
 
-	if maxDegree <= (1 << L) {
-		return evaluatePolyFromPowerBasis(coeffs, C, evaluator, evakey)
+		return
 	}
 
 	for 1<<(M-1) > maxDegree {
 		M--
 	}
 
-	coeffsq, coeffsr := splitCoeffs(coeffs, 1<<(M-1), maxDegree)
+	coeffsq, coeffsr := polyEval.splitCoeffs(coeffs, 1<<(M-1), maxDegree)
 
-	res = recurse(maxDegree-(1<<(M-1)), L, M-1, coeffsq, C, evaluator, evakey)
+	polyEval.computePowerBasis(1 << (M - 1))
 
-	var tmp *Ciphertext
-	tmp = recurse((1<<(M-1))-1, L, M-1, coeffsr, C, evaluator, evakey)
+	levelq := polyEval.maxLevel(maxDegree-(1<<(M-1)), M-1, coeffsq)
+	levelr := polyEval.maxLevel((1<<(M-1))-1, M-1, coeffsr)
 
-	evaluator.MulRelin(res, C[1<<(M-1)], evakey, res)
+	if levelq == 0 {
+		panic("cannot EvaluatePoly : input level is too small for the degree of the polynomial")
+	}
 
-	evaluator.Add(res, tmp, res)
+	return utils.MinUint64(utils.MinUint64(polyEval.C[1<<(M-1)].Level(), levelq-1), levelr)
+}
 
-	evaluator.Rescale(res, evaluator.ckksContext.scale, res)
+// recurse recursively evaluates the polynomial p = q*C[2^(M-1)] + r and returns the result, which is not rescaled, at
+// exactly the given level and scale. The quotient q is evaluated one level above at the scale that gives the target
+// scale once rescaled and multiplied by C[2^(M-1)], and the remainder r is directly evaluated at the target level and
+// scale, so that both terms are added without error.
+func (polyEval *polynomialEvaluator) recurse(targetLevel uint64, targetScale float64, maxDegree, M uint64, coeffs map[uint64]complex128) (res *Ciphertext) {
 
-	return res
+	if maxDegree <= (1 << polyEval.L) {
+		return polyEval.evaluatePolyFromPowerBasis(targetLevel, targetScale, coeffs)
+	}
+
+	for 1<<(M-1) > maxDegree {
+		M--
+	}
+
+	coeffsq, coeffsr := polyEval.splitCoeffs(coeffs, 1<<(M-1), maxDegree)
+
+	X := polyEval.C[1<<(M-1)]
 
+	qi := float64(polyEval.ckksContext.contextQ.Modulus[targetLevel+1])
+
+	res = polyEval.recurse(targetLevel+1, targetScale*qi/X.Scale(), maxDegree-(1<<(M-1)), M-1, coeffsq)
+
+	polyEval.RescaleMany(res, 1, res)
+
+	polyEval.MulRelin(res, X, polyEval.evakey, res)
+
+	res.SetScale(targetScale)
+
+	tmp := polyEval.recurse(targetLevel, targetScale, (1<<(M-1))-1, M-1, coeffsr)
+
+	polyEval.Add(res, tmp, res)
+
+	return res
 }
 
-func evaluatePolyFromPowerBasis(coeffs map[uint64]complex128, C map[uint64]*Ciphertext, evaluator *evaluator, evakey *EvaluationKey) (res *Ciphertext) {
+// evaluatePolyFromPowerBasis evaluates the polynomial, of degree at most the number of baby steps, as a linear
+// combination of the power basis and returns the result at exactly the given level and scale: each element of the
+// power basis is multiplied by its coefficient scaled by the ratio between the target scale and its scale.
+func (polyEval *polynomialEvaluator) evaluatePolyFromPowerBasis(targetLevel uint64, targetScale float64, coeffs map[uint64]complex128) (res *Ciphertext) {
 
-	res = NewCiphertext(evaluator.params, 1, C[1].Level(), C[1].Scale())
+	res = NewCiphertext(polyEval.params, 1, targetLevel, targetScale)
 
-	if math.Abs(real(coeffs[0])) > 1e-15 || math.Abs(imag(coeffs[0])) > 1e-15 {
-		evaluator.AddConst(res, coeffs[0], res)
+	if coeffs[0] != 0 {
+		polyEval.AddConst(res, coeffs[0], res)
 	}
 
 	for key := range coeffs {
-		if key != 0 && (math.Abs(real(coeffs[key])) > 1e-15 || math.Abs(imag(coeffs[key])) > 1e-15) {
-			evaluator.MultByConstAndAdd(C[key], coeffs[key], res)
+		if key != 0 && coeffs[key] != 0 {
+			polyEval.multByConstAndAdd(targetLevel, polyEval.C[key], real(coeffs[key]), imag(coeffs[key]), targetScale/polyEval.C[key].Scale(), res)
 		}
 	}
 
-	evaluator.Rescale(res, evaluator.ckksContext.scale, res)
-
 	return
 }
@@ -0,0 +1,127 @@
+package ckks
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// PrecisionStats is a struct storing statistics about the precision of approximate values with respect to their
+// expected values. The real part of each field refers to the real part of the values, and the imaginary part to their
+// imaginary part. The precisions are given in bits, as log2(1/delta), where delta is the absolute error.
+type PrecisionStats struct {
+	MinPrecision    complex128
+	MaxPrecision    complex128
+	MeanPrecision   complex128
+	MedianPrecision complex128
+
+	MinDelta    complex128
+	MaxDelta    complex128
+	MeanDelta   complex128
+	MedianDelta complex128
+
+	// Distributions of the precision of the real and imaginary parts, in bins of one bit
+	RealDist []PrecisionBin
+	ImagDist []PrecisionBin
+}
+
+// PrecisionBin is a bin of the distribution of the precision: Count values have a precision in [Prec, Prec+1) bits.
+// The values without error are counted in the bin of precision +Inf.
+type PrecisionBin struct {
+	Prec  float64
+	Count uint64
+}
+
+// GetPrecisionStats computes the statistics of the precision of valuesTest (e.g. decrypted and decoded values) with respect
+// to valuesWant.
+func GetPrecisionStats(valuesWant, valuesTest []complex128) (prec PrecisionStats) {
+
+	if len(valuesWant) != len(valuesTest) {
+		panic("cannot GetPrecisionStats : valuesWant and valuesTest must have the same length")
+	}
+
+	if len(valuesWant) == 0 {
+		panic("cannot GetPrecisionStats : no values")
+	}
+
+	deltaReal := make([]float64, len(valuesWant))
+	deltaImag := make([]float64, len(valuesWant))
+
+	distribReal := make(map[float64]uint64)
+	distribImag := make(map[float64]uint64)
+
+	var sumReal, sumImag float64
+
+	for i := range valuesWant {
+
+		deltaReal[i] = math.Abs(real(valuesTest[i]) - real(valuesWant[i]))
+		deltaImag[i] = math.Abs(imag(valuesTest[i]) - imag(valuesWant[i]))
+
+		sumReal += deltaReal[i]
+		sumImag += deltaImag[i]
+
+		distribReal[math.Floor(bitPrecision(deltaReal[i]))]++
+		distribImag[math.Floor(bitPrecision(deltaImag[i]))]++
+	}
+
+	sort.Float64s(deltaReal)
+	sort.Float64s(deltaImag)
+
+	prec.MinDelta = complex(deltaReal[0], deltaImag[0])
+	prec.MaxDelta = complex(deltaReal[len(deltaReal)-1], deltaImag[len(deltaImag)-1])
+	prec.MeanDelta = complex(sumReal/float64(len(deltaReal)), sumImag/float64(len(deltaImag)))
+	prec.MedianDelta = complex(median(deltaReal), median(deltaImag))
+
+	// The minimum precision is given by the largest error and the maximum precision by the smallest error
+	prec.MinPrecision = deltaToPrecision(prec.MaxDelta)
+	prec.MaxPrecision = deltaToPrecision(prec.MinDelta)
+	prec.MeanPrecision = deltaToPrecision(prec.MeanDelta)
+	prec.MedianPrecision = deltaToPrecision(prec.MedianDelta)
+
+	prec.RealDist = sortedDistribution(distribReal)
+	prec.ImagDist = sortedDistribution(distribImag)
+
+	return prec
+}
+
+// String returns a printable summary of the PrecisionStats.
+func (prec PrecisionStats) String() string {
+	return fmt.Sprintf("Minimum precision : (%.2f, %.2f) bits \n", real(prec.MinPrecision), imag(prec.MinPrecision)) +
+		fmt.Sprintf("Maximum precision : (%.2f, %.2f) bits \n", real(prec.MaxPrecision), imag(prec.MaxPrecision)) +
+		fmt.Sprintf("Mean    precision : (%.2f, %.2f) bits \n", real(prec.MeanPrecision), imag(prec.MeanPrecision)) +
+		fmt.Sprintf("Median  precision : (%.2f, %.2f) bits \n", real(prec.MedianPrecision), imag(prec.MedianPrecision))
+}
+
+// bitPrecision returns log2(1/delta).
+func bitPrecision(delta float64) float64 {
+	return math.Log2(1 / delta)
+}
+
+func deltaToPrecision(delta complex128) complex128 {
+	return complex(bitPrecision(real(delta)), bitPrecision(imag(delta)))
+}
+
+// median returns the median of a sorted slice.
+func median(values []float64) float64 {
+
+	index := len(values) / 2
+
+	if len(values)&1 == 1 {
+		return values[index]
+	}
+
+	return (values[index-1] + values[index]) / 2
+}
+
+func sortedDistribution(distrib map[float64]uint64) (bins []PrecisionBin) {
+
+	bins = make([]PrecisionBin, 0, len(distrib))
+
+	for prec, count := range distrib {
+		bins = append(bins, PrecisionBin{Prec: prec, Count: count})
+	}
+
+	sort.Slice(bins, func(i, j int) bool { return bins[i].Prec < bins[j].Prec })
+
+	return bins
+}
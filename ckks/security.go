@@ -0,0 +1,88 @@
+package ckks
+
+import (
+	"fmt"
+	"github.com/ldsec/lattigo/ring"
+	"github.com/ldsec/lattigo/utils"
+)
+
+// logMessage is the bit-size of the largest absolute value of the messages supported by the parameters returned by
+// GenParameters, which is the difference between the bit-size of the modulus q0 and the log2 of the scale.
+const logMessage = 10
+
+// rescaleNoiseBits is a bound on the number of bits, in addition to LogN/2, of the error added to the messages by
+// the encryption and by each multiplication followed by a relinearization and a rescaling.
+const rescaleNoiseBits = 10
+
+// SecurityLevel returns the highest security level (128, 192 or 256 bits) ensured by the parameters for the uniform
+// ternary secrets generated by GenSecretKey (see ring.SecurityLevel). It returns 0 if the parameters ensure none of
+// these security levels, and ring.ErrSecurityNotEstimated if their ring degree is not covered by the Homomorphic
+// Encryption Standard (LogN larger than 15).
+func SecurityLevel(params *Parameters) (uint64, error) {
+
+	if !params.isValid {
+		panic("cannot SecurityLevel: params not valid (check if they were generated properly)")
+	}
+
+	return ring.SecurityLevel(params.LogN, ring.Log2OfProduct(params.Qi, params.Pi))
+}
+
+// GenParameters returns the parameters with the smallest ring degree that ensure the given security level (128, 192
+// or 256 bits, see SecurityLevel) and enough moduli to evaluate a circuit of the given multiplicative depth on messages
+// of absolute value at most 2^10, with at least logPrecision bits of precision after the decimal point for most of the
+// values (see GetPrecisionStats). Each level of the circuit consumes one modulus of log2(Scale) bits, where the scale
+// is large enough for the error added by the encryption and by the multiplications to stay below 2^-logPrecision.
+// The returned parameters use all the slots. As the security is only estimated up to LogN = 15, their ring degree is
+// at most 2^15.
+func GenParameters(depth, logPrecision, securityLevel uint64) (params *Parameters, err error) {
+
+	if _, err = ring.MaxLogQP(ring.MaxSecurityLogN, securityLevel); err != nil {
+		return nil, fmt.Errorf("cannot GenParameters: unsupported security level of %d bits", securityLevel)
+	}
+
+	for logN := uint64(1); logN <= MaxLogN; logN++ {
+
+		maxLogQP, errLogN := ring.MaxLogQP(logN, securityLevel)
+		if errLogN != nil {
+			continue
+		}
+
+		logScale := logPrecision + logN/2 + rescaleNoiseBits
+
+		if logScale+logMessage > MaxModuliSize || depth+1 > MaxModuliCount {
+			continue
+		}
+
+		logModuli := genLogModuli(logScale, depth)
+
+		if utils.SumUint64(logModuli.LogQi)+utils.SumUint64(logModuli.LogPi) > maxLogQP {
+			continue
+		}
+
+		params = NewParametersFromLogModuli(logN, logN-1, float64(uint64(1)<<logScale), logModuli, 3.2)
+
+		if level, _ := SecurityLevel(params); level >= securityLevel {
+			return params, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot GenParameters: no parameters ensure %d bits of security for a depth of %d with %d bits of precision", securityLevel, depth, logPrecision)
+}
+
+// genLogModuli returns the modulus q0 of logScale + logMessage bits, followed by one modulus of logScale bits per level,
+// along with the moduli Pi (one for every six moduli Qi, each at least as large as the moduli Qi).
+func genLogModuli(logScale, depth uint64) (logModuli LogModuli) {
+
+	logModuli.LogQi = make([]uint64, depth+1)
+	logModuli.LogQi[0] = logScale + logMessage
+	for i := uint64(1); i < depth+1; i++ {
+		logModuli.LogQi[i] = logScale
+	}
+
+	logModuli.LogPi = make([]uint64, (depth+6)/6)
+	for i := range logModuli.LogPi {
+		logModuli.LogPi[i] = logScale + logMessage
+	}
+
+	return
+}
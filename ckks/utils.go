@@ -2,6 +2,7 @@ package ckks
 
 import (
 	"github.com/ldsec/lattigo/ring"
+	"math"
 	"math/big"
 	"math/cmplx"
 	"math/rand"
@@ -57,7 +58,7 @@ func scaleUpVecExact(values []float64, n float64, moduli []uint64, coeffs [][]ui
 
 	for i := range values {
 
-		if n*values[i] > 1.8446744073709552e+19 {
+		if math.Abs(n*values[i]) > 1.8446744073709552e+19 {
 
 			isNegative = false
 			if values[i] < 0 {
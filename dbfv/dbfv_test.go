@@ -68,6 +68,7 @@ func Test_DBFV(t *testing.T) {
 	t.Run("RotKeyGenRotRows", testRotKeyGenRotRows)
 	t.Run("RotKeyGenRotCols", testRotKeyGenRotCols)
 	t.Run("Refresh", testRefresh)
+	t.Run("Threshold", testThreshold)
 
 }
 
@@ -1004,3 +1005,92 @@ func Test_Relin_Marshalling(t *testing.T) {
 	})
 
 }
+
+func testThreshold(t *testing.T) {
+
+	parties := testParams.parties
+	threshold := parties - 1
+
+	for _, parameters := range testParams.contexts {
+
+		testCtx := genDBFVTestContext(parameters)
+
+		sk0Shards := testCtx.sk0Shards
+		encryptorPk0 := testCtx.encryptorPk0
+
+		t.Run(testString(fmt.Sprintf("threshold=%d/", threshold), parties, parameters), func(t *testing.T) {
+
+			type Party struct {
+				*Thresholdizer
+				*Combiner
+				*CKSProtocol
+				pk         ShamirPublicKey
+				secretPoly *ShamirPolynomial
+				tsk        ShamirSecretShare
+				tmp        ShamirSecretShare
+				sk         *ring.Poly
+				share      CKSShare
+			}
+
+			thresholdParties := make([]*Party, parties)
+			for i := uint64(0); i < parties; i++ {
+				p := new(Party)
+				p.Thresholdizer = NewThresholdizer(parameters)
+				p.Combiner = NewCombiner(parameters, threshold)
+				p.CKSProtocol = NewCKSProtocol(parameters, 6.36)
+				p.pk = ShamirPublicKey(i + 1)
+				p.secretPoly = p.GenShamirPolynomial(threshold, sk0Shards[i].Get())
+				p.tsk = p.AllocateShamirSecretShare()
+				p.tmp = p.AllocateShamirSecretShare()
+				p.sk = testCtx.contextQP.NewPoly()
+				p.share = p.CKSProtocol.AllocateShare()
+				thresholdParties[i] = p
+			}
+
+			// Each party sends to each other party the evaluation of its ShamirPolynomial at the recipient's public point
+			for _, recipient := range thresholdParties {
+				for _, sender := range thresholdParties {
+					sender.GenShamirSecretShare(recipient.pk, sender.secretPoly, recipient.tmp)
+					recipient.Thresholdizer.AggregateShares(recipient.tsk, recipient.tmp, recipient.tsk)
+				}
+			}
+
+			// The last party drops out
+			activeParties := thresholdParties[:threshold]
+			activePoints := make([]ShamirPublicKey, threshold)
+			for i, p := range activeParties {
+				activePoints[i] = p.pk
+			}
+
+			skWant := testCtx.contextQP.NewPoly()
+			for _, p := range activeParties {
+				p.GenAdditiveShare(activePoints, p.pk, p.tsk, p.sk)
+				testCtx.contextQP.Add(skWant, p.sk, skWant)
+			}
+
+			if !testCtx.contextQP.Equal(skWant, testCtx.sk0.Get()) {
+				t.Errorf("additive shares do not sum to the collective secret-key")
+			}
+
+			// Collective decryption with the active parties: key-switching to the zero secret-key
+			coeffs, _, ciphertext := newTestVectors(testCtx, encryptorPk0, t)
+
+			zero := testCtx.contextQP.NewPoly()
+			P0 := activeParties[0]
+			for i, p := range activeParties {
+				p.CKSProtocol.GenShare(p.sk, zero, ciphertext, p.share)
+				if i > 0 {
+					P0.CKSProtocol.AggregateShares(p.share, P0.share, P0.share)
+				}
+			}
+
+			ksCiphertext := bfv.NewCiphertext(parameters, 1)
+			P0.KeySwitch(P0.share, ciphertext, ksCiphertext)
+
+			skZero := new(bfv.SecretKey)
+			skZero.Set(zero)
+
+			verifyTestVectors(testCtx, bfv.NewDecryptor(parameters, skZero), coeffs, ksCiphertext, t)
+		})
+	}
+}
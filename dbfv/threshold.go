@@ -0,0 +1,192 @@
+package dbfv
+
+import (
+	"github.com/ldsec/lattigo/bfv"
+	"github.com/ldsec/lattigo/ring"
+	"math/big"
+)
+
+// ShamirPublicKey is the public point of a party in the Shamir secret-sharing scheme. Each of the N parties
+// must be identified by a distinct and non-zero ShamirPublicKey.
+type ShamirPublicKey uint64
+
+// ShamirPolynomial is a polynomial of degree threshold-1 with coefficients in R_QP, whose constant coefficient is
+// the secret-key share of the party that generated it. It must be kept secret.
+type ShamirPolynomial struct {
+	coeffs []*ring.Poly
+}
+
+// ShamirSecretShare is a struct holding a share of the Shamir secret-sharing protocol. Once aggregated, it is the
+// t-out-of-N share of the collective secret-key of the party.
+type ShamirSecretShare struct {
+	*ring.Poly
+}
+
+// MarshalBinary encodes the target ShamirSecretShare on a slice of bytes.
+func (share *ShamirSecretShare) MarshalBinary() ([]byte, error) {
+	return share.Poly.MarshalBinary()
+}
+
+// UnmarshalBinary decodes a marshaled ShamirSecretShare on the target ShamirSecretShare.
+func (share *ShamirSecretShare) UnmarshalBinary(data []byte) error {
+	if share.Poly == nil {
+		share.Poly = new(ring.Poly)
+	}
+	err := share.Poly.UnmarshalBinary(data)
+	return err
+}
+
+// Thresholdizer is the structure storing the parameters for a party in the threshold secret-sharing protocol. This protocol
+// turns the N-out-of-N additive shares of the collective secret-key into t-out-of-N Shamir shares:
+//
+// - each party i generates a ShamirPolynomial P_i of degree t-1 with P_i(0) = s_i,
+//
+// - each party i sends P_i(pk_j) to each party j, where pk_j is the ShamirPublicKey of party j,
+//
+// - each party j aggregates the received shares, obtaining a Shamir share sum(P_i(pk_j)) of the collective secret-key sum(s_i).
+type Thresholdizer struct {
+	context *ring.Context
+}
+
+// NewThresholdizer creates a new Thresholdizer instance.
+func NewThresholdizer(params *bfv.Parameters) *Thresholdizer {
+
+	if !params.IsValid() {
+		panic("cannot NewThresholdizer : params not valid (check if they where generated properly)")
+	}
+
+	thresholdizer := new(Thresholdizer)
+	thresholdizer.context = newDbfvContext(params).contextQP
+	return thresholdizer
+}
+
+// GenShamirPolynomial generates a new secret ShamirPolynomial of degree threshold-1, with uniformly random
+// coefficients and whose constant coefficient is the input secret-key share.
+func (thresholdizer *Thresholdizer) GenShamirPolynomial(threshold uint64, sk *ring.Poly) *ShamirPolynomial {
+
+	if threshold == 0 {
+		panic("cannot GenShamirPolynomial : threshold must be at least 1")
+	}
+
+	secretPoly := new(ShamirPolynomial)
+	secretPoly.coeffs = make([]*ring.Poly, threshold)
+	secretPoly.coeffs[0] = sk.CopyNew()
+	for i := uint64(1); i < threshold; i++ {
+		secretPoly.coeffs[i] = thresholdizer.context.NewUniformPoly()
+	}
+
+	return secretPoly
+}
+
+// AllocateShamirSecretShare allocates a ShamirSecretShare.
+func (thresholdizer *Thresholdizer) AllocateShamirSecretShare() ShamirSecretShare {
+	return ShamirSecretShare{thresholdizer.context.NewPoly()}
+}
+
+// GenShamirSecretShare evaluates the secret ShamirPolynomial at the ShamirPublicKey of the recipient, generating
+// the share to be sent to the recipient.
+func (thresholdizer *Thresholdizer) GenShamirSecretShare(recipient ShamirPublicKey, secretPoly *ShamirPolynomial, shareOut ShamirSecretShare) {
+
+	if recipient == 0 {
+		panic("cannot GenShamirSecretShare : ShamirPublicKey cannot be zero")
+	}
+
+	context := thresholdizer.context
+
+	// Horner evaluation of the ShamirPolynomial at the recipient's public point
+	context.Copy(secretPoly.coeffs[len(secretPoly.coeffs)-1], shareOut.Poly)
+	for i := len(secretPoly.coeffs) - 2; i >= 0; i-- {
+		context.MulScalar(shareOut.Poly, uint64(recipient), shareOut.Poly)
+		context.Add(shareOut.Poly, secretPoly.coeffs[i], shareOut.Poly)
+	}
+}
+
+// AggregateShares aggregates two ShamirSecretShares.
+func (thresholdizer *Thresholdizer) AggregateShares(share1, share2, shareOut ShamirSecretShare) {
+	thresholdizer.context.Add(share1.Poly, share2.Poly, shareOut.Poly)
+}
+
+// Combiner is the structure storing the parameters for a party to turn its t-out-of-N Shamir share of the collective
+// secret-key into an additive share among a set of t active parties. The additive shares of the active parties sum
+// to the collective secret-key, and can be used as the secret-key shares of the CKS, PCKS and Refresh protocols.
+type Combiner struct {
+	context   *ring.Context
+	threshold uint64
+}
+
+// NewCombiner creates a new Combiner instance for the given threshold.
+func NewCombiner(params *bfv.Parameters, threshold uint64) *Combiner {
+
+	if !params.IsValid() {
+		panic("cannot NewCombiner : params not valid (check if they where generated properly)")
+	}
+
+	if threshold == 0 {
+		panic("cannot NewCombiner : threshold must be at least 1")
+	}
+
+	combiner := new(Combiner)
+	combiner.context = newDbfvContext(params).contextQP
+	combiner.threshold = threshold
+	return combiner
+}
+
+// GenAdditiveShare computes the additive share of the party with ShamirPublicKey ownPoint for the set of active
+// parties activePoints (which must contain exactly threshold distinct keys, including ownPoint), by multiplying
+// its ShamirSecretShare by the corresponding Lagrange coefficient.
+func (combiner *Combiner) GenAdditiveShare(activePoints []ShamirPublicKey, ownPoint ShamirPublicKey, ownShare ShamirSecretShare, skOut *ring.Poly) {
+
+	if uint64(len(activePoints)) != combiner.threshold {
+		panic("cannot GenAdditiveShare : the number of active parties must be equal to the threshold")
+	}
+
+	lagrange := combiner.lagrangeCoefficient(activePoints, ownPoint)
+
+	combiner.context.MulScalarBigint(ownShare.Poly, lagrange, skOut)
+}
+
+// lagrangeCoefficient returns prod(pk_k/(pk_k - ownPoint)) mod QP for all the pk_k in activePoints different from ownPoint.
+func (combiner *Combiner) lagrangeCoefficient(activePoints []ShamirPublicKey, ownPoint ShamirPublicKey) *big.Int {
+
+	modulus := combiner.context.ModulusBigint
+
+	num := ring.NewUint(1)
+	den := ring.NewUint(1)
+	tmp := new(big.Int)
+
+	found := false
+	for i, pk := range activePoints {
+
+		if pk == 0 {
+			panic("cannot GenAdditiveShare : ShamirPublicKey cannot be zero")
+		}
+
+		for _, other := range activePoints[:i] {
+			if pk == other {
+				panic("cannot GenAdditiveShare : active parties ShamirPublicKeys must be distinct")
+			}
+		}
+
+		if pk == ownPoint {
+			found = true
+			continue
+		}
+
+		num.Mul(num, ring.NewUint(uint64(pk)))
+		den.Mul(den, tmp.Sub(ring.NewUint(uint64(pk)), ring.NewUint(uint64(ownPoint))))
+	}
+
+	if !found {
+		panic("cannot GenAdditiveShare : own ShamirPublicKey must be among the active parties")
+	}
+
+	den.Mod(den, modulus)
+	if den.ModInverse(den, modulus) == nil {
+		panic("cannot GenAdditiveShare : ShamirPublicKeys differences are not invertible modulo QP")
+	}
+
+	num.Mul(num, den)
+	num.Mod(num, modulus)
+
+	return num
+}
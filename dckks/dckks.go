@@ -28,6 +28,7 @@ func newDckksContext(params *ckks.Parameters) (context *dckksContext) {
 	}
 
 	context = new(dckksContext)
+	var err error
 
 	context.params = params.Copy()
 
@@ -2,8 +2,6 @@ package dckks
 
 import (
 	"fmt"
-	"math"
-	"sort"
 	"testing"
 
 	"github.com/ldsec/lattigo/ckks"
@@ -77,6 +75,7 @@ func TestDCKKS(t *testing.T) {
 	t.Run("RotKeyGenConjugate", testRotKeyGenConjugate)
 	t.Run("RotKeyGenCols", testRotKeyGenCols)
 	t.Run("Refresh", testRefresh)
+	t.Run("Threshold", testThreshold)
 }
 
 func gendckksTestContext(contextParameters *ckks.Parameters) (params *dckksTestContext) {
@@ -698,99 +697,121 @@ func verifyTestVectors(contextParams *dckksTestContext, decryptor ckks.Decryptor
 
 	valuesTest = contextParams.encoder.Decode(plaintextTest, slots)
 
-	var deltaReal, deltaImag float64
+	precStats := ckks.GetPrecisionStats(valuesWant, valuesTest)
 
-	var minprec, maxprec, meanprec, medianprec complex128
-
-	diff := make([]complex128, slots)
-
-	minprec = complex(0, 0)
-	maxprec = complex(1, 1)
-
-	meanprec = complex(0, 0)
-
-	distribReal := make(map[uint64]uint64)
-	distribImag := make(map[uint64]uint64)
-
-	for i := range valuesWant {
-
-		deltaReal = math.Abs(real(valuesTest[i]) - real(valuesWant[i]))
-		deltaImag = math.Abs(imag(valuesTest[i]) - imag(valuesWant[i]))
+	if testParams.verbose {
+		t.Log(precStats.String())
+	}
 
-		diff[i] += complex(deltaReal, 0)
-		diff[i] += complex(0, deltaImag)
+	if real(precStats.MedianPrecision) < testParams.medianprec || imag(precStats.MedianPrecision) < testParams.medianprec {
+		t.Errorf("Median precision error: target (%.2f, %.2f) > result (%.2f, %.2f)", testParams.medianprec, testParams.medianprec, real(precStats.MedianPrecision), imag(precStats.MedianPrecision))
+	}
+}
 
-		meanprec += diff[i]
+func testThreshold(t *testing.T) {
 
-		if real(diff[i]) > real(minprec) {
-			minprec = complex(real(diff[i]), 0)
-		}
+	parties := testParams.parties
+	threshold := parties - 1
 
-		if imag(diff[i]) > imag(minprec) {
-			minprec = complex(real(minprec), imag(diff[i]))
-		}
+	for _, parameters := range testParams.ckksParameters {
 
-		if real(diff[i]) < real(maxprec) {
-			maxprec = complex(real(diff[i]), 0)
-		}
+		params := gendckksTestContext(parameters)
 
-		if imag(diff[i]) < imag(maxprec) {
-			maxprec = complex(real(maxprec), imag(diff[i]))
-		}
+		encryptorPk0 := params.encryptorPk0
+		sk0Shards := params.sk0Shards
+		contextQP := params.dckksContext.contextQP
 
-		distribReal[uint64(math.Floor(math.Log2(1/real(diff[i]))))]++
-		distribImag[uint64(math.Floor(math.Log2(1/imag(diff[i]))))]++
-	}
+		t.Run(testString(fmt.Sprintf("threshold=%d/", threshold), parties, parameters), func(t *testing.T) {
 
-	meanprec /= complex(float64(slots), 0)
-	medianprec = calcmedian(diff)
+			type Party struct {
+				*Thresholdizer
+				*Combiner
+				*CKSProtocol
+				pk         ShamirPublicKey
+				secretPoly *ShamirPolynomial
+				tsk        ShamirSecretShare
+				tmp        ShamirSecretShare
+				sk         *ring.Poly
+				share      CKSShare
+			}
 
-	if testParams.verbose {
-		t.Logf("Minimum precision : (%.2f, %.2f) bits \n", math.Log2(1/real(minprec)), math.Log2(1/imag(minprec)))
-		t.Logf("Maximum precision : (%.2f, %.2f) bits \n", math.Log2(1/real(maxprec)), math.Log2(1/imag(maxprec)))
-		t.Logf("Mean    precision : (%.2f, %.2f) bits \n", math.Log2(1/real(meanprec)), math.Log2(1/imag(meanprec)))
-		t.Logf("Median  precision : (%.2f, %.2f) bits \n", math.Log2(1/real(medianprec)), math.Log2(1/imag(medianprec)))
-		t.Log()
-	}
+			thresholdParties := make([]*Party, parties)
+			for i := uint64(0); i < parties; i++ {
+				p := new(Party)
+				p.Thresholdizer = NewThresholdizer(parameters)
+				p.Combiner = NewCombiner(parameters, threshold)
+				p.CKSProtocol = NewCKSProtocol(parameters, 6.36)
+				p.pk = ShamirPublicKey(i + 1)
+				p.secretPoly = p.GenShamirPolynomial(threshold, sk0Shards[i].Get())
+				p.tsk = p.AllocateShamirSecretShare()
+				p.tmp = p.AllocateShamirSecretShare()
+				p.sk = contextQP.NewPoly()
+				p.share = p.CKSProtocol.AllocateShare()
+				thresholdParties[i] = p
+			}
+
+			// Each party sends to each other party the evaluation of its ShamirPolynomial at the recipient's public point
+			for _, recipient := range thresholdParties {
+				for _, sender := range thresholdParties {
+					sender.GenShamirSecretShare(recipient.pk, sender.secretPoly, recipient.tmp)
+					recipient.Thresholdizer.AggregateShares(recipient.tsk, recipient.tmp, recipient.tsk)
+				}
+			}
 
-	if math.Log2(1/real(medianprec)) < testParams.medianprec || math.Log2(1/imag(medianprec)) < testParams.medianprec {
-		t.Errorf("Mean precision error : target (%.2f, %.2f) > result (%.2f, %.2f)", testParams.medianprec, testParams.medianprec, math.Log2(1/real(medianprec)), math.Log2(1/imag(medianprec)))
-	}
-}
+			// The aggregated shares can be marshaled, e.g. to be stored by the parties until the combining phase
+			for _, p := range thresholdParties {
 
-func calcmedian(values []complex128) (median complex128) {
+				data, err := p.tsk.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
 
-	tmp := make([]float64, len(values))
+				tskTest := new(ShamirSecretShare)
+				if err = tskTest.UnmarshalBinary(data); err != nil {
+					t.Fatal(err)
+				}
 
-	for i := range values {
-		tmp[i] = real(values[i])
-	}
+				if !contextQP.Equal(p.tsk.Poly, tskTest.Poly) {
+					t.Errorf("error : ShamirSecretShare marshaling")
+				}
+			}
 
-	sort.Float64s(tmp)
+			// The last party drops out
+			activeParties := thresholdParties[:threshold]
+			activePoints := make([]ShamirPublicKey, threshold)
+			for i, p := range activeParties {
+				activePoints[i] = p.pk
+			}
 
-	for i := range values {
-		values[i] = complex(tmp[i], imag(values[i]))
-	}
+			skWant := contextQP.NewPoly()
+			for _, p := range activeParties {
+				p.GenAdditiveShare(activePoints, p.pk, p.tsk, p.sk)
+				contextQP.Add(skWant, p.sk, skWant)
+			}
 
-	for i := range values {
-		tmp[i] = imag(values[i])
-	}
+			if !contextQP.Equal(skWant, params.sk0.Get()) {
+				t.Errorf("additive shares do not sum to the collective secret-key")
+			}
 
-	sort.Float64s(tmp)
+			// Collective decryption with the active parties: key-switching to the zero secret-key
+			coeffs, _, ciphertext := newTestVectors(params, encryptorPk0, 1, t)
 
-	for i := range values {
-		values[i] = complex(real(values[i]), tmp[i])
-	}
+			zero := contextQP.NewPoly()
+			P0 := activeParties[0]
+			for i, p := range activeParties {
+				p.CKSProtocol.GenShare(p.sk, zero, ciphertext, p.share)
+				if i > 0 {
+					P0.CKSProtocol.AggregateShares(p.share, P0.share, P0.share)
+				}
+			}
 
-	index := len(values) / 2
+			ksCiphertext := ckks.NewCiphertext(parameters, 1, ciphertext.Level(), ciphertext.Scale())
+			P0.KeySwitch(P0.share, ciphertext, ksCiphertext)
 
-	if len(values)&1 == 1 {
-		return values[index]
-	}
+			skZero := new(ckks.SecretKey)
+			skZero.Set(zero)
 
-	if index+1 == len(values) {
-		return values[index]
+			verifyTestVectors(params, ckks.NewDecryptor(parameters, skZero), coeffs, ksCiphertext, t)
+		})
 	}
-
-	return (values[index] + values[index+1]) / 2
 }
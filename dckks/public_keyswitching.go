@@ -23,6 +23,49 @@ type PCKSProtocol struct {
 // PCKSShare is a struct storing the share of the PCKS protocol.
 type PCKSShare [2]*ring.Poly
 
+// MarshalBinary encodes a PCKS share on a slice of bytes.
+func (share *PCKSShare) MarshalBinary() ([]byte, error) {
+	lenR1 := share[0].GetDataLen(true)
+	lenR2 := share[1].GetDataLen(true)
+
+	data := make([]byte, lenR1+lenR2)
+	_, err := share[0].WriteTo(data[0:lenR1])
+	if err != nil {
+		return []byte{}, err
+	}
+
+	_, err = share[1].WriteTo(data[lenR1 : lenR1+lenR2])
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes marshaled PCKS share on the target PCKS share.
+func (share *PCKSShare) UnmarshalBinary(data []byte) error {
+
+	if share[0] == nil {
+		share[0] = new(ring.Poly)
+	}
+
+	if share[1] == nil {
+		share[1] = new(ring.Poly)
+	}
+
+	err := share[0].UnmarshalBinary(data[0 : len(data)/2])
+	if err != nil {
+		return err
+	}
+
+	err = share[1].UnmarshalBinary(data[len(data)/2:])
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // NewPCKSProtocol creates a new PCKSProtocol object and will be used to re-encrypt a ciphertext ctx encrypted under a secret-shared key mong j parties under a new
 // collective public-key.
 func NewPCKSProtocol(params *ckks.Parameters, sigmaSmudging float64) *PCKSProtocol {
@@ -1,6 +1,8 @@
 package dckks
 
 import (
+	"errors"
+
 	"github.com/ldsec/lattigo/ckks"
 	"github.com/ldsec/lattigo/ring"
 )
@@ -20,6 +22,149 @@ type RKGShareRoundTwo [][2]*ring.Poly
 // RKGShareRoundThree is a struct storing the round three share of the RKG protocol.
 type RKGShareRoundThree []*ring.Poly
 
+// MarshalBinary encodes the target element on a slice of bytes.
+func (share *RKGShareRoundOne) MarshalBinary() ([]byte, error) {
+	rLength := (*share)[0].GetDataLen(true)
+	data := make([]byte, 1+rLength*uint64(len(*share)))
+	data[0] = uint8(len(*share))
+
+	pointer := uint64(1)
+	for _, s := range *share {
+		tmp, err := s.WriteTo(data[pointer : pointer+rLength])
+		if err != nil {
+			return []byte{}, err
+		}
+		pointer += tmp
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a slice of bytes on the target element.
+func (share *RKGShareRoundOne) UnmarshalBinary(data []byte) error {
+	//share.modulus = data[0]
+	lenShare := data[0]
+	rLength := len(data[1:]) / int(lenShare)
+	if *share == nil {
+		*share = make([]*ring.Poly, lenShare)
+	}
+	ptr := 1
+	for i := uint8(0); i < lenShare; i++ {
+		if (*share)[i] == nil {
+			(*share)[i] = new(ring.Poly)
+		}
+		err := (*share)[i].UnmarshalBinary(data[ptr : ptr+rLength])
+		if err != nil {
+			return err
+		}
+		ptr += rLength
+	}
+
+	return nil
+}
+
+// MarshalBinary encodes the target element on a slice of bytes.
+func (share *RKGShareRoundTwo) MarshalBinary() ([]byte, error) {
+	//we have modulus * bitLog * Len of 1 ring rings
+	rLength := ((*share)[0])[0].GetDataLen(true)
+	data := make([]byte, 1+2*rLength*uint64(len(*share)))
+	if len(*share) > 0xFF {
+		return []byte{}, errors.New("RKGShareRoundTwo : uint8 overflow on length")
+	}
+	data[0] = uint8(len(*share))
+
+	//write all of our rings in the data.
+	//write all the polys
+	ptr := uint64(1)
+	for _, elem := range *share {
+		_, err := elem[0].WriteTo(data[ptr : ptr+rLength])
+		if err != nil {
+			return []byte{}, err
+		}
+		ptr += rLength
+		_, err = elem[1].WriteTo(data[ptr : ptr+rLength])
+		if err != nil {
+			return []byte{}, err
+		}
+		ptr += rLength
+	}
+
+	return data, nil
+
+}
+
+// UnmarshalBinary decodes a slice of bytes on the target element.
+func (share *RKGShareRoundTwo) UnmarshalBinary(data []byte) error {
+	lenShare := data[0]
+	rLength := (len(data) - 1) / (2 * int(lenShare))
+
+	if *share == nil {
+		*share = make([][2]*ring.Poly, lenShare)
+	}
+	ptr := (1)
+	for i := (0); i < int(lenShare); i++ {
+		if (*share)[i][0] == nil || (*share)[i][1] == nil {
+			(*share)[i][0] = new(ring.Poly)
+			(*share)[i][1] = new(ring.Poly)
+		}
+
+		err := (*share)[i][0].UnmarshalBinary(data[ptr : ptr+rLength])
+		if err != nil {
+			return err
+		}
+		ptr += rLength
+		err = (*share)[i][1].UnmarshalBinary(data[ptr : ptr+rLength])
+		if err != nil {
+			return err
+		}
+		ptr += rLength
+
+	}
+
+	return nil
+}
+
+// MarshalBinary encodes the target element on a slice of bytes.
+func (share *RKGShareRoundThree) MarshalBinary() ([]byte, error) {
+	rLength := (*share)[0].GetDataLen(true)
+	data := make([]byte, 1+rLength*uint64(len(*share)))
+	data[0] = uint8(len(*share))
+
+	pointer := uint64(1)
+	for _, s := range *share {
+		tmp, err := s.WriteTo(data[pointer : pointer+rLength])
+		if err != nil {
+			return []byte{}, err
+		}
+		pointer += tmp
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a slice of bytes on the target element.
+func (share *RKGShareRoundThree) UnmarshalBinary(data []byte) error {
+	//share.modulus = data[0]
+	lenShare := data[0]
+	rLength := len(data[1:]) / int(lenShare)
+	if *share == nil {
+		*share = make([]*ring.Poly, lenShare)
+	}
+	ptr := 1
+	for i := uint8(0); i < lenShare; i++ {
+		if (*share)[i] == nil {
+			(*share)[i] = new(ring.Poly)
+		}
+		err := (*share)[i].UnmarshalBinary(data[ptr : ptr+rLength])
+		if err != nil {
+			return err
+		}
+		ptr += rLength
+	}
+
+	return nil
+}
+
 // AllocateShares allocates the shares of the RKG protocol.
 func (ekg *RKGProtocol) AllocateShares() (r1 RKGShareRoundOne, r2 RKGShareRoundTwo, r3 RKGShareRoundThree) {
 
@@ -1,6 +1,9 @@
 package dckks
 
 import (
+	"encoding/binary"
+	"errors"
+
 	"github.com/ldsec/lattigo/ckks"
 	"github.com/ldsec/lattigo/ring"
 )
@@ -23,6 +26,50 @@ type RTGShare struct {
 	Value []*ring.Poly
 }
 
+// MarshalBinary encode the target element on a slice of byte.
+func (share *RTGShare) MarshalBinary() ([]byte, error) {
+	lenRing := share.Value[0].GetDataLen(true)
+	data := make([]byte, 3*8+lenRing*uint64(len(share.Value)))
+	binary.BigEndian.PutUint64(data[0:8], share.K)
+	binary.BigEndian.PutUint64(data[8:16], uint64(share.Type))
+	binary.BigEndian.PutUint64(data[16:24], lenRing)
+	ptr := uint64(24)
+	for _, val := range share.Value {
+		cnt, err := val.WriteTo(data[ptr : ptr+lenRing])
+		if err != nil {
+			return []byte{}, err
+		}
+		ptr += cnt
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a slice of bytes on the target element.
+func (share *RTGShare) UnmarshalBinary(data []byte) error {
+	if len(data) <= 24 {
+		return errors.New("Unsufficient data length")
+	}
+	share.K = binary.BigEndian.Uint64(data[0:8])
+	share.Type = ckks.Rotation(binary.BigEndian.Uint64(data[8:16]))
+	lenRing := binary.BigEndian.Uint64(data[16:24])
+	valLength := uint64(len(data)-3*8) / lenRing
+
+	share.Value = make([]*ring.Poly, valLength)
+	ptr := uint64(24)
+	for i := range share.Value {
+		share.Value[i] = new(ring.Poly)
+		err := share.Value[i].UnmarshalBinary(data[ptr : ptr+lenRing])
+		if err != nil {
+			return err
+		}
+		ptr += lenRing
+
+	}
+
+	return nil
+}
+
 // AllocateShare allocates the share the the RTG protocol.
 func (rtg *RTGProtocol) AllocateShare() (rtgShare RTGShare) {
 	rtgShare.Value = make([]*ring.Poly, rtg.dckksContext.beta)
@@ -5,7 +5,6 @@ import (
 	"math"
 	"math/cmplx"
 	"math/rand"
-	"sort"
 	"time"
 
 	"github.com/ldsec/lattigo/ckks"
@@ -118,103 +117,15 @@ func round(x complex128) complex128 {
 
 func verifyVector(valuesWant, valuesTest []complex128) (err error) {
 
-	var deltaReal, deltaImag float64
-
-	var minprec, maxprec, meanprec, medianprec complex128
-
-	diff := make([]complex128, len(valuesWant))
-
-	minprec = complex(0, 0)
-	maxprec = complex(1, 1)
-
-	meanprec = complex(0, 0)
-
-	distribReal := make(map[uint64]uint64)
-	distribImag := make(map[uint64]uint64)
-
-	for i := range valuesWant {
-
-		// Test the ratio for big values (> 1) and difference for small values (< 1)
-
-		deltaReal = math.Abs(real(valuesTest[i]) - real(valuesWant[i]))
-		deltaImag = math.Abs(imag(valuesTest[i]) - imag(valuesWant[i]))
-
-		diff[i] += complex(deltaReal, 0)
-		diff[i] += complex(0, deltaImag)
-
-		meanprec += diff[i]
-
-		if real(diff[i]) > real(minprec) {
-			minprec = complex(real(diff[i]), 0)
-		}
-
-		if imag(diff[i]) > imag(minprec) {
-			minprec = complex(real(minprec), imag(diff[i]))
-		}
-
-		if real(diff[i]) < real(maxprec) {
-			maxprec = complex(real(diff[i]), 0)
-		}
-
-		if imag(diff[i]) < imag(maxprec) {
-			maxprec = complex(real(maxprec), imag(diff[i]))
-		}
-
-		distribReal[uint64(math.Floor(math.Log2(1/real(diff[i]))))]++
-		distribImag[uint64(math.Floor(math.Log2(1/imag(diff[i]))))]++
-	}
-
-	meanprec /= complex(float64(len(valuesWant)), 0)
-	medianprec = calcmedian(diff)
+	precStats := ckks.GetPrecisionStats(valuesWant, valuesTest)
 
 	fmt.Println()
-	fmt.Printf("Minimum precision : (%.2f, %.2f) bits \n",
-		math.Log2(1/real(minprec)), math.Log2(1/imag(minprec)))
-	fmt.Printf("Maximum precision : (%.2f, %.2f) bits \n",
-		math.Log2(1/real(maxprec)), math.Log2(1/imag(maxprec)))
-	fmt.Printf("Mean    precision : (%.2f, %.2f) bits \n",
-		math.Log2(1/real(meanprec)), math.Log2(1/imag(meanprec)))
-	fmt.Printf("Median  precision : (%.2f, %.2f) bits \n",
-		math.Log2(1/real(medianprec)), math.Log2(1/imag(medianprec)))
+	fmt.Print(precStats.String())
 	fmt.Println()
 
 	return nil
 }
 
-func calcmedian(values []complex128) (median complex128) {
-
-	tmp := make([]float64, len(values))
-
-	for i := range values {
-		tmp[i] = real(values[i])
-	}
-
-	sort.Float64s(tmp)
-
-	for i := range values {
-		values[i] = complex(tmp[i], imag(values[i]))
-	}
-
-	for i := range values {
-		tmp[i] = imag(values[i])
-	}
-
-	sort.Float64s(tmp)
-
-	for i := range values {
-		values[i] = complex(real(values[i]), tmp[i])
-	}
-
-	index := len(values) / 2
-
-	if len(values)&1 == 1 {
-		return values[index]
-	}
-
-	return (values[index] + values[index+1]) / 2
-
-}
-
 func main() {
 	chebyshevinterpolation()
 }
@@ -0,0 +1,124 @@
+package network
+
+import (
+	"github.com/ldsec/lattigo/bfv"
+	"github.com/ldsec/lattigo/dbfv"
+	"github.com/ldsec/lattigo/ring"
+)
+
+// RunBFVCKG executes the collective public-key generation protocol of the dbfv package over the network, given the
+// secret-key share sk of the party and the common reference string crs, and writes the collective public-key on pk.
+func (node *Node) RunBFVCKG(ckg *dbfv.CKGProtocol, sk *ring.Poly, crs *ring.Poly, pk *bfv.PublicKey) error {
+
+	share, tmp := ckg.AllocateShares(), ckg.AllocateShares()
+
+	ckg.GenShare(sk, crs, share)
+
+	if err := node.AggregateAndBroadcast(&share, &tmp, func() { ckg.AggregateShares(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	ckg.GenPublicKey(share, crs, pk)
+
+	return nil
+}
+
+// RunBFVRKG executes the three rounds of the collective relinearization-key generation protocol of the dbfv package over
+// the network, given the secret-key share sk of the party and the common reference polynomials crp, and writes the collective
+// relinearization-key on evk.
+func (node *Node) RunBFVRKG(ekg *dbfv.RKGProtocol, sk *ring.Poly, crp []*ring.Poly, evk *bfv.EvaluationKey) (err error) {
+
+	u := ekg.NewEphemeralKey(1.0 / 3.0)
+
+	r1, r2, r3 := ekg.AllocateShares()
+	tmp1, tmp2, tmp3 := ekg.AllocateShares()
+
+	ekg.GenShareRoundOne(u, sk, crp, r1)
+	if err = node.AggregateAndBroadcast(&r1, &tmp1, func() { ekg.AggregateShareRoundOne(r1, tmp1, r1) }); err != nil {
+		return err
+	}
+
+	ekg.GenShareRoundTwo(r1, sk, crp, r2)
+	if err = node.AggregateAndBroadcast(&r2, &tmp2, func() { ekg.AggregateShareRoundTwo(r2, tmp2, r2) }); err != nil {
+		return err
+	}
+
+	ekg.GenShareRoundThree(r2, u, sk, r3)
+	if err = node.AggregateAndBroadcast(&r3, &tmp3, func() { ekg.AggregateShareRoundThree(r3, tmp3, r3) }); err != nil {
+		return err
+	}
+
+	ekg.GenRelinearizationKey(r2, r3, evk)
+
+	return nil
+}
+
+// RunBFVRTG executes the collective rotation-key generation protocol of the dbfv package over the network for the
+// rotation of type rotType by k positions, given the secret-key share sk of the party and the common reference polynomials
+// crp, and adds the collective rotation-key to rotKey.
+func (node *Node) RunBFVRTG(rtg *dbfv.RTGProtocol, rotType bfv.Rotation, k uint64, sk *ring.Poly, crp []*ring.Poly, rotKey *bfv.RotationKeys) error {
+
+	share, tmp := rtg.AllocateShare(), rtg.AllocateShare()
+
+	rtg.GenShare(rotType, k, sk, crp, &share)
+
+	if err := node.AggregateAndBroadcast(&share, &tmp, func() { rtg.Aggregate(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	rtg.Finalize(share, crp, rotKey)
+
+	return nil
+}
+
+// RunBFVCKS executes the collective key-switching protocol of the dbfv package over the network, given the input and
+// output secret-key shares skInput and skOutput of the party, and writes on ctOut the re-encryption of ct under the
+// collective output secret-key.
+func (node *Node) RunBFVCKS(cks *dbfv.CKSProtocol, skInput, skOutput *ring.Poly, ct, ctOut *bfv.Ciphertext) error {
+
+	share, tmp := cks.AllocateShare(), cks.AllocateShare()
+
+	cks.GenShare(skInput, skOutput, ct, share)
+
+	if err := node.AggregateAndBroadcast(&share, &tmp, func() { cks.AggregateShares(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	cks.KeySwitch(share, ct, ctOut)
+
+	return nil
+}
+
+// RunBFVPCKS executes the collective public-key switching protocol of the dbfv package over the network, given the
+// secret-key share sk of the party and the output public-key pk, and writes on ctOut the re-encryption of ct under pk.
+func (node *Node) RunBFVPCKS(pcks *dbfv.PCKSProtocol, sk *ring.Poly, pk *bfv.PublicKey, ct, ctOut *bfv.Ciphertext) error {
+
+	share, tmp := pcks.AllocateShares(), pcks.AllocateShares()
+
+	pcks.GenShare(sk, pk, ct, share)
+
+	if err := node.AggregateAndBroadcast(&share, &tmp, func() { pcks.AggregateShares(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	pcks.KeySwitch(share, ct, ctOut)
+
+	return nil
+}
+
+// RunBFVRefresh executes the collective refresh protocol of the dbfv package over the network, given the secret-key
+// share sk of the party and the common reference string crs, and writes on ctOut the refreshed ciphertext ct.
+func (node *Node) RunBFVRefresh(refresh *dbfv.RefreshProtocol, sk *ring.Poly, ct *bfv.Ciphertext, crs *ring.Poly, ctOut *bfv.Ciphertext) error {
+
+	share, tmp := refresh.AllocateShares(), refresh.AllocateShares()
+
+	refresh.GenShares(sk, ct, crs, share)
+
+	if err := node.AggregateAndBroadcast(&share, &tmp, func() { refresh.Aggregate(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	refresh.Finalize(ct, crs, share, ctOut)
+
+	return nil
+}
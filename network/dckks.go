@@ -0,0 +1,139 @@
+package network
+
+import (
+	"github.com/ldsec/lattigo/ckks"
+	"github.com/ldsec/lattigo/dckks"
+	"github.com/ldsec/lattigo/ring"
+)
+
+// RunCKKSCKG executes the collective public-key generation protocol of the dckks package over the network, given the
+// secret-key share sk of the party and the common reference string crs, and writes the collective public-key on pk.
+func (node *Node) RunCKKSCKG(ckg *dckks.CKGProtocol, sk *ring.Poly, crs *ring.Poly, pk *ckks.PublicKey) error {
+
+	share, tmp := ckg.AllocateShares(), ckg.AllocateShares()
+
+	ckg.GenShare(sk, crs, share)
+
+	if err := node.AggregateAndBroadcast((*ring.Poly)(share), (*ring.Poly)(tmp), func() { ckg.AggregateShares(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	ckg.GenPublicKey(share, crs, pk)
+
+	return nil
+}
+
+// RunCKKSRKG executes the three rounds of the collective relinearization-key generation protocol of the dckks package over
+// the network, given the secret-key share sk of the party and the common reference polynomials crp, and writes the collective
+// relinearization-key on evk.
+func (node *Node) RunCKKSRKG(ekg *dckks.RKGProtocol, sk *ring.Poly, crp []*ring.Poly, evk *ckks.EvaluationKey) (err error) {
+
+	u := ekg.NewEphemeralKey(1.0 / 3.0)
+
+	r1, r2, r3 := ekg.AllocateShares()
+	tmp1, tmp2, tmp3 := ekg.AllocateShares()
+
+	ekg.GenShareRoundOne(u, sk, crp, r1)
+	if err = node.AggregateAndBroadcast(&r1, &tmp1, func() { ekg.AggregateShareRoundOne(r1, tmp1, r1) }); err != nil {
+		return err
+	}
+
+	ekg.GenShareRoundTwo(r1, sk, crp, r2)
+	if err = node.AggregateAndBroadcast(&r2, &tmp2, func() { ekg.AggregateShareRoundTwo(r2, tmp2, r2) }); err != nil {
+		return err
+	}
+
+	ekg.GenShareRoundThree(r2, u, sk, r3)
+	if err = node.AggregateAndBroadcast(&r3, &tmp3, func() { ekg.AggregateShareRoundThree(r3, tmp3, r3) }); err != nil {
+		return err
+	}
+
+	ekg.GenRelinearizationKey(r2, r3, evk)
+
+	return nil
+}
+
+// RunCKKSRTG executes the collective rotation-key generation protocol of the dckks package over the network for the
+// rotation of type rotType by k positions, given the secret-key share sk of the party and the common reference polynomials
+// crp, and adds the collective rotation-key to rotKey.
+func (node *Node) RunCKKSRTG(rtg *dckks.RTGProtocol, params *ckks.Parameters, rotType ckks.Rotation, k uint64, sk *ring.Poly, crp []*ring.Poly, rotKey *ckks.RotationKeys) error {
+
+	share, tmp := rtg.AllocateShare(), rtg.AllocateShare()
+
+	rtg.GenShare(rotType, k, sk, crp, &share)
+
+	if err := node.AggregateAndBroadcast(&share, &tmp, func() { rtg.Aggregate(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	rtg.Finalize(params, share, crp, rotKey)
+
+	return nil
+}
+
+// RunCKKSCKS executes the collective key-switching protocol of the dckks package over the network, given the input and
+// output secret-key shares skInput and skOutput of the party, and writes on ctOut the re-encryption of ct under the
+// collective output secret-key.
+func (node *Node) RunCKKSCKS(cks *dckks.CKSProtocol, skInput, skOutput *ring.Poly, ct, ctOut *ckks.Ciphertext) error {
+
+	share, tmp := cks.AllocateShare(), cks.AllocateShare()
+
+	cks.GenShare(skInput, skOutput, ct, share)
+
+	if err := node.AggregateAndBroadcast((*ring.Poly)(share), (*ring.Poly)(tmp), func() { cks.AggregateShares(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	cks.KeySwitch(share, ct, ctOut)
+
+	return nil
+}
+
+// RunCKKSPCKS executes the collective public-key switching protocol of the dckks package over the network, given the
+// secret-key share sk of the party and the output public-key pk, and writes on ctOut the re-encryption of ct under pk.
+func (node *Node) RunCKKSPCKS(pcks *dckks.PCKSProtocol, sk *ring.Poly, pk *ckks.PublicKey, ct, ctOut *ckks.Ciphertext) error {
+
+	share, tmp := pcks.AllocateShares(ct.Level()), pcks.AllocateShares(ct.Level())
+
+	pcks.GenShare(sk, pk, ct, share)
+
+	if err := node.AggregateAndBroadcast(&share, &tmp, func() { pcks.AggregateShares(share, tmp, share) }); err != nil {
+		return err
+	}
+
+	pcks.KeySwitch(share, ct, ctOut)
+
+	return nil
+}
+
+// RunCKKSRefresh executes the collective refresh protocol of the dckks package over the network, given the secret-key
+// share sk of the party and the common reference string crs, and writes on ctOut the refreshed ciphertext ct, at the
+// maximum level. The protocol starts at the level of ct, and the decryption and recryption shares are aggregated in
+// two consecutive rounds.
+func (node *Node) RunCKKSRefresh(refresh *dckks.RefreshProtocol, sk *ring.Poly, ct *ckks.Ciphertext, crs *ring.Poly, ctOut *ckks.Ciphertext) error {
+
+	levelStart := ct.Level()
+
+	shareDecrypt, shareRecrypt := refresh.AllocateShares(levelStart)
+	tmpDecrypt, tmpRecrypt := refresh.AllocateShares(levelStart)
+
+	refresh.GenShares(sk, levelStart, node.topology.Parties(), ct, crs, shareDecrypt, shareRecrypt)
+
+	if err := node.AggregateAndBroadcast((*ring.Poly)(shareDecrypt), (*ring.Poly)(tmpDecrypt), func() { refresh.Aggregate(shareDecrypt, tmpDecrypt, shareDecrypt) }); err != nil {
+		return err
+	}
+
+	if err := node.AggregateAndBroadcast((*ring.Poly)(shareRecrypt), (*ring.Poly)(tmpRecrypt), func() { refresh.Aggregate(shareRecrypt, tmpRecrypt, shareRecrypt) }); err != nil {
+		return err
+	}
+
+	if ctOut != ct {
+		*ctOut = *ct.CopyNew().Ciphertext()
+	}
+
+	refresh.Decrypt(ctOut, shareDecrypt)
+	refresh.Recode(ctOut)
+	refresh.Recrypt(ctOut, crs, shareRecrypt)
+
+	return nil
+}
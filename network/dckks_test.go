@@ -0,0 +1,258 @@
+package network
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ldsec/lattigo/ckks"
+	"github.com/ldsec/lattigo/dckks"
+	"github.com/ldsec/lattigo/ring"
+)
+
+type networkCKKSTestContext struct {
+	params *ckks.Parameters
+
+	encoder ckks.Encoder
+
+	sk0Shards []*ckks.SecretKey
+	sk0       *ckks.SecretKey
+
+	sk1Shards []*ckks.SecretKey
+	sk1       *ckks.SecretKey
+
+	pk0 *ckks.PublicKey
+	pk1 *ckks.PublicKey
+
+	crs *ring.Poly
+	crp []*ring.Poly
+
+	crsRefresh *ring.Poly
+}
+
+var testCKKSMedianPrecision = 15.0
+
+func Test_NetworkCKKS(t *testing.T) {
+
+	params := ckks.DefaultParams[ckks.PN13QP218]
+
+	testCtx := genNetworkCKKSTestContext(params)
+
+	for _, test := range testTopologies {
+		t.Run(fmt.Sprintf("%s/parties=%d/LogN=%d/logQ=%d", test.name, testParties, params.LogN, params.LogQP()), func(t *testing.T) {
+			t.Run("PublicKeyGen", func(t *testing.T) { testCKKSPublicKeyGen(testCtx, test.topology, t) })
+			t.Run("RelinKeyGen", func(t *testing.T) { testCKKSRelinKeyGen(testCtx, test.topology, t) })
+			t.Run("RotKeyGen", func(t *testing.T) { testCKKSRotKeyGen(testCtx, test.topology, t) })
+			t.Run("KeySwitching", func(t *testing.T) { testCKKSKeySwitching(testCtx, test.topology, t) })
+			t.Run("PublicKeySwitching", func(t *testing.T) { testCKKSPublicKeySwitching(testCtx, test.topology, t) })
+			t.Run("Refresh", func(t *testing.T) { testCKKSRefresh(testCtx, test.topology, t) })
+		})
+	}
+}
+
+func genNetworkCKKSTestContext(params *ckks.Parameters) (testCtx *networkCKKSTestContext) {
+
+	testCtx = new(networkCKKSTestContext)
+	testCtx.params = params
+	testCtx.encoder = ckks.NewEncoder(params)
+
+	contextQ, err := ring.NewContextWithParams(1<<params.LogN, params.Qi)
+	if err != nil {
+		panic(err)
+	}
+
+	contextQP, err := ring.NewContextWithParams(1<<params.LogN, append(params.Qi, params.Pi...))
+	if err != nil {
+		panic(err)
+	}
+
+	kgen := ckks.NewKeyGenerator(params)
+
+	testCtx.sk0Shards = make([]*ckks.SecretKey, testParties)
+	testCtx.sk1Shards = make([]*ckks.SecretKey, testParties)
+	tmp0 := contextQP.NewPoly()
+	tmp1 := contextQP.NewPoly()
+
+	for i := uint64(0); i < testParties; i++ {
+		testCtx.sk0Shards[i] = kgen.GenSecretKey()
+		testCtx.sk1Shards[i] = kgen.GenSecretKey()
+		contextQP.Add(tmp0, testCtx.sk0Shards[i].Get(), tmp0)
+		contextQP.Add(tmp1, testCtx.sk1Shards[i].Get(), tmp1)
+	}
+
+	testCtx.sk0 = new(ckks.SecretKey)
+	testCtx.sk1 = new(ckks.SecretKey)
+	testCtx.sk0.Set(tmp0)
+	testCtx.sk1.Set(tmp1)
+
+	testCtx.pk0 = kgen.GenPublicKey(testCtx.sk0)
+	testCtx.pk1 = kgen.GenPublicKey(testCtx.sk1)
+
+	crpGenerator := dckks.NewCRPGenerator(params, []byte{'l', 'a', 't', 't', 'i', 'g', 'o'})
+	testCtx.crs = crpGenerator.ClockNew()
+	testCtx.crp = make([]*ring.Poly, params.Beta())
+	for i := range testCtx.crp {
+		testCtx.crp[i] = crpGenerator.ClockNew()
+	}
+
+	// The refresh protocol samples its common reference string in the ring of the ciphertexts
+	testCtx.crsRefresh = ring.NewCRPGenerator([]byte{'l', 'a', 't', 't', 'i', 'g', 'o'}, contextQ).ClockNew()
+
+	return
+}
+
+func newCKKSTestVectors(testCtx *networkCKKSTestContext, encryptor ckks.Encryptor) (values []complex128, ciphertext *ckks.Ciphertext) {
+
+	slots := uint64(1 << testCtx.params.LogSlots)
+
+	values = make([]complex128, slots)
+	for i := range values {
+		values[i] = complex(2*rand.Float64()-1, 2*rand.Float64()-1)
+	}
+
+	plaintext := ckks.NewPlaintext(testCtx.params, testCtx.params.MaxLevel(), testCtx.params.Scale)
+	testCtx.encoder.Encode(plaintext, values, slots)
+
+	return values, encryptor.EncryptNew(plaintext)
+}
+
+func verifyCKKSTestVectors(testCtx *networkCKKSTestContext, decryptor ckks.Decryptor, valuesWant []complex128, ciphertext *ckks.Ciphertext, t *testing.T) {
+
+	valuesTest := testCtx.encoder.Decode(decryptor.DecryptNew(ciphertext), uint64(1<<testCtx.params.LogSlots))
+
+	precStats := ckks.GetPrecisionStats(valuesWant, valuesTest)
+
+	if real(precStats.MedianPrecision) < testCKKSMedianPrecision || imag(precStats.MedianPrecision) < testCKKSMedianPrecision {
+		t.Errorf("Median precision error: target (%.2f, %.2f) > result (%.2f, %.2f)", testCKKSMedianPrecision, testCKKSMedianPrecision, real(precStats.MedianPrecision), imag(precStats.MedianPrecision))
+	}
+}
+
+func testCKKSPublicKeyGen(testCtx *networkCKKSTestContext, topology *Topology, t *testing.T) {
+
+	pks := make([]*ckks.PublicKey, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		pks[node.ID()] = ckks.NewPublicKey(testCtx.params)
+		return node.RunCKKSCKG(dckks.NewCKGProtocol(testCtx.params), testCtx.sk0Shards[node.ID()].Get(), testCtx.crs, pks[node.ID()])
+	})
+
+	decryptor := ckks.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	for _, pk := range pks {
+		values, ciphertext := newCKKSTestVectors(testCtx, ckks.NewEncryptorFromPk(testCtx.params, pk))
+		verifyCKKSTestVectors(testCtx, decryptor, values, ciphertext, t)
+	}
+}
+
+func testCKKSRelinKeyGen(testCtx *networkCKKSTestContext, topology *Topology, t *testing.T) {
+
+	evks := make([]*ckks.EvaluationKey, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		evks[node.ID()] = ckks.NewRelinKey(testCtx.params)
+		return node.RunCKKSRKG(dckks.NewEkgProtocol(testCtx.params), testCtx.sk0Shards[node.ID()].Get(), testCtx.crp, evks[node.ID()])
+	})
+
+	evaluator := ckks.NewEvaluator(testCtx.params)
+	decryptor := ckks.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	values, ciphertext := newCKKSTestVectors(testCtx, ckks.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	for i := range values {
+		values[i] *= values[i]
+	}
+
+	for _, evk := range evks {
+		res := evaluator.MulRelinNew(ciphertext, ciphertext, evk)
+		evaluator.Rescale(res, testCtx.params.Scale, res)
+		verifyCKKSTestVectors(testCtx, decryptor, values, res, t)
+	}
+}
+
+func testCKKSRotKeyGen(testCtx *networkCKKSTestContext, topology *Topology, t *testing.T) {
+
+	rotKeys := make([]*ckks.RotationKeys, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		rotKeys[node.ID()] = ckks.NewRotationKeys()
+		return node.RunCKKSRTG(dckks.NewRotKGProtocol(testCtx.params), testCtx.params, ckks.RotationLeft, 1, testCtx.sk0Shards[node.ID()].Get(), testCtx.crp, rotKeys[node.ID()])
+	})
+
+	evaluator := ckks.NewEvaluator(testCtx.params)
+	decryptor := ckks.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	values, ciphertext := newCKKSTestVectors(testCtx, ckks.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	valuesWant := append(append([]complex128{}, values[1:]...), values[0])
+
+	for _, rotKey := range rotKeys {
+		res := ckks.NewCiphertext(testCtx.params, 1, ciphertext.Level(), ciphertext.Scale())
+		evaluator.RotateColumns(ciphertext, 1, rotKey, res)
+		verifyCKKSTestVectors(testCtx, decryptor, valuesWant, res, t)
+	}
+}
+
+func testCKKSKeySwitching(testCtx *networkCKKSTestContext, topology *Topology, t *testing.T) {
+
+	values, ciphertext := newCKKSTestVectors(testCtx, ckks.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	ciphertexts := make([]*ckks.Ciphertext, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		ciphertexts[node.ID()] = ckks.NewCiphertext(testCtx.params, 1, ciphertext.Level(), ciphertext.Scale())
+		cks := dckks.NewCKSProtocol(testCtx.params, 6.36)
+		return node.RunCKKSCKS(cks, testCtx.sk0Shards[node.ID()].Get(), testCtx.sk1Shards[node.ID()].Get(), ciphertext, ciphertexts[node.ID()])
+	})
+
+	decryptor := ckks.NewDecryptor(testCtx.params, testCtx.sk1)
+
+	for _, ct := range ciphertexts {
+		verifyCKKSTestVectors(testCtx, decryptor, values, ct, t)
+	}
+}
+
+func testCKKSPublicKeySwitching(testCtx *networkCKKSTestContext, topology *Topology, t *testing.T) {
+
+	values, ciphertext := newCKKSTestVectors(testCtx, ckks.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	ciphertexts := make([]*ckks.Ciphertext, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		ciphertexts[node.ID()] = ckks.NewCiphertext(testCtx.params, 1, ciphertext.Level(), ciphertext.Scale())
+		pcks := dckks.NewPCKSProtocol(testCtx.params, 6.36)
+		return node.RunCKKSPCKS(pcks, testCtx.sk0Shards[node.ID()].Get(), testCtx.pk1, ciphertext, ciphertexts[node.ID()])
+	})
+
+	decryptor := ckks.NewDecryptor(testCtx.params, testCtx.sk1)
+
+	for _, ct := range ciphertexts {
+		verifyCKKSTestVectors(testCtx, decryptor, values, ct, t)
+	}
+}
+
+func testCKKSRefresh(testCtx *networkCKKSTestContext, topology *Topology, t *testing.T) {
+
+	levelStart := uint64(2)
+
+	evaluator := ckks.NewEvaluator(testCtx.params)
+
+	values, ciphertext := newCKKSTestVectors(testCtx, ckks.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	evaluator.DropLevel(ciphertext, ciphertext.Level()-levelStart)
+
+	ciphertexts := make([]*ckks.Ciphertext, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		ciphertexts[node.ID()] = ckks.NewCiphertext(testCtx.params, 1, testCtx.params.MaxLevel(), ciphertext.Scale())
+		return node.RunCKKSRefresh(dckks.NewRefreshProtocol(testCtx.params), testCtx.sk0Shards[node.ID()].Get(), ciphertext, testCtx.crsRefresh, ciphertexts[node.ID()])
+	})
+
+	decryptor := ckks.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	for _, ct := range ciphertexts {
+		if ct.Level() != testCtx.params.MaxLevel() {
+			t.Errorf("refreshed ciphertext is not at the maximum level")
+		}
+		verifyCKKSTestVectors(testCtx, decryptor, values, ct, t)
+	}
+}
@@ -0,0 +1,361 @@
+// Package network implements a network layer for the multiparty protocols of the dbfv and dckks packages. The parties are connected
+// through TCP streams along an aggregation tree (of which the star topology is a special case): in each round of a protocol,
+// the shares are aggregated from the leaves up to the root, and the aggregated share is then broadcast back down the tree
+// to all the parties.
+package network
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Share is the interface implemented by the shares of the multiparty protocols that can be sent over the network.
+type Share interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// DefaultMaxFrameSize is the default maximum size, in bytes, of a share received by a Node. It can be changed
+// with SetMaxFrameSize.
+const DefaultMaxFrameSize = 1 << 30
+
+// DefaultTimeout is the default maximum duration of each network operation of a Node (connection, reception or
+// sending of a share), after which the operation fails, so that a silent peer cannot block the other parties forever.
+// It can be changed with SetTimeout.
+const DefaultTimeout = time.Minute
+
+// Topology is a structure describing the aggregation tree among the parties. The parties are identified by
+// their index in [0, parties) and the root of the tree is the party 0.
+type Topology struct {
+	parent   []uint64
+	children [][]uint64
+}
+
+// NewStarTopology creates a new Topology where the party 0 is the aggregator, connected to all the other parties.
+func NewStarTopology(parties uint64) *Topology {
+
+	if parties == 0 {
+		panic("cannot NewStarTopology : the number of parties must be at least 1")
+	}
+
+	return newTopology(parties, parties-1)
+}
+
+// NewTreeTopology creates a new Topology where the parties are arranged in a complete tree of the given arity,
+// in breadth-first order (i.e. the parent of the party i is the party (i-1)/arity).
+func NewTreeTopology(parties, arity uint64) *Topology {
+
+	if parties == 0 {
+		panic("cannot NewTreeTopology : the number of parties must be at least 1")
+	}
+
+	if arity == 0 {
+		panic("cannot NewTreeTopology : arity must be at least 1")
+	}
+
+	return newTopology(parties, arity)
+}
+
+func newTopology(parties, arity uint64) *Topology {
+
+	topology := new(Topology)
+	topology.parent = make([]uint64, parties)
+	topology.children = make([][]uint64, parties)
+
+	for i := uint64(1); i < parties; i++ {
+		topology.parent[i] = (i - 1) / arity
+		topology.children[topology.parent[i]] = append(topology.children[topology.parent[i]], i)
+	}
+
+	return topology
+}
+
+// Parties returns the number of parties in the topology.
+func (topology *Topology) Parties() uint64 {
+	return uint64(len(topology.parent))
+}
+
+// Parent returns the parent of the given party. The second returned value is false if the party is the root.
+func (topology *Topology) Parent(id uint64) (uint64, bool) {
+	return topology.parent[id], id != 0
+}
+
+// Children returns the children of the given party.
+func (topology *Topology) Children(id uint64) []uint64 {
+	return topology.children[id]
+}
+
+// Node is a structure storing the connections of a party to its parent and to its children in the Topology.
+type Node struct {
+	id       uint64
+	topology *Topology
+
+	listener net.Listener
+	parent   net.Conn
+	children []net.Conn
+
+	maxFrameSize uint64
+	timeout      time.Duration
+}
+
+// NewNode creates a new Node for the party id in the given Topology. The listener is used to accept the
+// connections of the children of the party, and can be nil if the party has no children.
+func NewNode(id uint64, topology *Topology, listener net.Listener) *Node {
+
+	if id >= topology.Parties() {
+		panic("cannot NewNode : id is not a party of the topology")
+	}
+
+	if listener == nil && len(topology.Children(id)) != 0 {
+		panic("cannot NewNode : a listener is required for a party with children")
+	}
+
+	node := new(Node)
+	node.id = id
+	node.topology = topology
+	node.listener = listener
+	node.children = make([]net.Conn, len(topology.Children(id)))
+	node.maxFrameSize = DefaultMaxFrameSize
+	node.timeout = DefaultTimeout
+	return node
+}
+
+// ID returns the id of the party of the Node.
+func (node *Node) ID() uint64 {
+	return node.id
+}
+
+// SetMaxFrameSize sets the maximum size, in bytes, of the shares received by the Node. A share whose announced
+// length exceeds this size is rejected before any allocation.
+func (node *Node) SetMaxFrameSize(size uint64) {
+	node.maxFrameSize = size
+}
+
+// SetTimeout sets the maximum duration of each network operation of the Node: the connection to its parent, the
+// acceptance of the connection of each of its children, and each reception or sending of a share. A zero timeout
+// disables the deadlines. The timeout must be set before calling Connect.
+func (node *Node) SetTimeout(timeout time.Duration) {
+	node.timeout = timeout
+}
+
+// setDeadline sets the deadline of the next operations on the connection according to the timeout of the Node.
+func (node *Node) setDeadline(conn net.Conn) error {
+	if node.timeout == 0 {
+		return conn.SetDeadline(time.Time{})
+	}
+	return conn.SetDeadline(time.Now().Add(node.timeout))
+}
+
+// IsRoot returns true if the party of the Node is the root of the Topology.
+func (node *Node) IsRoot() bool {
+	_, hasParent := node.topology.Parent(node.id)
+	return !hasParent
+}
+
+// Connect establishes the connections of the Node: it dials its parent at parentAddress (which is ignored
+// for the root) and accepts the connections of all its children.
+func (node *Node) Connect(parentAddress string) (err error) {
+
+	dialErr := make(chan error, 1)
+
+	go func() {
+		if node.IsRoot() {
+			dialErr <- nil
+			return
+		}
+
+		conn, err := net.DialTimeout("tcp", parentAddress, node.timeout)
+		if err != nil {
+			dialErr <- err
+			return
+		}
+
+		// Identifies the party to its parent
+		id := make([]byte, 8)
+		binary.BigEndian.PutUint64(id, node.id)
+		if err = node.setDeadline(conn); err != nil {
+			conn.Close()
+			dialErr <- err
+			return
+		}
+		if _, err = conn.Write(id); err != nil {
+			conn.Close()
+			dialErr <- err
+			return
+		}
+
+		node.parent = conn
+		dialErr <- nil
+	}()
+
+	err = node.acceptChildren()
+
+	if errDial := <-dialErr; err == nil {
+		err = errDial
+	}
+
+	return err
+}
+
+func (node *Node) acceptChildren() error {
+
+	children := node.topology.Children(node.id)
+
+	// The deadline can only be set on the listeners that support it, such as the TCP listeners
+	listener, hasDeadline := node.listener.(interface{ SetDeadline(time.Time) error })
+	if hasDeadline && node.timeout != 0 {
+		defer listener.SetDeadline(time.Time{})
+	}
+
+	for range children {
+
+		if hasDeadline && node.timeout != 0 {
+			if err := listener.SetDeadline(time.Now().Add(node.timeout)); err != nil {
+				return err
+			}
+		}
+
+		conn, err := node.listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		id := make([]byte, 8)
+		if err = node.setDeadline(conn); err != nil {
+			conn.Close()
+			return err
+		}
+		if _, err = io.ReadFull(conn, id); err != nil {
+			conn.Close()
+			return err
+		}
+
+		index := -1
+		for i, child := range children {
+			if child == binary.BigEndian.Uint64(id) && node.children[i] == nil {
+				index = i
+			}
+		}
+
+		if index < 0 {
+			conn.Close()
+			return fmt.Errorf("unexpected connection from party %d", binary.BigEndian.Uint64(id))
+		}
+
+		node.children[index] = conn
+	}
+
+	return nil
+}
+
+// Close closes the connections of the Node.
+func (node *Node) Close() (err error) {
+
+	if node.parent != nil {
+		err = node.parent.Close()
+	}
+
+	for _, conn := range node.children {
+		if conn != nil {
+			if errClose := conn.Close(); err == nil {
+				err = errClose
+			}
+		}
+	}
+
+	return err
+}
+
+// AggregateAndBroadcast executes one round of a multiparty protocol over the Topology. The Node receives on tmp the aggregated
+// share of each of its children, and calls aggregate after each reception, which must add tmp to share. It then sends share
+// to its parent and receives from it the aggregation of the shares of all the parties, which it forwards to its children.
+// Upon return, share stores the aggregation of the shares of all the parties. Each reception and sending of a share fails
+// if it does not complete within the timeout of the Node.
+func (node *Node) AggregateAndBroadcast(share, tmp Share, aggregate func()) (err error) {
+
+	if node.parent == nil && !node.IsRoot() {
+		return errors.New("node is not connected")
+	}
+
+	// Aggregation up the tree
+	for _, conn := range node.children {
+		if err = node.setDeadline(conn); err != nil {
+			return err
+		}
+		if err = receive(conn, tmp, node.maxFrameSize); err != nil {
+			return err
+		}
+		aggregate()
+	}
+
+	if !node.IsRoot() {
+		if err = node.setDeadline(node.parent); err != nil {
+			return err
+		}
+		if err = send(node.parent, share); err != nil {
+			return err
+		}
+
+		if err = node.setDeadline(node.parent); err != nil {
+			return err
+		}
+		if err = receive(node.parent, share, node.maxFrameSize); err != nil {
+			return err
+		}
+	}
+
+	// Broadcast down the tree
+	for _, conn := range node.children {
+		if err = node.setDeadline(conn); err != nil {
+			return err
+		}
+		if err = send(conn, share); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send writes the marshaled share on the connection, prefixed by its length.
+func send(w io.Writer, share Share) (err error) {
+
+	var data []byte
+	if data, err = share.MarshalBinary(); err != nil {
+		return err
+	}
+
+	frame := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(frame[:8], uint64(len(data)))
+	copy(frame[8:], data)
+
+	_, err = w.Write(frame)
+	return err
+}
+
+// receive reads a share prefixed by its length from the connection and decodes it on the target share. It returns an
+// error without reading the share if its length is larger than maxFrameSize.
+func receive(r io.Reader, share Share, maxFrameSize uint64) (err error) {
+
+	header := make([]byte, 8)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return err
+	}
+
+	length := binary.BigEndian.Uint64(header)
+	if length > maxFrameSize {
+		return fmt.Errorf("frame of %d bytes exceeds the maximum frame size of %d bytes", length, maxFrameSize)
+	}
+
+	data := make([]byte, length)
+	if _, err = io.ReadFull(r, data); err != nil {
+		return err
+	}
+
+	return share.UnmarshalBinary(data)
+}
@@ -0,0 +1,401 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ldsec/lattigo/bfv"
+	"github.com/ldsec/lattigo/dbfv"
+	"github.com/ldsec/lattigo/ring"
+	"github.com/ldsec/lattigo/utils"
+)
+
+type networkTestContext struct {
+	params *bfv.Parameters
+
+	encoder bfv.Encoder
+
+	sk0Shards []*bfv.SecretKey
+	sk0       *bfv.SecretKey
+
+	sk1Shards []*bfv.SecretKey
+	sk1       *bfv.SecretKey
+
+	pk0 *bfv.PublicKey
+	pk1 *bfv.PublicKey
+
+	crs *ring.Poly
+	crp []*ring.Poly
+}
+
+var testParties = uint64(5)
+
+var testTopologies = []struct {
+	name     string
+	topology *Topology
+}{
+	{"Star", NewStarTopology(testParties)},
+	{"Tree", NewTreeTopology(testParties, 2)},
+}
+
+func Test_Network(t *testing.T) {
+
+	params := bfv.DefaultParams[bfv.PN12QP109]
+
+	testCtx := genNetworkTestContext(params)
+
+	for _, test := range testTopologies {
+		t.Run(fmt.Sprintf("%s/parties=%d/LogN=%d/logQ=%d", test.name, testParties, params.LogN, params.LogQP()), func(t *testing.T) {
+			t.Run("Topology", func(t *testing.T) { testTopology(test.topology, t) })
+			t.Run("PublicKeyGen", func(t *testing.T) { testPublicKeyGen(testCtx, test.topology, t) })
+			t.Run("RelinKeyGen", func(t *testing.T) { testRelinKeyGen(testCtx, test.topology, t) })
+			t.Run("RotKeyGen", func(t *testing.T) { testRotKeyGen(testCtx, test.topology, t) })
+			t.Run("KeySwitching", func(t *testing.T) { testKeySwitching(testCtx, test.topology, t) })
+			t.Run("PublicKeySwitching", func(t *testing.T) { testPublicKeySwitching(testCtx, test.topology, t) })
+			t.Run("Refresh", func(t *testing.T) { testRefresh(testCtx, test.topology, t) })
+		})
+	}
+}
+
+func Test_MaxFrameSize(t *testing.T) {
+
+	params := bfv.DefaultParams[bfv.PN12QP109]
+
+	contextQ := ring.NewContext()
+	contextQ.SetParameters(1<<params.LogN, params.Qi)
+	if err := contextQ.GenNTTParams(); err != nil {
+		t.Fatal(err)
+	}
+
+	share := contextQ.NewUniformPoly()
+
+	buffer := new(bytes.Buffer)
+	if err := send(buffer, share); err != nil {
+		t.Fatal(err)
+	}
+
+	frame := buffer.Bytes()
+	length := uint64(len(frame) - 8)
+
+	if err := receive(bytes.NewReader(frame), contextQ.NewPoly(), length-1); err == nil {
+		t.Errorf("frame larger than the maximum frame size has been accepted")
+	}
+
+	received := contextQ.NewPoly()
+	if err := receive(bytes.NewReader(frame), received, length); err != nil {
+		t.Fatal(err)
+	}
+
+	if !contextQ.Equal(share, received) {
+		t.Errorf("received share does not match the sent share")
+	}
+}
+
+func Test_Timeout(t *testing.T) {
+
+	topology := NewStarTopology(2)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	root := NewNode(0, topology, listener)
+	root.SetTimeout(100 * time.Millisecond)
+
+	// The child never connects
+	if err = root.Connect(""); err == nil {
+		t.Errorf("root accepted a connection that was never made")
+	}
+
+	root = NewNode(0, topology, listener)
+	root.SetTimeout(100 * time.Millisecond)
+	defer root.Close()
+
+	child := NewNode(1, topology, nil)
+	defer child.Close()
+
+	childErr := make(chan error, 1)
+	go func() { childErr <- child.Connect(listener.Addr().String()) }()
+
+	if err = root.Connect(""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = <-childErr; err != nil {
+		t.Fatal(err)
+	}
+
+	// The child is connected but never sends its share
+	share := ring.NewPoly(1<<4, 1)
+	if err = root.AggregateAndBroadcast(share, share.CopyNew(), func() {}); err == nil {
+		t.Errorf("root received a share that was never sent")
+	}
+}
+
+func genNetworkTestContext(params *bfv.Parameters) (testCtx *networkTestContext) {
+
+	testCtx = new(networkTestContext)
+	testCtx.params = params
+	testCtx.encoder = bfv.NewEncoder(params)
+
+	contextQP := ring.NewContext()
+	contextQP.SetParameters(1<<params.LogN, append(params.Qi, params.Pi...))
+	if err := contextQP.GenNTTParams(); err != nil {
+		panic(err)
+	}
+
+	kgen := bfv.NewKeyGenerator(params)
+
+	testCtx.sk0Shards = make([]*bfv.SecretKey, testParties)
+	testCtx.sk1Shards = make([]*bfv.SecretKey, testParties)
+	tmp0 := contextQP.NewPoly()
+	tmp1 := contextQP.NewPoly()
+
+	for i := uint64(0); i < testParties; i++ {
+		testCtx.sk0Shards[i] = kgen.GenSecretKey()
+		testCtx.sk1Shards[i] = kgen.GenSecretKey()
+		contextQP.Add(tmp0, testCtx.sk0Shards[i].Get(), tmp0)
+		contextQP.Add(tmp1, testCtx.sk1Shards[i].Get(), tmp1)
+	}
+
+	testCtx.sk0 = new(bfv.SecretKey)
+	testCtx.sk1 = new(bfv.SecretKey)
+	testCtx.sk0.Set(tmp0)
+	testCtx.sk1.Set(tmp1)
+
+	testCtx.pk0 = kgen.GenPublicKey(testCtx.sk0)
+	testCtx.pk1 = kgen.GenPublicKey(testCtx.sk1)
+
+	crpGenerator := dbfv.NewCRPGenerator(params, []byte{'l', 'a', 't', 't', 'i', 'g', 'o'})
+	testCtx.crs = crpGenerator.ClockNew()
+	testCtx.crp = make([]*ring.Poly, params.Beta())
+	for i := range testCtx.crp {
+		testCtx.crp[i] = crpGenerator.ClockNew()
+	}
+
+	return
+}
+
+// runParties connects the parties of the topology over loopback sockets and runs the given function for each of them concurrently.
+func runParties(topology *Topology, t *testing.T, run func(node *Node) error) {
+
+	parties := topology.Parties()
+
+	listeners := make([]net.Listener, parties)
+	for i := uint64(0); i < parties; i++ {
+		if len(topology.Children(i)) != 0 {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+			listeners[i] = listener
+		}
+	}
+
+	errs := make(chan error, parties)
+
+	for i := uint64(0); i < parties; i++ {
+		go func(id uint64) {
+
+			node := NewNode(id, topology, listeners[id])
+			defer node.Close()
+
+			var parentAddress string
+			if parent, hasParent := topology.Parent(id); hasParent {
+				parentAddress = listeners[parent].Addr().String()
+			}
+
+			if err := node.Connect(parentAddress); err != nil {
+				errs <- err
+				return
+			}
+
+			errs <- run(node)
+		}(i)
+	}
+
+	for i := uint64(0); i < parties; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func newTestVectors(testCtx *networkTestContext, encryptor bfv.Encryptor) (coeffs []uint64, ciphertext *bfv.Ciphertext) {
+	coeffs = make([]uint64, 1<<testCtx.params.LogN)
+	for i := range coeffs {
+		coeffs[i] = uint64(i) % testCtx.params.T
+	}
+	plaintext := bfv.NewPlaintext(testCtx.params)
+	testCtx.encoder.EncodeUint(coeffs, plaintext)
+	return coeffs, encryptor.EncryptNew(plaintext)
+}
+
+func verifyTestVectors(testCtx *networkTestContext, decryptor bfv.Decryptor, coeffs []uint64, ciphertext *bfv.Ciphertext, t *testing.T) {
+	if utils.EqualSliceUint64(coeffs, testCtx.encoder.DecodeUint(decryptor.DecryptNew(ciphertext))) != true {
+		t.Errorf("decryption error")
+	}
+}
+
+func testTopology(topology *Topology, t *testing.T) {
+
+	seen := make([]bool, topology.Parties())
+	seen[0] = true
+
+	for i := uint64(1); i < topology.Parties(); i++ {
+		parent, hasParent := topology.Parent(i)
+		if !hasParent || parent >= i {
+			t.Errorf("invalid parent %d for party %d", parent, i)
+		}
+
+		found := false
+		for _, child := range topology.Children(parent) {
+			if child == i {
+				found = true
+			}
+		}
+
+		if !found {
+			t.Errorf("party %d is not a child of its parent %d", i, parent)
+		}
+
+		seen[i] = seen[parent]
+	}
+
+	for i := range seen {
+		if !seen[i] {
+			t.Errorf("party %d is not connected to the root", i)
+		}
+	}
+}
+
+func testPublicKeyGen(testCtx *networkTestContext, topology *Topology, t *testing.T) {
+
+	pks := make([]*bfv.PublicKey, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		pks[node.ID()] = bfv.NewPublicKey(testCtx.params)
+		return node.RunBFVCKG(dbfv.NewCKGProtocol(testCtx.params), testCtx.sk0Shards[node.ID()].Get(), testCtx.crs, pks[node.ID()])
+	})
+
+	decryptor := bfv.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	for _, pk := range pks {
+		coeffs, ciphertext := newTestVectors(testCtx, bfv.NewEncryptorFromPk(testCtx.params, pk))
+		verifyTestVectors(testCtx, decryptor, coeffs, ciphertext, t)
+	}
+}
+
+func testRelinKeyGen(testCtx *networkTestContext, topology *Topology, t *testing.T) {
+
+	evks := make([]*bfv.EvaluationKey, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		evks[node.ID()] = bfv.NewRelinKey(testCtx.params, 1)
+		return node.RunBFVRKG(dbfv.NewEkgProtocol(testCtx.params), testCtx.sk0Shards[node.ID()].Get(), testCtx.crp, evks[node.ID()])
+	})
+
+	evaluator := bfv.NewEvaluator(testCtx.params)
+	decryptor := bfv.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	coeffs, ciphertext := newTestVectors(testCtx, bfv.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	for i := range coeffs {
+		coeffs[i] = ring.BRed(coeffs[i], coeffs[i], testCtx.params.T, ring.BRedParams(testCtx.params.T))
+	}
+
+	ciphertextMul := bfv.NewCiphertext(testCtx.params, 2)
+	evaluator.Mul(ciphertext, ciphertext, ciphertextMul)
+
+	for _, evk := range evks {
+		res := bfv.NewCiphertext(testCtx.params, 1)
+		evaluator.Relinearize(ciphertextMul, evk, res)
+		verifyTestVectors(testCtx, decryptor, coeffs, res, t)
+	}
+}
+
+func testRotKeyGen(testCtx *networkTestContext, topology *Topology, t *testing.T) {
+
+	rotKeys := make([]*bfv.RotationKeys, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		rotKeys[node.ID()] = bfv.NewRotationKeys()
+		return node.RunBFVRTG(dbfv.NewRotKGProtocol(testCtx.params), bfv.RotationRow, 0, testCtx.sk0Shards[node.ID()].Get(), testCtx.crp, rotKeys[node.ID()])
+	})
+
+	evaluator := bfv.NewEvaluator(testCtx.params)
+	decryptor := bfv.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	coeffs, ciphertext := newTestVectors(testCtx, bfv.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	slots := uint64(len(coeffs)) >> 1
+	coeffsWant := append(append([]uint64{}, coeffs[slots:]...), coeffs[:slots]...)
+
+	for _, rotKey := range rotKeys {
+		res := bfv.NewCiphertext(testCtx.params, 1)
+		evaluator.RotateRows(ciphertext, rotKey, res)
+		verifyTestVectors(testCtx, decryptor, coeffsWant, res, t)
+	}
+}
+
+func testKeySwitching(testCtx *networkTestContext, topology *Topology, t *testing.T) {
+
+	coeffs, ciphertext := newTestVectors(testCtx, bfv.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	ciphertexts := make([]*bfv.Ciphertext, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		ciphertexts[node.ID()] = bfv.NewCiphertext(testCtx.params, 1)
+		cks := dbfv.NewCKSProtocol(testCtx.params, 6.36)
+		return node.RunBFVCKS(cks, testCtx.sk0Shards[node.ID()].Get(), testCtx.sk1Shards[node.ID()].Get(), ciphertext, ciphertexts[node.ID()])
+	})
+
+	decryptor := bfv.NewDecryptor(testCtx.params, testCtx.sk1)
+
+	for _, ct := range ciphertexts {
+		verifyTestVectors(testCtx, decryptor, coeffs, ct, t)
+	}
+}
+
+func testPublicKeySwitching(testCtx *networkTestContext, topology *Topology, t *testing.T) {
+
+	coeffs, ciphertext := newTestVectors(testCtx, bfv.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	ciphertexts := make([]*bfv.Ciphertext, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		ciphertexts[node.ID()] = bfv.NewCiphertext(testCtx.params, 1)
+		pcks := dbfv.NewPCKSProtocol(testCtx.params, 6.36)
+		return node.RunBFVPCKS(pcks, testCtx.sk0Shards[node.ID()].Get(), testCtx.pk1, ciphertext, ciphertexts[node.ID()])
+	})
+
+	decryptor := bfv.NewDecryptor(testCtx.params, testCtx.sk1)
+
+	for _, ct := range ciphertexts {
+		verifyTestVectors(testCtx, decryptor, coeffs, ct, t)
+	}
+}
+
+func testRefresh(testCtx *networkTestContext, topology *Topology, t *testing.T) {
+
+	coeffs, ciphertext := newTestVectors(testCtx, bfv.NewEncryptorFromPk(testCtx.params, testCtx.pk0))
+
+	ciphertexts := make([]*bfv.Ciphertext, topology.Parties())
+
+	runParties(topology, t, func(node *Node) error {
+		ciphertexts[node.ID()] = bfv.NewCiphertext(testCtx.params, 1)
+		return node.RunBFVRefresh(dbfv.NewRefreshProtocol(testCtx.params), testCtx.sk0Shards[node.ID()].Get(), ciphertext, testCtx.crs, ciphertexts[node.ID()])
+	})
+
+	decryptor := bfv.NewDecryptor(testCtx.params, testCtx.sk0)
+
+	for _, ct := range ciphertexts {
+		verifyTestVectors(testCtx, decryptor, coeffs, ct, t)
+	}
+}
@@ -0,0 +1,113 @@
+package ring
+
+import (
+	"math/big"
+)
+
+// Complex is a type for arbitrary precision complex number, represented by its real and imaginary parts.
+type Complex [2]*big.Float
+
+// NewComplex creates a new arbitrary precision complex number from its real and imaginary parts.
+func NewComplex(a, b *big.Float) (c *Complex) {
+	c = new(Complex)
+
+	for i := 0; i < 2; i++ {
+		c[i] = new(big.Float)
+	}
+
+	if a != nil {
+		c[0].Set(a)
+	}
+
+	if b != nil {
+		c[1].Set(b)
+	}
+
+	return
+}
+
+// NewComplexFromComplex128 creates a new arbitrary precision complex number of precision prec from a complex128.
+func NewComplexFromComplex128(v complex128, prec uint) (c *Complex) {
+	c = NewComplex(nil, nil)
+	c.SetPrec(prec)
+	c[0].SetFloat64(real(v))
+	c[1].SetFloat64(imag(v))
+	return
+}
+
+// SetPrec sets the precision of the real and imaginary parts of the target complex number.
+func (c *Complex) SetPrec(prec uint) {
+	c[0].SetPrec(prec)
+	c[1].SetPrec(prec)
+}
+
+// Set sets the target complex number to the value of a.
+func (c *Complex) Set(a *Complex) {
+	c[0].Set(a[0])
+	c[1].Set(a[1])
+}
+
+// Copy returns a new copy of the target complex number.
+func (c *Complex) Copy() *Complex {
+	return NewComplex(c[0], c[1])
+}
+
+// Real returns the real part of the target complex number.
+func (c *Complex) Real() *big.Float {
+	return c[0]
+}
+
+// Imag returns the imaginary part of the target complex number.
+func (c *Complex) Imag() *big.Float {
+	return c[1]
+}
+
+// Complex128 returns the complex128 closest to the target complex number.
+func (c *Complex) Complex128() complex128 {
+	real, _ := c[0].Float64()
+	imag, _ := c[1].Float64()
+	return complex(real, imag)
+}
+
+// Add adds a and b, returning the result on the target complex number.
+func (c *Complex) Add(a, b *Complex) {
+	c[0].Add(a[0], b[0])
+	c[1].Add(a[1], b[1])
+}
+
+// Sub subtracts b to a, returning the result on the target complex number.
+func (c *Complex) Sub(a, b *Complex) {
+	c[0].Sub(a[0], b[0])
+	c[1].Sub(a[1], b[1])
+}
+
+// ComplexMultiplier is a struct storing the temporary values required to multiply arbitrary precision complex numbers
+// without allocating memory.
+type ComplexMultiplier struct {
+	tmp0 *big.Float
+	tmp1 *big.Float
+	tmp2 *big.Float
+	tmp3 *big.Float
+}
+
+// NewComplexMultiplier creates a new ComplexMultiplier.
+func NewComplexMultiplier() (cEval *ComplexMultiplier) {
+	cEval = new(ComplexMultiplier)
+	cEval.tmp0 = new(big.Float)
+	cEval.tmp1 = new(big.Float)
+	cEval.tmp2 = new(big.Float)
+	cEval.tmp3 = new(big.Float)
+	return
+}
+
+// Mul multiplies a by b, returning the result on c. The output c can be one of the inputs.
+func (cEval *ComplexMultiplier) Mul(a, b, c *Complex) {
+
+	cEval.tmp0.Mul(a[0], b[0])
+	cEval.tmp1.Mul(a[1], b[1])
+	cEval.tmp2.Mul(a[0], b[1])
+	cEval.tmp3.Mul(a[1], b[0])
+
+	c[0].Sub(cEval.tmp0, cEval.tmp1)
+	c[1].Add(cEval.tmp2, cEval.tmp3)
+}
@@ -2,6 +2,14 @@ package ring
 
 // NTT performs the NTT transformation on the CRT coefficients of a Polynomial, based on the target context.
 func (context *Context) NTT(p1, p2 *Poly) {
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(x uint64) {
+			NTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsi[x], context.Modulus[x], context.mredParams[x], context.bredParams[x])
+		})
+		return
+	}
+
 	for x := range context.Modulus {
 		NTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsi[x], context.Modulus[x], context.mredParams[x], context.bredParams[x])
 	}
@@ -9,6 +17,14 @@ func (context *Context) NTT(p1, p2 *Poly) {
 
 // NTTLvl performs the NTT transformation on the CRT coefficients of a Polynomial, based on the target context.
 func (context *Context) NTTLvl(level uint64, p1, p2 *Poly) {
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(x uint64) {
+			NTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsi[x], context.Modulus[x], context.mredParams[x], context.bredParams[x])
+		})
+		return
+	}
+
 	for x := uint64(0); x < level+1; x++ {
 		NTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsi[x], context.Modulus[x], context.mredParams[x], context.bredParams[x])
 	}
@@ -16,6 +32,14 @@ func (context *Context) NTTLvl(level uint64, p1, p2 *Poly) {
 
 // InvNTT performs the inverse NTT transformation on the CRT coefficients of of a Polynomial, based on the target context.
 func (context *Context) InvNTT(p1, p2 *Poly) {
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(x uint64) {
+			InvNTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsiInv[x], context.nttNInv[x], context.Modulus[x], context.mredParams[x])
+		})
+		return
+	}
+
 	for x := range context.Modulus {
 		InvNTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsiInv[x], context.nttNInv[x], context.Modulus[x], context.mredParams[x])
 	}
@@ -23,6 +47,14 @@ func (context *Context) InvNTT(p1, p2 *Poly) {
 
 // InvNTTLvl performs the NTT transformation on the CRT coefficients of a Polynomial, based on the target context.
 func (context *Context) InvNTTLvl(level uint64, p1, p2 *Poly) {
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(x uint64) {
+			InvNTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsiInv[x], context.nttNInv[x], context.Modulus[x], context.mredParams[x])
+		})
+		return
+	}
+
 	for x := uint64(0); x < level+1; x++ {
 		InvNTT(p1.Coeffs[x], p2.Coeffs[x], context.N, context.nttPsiInv[x], context.nttNInv[x], context.Modulus[x], context.mredParams[x])
 	}
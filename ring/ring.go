@@ -8,137 +8,185 @@ import (
 
 // Add adds p1 to p2 coefficient wise and applies a modular reduction, returning the result on p3.
 func (context *Context) Add(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed(p1tmp[j]+p2tmp[j], qi)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			addVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		addVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // AddLvl adds p1 to p2 coefficient wise and applies a modular reduction, returning the result on p3.
 func (context *Context) AddLvl(level uint64, p1, p2, p3 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			addVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed(p1tmp[j]+p2tmp[j], qi)
-		}
+		addVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // AddNoMod adds p1 to p2 coefficient wise without modular reduction, returning the result on p3.
 // The output range will be [0,2*Qi -1].
 func (context *Context) AddNoMod(p1, p2, p3 *Poly) {
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			addVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i])
+		})
+		return
+	}
+
 	for i := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = p1tmp[j] + p2tmp[j]
-		}
+		addVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i])
 	}
 }
 
 // AddNoModLvl adds p1 to p2 coefficient wise without modular reduction, returning the result on p3.
 // The output range will be [0,2*Qi -1].
 func (context *Context) AddNoModLvl(level uint64, p1, p2, p3 *Poly) {
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			addVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = p1tmp[j] + p2tmp[j]
-		}
+		addVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i])
 	}
 }
 
 // Sub subtracts p2 to p1 coefficient wise and applies a modular reduction, returning the result on p3.
 func (context *Context) Sub(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed((p1tmp[j]+qi)-p2tmp[j], qi)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			subVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		subVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // SubLvl subtracts p2 to p1 coefficient wise and applies a modular reduction, returning the result on p3.
 func (context *Context) SubLvl(level uint64, p1, p2, p3 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			subVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed((p1tmp[j]+qi)-p2tmp[j], qi)
-		}
+		subVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // SubNoMod subtracts p2 to p1 coefficient wise without modular reduction, returning the result on p3.
 // The output range will be [0,2*Qi -1].
 func (context *Context) SubNoMod(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = (p1tmp[j] + qi) - p2tmp[j]
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			subVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		subVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // SubNoModLvl subtracts p2 to p1 coefficient wise without modular reduction, returning the result on p3.
 // The output range will be [0,2*Qi -1].
 func (context *Context) SubNoModLvl(level uint64, p1, p2, p3 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			subVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = (p1tmp[j] + qi) - p2tmp[j]
-		}
+		subVecNoMod(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // Neg sets all coefficients of p1 to there additive inverse, returning the result on p2.
 func (context *Context) Neg(p1, p2 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = qi - p1tmp[j]
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			negVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		negVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // NegLvl sets all coefficients of p1 to there additive inverse, returning the result on p2.
 func (context *Context) NegLvl(level uint64, p1, p2 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			negVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = qi - p1tmp[j]
-		}
+		negVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i])
 	}
 }
 
 // Reduce applies a modular reduction over the coefficients of p1 returning the result on p2.
 func (context *Context) Reduce(p1, p2 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		bredParams := context.bredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = BRedAdd(p1tmp[j], qi, bredParams)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			reduceVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		reduceVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
 // ReduceLvl applies a modular reduction over the coefficients of p1 returning the result on p2.
 func (context *Context) ReduceLvl(level uint64, p1, p2 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			reduceVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		bredParams := context.bredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = BRedAdd(p1tmp[j], qi, bredParams)
-		}
+		reduceVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
@@ -185,172 +233,220 @@ func (context *Context) XOR(p1 *Poly, m uint64, p2 *Poly) {
 
 // MulCoeffs multiplies p1 by p2 coefficient wise with a Barrett modular reduction, returning the result on p3.
 func (context *Context) MulCoeffs(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		bredParams := context.bredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = BRed(p1tmp[j], p2tmp[j], qi, bredParams)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
 // MulCoeffsAndAdd multiplies p1 by p2 coefficient wise with a Barret modular reduction, adding the result to p3 with modular reduction.
 func (context *Context) MulCoeffsAndAdd(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		bredParams := context.bredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed(p3tmp[j]+BRed(p1tmp[j], p2tmp[j], qi, bredParams), qi)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsAndAddVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsAndAddVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
 // MulCoeffsAndAddNoMod multiplies p1 by p2 coefficient wise with a Barrett modular reduction, adding the result to p3 without modular reduction.
 func (context *Context) MulCoeffsAndAddNoMod(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		bredParams := context.bredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] += BRed(p1tmp[j], p2tmp[j], qi, bredParams)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
 // MulCoeffsMontgomery multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, returning the result on p3.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomery(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = MRed(p1tmp[j], p2tmp[j], qi, mredParams)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsMontgomeryVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsMontgomeryVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryLvl multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, returning the result on p3.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomeryLvl(level uint64, p1, p2, p3 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			mulCoeffsMontgomeryVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = MRed(p1tmp[j], p2tmp[j], qi, mredParams)
-		}
+		mulCoeffsMontgomeryVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryAndAdd multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, adding the result to p3.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomeryAndAdd(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed(p3tmp[j]+MRed(p1tmp[j], p2tmp[j], qi, mredParams), qi)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsMontgomeryAndAddVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsMontgomeryAndAddVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryAndAddLvl multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, adding the result to p3.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomeryAndAddLvl(level uint64, p1, p2, p3 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			mulCoeffsMontgomeryAndAddVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed(p3tmp[j]+MRed(p1tmp[j], p2tmp[j], qi, mredParams), qi)
-		}
+		mulCoeffsMontgomeryAndAddVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryAndAddNoMod multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, adding the result to p3 without modular reduction.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomeryAndAddNoMod(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] += MRed(p1tmp[j], p2tmp[j], qi, mredParams)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsMontgomeryAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsMontgomeryAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryAndAddNoModLvl multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, adding the result to p3 without modular reduction.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomeryAndAddNoModLvl(level uint64, p1, p2, p3 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			mulCoeffsMontgomeryAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] += MRed(p1tmp[j], p2tmp[j], qi, mredParams)
-		}
+		mulCoeffsMontgomeryAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 func (context *Context) MulCoeffsMontgomeryConstantAndAddNoModLvl(level uint64, p1, p2, p3 *Poly) {
-	var qi uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			mulCoeffsMontgomeryConstantAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] += MRedConstant(p1tmp[j], p2tmp[j], qi, mredParams)
-		}
+		mulCoeffsMontgomeryConstantAndAddNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryAndSub multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, subtractsing the result to p3 with modular reduction.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomeryAndSub(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = CRed(p3tmp[j]+(qi-MRed(p1tmp[j], p2tmp[j], qi, mredParams)), qi)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsMontgomeryAndSubVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsMontgomeryAndSubVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryAndSubNoMod multiplies p1 by p2 coefficient wise with a Montgomery modular reduction, subtractsing the result to p3 without modular reduction.
 // Expects p1 and/or p2 to be in Montgomery form for correctness (see MRed).
 func (context *Context) MulCoeffsMontgomeryAndSubNoMod(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = p3tmp[j] + (qi - MRed(p1tmp[j], p2tmp[j], qi, mredParams))
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsMontgomeryAndSubNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsMontgomeryAndSubNoModVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
 // MulCoeffsConstant multiplies p1 by p2 coefficient wise with a constant time Barrett modular reduction, returning the result on p3.
 // The output range of the modular reduction is [0, 2*Qi -1].
 func (context *Context) MulCoeffsConstant(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		bredParams := context.bredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = BRedConstant(p1tmp[j], p2tmp[j], qi, bredParams)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsConstantVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsConstantVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
 // MulCoeffsMontgomeryConstant multiplies p1 by p2 coefficient wise with a constant time Montgomery modular reduction, returning the result on p3.
 // The output range of the modular reduction is [0, 2*Qi -1].
 func (context *Context) MulCoeffsMontgomeryConstant(p1, p2, p3 *Poly) {
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp, p3tmp := p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p3tmp[j] = MRedConstant(p1tmp[j], p2tmp[j], qi, mredParams)
-		}
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulCoeffsMontgomeryConstantVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulCoeffsMontgomeryConstantVec(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
@@ -509,30 +605,47 @@ func (context *Context) SubScalarBigint(p1 *Poly, scalar *big.Int, p2 *Poly) {
 	}
 }
 
-// MulScalar multiplies each coefficient of p1 by a scalar and applies a modular reduction, returning the result on p2.
-func (context *Context) MulScalar(p1 *Poly, scalar uint64, p2 *Poly) {
-	var scalarMont uint64
-	for i, Qi := range context.Modulus {
-		scalarMont = MForm(BRedAdd(scalar, Qi, context.bredParams[i]), Qi, context.bredParams[i])
-		mredParams := context.mredParams[i]
+// SubScalarBigintLvl subtracts to each coefficient of p1 a big.Int scalar for the moduli from q_0 up to q_level and applies a modular reduction, returing the result on p2.
+func (context *Context) SubScalarBigintLvl(level uint64, p1 *Poly, scalar *big.Int, p2 *Poly) {
+	tmp := new(big.Int)
+	var scalarQi uint64
+	for i := uint64(0); i < level+1; i++ {
+		Qi := context.Modulus[i]
+		scalarQi = tmp.Mod(scalar, NewUint(Qi)).Uint64()
 		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
 		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = MRed(p1tmp[j], scalarMont, Qi, mredParams)
+			p2tmp[j] = CRed(p1tmp[j]+(Qi-scalarQi), Qi)
 		}
 	}
 }
 
+// MulScalar multiplies each coefficient of p1 by a scalar and applies a modular reduction, returning the result on p2.
+func (context *Context) MulScalar(p1 *Poly, scalar uint64, p2 *Poly) {
+
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mulScalarVec(p1.Coeffs[i], p2.Coeffs[i], scalar, context.Modulus[i], context.bredParams[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mulScalarVec(p1.Coeffs[i], p2.Coeffs[i], scalar, context.Modulus[i], context.bredParams[i], context.mredParams[i])
+	}
+}
+
 // MulScalarLvl multiplies each coefficient of p1 by a scalar and applies a modular reduction, returning the result on p2.
 func (context *Context) MulScalarLvl(level uint64, p1 *Poly, scalar uint64, p2 *Poly) {
-	var Qi, scalarMont uint64
+
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			mulScalarVec(p1.Coeffs[i], p2.Coeffs[i], scalar, context.Modulus[i], context.bredParams[i], context.mredParams[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		Qi = context.Modulus[i]
-		scalarMont = MForm(BRedAdd(scalar, Qi, context.bredParams[i]), Qi, context.bredParams[i])
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = MRed(p1tmp[j], scalarMont, Qi, mredParams)
-		}
+		mulScalarVec(p1.Coeffs[i], p2.Coeffs[i], scalar, context.Modulus[i], context.bredParams[i], context.mredParams[i])
 	}
 }
 
@@ -582,39 +695,45 @@ func (context *Context) Shift(p1 *Poly, n uint64, p2 *Poly) {
 // MForm setss p1 in conventional form to its Montgomeryform, returning the result on p2.
 func (context *Context) MForm(p1, p2 *Poly) {
 
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		bredParams := context.bredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = MForm(p1tmp[j], qi, bredParams)
-		}
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			mformVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		mformVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
 // MFormLvl setss p1 in conventional form to its Montgomeryform, returning the result on p2.
 func (context *Context) MFormLvl(level uint64, p1, p2 *Poly) {
 
-	var qi uint64
-	var bredParams []uint64
+	if context.workerPool != nil {
+		context.workerPool.run(level+1, func(i uint64) {
+			mformVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
+		})
+		return
+	}
+
 	for i := uint64(0); i < level+1; i++ {
-		qi = context.Modulus[i]
-		bredParams = context.bredParams[i]
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = MForm(p1tmp[j], qi, bredParams)
-		}
+		mformVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.bredParams[i])
 	}
 }
 
 // InvMForm setss p1 in Montgomeryform to its conventional form, returning the result on p2.
 func (context *Context) InvMForm(p1, p2 *Poly) {
 
-	for i, qi := range context.Modulus {
-		p1tmp, p2tmp := p1.Coeffs[i], p2.Coeffs[i]
-		mredParams := context.mredParams[i]
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = InvMForm(p1tmp[j], qi, mredParams)
-		}
+	if context.workerPool != nil {
+		context.workerPool.run(uint64(len(context.Modulus)), func(i uint64) {
+			invMFormVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.mredParams[i])
+		})
+		return
+	}
+
+	for i := range context.Modulus {
+		invMFormVec(p1.Coeffs[i], p2.Coeffs[i], context.Modulus[i], context.mredParams[i])
 	}
 }
 
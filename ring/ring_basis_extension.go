@@ -73,6 +73,24 @@ func NewFastBasisExtender(contextQ, contextP *Context) *FastBasisExtender {
 	return newParams
 }
 
+// ShallowCopy creates a copy of the target FastBasisExtender, sharing its read-only parameters with the original
+// but with its own memory pool, so that the copy and the original can be used concurrently.
+func (basisextender *FastBasisExtender) ShallowCopy() *FastBasisExtender {
+
+	newParams := new(FastBasisExtender)
+	*newParams = *basisextender
+
+	newParams.polypoolQ = basisextender.contextQ.NewPoly()
+	newParams.polypoolP = basisextender.contextP.NewPoly()
+
+	return newParams
+}
+
+// workerPool returns the WorkerPool of the context Q of the FastBasisExtender, over which the basis extensions are split.
+func (basisextender *FastBasisExtender) workerPool() *WorkerPool {
+	return basisextender.contextQ.workerPool
+}
+
 func basisextenderparameters(Q, P []uint64) (params *modupParams) {
 
 	params = new(modupParams)
@@ -145,14 +163,14 @@ func basisextenderparameters(Q, P []uint64) (params *modupParams) {
 // Given a polynomial with coefficients in basis {Q0,Q1....Qi}
 // Extends its basis from {Q0,Q1....Qi} to {Q0,Q1....Qi,P0,P1...Pj}
 func (basisextender *FastBasisExtender) ModUpSplitQP(level uint64, p1, p2 *Poly) {
-	modUpExact(p1.Coeffs[:level+1], p2.Coeffs[:uint64(len(basisextender.paramsQP.P))], basisextender.paramsQP)
+	modUpExact(p1.Coeffs[:level+1], p2.Coeffs[:uint64(len(basisextender.paramsQP.P))], basisextender.paramsQP, basisextender.workerPool())
 }
 
 // ModUpSplitPQ extends the basis of a polynomial
 // Given a polynomial with coefficients in basis {P0,P1....Pi}
 // Extends its basis from {P0,P1....Pi} to {Q0,Q1...Qj}
 func (basisextender *FastBasisExtender) ModUpSplitPQ(level uint64, p1, p2 *Poly) {
-	modUpExact(p1.Coeffs[:level+1], p2.Coeffs[:uint64(len(basisextender.paramsPQ.P))], basisextender.paramsPQ)
+	modUpExact(p1.Coeffs[:level+1], p2.Coeffs[:uint64(len(basisextender.paramsPQ.P))], basisextender.paramsPQ, basisextender.workerPool())
 }
 
 // ModDownNTTPQ reduces the basis of a polynomial.
@@ -168,33 +186,22 @@ func (basisextender *FastBasisExtender) ModDownNTTPQ(level uint64, p1, p2 *Poly)
 	polypool := basisextender.polypoolQ
 
 	// First we get the P basis part of p1 out of the NTT domain
-	for j := 0; j < len(contextP.Modulus); j++ {
-		InvNTT(p1.Coeffs[len(contextQ.Modulus)+j], p1.Coeffs[len(contextQ.Modulus)+j], contextP.N, contextP.GetNttPsiInv()[j], contextP.GetNttNInv()[j], contextP.Modulus[j], contextP.GetMredParams()[j])
+	if pool := basisextender.workerPool(); pool != nil {
+		pool.run(uint64(len(contextP.Modulus)), func(j uint64) {
+			InvNTT(p1.Coeffs[uint64(len(contextQ.Modulus))+j], p1.Coeffs[uint64(len(contextQ.Modulus))+j], contextP.N, contextP.GetNttPsiInv()[j], contextP.GetNttNInv()[j], contextP.Modulus[j], contextP.GetMredParams()[j])
+		})
+	} else {
+		for j := 0; j < len(contextP.Modulus); j++ {
+			InvNTT(p1.Coeffs[len(contextQ.Modulus)+j], p1.Coeffs[len(contextQ.Modulus)+j], contextP.N, contextP.GetNttPsiInv()[j], contextP.GetNttNInv()[j], contextP.Modulus[j], contextP.GetMredParams()[j])
+		}
 	}
 
 	// Then we target this P basis of p1 and convert it to a Q basis (at the "level" of p1) and copy it on polypool
 	// polypool is now the representation of the P basis of p1 but in basis Q (at the "level" of p1)
-	modUpExact(p1.Coeffs[len(contextQ.Modulus):len(contextQ.Modulus)+len(contextP.Modulus)], polypool.Coeffs[:level+1], basisextender.paramsPQ)
+	modUpExact(p1.Coeffs[len(contextQ.Modulus):len(contextQ.Modulus)+len(contextP.Modulus)], polypool.Coeffs[:level+1], basisextender.paramsPQ, basisextender.workerPool())
 
 	// Finaly, for each level of p1 (and polypool since they now share the same basis) we compute p2 = (P^-1) * (p1 - polypool) mod Q
-	for i := uint64(0); i < level+1; i++ {
-
-		qi := contextQ.Modulus[i]
-		p1tmp := p1.Coeffs[i]
-		p2tmp := p2.Coeffs[i]
-		p3tmp := polypool.Coeffs[i]
-		params := modDownParams[i]
-		mredParams := contextQ.mredParams[i]
-		bredParams := contextQ.bredParams[i]
-
-		// First we switch back the relevant polypool CRT array back to the NTT domain
-		NTT(p3tmp, p3tmp, contextQ.N, contextQ.GetNttPsi()[i], qi, mredParams, bredParams)
-
-		// Then for each coefficient we compute (P^-1) * (p1[i][j] - polypool[i][j]) mod qi
-		for j := uint64(0); j < contextQ.N; j++ {
-			p2tmp[j] = MRed(p1tmp[j]+(qi-p3tmp[j]), params, qi, mredParams)
-		}
-	}
+	modDown(contextQ, basisextender.workerPool(), level, p1, polypool, p2, modDownParams, true)
 
 	// In total we do len(P) + len(Q) NTT, which is optimal (linear in the number of moduli of P and Q)
 }
@@ -216,27 +223,10 @@ func (basisextender *FastBasisExtender) ModDownSplitedNTTPQ(level uint64, p1Q, p
 
 	// Then we target this P basis of p1 and convert it to a Q basis (at the "level" of p1) and copy it on polypool
 	// polypool is now the representation of the P basis of p1 but in basis Q (at the "level" of p1)
-	modUpExact(p1P.Coeffs, polypool.Coeffs[:level+1], basisextender.paramsPQ)
+	modUpExact(p1P.Coeffs, polypool.Coeffs[:level+1], basisextender.paramsPQ, basisextender.workerPool())
 
 	// Finaly, for each level of p1 (and polypool since they now share the same basis) we compute p2 = (P^-1) * (p1 - polypool) mod Q
-	for i := uint64(0); i < level+1; i++ {
-
-		qi := contextQ.Modulus[i]
-		p1tmp := p1Q.Coeffs[i]
-		p2tmp := p2.Coeffs[i]
-		p3tmp := polypool.Coeffs[i]
-		params := modDownParams[i]
-		mredParams := contextQ.mredParams[i]
-		bredParams := contextQ.bredParams[i]
-
-		// First we switch back the relevant polypool CRT array back to the NTT domain
-		NTT(p3tmp, p3tmp, contextQ.N, contextQ.GetNttPsi()[i], contextQ.Modulus[i], mredParams, bredParams)
-
-		// Then for each coefficient we compute (P^-1) * (p1[i][j] - polypool[i][j]) mod qi
-		for j := uint64(0); j < contextQ.N; j++ {
-			p2tmp[j] = MRed(p1tmp[j]+(qi-p3tmp[j]), params, qi, mredParams)
-		}
-	}
+	modDown(contextQ, basisextender.workerPool(), level, p1Q, polypool, p2, modDownParams, true)
 
 	// In total we do len(P) + len(Q) NTT, which is optimal (linear in the number of moduli of P and Q)
 }
@@ -253,23 +243,10 @@ func (basisextender *FastBasisExtender) ModDownPQ(level uint64, p1, p2 *Poly) {
 
 	// Then we target this P basis of p1 and convert it to a Q basis (at the "level" of p1) and copy it on polypool
 	// polypool is now the representation of the P basis of p1 but in basis Q (at the "level" of p1)
-	modUpExact(p1.Coeffs[level+1:level+1+uint64(len(basisextender.paramsQP.P))], polypool.Coeffs[:level+1], basisextender.paramsPQ)
+	modUpExact(p1.Coeffs[level+1:level+1+uint64(len(basisextender.paramsQP.P))], polypool.Coeffs[:level+1], basisextender.paramsPQ, basisextender.workerPool())
 
 	// Finaly, for each level of p1 (and polypool since they now share the same basis) we compute p2 = (P^-1) * (p1 - polypool) mod Q
-	for i := uint64(0); i < level+1; i++ {
-
-		qi := context.Modulus[i]
-		p1tmp := p1.Coeffs[i]
-		p2tmp := p2.Coeffs[i]
-		p3tmp := polypool.Coeffs[i]
-		params := modDownParams[i]
-		mredParams := context.mredParams[i]
-
-		// Then for each coefficient we compute (P^-1) * (p1[i][j] - polypool[i][j]) mod qi
-		for j := uint64(0); j < context.N; j++ {
-			p2tmp[j] = MRed(p1tmp[j]+(qi-p3tmp[j]), params, qi, mredParams)
-		}
-	}
+	modDown(context, basisextender.workerPool(), level, p1, polypool, p2, modDownParams, false)
 
 	// In total we do len(P) + len(Q) NTT, which is optimal (linear in the number of moduli of P and Q)
 }
@@ -286,23 +263,10 @@ func (basisextender *FastBasisExtender) ModDownSplitedPQ(level uint64, p1Q, p1P,
 
 	// Then we target this P basis of p1 and convert it to a Q basis (at the "level" of p1) and copy it on polypool
 	// polypool is now the representation of the P basis of p1 but in basis Q (at the "level" of p1)
-	modUpExact(p1P.Coeffs, polypool.Coeffs[:level+1], basisextender.paramsPQ)
+	modUpExact(p1P.Coeffs, polypool.Coeffs[:level+1], basisextender.paramsPQ, basisextender.workerPool())
 
 	// Finaly, for each level of p1 (and polypool since they now share the same basis) we compute p2 = (P^-1) * (p1 - polypool) mod Q
-	for i := uint64(0); i < level+1; i++ {
-
-		qi := contextQ.Modulus[i]
-		p1tmp := p1Q.Coeffs[i]
-		p2tmp := p2.Coeffs[i]
-		p3tmp := polypool.Coeffs[i]
-		params := modDownParams[i]
-		mredParams := contextQ.mredParams[i]
-
-		// Then for each coefficient we compute (P^-1) * (p1[i][j] - polypool[i][j]) mod qi
-		for j := uint64(0); j < contextQ.N; j++ {
-			p2tmp[j] = MRed(p1tmp[j]+(qi-p3tmp[j]), params, qi, mredParams)
-		}
-	}
+	modDown(contextQ, basisextender.workerPool(), level, p1Q, polypool, p2, modDownParams, false)
 
 	// In total we do len(P) + len(Q) NTT, which is optimal (linear in the number of moduli of P and Q)
 }
@@ -331,25 +295,56 @@ func (basisextender *FastBasisExtender) ModDownSplitedQP(levelQ, levelP uint64,
 	//fmt.Println("P", bigint_coeffs[0])
 
 	// Finaly, for each level of p1 (and polypool since they now share the same basis) we compute p2 = (P^-1) * (p1 - polypool) mod Q
-	for i := uint64(0); i < levelP+1; i++ {
-
-		qi := contextP.Modulus[i]
-		p1tmp := p1P.Coeffs[i]
-		p2tmp := p2.Coeffs[i]
-		p3tmp := polypool.Coeffs[i]
-		params := modDownParams[i]
-		mredParams := contextP.mredParams[i]
-
-		// Then for each coefficient we compute (P^-1) * (p1[i][j] - polypool[i][j]) mod qi
-		for j := uint64(0); j < contextP.N; j++ {
-			p2tmp[j] = MRed(p1tmp[j]+(qi-p3tmp[j]), params, qi, mredParams)
-		}
-	}
+	modDown(contextP, basisextender.workerPool(), levelP, p1P, polypool, p2, modDownParams, false)
 
 	// In total we do len(P) + len(Q) NTT, which is optimal (linear in the number of moduli of P and Q)
 }
 
-func modUpExact(p1, p2 [][]uint64, params *modupParams) {
+// modDown computes p2 = (p1 - polypool) * modDownParams mod qi for each modulus qi of the context up to the given level,
+// first switching polypool back to the NTT domain if nttPolypool is true.
+func modDown(context *Context, pool *WorkerPool, level uint64, p1, polypool, p2 *Poly, modDownParams []uint64, nttPolypool bool) {
+
+	if pool != nil {
+		pool.run(level+1, func(i uint64) {
+			modDownModulus(context, i, p1, polypool, p2, modDownParams[i], nttPolypool)
+		})
+		return
+	}
+
+	for i := uint64(0); i < level+1; i++ {
+		modDownModulus(context, i, p1, polypool, p2, modDownParams[i], nttPolypool)
+	}
+}
+
+func modDownModulus(context *Context, i uint64, p1, polypool, p2 *Poly, modDownParam uint64, nttPolypool bool) {
+
+	qi := context.Modulus[i]
+	mredParams := context.mredParams[i]
+
+	// First we switch back the relevant polypool CRT array back to the NTT domain
+	if nttPolypool {
+		NTT(polypool.Coeffs[i], polypool.Coeffs[i], context.N, context.nttPsi[i], qi, mredParams, context.bredParams[i])
+	}
+
+	// Then for each coefficient we compute (P^-1) * (p1[i][j] - polypool[i][j]) mod qi
+	subVecAndMulScalarMontgomery(p1.Coeffs[i], polypool.Coeffs[i], p2.Coeffs[i], modDownParam, qi, mredParams)
+}
+
+func modUpExact(p1, p2 [][]uint64, params *modupParams, pool *WorkerPool) {
+
+	//The coefficients are split in contiguous blocks, each extended by a worker of the pool
+	if pool != nil {
+		pool.runBlocks(uint64(len(p1[0])), func(start, end uint64) {
+			modUpExactBlock(p1, p2, params, start, end)
+		})
+		return
+	}
+
+	modUpExactBlock(p1, p2, params, 0, uint64(len(p1[0])))
+}
+
+// modUpExactBlock applies the basis extension of modUpExact on the coefficients of index start to end-1.
+func modUpExactBlock(p1, p2 [][]uint64, params *modupParams, start, end uint64) {
 
 	var v uint64
 	var vi float64
@@ -358,7 +353,7 @@ func modUpExact(p1, p2 [][]uint64, params *modupParams) {
 	y := make([]uint64, len(p1))
 
 	//We loop over each coefficient and apply the basis extension
-	for x := uint64(0); x < uint64(len(p1[0])); x++ {
+	for x := start; x < end; x++ {
 
 		vi = 0
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/bits"
 	"math/rand"
+	"runtime"
 	"testing"
 )
 
@@ -19,6 +20,8 @@ func BenchmarkRing(b *testing.B) {
 	b.Run("NegCoeffs", benchNegCoeffs)
 	b.Run("MulScalar", benchMulScalar)
 	b.Run("ExtendBasis", benchExtendBasis)
+	b.Run("WorkerPool", benchWorkerPool)
+	b.Run("NoWorkerPool", benchNoWorkerPool)
 	b.Run("DivByLastModulus", benchDivByLastModulus)
 	b.Run("MRed", benchMRed)
 	b.Run("BRed", benchBRed)
@@ -346,6 +349,95 @@ func benchExtendBasis(b *testing.B) {
 	}
 }
 
+func benchWorkerPool(b *testing.B) {
+
+	// The speedup is measured against the sequential execution (workers=1), up to the number of available cores
+	for workers := uint64(1); workers <= uint64(runtime.NumCPU()); workers <<= 1 {
+
+		pool := NewWorkerPool(workers)
+
+		for _, parameters := range testParams.polyParams {
+
+			contextQ := genPolyContext(parameters[0])
+			contextP := genPolyContext(parameters[1])
+
+			contextQ.SetWorkerPool(pool)
+			contextP.SetWorkerPool(pool)
+
+			basisExtender := NewFastBasisExtender(contextQ, contextP)
+
+			p0 := contextQ.NewUniformPoly()
+			p1 := contextQ.NewUniformPoly()
+			p2 := contextP.NewUniformPoly()
+
+			level := uint64(len(contextQ.Modulus) - 1)
+
+			b.Run(testString(fmt.Sprintf("NTT/workers=%d/", workers), contextQ), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					contextQ.NTT(p0, p0)
+				}
+			})
+
+			b.Run(testString(fmt.Sprintf("MulCoeffsMontgomery/workers=%d/", workers), contextQ), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					contextQ.MulCoeffsMontgomery(p0, p1, p0)
+				}
+			})
+
+			b.Run(testString(fmt.Sprintf("ModUp/workers=%d/", workers), contextQ), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					basisExtender.ModUpSplitQP(level, p0, p2)
+				}
+			})
+
+			b.Run(testString(fmt.Sprintf("ModDownNTT/workers=%d/", workers), contextQ), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					basisExtender.ModDownSplitedNTTPQ(level, p0, p2, p0)
+				}
+			})
+		}
+
+		pool.Close()
+	}
+}
+
+// benchNoWorkerPool measures the operations of a context without WorkerPool, which must not allocate.
+func benchNoWorkerPool(b *testing.B) {
+
+	for _, parameters := range testParams.polyParams {
+
+		context := genPolyContext(parameters[0])
+
+		p0 := context.NewUniformPoly()
+		p1 := context.NewUniformPoly()
+
+		ops := []struct {
+			name string
+			op   func()
+		}{
+			{"Add", func() { context.Add(p0, p1, p0) }},
+			{"NTT", func() { context.NTT(p0, p0) }},
+			{"MulCoeffsMontgomery", func() { context.MulCoeffsMontgomery(p0, p1, p0) }},
+		}
+
+		for _, op := range ops {
+			b.Run(testString(op.name+"/", context), func(b *testing.B) {
+
+				if allocs := testing.AllocsPerRun(10, op.op); allocs != 0 {
+					b.Fatalf("%s without worker pool allocates %.0f times per call", op.name, allocs)
+				}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					op.op()
+				}
+			})
+		}
+	}
+}
+
 func benchDivByLastModulus(b *testing.B) {
 	for _, parameters := range testParams.polyParams {
 
@@ -125,3 +125,31 @@ func (context *Context) Permute(polIn *Poly, gen uint64, polOut *Poly) {
 		}
 	}
 }
+
+// PermuteLvl applies the galois transform on a polynonial outside of the NTT domain, for the moduli from q_0 up to q_level.
+// It maps the coefficients x^i to x^(gen*i)
+// Careful, not inplace!
+func (context *Context) PermuteLvl(level uint64, polIn *Poly, gen uint64, polOut *Poly) {
+
+	var mask, index, indexRaw, logN, tmp uint64
+
+	mask = context.N - 1
+
+	logN = uint64(bits.Len64(mask))
+
+	for i := uint64(0); i < context.N; i++ {
+
+		indexRaw = i * gen
+
+		index = indexRaw & mask
+
+		tmp = (indexRaw >> logN) & 1
+
+		for j := uint64(0); j < level+1; j++ {
+
+			qi := context.Modulus[j]
+
+			polOut.Coeffs[j][index] = polIn.Coeffs[j][i]*(tmp^1) | (qi-polIn.Coeffs[j][i])*tmp
+		}
+	}
+}
@@ -55,6 +55,8 @@ func TestRing(t *testing.T) {
 	t.Run("ExtendBasis", testExtendBasis)
 	t.Run("SimpleScaling", testSimpleScaling)
 	t.Run("MultByMonomial", testMultByMonomial)
+	t.Run("WorkerPool", testWorkerPool)
+	t.Run("SecurityLevel", testSecurityLevel)
 }
 
 func genPolyContext(params *Parameters) (context *Context) {
@@ -650,3 +652,116 @@ func testMultByMonomial(t *testing.T) {
 		})
 	}
 }
+
+func testWorkerPool(t *testing.T) {
+
+	pool := NewWorkerPool(4)
+	defer pool.Close()
+
+	for _, parameters := range testParams.polyParams {
+
+		contextQ := genPolyContext(parameters[0])
+		contextP := genPolyContext(parameters[1])
+
+		contextQPool := genPolyContext(parameters[0])
+		contextPPool := genPolyContext(parameters[1])
+		contextQPool.SetWorkerPool(pool)
+		contextPPool.SetWorkerPool(pool)
+
+		t.Run(testString("", contextQ), func(t *testing.T) {
+
+			p0 := contextQ.NewUniformPoly()
+			p1 := contextQ.NewUniformPoly()
+
+			pWant := contextQ.NewPoly()
+			pTest := contextQ.NewPoly()
+
+			contextQ.NTT(p0, pWant)
+			contextQPool.NTT(p0, pTest)
+			if !contextQ.Equal(pWant, pTest) {
+				t.Errorf("error : NTT with worker pool")
+			}
+
+			contextQ.MulCoeffsMontgomery(p0, p1, pWant)
+			contextQPool.MulCoeffsMontgomery(p0, p1, pTest)
+			if !contextQ.Equal(pWant, pTest) {
+				t.Errorf("error : MulCoeffsMontgomery with worker pool")
+			}
+
+			level := uint64(len(contextQ.Modulus) - 1)
+
+			basisextender := NewFastBasisExtender(contextQ, contextP)
+			basisextenderPool := NewFastBasisExtender(contextQPool, contextPPool)
+
+			pPWant := contextP.NewPoly()
+			pPTest := contextP.NewPoly()
+
+			basisextender.ModUpSplitQP(level, p0, pPWant)
+			basisextenderPool.ModUpSplitQP(level, p0, pPTest)
+			if !contextP.Equal(pPWant, pPTest) {
+				t.Errorf("error : ModUpSplitQP with worker pool")
+			}
+
+			basisextender.ModDownSplitedNTTPQ(level, p1, pPWant, pWant)
+			basisextenderPool.ModDownSplitedNTTPQ(level, p1, pPTest, pTest)
+			if !contextQ.Equal(pWant, pTest) {
+				t.Errorf("error : ModDownSplitedNTTPQ with worker pool")
+			}
+
+			// Without worker pool, the operations must run sequentially without any allocation
+			if allocs := testing.AllocsPerRun(10, func() {
+				contextQ.Add(p0, p1, pWant)
+				contextQ.NTTLvl(level, p0, pWant)
+				contextQ.MulCoeffsMontgomeryLvl(level, p0, p1, pWant)
+				contextQ.MulScalar(p0, 3, pWant)
+			}); allocs != 0 {
+				t.Errorf("error : %.0f allocations without worker pool", allocs)
+			}
+		})
+	}
+}
+
+func testSecurityLevel(t *testing.T) {
+
+	for logN := uint64(MinSecurityLogN); logN <= MaxSecurityLogN; logN++ {
+
+		for i, level := range SecurityLevels {
+
+			bound, err := MaxLogQP(logN, level)
+
+			if err != nil || bound == 0 {
+				t.Errorf("error : no bound for logN=%d and %d bits of security", logN, level)
+			}
+
+			if securityLevel, err := SecurityLevel(logN, float64(bound)); err != nil || securityLevel != level {
+				t.Errorf("error : SecurityLevel for logN=%d and logQP=%d", logN, bound)
+			}
+
+			if securityLevel, _ := SecurityLevel(logN, float64(bound)+1); i < len(SecurityLevels)-1 && securityLevel == level {
+				t.Errorf("error : SecurityLevel for logN=%d and logQP=%d", logN, bound+1)
+			}
+		}
+
+		bound, _ := MaxLogQP(logN, 128)
+		if securityLevel, err := SecurityLevel(logN, float64(bound+1)); err != nil || securityLevel != 0 {
+			t.Errorf("error : SecurityLevel for logN=%d above the 128 bits bound", logN)
+		}
+	}
+
+	// The ring degrees and security levels that are not covered by the standard are not estimated
+	if _, err := SecurityLevel(MinSecurityLogN-1, 10); err != ErrSecurityNotEstimated {
+		t.Errorf("error : SecurityLevel for logN=%d", MinSecurityLogN-1)
+	}
+
+	if _, err := SecurityLevel(MaxSecurityLogN+1, 10); err != ErrSecurityNotEstimated {
+		t.Errorf("error : SecurityLevel for logN=%d", MaxSecurityLogN+1)
+	}
+
+	if _, err := MaxLogQP(12, 100); err != ErrSecurityNotEstimated {
+		t.Errorf("error : MaxLogQP for 100 bits of security")
+	}
+
+	if Log2OfProduct([]uint64{1 << 20, 1 << 30}, []uint64{1 << 40}) != 90 {
+		t.Errorf("error : Log2OfProduct")
+	}
+}
@@ -0,0 +1,135 @@
+package ring
+
+// The functions of this file apply the coefficient-wise operations of the context on the coefficients of a
+// polynomial for a single modulus, so that they can be called either sequentially or by the workers of a WorkerPool.
+
+func addVec(p1, p2, p3 []uint64, qi uint64) {
+	for j := range p3 {
+		p3[j] = CRed(p1[j]+p2[j], qi)
+	}
+}
+
+func addVecNoMod(p1, p2, p3 []uint64) {
+	for j := range p3 {
+		p3[j] = p1[j] + p2[j]
+	}
+}
+
+func subVec(p1, p2, p3 []uint64, qi uint64) {
+	for j := range p3 {
+		p3[j] = CRed((p1[j]+qi)-p2[j], qi)
+	}
+}
+
+func subVecNoMod(p1, p2, p3 []uint64, qi uint64) {
+	for j := range p3 {
+		p3[j] = (p1[j] + qi) - p2[j]
+	}
+}
+
+func negVec(p1, p2 []uint64, qi uint64) {
+	for j := range p2 {
+		p2[j] = qi - p1[j]
+	}
+}
+
+func reduceVec(p1, p2 []uint64, qi uint64, bredParams []uint64) {
+	for j := range p2 {
+		p2[j] = BRedAdd(p1[j], qi, bredParams)
+	}
+}
+
+func mulCoeffsVec(p1, p2, p3 []uint64, qi uint64, bredParams []uint64) {
+	for j := range p3 {
+		p3[j] = BRed(p1[j], p2[j], qi, bredParams)
+	}
+}
+
+func mulCoeffsAndAddVec(p1, p2, p3 []uint64, qi uint64, bredParams []uint64) {
+	for j := range p3 {
+		p3[j] = CRed(p3[j]+BRed(p1[j], p2[j], qi, bredParams), qi)
+	}
+}
+
+func mulCoeffsAndAddNoModVec(p1, p2, p3 []uint64, qi uint64, bredParams []uint64) {
+	for j := range p3 {
+		p3[j] += BRed(p1[j], p2[j], qi, bredParams)
+	}
+}
+
+func mulCoeffsConstantVec(p1, p2, p3 []uint64, qi uint64, bredParams []uint64) {
+	for j := range p3 {
+		p3[j] = BRedConstant(p1[j], p2[j], qi, bredParams)
+	}
+}
+
+func mulCoeffsMontgomeryVec(p1, p2, p3 []uint64, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] = MRed(p1[j], p2[j], qi, mredParams)
+	}
+}
+
+func mulCoeffsMontgomeryAndAddVec(p1, p2, p3 []uint64, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] = CRed(p3[j]+MRed(p1[j], p2[j], qi, mredParams), qi)
+	}
+}
+
+func mulCoeffsMontgomeryAndAddNoModVec(p1, p2, p3 []uint64, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] += MRed(p1[j], p2[j], qi, mredParams)
+	}
+}
+
+func mulCoeffsMontgomeryAndSubVec(p1, p2, p3 []uint64, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] = CRed(p3[j]+(qi-MRed(p1[j], p2[j], qi, mredParams)), qi)
+	}
+}
+
+func mulCoeffsMontgomeryAndSubNoModVec(p1, p2, p3 []uint64, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] = p3[j] + (qi - MRed(p1[j], p2[j], qi, mredParams))
+	}
+}
+
+func mulCoeffsMontgomeryConstantVec(p1, p2, p3 []uint64, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] = MRedConstant(p1[j], p2[j], qi, mredParams)
+	}
+}
+
+func mulCoeffsMontgomeryConstantAndAddNoModVec(p1, p2, p3 []uint64, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] += MRedConstant(p1[j], p2[j], qi, mredParams)
+	}
+}
+
+func mulScalarVec(p1, p2 []uint64, scalar, qi uint64, bredParams []uint64, mredParams uint64) {
+	mulScalarMontgomeryVec(p1, p2, MForm(BRedAdd(scalar, qi, bredParams), qi, bredParams), qi, mredParams)
+}
+
+func mulScalarMontgomeryVec(p1, p2 []uint64, scalarMont, qi, mredParams uint64) {
+	for j := range p2 {
+		p2[j] = MRed(p1[j], scalarMont, qi, mredParams)
+	}
+}
+
+// subVecAndMulScalarMontgomery computes p3 = (p1 - p2) * scalarMont mod qi, scalarMont being in Montgomery form.
+func subVecAndMulScalarMontgomery(p1, p2, p3 []uint64, scalarMont, qi, mredParams uint64) {
+	for j := range p3 {
+		p3[j] = MRed(p1[j]+(qi-p2[j]), scalarMont, qi, mredParams)
+	}
+}
+
+func mformVec(p1, p2 []uint64, qi uint64, bredParams []uint64) {
+	for j := range p2 {
+		p2[j] = MForm(p1[j], qi, bredParams)
+	}
+}
+
+func invMFormVec(p1, p2 []uint64, qi, mredParams uint64) {
+	for j := range p2 {
+		p2[j] = InvMForm(p1[j], qi, mredParams)
+	}
+}
@@ -0,0 +1,88 @@
+package ring
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrSecurityNotEstimated is returned by MaxLogQP and SecurityLevel for the ring degrees and the security levels that
+// are not covered by the tables of the Homomorphic Encryption Standard.
+var ErrSecurityNotEstimated = errors.New("security not estimated : ring degree or security level not covered by the Homomorphic Encryption Standard")
+
+// SecurityLevels are the supported security levels in bits, in ascending order.
+var SecurityLevels = []uint64{128, 192, 256}
+
+// MinSecurityLogN and MaxSecurityLogN are the smallest and the largest LogN for which the security is estimated.
+const (
+	MinSecurityLogN = 10
+	MaxSecurityLogN = 15
+)
+
+// maxLogQP stores, for each LogN, the largest log2(QP) ensuring 128, 192 and 256 bits of security against the known
+// classical attacks, for a uniform ternary secret and a Gaussian error of standard deviation 3.2.
+//
+// The bounds are the ones of the Homomorphic Encryption Standard (Albrecht et al., 2018) for the ternary secrets. The
+// standard does not cover the ring degrees larger than 2^15 nor the sparse secrets, whose security is therefore not
+// estimated.
+var maxLogQP = map[uint64][3]uint64{
+	10: {27, 19, 14},
+	11: {54, 37, 29},
+	12: {109, 75, 58},
+	13: {218, 152, 118},
+	14: {438, 305, 237},
+	15: {881, 611, 476},
+}
+
+// MaxLogQP returns the largest log2(QP) ensuring the given security level (128, 192 or 256 bits) for a ring of degree
+// 2^logN and a uniform ternary secret. It returns ErrSecurityNotEstimated if the ring degree or the security level is
+// not supported.
+func MaxLogQP(logN, securityLevel uint64) (uint64, error) {
+
+	bounds, ok := maxLogQP[logN]
+	if !ok {
+		return 0, ErrSecurityNotEstimated
+	}
+
+	for i, level := range SecurityLevels {
+		if level == securityLevel {
+			return bounds[i], nil
+		}
+	}
+
+	return 0, ErrSecurityNotEstimated
+}
+
+// SecurityLevel returns the highest security level (128, 192 or 256 bits) ensured by a ring of degree 2^logN, a
+// modulus QP of log2(QP) = logQP and a uniform ternary secret. As for the bounds of the standard, logQP is rounded to
+// the nearest integer, so that moduli slightly larger than powers of two of total bit-size MaxLogQP are accepted.
+// It returns 0 if none of the security levels is ensured, and ErrSecurityNotEstimated if the ring degree is not
+// supported.
+func SecurityLevel(logN uint64, logQP float64) (securityLevel uint64, err error) {
+
+	bounds, ok := maxLogQP[logN]
+	if !ok {
+		return 0, ErrSecurityNotEstimated
+	}
+
+	logQP = math.Round(logQP)
+
+	for i, level := range SecurityLevels {
+		if logQP <= float64(bounds[i]) {
+			securityLevel = level
+		}
+	}
+
+	return securityLevel, nil
+}
+
+// Log2OfProduct returns log2(prod(moduli)), computed without reconstructing the product.
+func Log2OfProduct(moduli ...[]uint64) (logQP float64) {
+
+	for _, m := range moduli {
+		for _, qi := range m {
+			logQP += math.Log2(float64(qi))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,184 @@
+package ring
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// PolySeed is a structure that stores the seed from which the uniform polynomials of seeded keys and ciphertexts
+// are generated with a CRPGenerator, along with the moduli of these polynomials.
+type PolySeed struct {
+	seed   []byte
+	moduli []uint64
+}
+
+// PolySeedSize is the size in bytes of the seeds generated by NewPolySeed.
+const PolySeedSize = 32
+
+// NewPolySeed creates a new PolySeed with a fresh random seed, for uniform polynomials with the given moduli.
+func NewPolySeed(moduli []uint64) (seed *PolySeed) {
+
+	seed = new(PolySeed)
+	seed.seed = make([]byte, PolySeedSize)
+	if _, err := rand.Read(seed.seed); err != nil {
+		panic("crypto rand error")
+	}
+
+	seed.moduli = make([]uint64, len(moduli))
+	copy(seed.moduli, moduli)
+
+	return seed
+}
+
+// Seed returns the seed of the target PolySeed.
+func (seed *PolySeed) Seed() []byte {
+	return seed.seed
+}
+
+// Moduli returns the moduli of the polynomials generated from the target PolySeed.
+func (seed *PolySeed) Moduli() []uint64 {
+	return seed.moduli
+}
+
+// CRPGenerator returns a new CRPGenerator of ring degree N, seeded with the target PolySeed.
+func (seed *PolySeed) CRPGenerator(N uint64) *CRPGenerator {
+
+	context := NewContext()
+	context.SetParameters(N, seed.moduli)
+
+	crpGenerator := NewCRPGenerator(nil, context)
+	crpGenerator.Seed(seed.seed)
+
+	return crpGenerator
+}
+
+// Expand regenerates nbPolys uniform polynomials of degree N from the target PolySeed.
+func (seed *PolySeed) Expand(N, nbPolys uint64) (polys []*Poly) {
+
+	crpGenerator := seed.CRPGenerator(N)
+
+	polys = make([]*Poly, nbPolys)
+	for i := range polys {
+		polys[i] = crpGenerator.ClockNew()
+	}
+
+	return polys
+}
+
+// GetDataLen returns the length in bytes of the target PolySeed.
+func (seed *PolySeed) GetDataLen(WithMetaData bool) (dataLen uint64) {
+
+	dataLen = uint64(len(seed.seed))
+
+	if WithMetaData {
+		dataLen += 2 + uint64(len(seed.moduli))<<3
+	}
+
+	return
+}
+
+// Encode writes the target PolySeed on data and returns the number of bytes written.
+func (seed *PolySeed) Encode(data []byte) (uint64, error) {
+
+	if uint64(len(data)) < seed.GetDataLen(true) {
+		return 0, errors.New("data array is too small to write the seed")
+	}
+
+	data[0] = uint8(len(seed.seed))
+	pointer := uint64(1)
+
+	pointer += uint64(copy(data[pointer:], seed.seed))
+
+	data[pointer] = uint8(len(seed.moduli))
+	pointer++
+
+	for _, qi := range seed.moduli {
+		binary.BigEndian.PutUint64(data[pointer:pointer+8], qi)
+		pointer += 8
+	}
+
+	return pointer, nil
+}
+
+// Decode reads a PolySeed from data and returns the number of bytes read.
+func (seed *PolySeed) Decode(data []byte) (pointer uint64, err error) {
+
+	if len(data) < 1 || len(data) < 2+int(data[0]) {
+		return 0, errors.New("invalid seed encoding")
+	}
+
+	seed.seed = make([]byte, data[0])
+	pointer = 1
+
+	pointer += uint64(copy(seed.seed, data[pointer:]))
+
+	seed.moduli = make([]uint64, data[pointer])
+	pointer++
+
+	if uint64(len(data)) < pointer+uint64(len(seed.moduli))<<3 {
+		return pointer, errors.New("invalid seed encoding")
+	}
+
+	for i := range seed.moduli {
+		seed.moduli[i] = binary.BigEndian.Uint64(data[pointer : pointer+8])
+		pointer += 8
+	}
+
+	return pointer, nil
+}
+
+// WriteTo writes the target PolySeed on w and returns the number of bytes written.
+func (seed *PolySeed) WriteTo(w io.Writer) (int64, error) {
+
+	data := make([]byte, seed.GetDataLen(true))
+
+	if _, err := seed.Encode(data); err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom reads a PolySeed from r and returns the number of bytes read.
+func (seed *PolySeed) ReadFrom(r io.Reader) (n int64, err error) {
+
+	var inc int
+
+	header := make([]byte, 1)
+	inc, err = io.ReadFull(r, header)
+	n += int64(inc)
+	if err != nil {
+		return n, err
+	}
+
+	seed.seed = make([]byte, header[0])
+
+	inc, err = io.ReadFull(r, seed.seed)
+	n += int64(inc)
+	if err != nil {
+		return n, err
+	}
+
+	inc, err = io.ReadFull(r, header)
+	n += int64(inc)
+	if err != nil {
+		return n, err
+	}
+
+	moduli := make([]byte, uint64(header[0])<<3)
+	inc, err = io.ReadFull(r, moduli)
+	n += int64(inc)
+	if err != nil {
+		return n, err
+	}
+
+	seed.moduli = make([]uint64, header[0])
+	for i := range seed.moduli {
+		seed.moduli[i] = binary.BigEndian.Uint64(moduli[i<<3 : (i+1)<<3])
+	}
+
+	return n, nil
+}
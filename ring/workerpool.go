@@ -0,0 +1,125 @@
+package ring
+
+import (
+	"sync"
+)
+
+// WorkerPool is a pool of goroutines that can be attached to one or several contexts to split the per-modulus
+// work of their operations across several cores. A context without WorkerPool runs all its operations sequentially,
+// in plain loops that neither allocate nor dispatch through closures.
+type WorkerPool struct {
+	workers uint64
+	tasks   chan func()
+	closed  sync.Once
+}
+
+// NewWorkerPool creates a new WorkerPool splitting the work in at most the given number of workers. Since the calling
+// goroutine always executes one share of the work, the pool starts workers-1 goroutines, which live until Close is called.
+func NewWorkerPool(workers uint64) *WorkerPool {
+
+	if workers == 0 {
+		panic("cannot NewWorkerPool : the number of workers must be at least 1")
+	}
+
+	pool := new(WorkerPool)
+	pool.workers = workers
+	pool.tasks = make(chan func())
+
+	for i := uint64(1); i < workers; i++ {
+		go func() {
+			for task := range pool.tasks {
+				task()
+			}
+		}()
+	}
+
+	return pool
+}
+
+// Workers returns the number of workers of the WorkerPool.
+func (pool *WorkerPool) Workers() uint64 {
+	return pool.workers
+}
+
+// Close stops the goroutines of the WorkerPool. The contexts using the WorkerPool must not be used afterward.
+func (pool *WorkerPool) Close() {
+	pool.closed.Do(func() {
+		close(pool.tasks)
+	})
+}
+
+// run calls f(i) for each i in [0, n), splitting the indexes among the workers of the pool, and returns once all
+// the calls are done. The calls are sequential if the pool is nil. A task that cannot be handed to an idle worker
+// is executed by the calling goroutine, so that a pool shared by several contexts or goroutines never deadlocks.
+func (pool *WorkerPool) run(n uint64, f func(i uint64)) {
+
+	if pool == nil || pool.workers < 2 || n < 2 {
+		for i := uint64(0); i < n; i++ {
+			f(i)
+		}
+		return
+	}
+
+	chunks := pool.workers
+	if n < chunks {
+		chunks = n
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(int(chunks - 1))
+
+	for c := uint64(1); c < chunks; c++ {
+
+		start := c
+
+		task := func() {
+			for i := start; i < n; i += chunks {
+				f(i)
+			}
+			wg.Done()
+		}
+
+		select {
+		case pool.tasks <- task:
+		default:
+			task()
+		}
+	}
+
+	for i := uint64(0); i < n; i += chunks {
+		f(i)
+	}
+
+	wg.Wait()
+}
+
+// runBlocks splits [0, n) into contiguous blocks, one per worker of the pool, and calls f(start, end) on each
+// of them, returning once all the calls are done.
+func (pool *WorkerPool) runBlocks(n uint64, f func(start, end uint64)) {
+
+	blocks := uint64(1)
+	if pool != nil {
+		blocks = pool.workers
+	}
+
+	if n < blocks {
+		blocks = n
+	}
+
+	pool.run(blocks, func(i uint64) {
+		f((i*n)/blocks, ((i+1)*n)/blocks)
+	})
+}
+
+// SetWorkerPool attaches the WorkerPool to the context. The per-modulus work of the operations of the context
+// (for example NTT, MulCoeffsMontgomery or the basis extension of a FastBasisExtender using this context for Q)
+// is then split across the workers of the pool. A nil WorkerPool restores the sequential execution.
+// This method must not be called while the context is being used.
+func (context *Context) SetWorkerPool(pool *WorkerPool) {
+	context.workerPool = pool
+}
+
+// WorkerPool returns the WorkerPool attached to the context, or nil if the context runs sequentially.
+func (context *Context) WorkerPool() *WorkerPool {
+	return context.workerPool
+}
@@ -30,6 +30,14 @@ func IsInSliceUint64(x uint64, slice []uint64) (v bool) {
 	return
 }
 
+// SumUint64 returns the sum of the values of the input slice of uint64 values.
+func SumUint64(slice []uint64) (s uint64) {
+	for _, v := range slice {
+		s += v
+	}
+	return
+}
+
 // MinUint64 returns the minimum value of the input slice of uint64 values.
 func MinUint64(a, b uint64) (r uint64) {
 	if a <= b {